@@ -0,0 +1,371 @@
+package addressing
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/dowlandaiello/GoP2P/common"
+)
+
+const (
+	// ProtocolIP4 - IPv4 address component (e.g. /ip4/1.2.3.4)
+	ProtocolIP4 = "ip4"
+
+	// ProtocolIP6 - IPv6 address component (e.g. /ip6/::1)
+	ProtocolIP6 = "ip6"
+
+	// ProtocolDNS - hostname component, resolved to any address family (e.g. /dns/example.com)
+	ProtocolDNS = "dns"
+
+	// ProtocolDNS4 - hostname component, resolved to IPv4 addresses
+	ProtocolDNS4 = "dns4"
+
+	// ProtocolDNS6 - hostname component, resolved to IPv6 addresses
+	ProtocolDNS6 = "dns6"
+
+	// ProtocolUnix - Unix-domain socket path component (e.g. /unix/tmp/gop2p.sock)
+	ProtocolUnix = "unix"
+
+	// ProtocolMemory - in-process transport address component (e.g. /memory/node)
+	ProtocolMemory = "memory"
+
+	// ProtocolTCP - TCP port component (e.g. /tcp/3000)
+	ProtocolTCP = "tcp"
+
+	// ProtocolTLS - TLS security component (GoP2P always secures connections with TLS, /tls is accepted for interoperability)
+	ProtocolTLS = "tls"
+
+	// ProtocolP2P - NodeID component, pinning identity of node dialed on address (e.g. /p2p/<NodeID>)
+	ProtocolP2P = "p2p"
+
+	// ProtocolCircuit - relayed address component, separating address of rendezvous, relay service from port, NodeID of node relayed through it (e.g. /ip4/1.2.3.4/tcp/3001/tls/p2p-circuit/tcp/3000/p2p/<NodeID>)
+	ProtocolCircuit = "p2p-circuit"
+
+	// separator - separator between address components
+	separator = "/"
+
+	// relaySeparator - separator between service address, NodeID of relayed transport addresses
+	relaySeparator = "/p2p/"
+)
+
+// Address - self-describing peer address encoding network, transport, port and identity (e.g. /ip4/1.2.3.4/tcp/3000/tls/p2p/<NodeID>, /unix/tmp/gop2p.sock)
+type Address struct {
+	Network string `json:"network"` // Network - network protocol (ip4, ip6, dns, dns4, dns6, unix, memory or p2p-circuit)
+
+	Relay string `json:"relay,omitempty"` // Relay - self-describing address of rendezvous, relay service node is reached through (p2p-circuit addresses only)
+
+	Host string `json:"host"` // Host - IP address, hostname, socket path or in-process address
+
+	Port int `json:"port,omitempty"` // Port - TCP port (0 if unspecified)
+
+	TLS bool `json:"tls,omitempty"` // TLS - address specifies /tls
+
+	NodeID string `json:"id,omitempty"` // NodeID - identity of node dialed on address (empty if unpinned)
+}
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// IsAddress - check given address is self-describing (i.e. starts with /), rather than a plain IP address, hostname or transport address
+func IsAddress(address string) bool {
+	return strings.HasPrefix(address, separator) // Check for leading separator
+}
+
+// Parse - parse given self-describing address (e.g. /ip4/1.2.3.4/tcp/3000/tls/p2p/<NodeID>, /ip6/::1/tcp/3000, /dns/example.com/tcp/3000, /unix/tmp/gop2p.sock/p2p/<NodeID>, /ip4/1.2.3.4/tcp/3001/tls/p2p-circuit/tcp/3000/p2p/<NodeID>)
+func Parse(address string) (Address, error) {
+	if !IsAddress(address) { // Check for invalid address
+		return Address{}, fmt.Errorf("address %s doesn't start with %s", address, separator) // Return error
+	}
+
+	components := strings.Split(address[1:], separator) // Split components
+
+	for x := 1; x < len(components); x++ { // Iterate through components following network
+		if components[x] == ProtocolCircuit { // Check for relayed address
+			return parseCircuit(address, components[:x], components[x+1:]) // Parse relayed address
+		}
+	}
+
+	parsed := Address{Network: components[0]} // Init address buffer
+
+	switch parsed.Network {
+	case ProtocolUnix: // Socket path (spans remaining components, except for a trailing NodeID)
+		if len(components) > 3 && components[len(components)-2] == ProtocolP2P { // Check for NodeID
+			parsed.NodeID = components[len(components)-1] // Set NodeID
+			components = components[:len(components)-2]   // Strip NodeID
+		}
+
+		parsed.Host = separator + strings.Join(components[1:], separator) // Set path
+
+		if len(components) < 2 || parsed.Host == separator { // Check for nil path
+			return Address{}, fmt.Errorf("missing socket path in address %s", address) // Return error
+		}
+
+		return parsed, nil // Return address
+	case ProtocolIP4, ProtocolIP6, ProtocolDNS, ProtocolDNS4, ProtocolDNS6, ProtocolMemory: // Single component host
+		if len(components) < 2 || components[1] == "" { // Check for nil host
+			return Address{}, fmt.Errorf("missing %s value in address %s", parsed.Network, address) // Return error
+		}
+
+		parsed.Host = components[1] // Set host
+	default:
+		return Address{}, fmt.Errorf("unsupported network protocol %s in address %s", parsed.Network, address) // Return error
+	}
+
+	if err := checkHost(parsed.Network, parsed.Host); err != nil { // Check host
+		return Address{}, fmt.Errorf("invalid address %s: %s", address, err.Error()) // Return error
+	}
+
+	return parseProtocols(parsed, components[2:], address) // Parse port, TLS, NodeID
+}
+
+// FromTransportAddress - convert given transport address (e.g. 1.2.3.4:3000, [::1]:3000, unix:///tmp/gop2p.sock, relay://1.2.3.4:3001/p2p/<NodeID>:3000) to self-describing address pinned to given NodeID (unpinned if empty)
+func FromTransportAddress(address string, nodeID string) (Address, error) {
+	if index := strings.Index(address, "://"); index != -1 && address[:index] != common.TransportSchemeTCP { // Check for non-TCP transport
+		switch scheme := address[:index]; scheme {
+		case common.TransportSchemeRelay: // Relayed address
+			return fromRelayAddress(address[index+3:], nodeID) // Convert relayed address
+		case common.TransportSchemeUnix, common.TransportSchemeMemory: // Opaque address
+			converted := Address{Network: scheme, Host: address[index+3:], NodeID: nodeID} // Init address
+
+			if converted.Host == "" { // Check for nil host
+				return Address{}, fmt.Errorf("missing host in address %s", address) // Return error
+			}
+
+			return converted, nil // Return address
+		default:
+			return Address{}, fmt.Errorf("%s addresses can't be expressed as self-describing addresses", scheme) // Return error
+		}
+	}
+
+	peerAddress, err := common.ParsePeerAddress(address) // Parse address
+
+	if err != nil { // Check for errors
+		return Address{}, err // Return found error
+	}
+
+	if peerAddress.Host == "" { // Check for nil host
+		return Address{}, fmt.Errorf("missing host in address %s", address) // Return error
+	}
+
+	converted := Address{Network: ProtocolDNS, Host: peerAddress.Host, Port: peerAddress.Port, TLS: peerAddress.Port != 0, NodeID: nodeID} // Init address (TCP connections are always secured with TLS)
+
+	if ip := peerAddress.IP(); ip != nil && ip.To4() != nil { // Check for IPv4 address
+		converted.Network = ProtocolIP4    // Set IPv4
+		converted.Host = ip.To4().String() // Normalize address
+	} else if ip != nil { // Check for IPv6 address
+		converted.Network = ProtocolIP6 // Set IPv6
+		converted.Host = ip.String()    // Normalize address
+	}
+
+	return converted, nil // Return address
+}
+
+// Select - parse first of given self-describing addresses local node supports (e.g. to pick one of several addresses published by a node)
+func Select(addresses []string) (Address, error) {
+	for _, address := range addresses { // Iterate through addresses
+		parsed, err := Parse(address) // Parse address
+
+		if err == nil && parsed.Supported() { // Check address can be dialed
+			return parsed, nil // Return address
+		}
+	}
+
+	return Address{}, fmt.Errorf("no supported address in %v", addresses) // Return error
+}
+
+// String - format address (e.g. /ip4/1.2.3.4/tcp/3000/tls/p2p/<NodeID>)
+func (address Address) String() string {
+	formatted := separator + address.Network + separator + strings.TrimPrefix(address.Host, separator) // Init formatted address
+
+	if address.Network == ProtocolCircuit { // Check for relayed address
+		formatted = address.Relay + separator + ProtocolCircuit // Init formatted relayed address
+	}
+
+	if address.Port != 0 { // Check for port
+		formatted += separator + ProtocolTCP + separator + strconv.Itoa(address.Port) // Append port
+	}
+
+	if address.TLS { // Check for TLS
+		formatted += separator + ProtocolTLS // Append TLS
+	}
+
+	if address.NodeID != "" { // Check for NodeID
+		formatted += separator + ProtocolP2P + separator + address.NodeID // Append NodeID
+	}
+
+	return formatted // Return formatted address
+}
+
+// PeerAddress - fetch host of address, without port (e.g. 1.2.3.4, unix:///tmp/gop2p.sock, relay://1.2.3.4:3001/p2p/<NodeID>)
+func (address Address) PeerAddress() common.PeerAddress {
+	switch address.Network {
+	case ProtocolCircuit: // Relayed address
+		relay, _ := Parse(address.Relay) // Parse service address (validated by Parse)
+
+		relayTransportAddress, _ := relay.TransportAddress() // Convert service address
+
+		return common.PeerAddress{Transport: common.TransportSchemeRelay, Host: relayTransportAddress + relaySeparator + address.NodeID, Port: address.Port} // Return address
+	case ProtocolUnix, ProtocolMemory: // Non-IP transport
+		return common.PeerAddress{Transport: address.Network, Host: address.Host} // Return address
+	default:
+		return common.PeerAddress{Host: address.Host, Port: address.Port} // Return address
+	}
+}
+
+// TransportAddress - convert address to transport address dialed by the connection pool (e.g. 1.2.3.4:3000, [::1]:3000, unix:///tmp/gop2p.sock, relay://1.2.3.4:3001/p2p/<NodeID>:3000)
+func (address Address) TransportAddress() (string, error) {
+	if address.Network == ProtocolUnix || address.Network == ProtocolMemory || address.Network == ProtocolCircuit { // Check for non-IP transport
+		return address.PeerAddress().String(), nil // Return transport address
+	}
+
+	if address.Port == 0 { // Check for nil port
+		return "", fmt.Errorf("address %s has no %s port", address.String(), ProtocolTCP) // Return error
+	}
+
+	return address.PeerAddress().String(), nil // Return transport address
+}
+
+// Supported - check local node can dial address (i.e. address specifies a port, and its transport is registered)
+func (address Address) Supported() bool {
+	transportAddress, err := address.TransportAddress() // Convert to transport address
+
+	if err != nil { // Check for errors
+		return false // Unsupported
+	}
+
+	_, _, err = common.ParseTransportAddress(transportAddress) // Find transport
+
+	return err == nil // Check transport is registered
+}
+
+/*
+	END EXPORTED METHODS
+*/
+
+/*
+	BEGIN INTERNAL METHODS:
+*/
+
+// parseCircuit - parse relayed address split into given components of service address, components following p2p-circuit
+func parseCircuit(address string, relayComponents []string, relayedComponents []string) (Address, error) {
+	relay, err := Parse(separator + strings.Join(relayComponents, separator)) // Parse service address
+
+	if err != nil { // Check for errors
+		return Address{}, err // Return found error
+	}
+
+	if _, err = relay.TransportAddress(); err != nil { // Check service can be dialed
+		return Address{}, fmt.Errorf("invalid relay in address %s: %s", address, err.Error()) // Return error
+	}
+
+	parsed, err := parseProtocols(Address{Network: ProtocolCircuit, Relay: relay.String()}, relayedComponents, address) // Parse port, TLS, NodeID of relayed node
+
+	if err != nil { // Check for errors
+		return Address{}, err // Return found error
+	}
+
+	if parsed.NodeID == "" { // Check for missing NodeID
+		return Address{}, fmt.Errorf("missing %s value of relayed node in address %s", ProtocolP2P, address) // Return error
+	}
+
+	return parsed, nil // Return address
+}
+
+// parseProtocols - parse given port, TLS and NodeID components of given address into given address
+func parseProtocols(parsed Address, components []string, address string) (Address, error) {
+	for x := 0; x < len(components); x++ { // Iterate through components
+		protocol := components[x] // Fetch protocol
+
+		value := "" // Init value buffer
+
+		if protocol == ProtocolTCP || protocol == ProtocolP2P { // Check protocol takes value
+			if x++; x == len(components) || components[x] == "" { // Check for nil value
+				return Address{}, fmt.Errorf("missing %s value in address %s", protocol, address) // Return error
+			}
+
+			value = components[x] // Set value
+		}
+
+		switch {
+		case protocol == ProtocolTCP && parsed.Port == 0 && !parsed.TLS && parsed.NodeID == "" && parsed.Network != ProtocolMemory: // Port
+			port, err := strconv.Atoi(value) // Parse port
+
+			if err != nil || port < 1 || port > 65535 { // Check for invalid port
+				return Address{}, fmt.Errorf("invalid port %s in address %s", value, address) // Return error
+			}
+
+			parsed.Port = port // Set port
+		case protocol == ProtocolTLS && parsed.Port != 0 && !parsed.TLS && parsed.NodeID == "": // TLS
+			parsed.TLS = true // Set TLS
+		case protocol == ProtocolP2P && parsed.NodeID == "": // NodeID
+			parsed.NodeID = value // Set NodeID
+		default:
+			return Address{}, fmt.Errorf("unexpected %s component in address %s", protocol, address) // Return error
+		}
+	}
+
+	return parsed, nil // Return address
+}
+
+// fromRelayAddress - convert given relayed transport address without scheme (e.g. 1.2.3.4:3001/p2p/<NodeID>:3000) to self-describing address, checking it is pinned to given NodeID (if set)
+func fromRelayAddress(address string, nodeID string) (Address, error) {
+	index := strings.LastIndex(address, relaySeparator) // Find NodeID
+
+	if index <= 0 { // Check for missing service, NodeID
+		return Address{}, fmt.Errorf("invalid relay address %s", address) // Return error
+	}
+
+	relay, err := FromTransportAddress(address[:index], "") // Convert service address
+
+	if err != nil { // Check for errors
+		return Address{}, err // Return found error
+	}
+
+	converted := Address{Network: ProtocolCircuit, Relay: relay.String(), NodeID: address[index+len(relaySeparator):]} // Init address
+
+	if portIndex := strings.LastIndex(converted.NodeID, ":"); portIndex != -1 { // Check for port
+		port, err := strconv.Atoi(converted.NodeID[portIndex+1:]) // Parse port
+
+		if err != nil || port < 1 || port > 65535 { // Check for invalid port
+			return Address{}, fmt.Errorf("invalid port in relay address %s", address) // Return error
+		}
+
+		converted.NodeID, converted.Port, converted.TLS = converted.NodeID[:portIndex], port, true // Set NodeID, port (relayed connections are always secured with TLS)
+	}
+
+	if converted.NodeID == "" || nodeID != "" && converted.NodeID != nodeID { // Check address is relayed to given node
+		return Address{}, fmt.Errorf("relay address %s isn't relayed to %s", address, nodeID) // Return error
+	}
+
+	return converted, nil // Return address
+}
+
+// checkHost - check given host is valid for given network protocol
+func checkHost(network string, host string) error {
+	ip := net.ParseIP(host) // Parse IP
+
+	switch network {
+	case ProtocolIP4:
+		if ip == nil || ip.To4() == nil || strings.Contains(host, ":") { // Check for invalid IPv4 address
+			return fmt.Errorf("invalid IPv4 address %s", host) // Return error
+		}
+	case ProtocolIP6:
+		if ip == nil || !strings.Contains(host, ":") { // Check for invalid IPv6 address
+			return fmt.Errorf("invalid IPv6 address %s", host) // Return error
+		}
+	case ProtocolDNS, ProtocolDNS4, ProtocolDNS6:
+		if strings.ContainsAny(host, ":[]") { // Check for invalid hostname
+			return errors.New("invalid hostname " + host) // Return error
+		}
+	}
+
+	return nil // Valid host
+}
+
+/*
+	END INTERNAL METHODS
+*/
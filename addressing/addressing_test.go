@@ -0,0 +1,98 @@
+package addressing
+
+import "testing"
+
+// TestParse - test functionality of Parse() method
+func TestParse(t *testing.T) {
+	for address, expected := range map[string]Address{
+		"/ip4/1.2.3.4/tcp/3000/tls/p2p/abc123": {Network: ProtocolIP4, Host: "1.2.3.4", Port: 3000, TLS: true, NodeID: "abc123"},
+		"/ip6/::1/tcp/3000":                    {Network: ProtocolIP6, Host: "::1", Port: 3000},
+		"/dns/example.com/tcp/3001/tls":        {Network: ProtocolDNS, Host: "example.com", Port: 3001, TLS: true},
+		"/ip4/1.2.3.4":                         {Network: ProtocolIP4, Host: "1.2.3.4"},
+		"/unix/tmp/gop2p.sock":                 {Network: ProtocolUnix, Host: "/tmp/gop2p.sock"},
+		"/unix/tmp/gop2p.sock/p2p/abc123":      {Network: ProtocolUnix, Host: "/tmp/gop2p.sock", NodeID: "abc123"},
+		"/memory/node:3000/p2p/abc123":         {Network: ProtocolMemory, Host: "node:3000", NodeID: "abc123"},
+		"/ip4/1.2.3.4/tcp/3001/tls/p2p/relay1/p2p-circuit/tcp/3000/tls/p2p/abc123": {Network: ProtocolCircuit, Relay: "/ip4/1.2.3.4/tcp/3001/tls/p2p/relay1", Port: 3000, TLS: true, NodeID: "abc123"},
+		"/memory/relay:3001/p2p-circuit/p2p/abc123":                                {Network: ProtocolCircuit, Relay: "/memory/relay:3001", NodeID: "abc123"},
+	} { // Iterate through valid addresses
+		parsed, err := Parse(address) // Parse address
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if parsed != expected { // Check for mismatch
+			t.Errorf("expected %+v for address %s, got %+v", expected, address, parsed) // Log found error
+			t.FailNow()                                                                 // Panic
+		}
+
+		if parsed.String() != address { // Check address round trips
+			t.Errorf("expected %s, got %s", address, parsed.String()) // Log found error
+			t.FailNow()                                               // Panic
+		}
+	}
+
+	for _, address := range []string{"", "1.2.3.4:3000", "/", "/ip4/::1/tcp/3000", "/ip6/1.2.3.4", "/ip4/1.2.3.4/tcp/port", "/ip4/1.2.3.4/tcp/0", "/ip4/1.2.3.4/tls", "/ip4/1.2.3.4/tcp/3000/p2p/abc123/tls", "/ip4/1.2.3.4/tcp/3000/tcp/3001", "/ip4/1.2.3.4/udp/3000", "/udp/1.2.3.4", "/unix", "/memory/node/tcp/3000", "/ip4/1.2.3.4/p2p", "/ip4/1.2.3.4/p2p-circuit/p2p/abc123", "/ip4/1.2.3.4/tcp/3001/p2p-circuit/tcp/3000", "/p2p-circuit/p2p/abc123"} { // Iterate through invalid addresses
+		if _, err := Parse(address); err == nil { // Parse address
+			t.Errorf("expected address %s to be rejected", address) // Log found error
+			t.FailNow()                                             // Panic
+		}
+	}
+}
+
+// TestFromTransportAddress - test transport addresses are converted to self-describing addresses, and back
+func TestFromTransportAddress(t *testing.T) {
+	for transportAddress, expected := range map[string]string{
+		"1.2.3.4:3000":                                "/ip4/1.2.3.4/tcp/3000/tls/p2p/abc123",
+		"[::1]:3000":                                  "/ip6/::1/tcp/3000/tls/p2p/abc123",
+		"example.com:3000":                            "/dns/example.com/tcp/3000/tls/p2p/abc123",
+		"unix:///tmp/x.sock":                          "/unix/tmp/x.sock/p2p/abc123",
+		"memory://node:3000":                          "/memory/node:3000/p2p/abc123",
+		"tcp://1.2.3.4:3000":                          "/ip4/1.2.3.4/tcp/3000/tls/p2p/abc123",
+		"relay://1.2.3.4:3001/p2p/abc123:3000":        "/ip4/1.2.3.4/tcp/3001/tls/p2p-circuit/tcp/3000/tls/p2p/abc123",
+		"relay://memory://relay:3001/p2p/abc123:3000": "/memory/relay:3001/p2p-circuit/tcp/3000/tls/p2p/abc123",
+	} { // Iterate through addresses
+		address, err := FromTransportAddress(transportAddress, "abc123") // Convert address
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if address.String() != expected { // Check for mismatch
+			t.Errorf("expected %s, got %s", expected, address.String()) // Log found error
+			t.FailNow()                                                 // Panic
+		}
+
+		if converted, err := address.TransportAddress(); err != nil || converted != transportAddress && "tcp://"+converted != transportAddress { // Check address converts back
+			t.Errorf("expected %s to convert back to %s, got %s (%v)", expected, transportAddress, converted, err) // Log found error
+			t.FailNow()                                                                                            // Panic
+		}
+	}
+
+	if _, err := FromTransportAddress("relay://1.2.3.4:3001/p2p/def456:3000", "abc123"); err == nil { // Check addresses relayed to other nodes are rejected
+		t.Errorf("expected relay address of other node to be rejected") // Log found error
+		t.FailNow()                                                     // Panic
+	}
+}
+
+// TestSelect - test first supported address is selected
+func TestSelect(t *testing.T) {
+	address, err := Select([]string{"/ip4/1.2.3.4", "invalid", "/ip6/::1/tcp/3000/tls", "/ip4/1.2.3.4/tcp/3000"}) // Select address
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if address.String() != "/ip6/::1/tcp/3000/tls" { // Check selected address
+		t.Errorf("invalid selected address %s", address.String()) // Log found error
+		t.FailNow()                                               // Panic
+	}
+
+	if _, err = Select([]string{"/ip4/1.2.3.4"}); err == nil { // Check addresses without port are unsupported
+		t.Errorf("expected selection to fail") // Log found error
+		t.FailNow()                            // Panic
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"strconv"
 
+	"github.com/dowlandaiello/GoP2P/addressing"
 	"github.com/dowlandaiello/GoP2P/common"
 	"github.com/dowlandaiello/GoP2P/types/database"
 	"github.com/dowlandaiello/GoP2P/types/node"
@@ -148,19 +149,21 @@ func (term *Terminal) handleAddSpecificNode(address string) (string, error) {
 		return "", err // Return found error
 	}
 
+	db.SetLocalNode(&foundNode) // Record operations on behalf of attached node
+
 	_, err = db.QueryForAddress(address)
 
 	if err == nil {
 		return "", errors.New("node already added to database")
 	}
 
-	newNode, err := node.NewNode(address, false) // Attempt to init node with specified address
+	newNode, err := newRemoteNode(address) // Attempt to init node with specified address
 
 	if err != nil { // Check for errors
 		return "", err // Return found error
 	}
 
-	err = db.AddNode(&newNode) // Attempt to add node
+	err = db.AddNode(newNode) // Attempt to add node
 
 	if err != nil { // Check for errors
 		return "", err // Return found error
@@ -197,6 +200,8 @@ func (term *Terminal) handleRemoveSpecificNode(address string) (string, error) {
 		return "", err
 	}
 
+	db.SetLocalNode(&foundNode) // Record operations on behalf of attached node
+
 	err = db.RemoveNode(address)
 
 	if err != nil { // Check for errors
@@ -246,6 +251,8 @@ func (term *Terminal) handleAddCurrentNode() (string, error) {
 		return "", err // Return found error
 	}
 
+	db.SetLocalNode(&foundNode) // Record operations on behalf of attached node
+
 	_, qErr := db.QueryForAddress(foundNode.Address) // Check for already existing node
 
 	if qErr != nil { // Check for already existing node
@@ -301,6 +308,8 @@ func (term *Terminal) handleRemoveCurrentNode() (string, error) {
 		return "", err // Return found error
 	}
 
+	db.SetLocalNode(&foundNode) // Record operations on behalf of attached node
+
 	_, qErr := db.QueryForAddress(foundNode.Address) // Check for already existing node
 
 	if qErr != nil { // Check for already existing node
@@ -437,3 +446,18 @@ func (term *Terminal) findDatabase() (*database.NodeDatabase, error) {
 
 	return db, nil // No error occurred, return found database
 }
+
+// newRemoteNode - initialize node at given plain or self-describing address (e.g. /ip4/1.2.3.4/tcp/3000/tls/p2p/<NodeID>)
+func newRemoteNode(address string) (*node.Node, error) {
+	if addressing.IsAddress(address) { // Check for self-describing address
+		return node.NodeFromAddress(address) // Return node pinned by address
+	}
+
+	newNode, err := node.NewNode(address, false) // Attempt to init node with specified address
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return &newNode, nil // Return node
+}
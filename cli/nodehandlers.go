@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"errors"
 	"strconv"
 	"time"
@@ -63,6 +64,32 @@ func (term *Terminal) handleStartHandlerCommand(port int) {
 	}
 }
 
+// handleStopHandlerCommand - attempt to gracefully shut down handler started on attached node
+func (term *Terminal) handleStopHandlerCommand() {
+	common.Println("attempting to stop handler") // Log begin
+
+	output, err := term.handleStopHandler() // Attempt to stop handler
+
+	if err != nil { // Check for errors
+		common.Println("Error: " + err.Error()) // Log error
+	} else {
+		common.Println(output) // Log success
+	}
+}
+
+// handleRestartHandlerCommand - attempt to restart handler started on attached node
+func (term *Terminal) handleRestartHandlerCommand() {
+	common.Println("attempting to restart handler") // Log begin
+
+	output, err := term.handleRestartHandler() // Attempt to restart handler
+
+	if err != nil { // Check for errors
+		common.Println("Error: " + err.Error()) // Log error
+	} else {
+		common.Println(output) // Log success
+	}
+}
+
 // handleNewNode - handle execution of NewNode() command
 func (term *Terminal) handleNewNode() (string, error) {
 	node, err := NewNode() // Attempt to create new node
@@ -150,29 +177,88 @@ func (term *Terminal) handleStartHandler(port int) (string, error) {
 		return "", errors.New("node not attached") // Log found error
 	}
 
-	err := upnp.ForwardPort(3000) // Attempt to forward port
+	_, err := upnp.ForwardPort(3000) // Attempt to forward port
 
 	if err != nil {
 		common.Println(err.Error())
 	}
 
-	ln, err := foundNode.StartListener(port) // Attempt to start handler
+	nodeHandler, err := handler.NewHandler(&foundNode, "", ":"+strconv.Itoa(port)) // Init handler on specified port
+
+	if err != nil { // Check for errors
+		return "", err // Return found error
+	}
+
+	err = nodeHandler.Listen() // Attempt to start listener
 
 	if err != nil { // Check for errors
 		return "", err // Return found error
 	}
 
-	err = term.AddVariable("", *ln, "Handler") // Attempt to save
+	err = term.AddVariable("", nodeHandler, "Handler") // Attempt to save
 
 	if err != nil { // Check for errors
 		return "", err // Return found error
 	}
 
-	go handler.StartHandler(&foundNode, ln)
+	go nodeHandler.Start(context.Background()) // Start handler
 
 	return "Success: started handler on port " + strconv.Itoa(port) + " with address " + foundNode.Address, nil // No error occurred, return success
 }
 
+// handleStopHandler - attempt to gracefully shut down handler started on node
+func (term *Terminal) handleStopHandler() (string, error) {
+	nodeHandler, err := term.findHandler() // Fetch handler
+
+	if err != nil { // Check for errors
+		return "", err // Return found error
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), handler.DefaultShutdownTimeout) // Init shutdown deadline
+
+	defer cancel() // Release deadline
+
+	err = nodeHandler.Shutdown(ctx) // Shut down handler
+
+	if err != nil { // Check for errors
+		return "", err // Return found error
+	}
+
+	return "Success: stopped handler", nil // No error occurred, return success
+}
+
+// handleRestartHandler - attempt to shut down, start handler started on node
+func (term *Terminal) handleRestartHandler() (string, error) {
+	output, err := term.handleStopHandler() // Stop handler
+
+	if err != nil { // Check for errors
+		return output, err // Return found error
+	}
+
+	nodeHandler, _ := term.findHandler() // Fetch handler
+
+	err = nodeHandler.Listen() // Attempt to start listener
+
+	if err != nil { // Check for errors
+		return "", err // Return found error
+	}
+
+	go nodeHandler.Start(context.Background()) // Start handler
+
+	return "Success: restarted handler", nil // No error occurred, return success
+}
+
+// findHandler - fetch latest handler started on node
+func (term *Terminal) findHandler() (*handler.Handler, error) {
+	for x := len(term.Variables) - 1; x >= 0; x-- { // Iterate through array
+		if term.Variables[x].VariableType == "Handler" { // Verify element is handler
+			return term.Variables[x].VariableData.(*handler.Handler), nil // Return handler
+		}
+	}
+
+	return nil, errors.New("handler not started") // Return error
+}
+
 /*
 	END NODE METHODS
 */
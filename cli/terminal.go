@@ -69,14 +69,16 @@ func NewTerminal(rpcPort uint, rpcAddress string) error {
 }
 
 func handleCommand(receiver string, methodname string, params []string, rpcPort uint, rpcAddress string, transport *http.Transport) {
-	nodeClient := nodeProto.NewNodeProtobufClient("https://"+rpcAddress+":"+strconv.Itoa(int(rpcPort)), &http.Client{Transport: transport})                      // Init node client
-	handlerClient := handlerProto.NewHandlerProtobufClient("https://"+rpcAddress+":"+strconv.Itoa(int(rpcPort)), &http.Client{Transport: transport})             // Init handler client
-	environmentClient := environmentProto.NewEnvironmentProtobufClient("https://"+rpcAddress+":"+strconv.Itoa(int(rpcPort)), &http.Client{Transport: transport}) // Init environment client
-	upnpClient := upnpProto.NewUpnpProtobufClient("https://"+rpcAddress+":"+strconv.Itoa(int(rpcPort)), &http.Client{Transport: transport})                      // Init upnp client
-	databaseClient := databaseProto.NewDatabaseProtobufClient("https://"+rpcAddress+":"+strconv.Itoa(int(rpcPort)), &http.Client{Transport: transport})          // Init database client
-	commonClient := commonProto.NewCommonProtobufClient("https://"+rpcAddress+":"+strconv.Itoa(int(rpcPort)), &http.Client{Transport: transport})                // Init common client
-	shardClient := shardProto.NewShardProtobufClient("https://"+rpcAddress+":"+strconv.Itoa(int(rpcPort)), &http.Client{Transport: transport})                   // Init shard client
-	protoClient := protoProto.NewProtoProtobufClient("https://"+rpcAddress+":"+strconv.Itoa(int(rpcPort)), &http.Client{Transport: transport})                   // Init proto client
+	endpoint := "https://" + common.PeerAddress{Host: rpcAddress, Port: int(rpcPort)}.String() // Init RPC endpoint (bracketing IPv6 addresses)
+
+	nodeClient := nodeProto.NewNodeProtobufClient(endpoint, &http.Client{Transport: transport})                      // Init node client
+	handlerClient := handlerProto.NewHandlerProtobufClient(endpoint, &http.Client{Transport: transport})             // Init handler client
+	environmentClient := environmentProto.NewEnvironmentProtobufClient(endpoint, &http.Client{Transport: transport}) // Init environment client
+	upnpClient := upnpProto.NewUpnpProtobufClient(endpoint, &http.Client{Transport: transport})                      // Init upnp client
+	databaseClient := databaseProto.NewDatabaseProtobufClient(endpoint, &http.Client{Transport: transport})          // Init database client
+	commonClient := commonProto.NewCommonProtobufClient(endpoint, &http.Client{Transport: transport})                // Init common client
+	shardClient := shardProto.NewShardProtobufClient(endpoint, &http.Client{Transport: transport})                   // Init shard client
+	protoClient := protoProto.NewProtoProtobufClient(endpoint, &http.Client{Transport: transport})                   // Init proto client
 
 	switch receiver {
 	case "node":
@@ -171,12 +173,12 @@ func handleHandler(handlerClient *handlerProto.Handler, methodname string, param
 	reflectParams = append(reflectParams, reflect.ValueOf(context.Background())) // Append request context
 
 	switch methodname {
-	case "StartHandler":
+	case "StartHandler", "StopHandler", "RestartHandler":
 		port, _ := strconv.Atoi(params[0]) // Parse port
 
 		reflectParams = append(reflectParams, reflect.ValueOf(&handlerProto.GeneralRequest{Port: uint32(port)})) // Append params
 	default:
-		return errors.New("illegal method: " + methodname + ", available methods: StartHandler()") // Return error
+		return errors.New("illegal method: " + methodname + ", available methods: StartHandler(), StopHandler(), RestartHandler()") // Return error
 	}
 
 	result := reflect.ValueOf(*handlerClient).MethodByName(methodname).Call(reflectParams) // Call method
@@ -258,6 +260,20 @@ func handleUpnp(upnpClient *upnpProto.Upnp, methodname string, params []string)
 	switch methodname {
 	case "GetGateway":
 		reflectParams = append(reflectParams, reflect.ValueOf(&upnpProto.GeneralRequest{})) // Append params
+	case "GetMappings":
+		port := 0 // Init port buffer (0 fetches all mappings)
+
+		if len(params) == 1 && params[0] != "" { // Check for port
+			var err error // Init error buffer
+
+			port, err = strconv.Atoi(params[0]) // Convert to int
+
+			if err != nil { // Check for errors
+				return err // Return found error
+			}
+		}
+
+		reflectParams = append(reflectParams, reflect.ValueOf(&upnpProto.GeneralRequest{PortNumber: uint32(port)})) // Append params
 	case "ForwardPortSilent", "ForwardPort", "RemoveForwarding":
 		if len(params) != 1 { // Check for invalid parameters
 			return errors.New("invalid parameters (requires uint32)") // Return error
@@ -271,7 +287,7 @@ func handleUpnp(upnpClient *upnpProto.Upnp, methodname string, params []string)
 
 		reflectParams = append(reflectParams, reflect.ValueOf(&upnpProto.GeneralRequest{PortNumber: uint32(port)})) // Append params
 	default:
-		return errors.New("illegal method: " + methodname + ", available methods: GetGateway(), ForwardPortSilent(), ForwardPort(), RemoveForwarding()") // Return error
+		return errors.New("illegal method: " + methodname + ", available methods: GetGateway(), ForwardPortSilent(), ForwardPort(), RemoveForwarding(), GetMappings()") // Return error
 	}
 
 	result := reflect.ValueOf(*upnpClient).MethodByName(methodname).Call(reflectParams) // Call method
@@ -303,7 +319,19 @@ func handleDatabase(databaseClient *databaseProto.Database, methodname string, p
 		networkID, _ := strconv.Atoi(params[1]) // Fetch network id
 
 		reflectParams = append(reflectParams, reflect.ValueOf(&databaseProto.GeneralRequest{NetworkName: params[0], NetworkID: uint32(networkID), AcceptableTimeout: uint32(acceptableTimeout), PrivateKey: params[len(params)-1]})) // Append params
-	case "AddNode", "UpdateRemoteDatabase", "LogDatabase":
+	case "AddNode":
+		if len(params) != 1 && len(params) != 2 { // Check for valid parameters
+			return errors.New("invalid parameters (requires string, optional string)") // Return error
+		}
+
+		address := "" // Init address buffer
+
+		if len(params) == 2 { // Check for address (e.g. /ip4/1.2.3.4/tcp/3000/tls/p2p/<NodeID>)
+			address = params[1] // Set address
+		}
+
+		reflectParams = append(reflectParams, reflect.ValueOf(&databaseProto.GeneralRequest{NetworkName: params[0], Address: address})) // Append params
+	case "UpdateRemoteDatabase", "LogDatabase":
 		if len(params) != 1 { // Check for valid parameters
 			return errors.New("invalid parameters (requires string)") // Return error
 		}
@@ -342,8 +370,38 @@ func handleDatabase(databaseClient *databaseProto.Database, methodname string, p
 		portIntVal, _ := strconv.Atoi(params[1])          // Convert to uint
 
 		reflectParams = append(reflectParams, reflect.ValueOf(&databaseProto.GeneralRequest{NetworkName: params[0], Port: uint32(portIntVal), PrivateKey: params[2], UintVal: uint32(uintVal), StringVals: params[3:5]})) // Append params
+	case "Put":
+		if len(params) != 4 { // Check for invalid parameters
+			return errors.New("invalid parameters (requires string, uint32, string, string)") // Return error
+		}
+
+		port, _ := strconv.Atoi(params[1]) // Convert port to uint
+
+		reflectParams = append(reflectParams, reflect.ValueOf(&databaseProto.GeneralRequest{NetworkName: params[0], Port: uint32(port), StringVals: params[2:3], ByteVal: []byte(params[3])})) // Append params
+	case "Get", "Provide", "FindProviders":
+		if len(params) != 3 { // Check for invalid parameters
+			return errors.New("invalid parameters (requires string, uint32, string)") // Return error
+		}
+
+		port, _ := strconv.Atoi(params[1]) // Convert port to uint
+
+		reflectParams = append(reflectParams, reflect.ValueOf(&databaseProto.GeneralRequest{NetworkName: params[0], Port: uint32(port), StringVals: params[2:3]})) // Append params
+	case "Ping":
+		if len(params) != 2 && len(params) != 3 { // Check for invalid parameters
+			return errors.New("invalid parameters (requires string, uint32, optional string)") // Return error
+		}
+
+		port, _ := strconv.Atoi(params[1]) // Convert port to uint
+
+		address := "" // Init address buffer
+
+		if len(params) == 3 { // Check for address
+			address = params[2] // Set address
+		}
+
+		reflectParams = append(reflectParams, reflect.ValueOf(&databaseProto.GeneralRequest{NetworkName: params[0], Port: uint32(port), Address: address})) // Append params
 	default:
-		return errors.New("illegal method: " + methodname + ", available methods: NewDatabase(), LogDatabase(), AddNode(), UpdateRemoteDatabase(), JoinDatabase(), FetchRemoteDatabase(), RemoveNode(), QueryForAddress(), WriteToMemory(), ReadFromMemory(), FromBytes(), SendDatabaseMessage()") // Return error
+		return errors.New("illegal method: " + methodname + ", available methods: NewDatabase(), LogDatabase(), AddNode(), UpdateRemoteDatabase(), JoinDatabase(), FetchRemoteDatabase(), RemoveNode(), QueryForAddress(), WriteToMemory(), ReadFromMemory(), FromBytes(), SendDatabaseMessage(), Put(), Get(), Provide(), FindProviders(), Ping()") // Return error
 	}
 
 	result := reflect.ValueOf(*databaseClient).MethodByName(methodname).Call(reflectParams) // Call method
@@ -68,6 +68,10 @@ func (term *Terminal) handleNode(command string) {
 		term.handleNewNodeCommand()
 	case strings.Contains(strings.ToLower(command), "attach"): // Account for readnode command
 		term.handleAttachNodeCommand()
+	case strings.Contains(strings.ToLower(command), "stophandler"): // Account for stophandler command
+		term.handleStopHandlerCommand() // Stop handler command execution
+	case strings.Contains(strings.ToLower(command), "restarthandler"): // Account for restarthandler command (checked before starthandler)
+		term.handleRestartHandlerCommand() // Restart handler command execution
 	case strings.Contains(strings.ToLower(command), "starthandler"):
 		intVal, _ := strconv.Atoi(strings.Split(strings.Split(command, "(")[1], ")")[0]) // Attempt to fetch port from command
 
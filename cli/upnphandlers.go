@@ -25,17 +25,17 @@ func (term *Terminal) handleForwardPortCommand(command string, portNumber int) {
 
 // handleForwardPort - handle execution of forwardport method
 func (term *Terminal) handleForwardPort(command string, portNumber int) (string, error) {
-	err := upnp.ForwardPort(uint(portNumber)) // Attempt to forward port
+	mapping, err := upnp.ForwardPort(uint(portNumber)) // Attempt to forward port
 
 	if err != nil { // Check for errors
 		return "", err // Return found error
 	}
 
 	if hasVariableSet(command) {
-		term.handleOutputVariable(command, "Success: port "+strconv.Itoa(portNumber)+" forwarded successfully", "string")
+		term.handleOutputVariable(command, "Success: port "+strconv.Itoa(portNumber)+" forwarded successfully ("+mapping.String()+")", "string")
 	}
 
-	return "Success: port " + strconv.Itoa(portNumber) + " forwarded successfully", nil // Return success
+	return "Success: port " + strconv.Itoa(portNumber) + " forwarded successfully (" + mapping.String() + ")", nil // Return success
 }
 
 func (term *Terminal) handleRemoveForwardPortCommand(command string, portNumber int) {
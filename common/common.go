@@ -24,7 +24,6 @@ import (
 	"time"
 
 	upnp "github.com/NebulousLabs/go-upnp"
-	fastping "github.com/tatsushid/go-fastping"
 	"golang.org/x/crypto/sha3"
 )
 
@@ -40,6 +39,9 @@ const (
 
 	// ProtobufPrefix - GoP2P standard protobuf message prefix
 	ProtobufPrefix = "ProtoID"
+
+	// shardSeedSeparator - separator between seed addresses of shard addresses (colons are taken by IPv6 addresses)
+	shardSeedSeparator = ","
 )
 
 var (
@@ -47,10 +49,11 @@ var (
 	ExtIPProviders = []string{"http://checkip.amazonaws.com/", "http://icanhazip.com/", "http://www.trackip.net/ip", "http://bot.whatismyipaddress.com/", "https://ipecho.net/plain", "http://myexternalip.com/raw"}
 
 	// GeneralTLSConfig - general global GoP2P TLS Config
-	GeneralTLSConfig = &tls.Config{ // Init TLS config
-		Certificates:       []tls.Certificate{getTLSCerts("GoP2PGeneral")},
-		InsecureSkipVerify: true,
-		ServerName:         "localhost"}
+	GeneralTLSConfig = &tls.Config{ // Init TLS config (unauthenticated, only for endpoints without a node identity; nodes are dialed through PeerTLSConfig)
+		Certificates:         []tls.Certificate{getTLSCerts("GoP2PGeneral")},
+		GetClientCertificate: getLocalClientCertificate,
+		InsecureSkipVerify:   true,
+		ServerName:           "localhost"}
 
 	// Silent - silence common.Println calls
 	Silent = false
@@ -138,24 +141,44 @@ func GetCommonByteDifference(b [][]byte) ([]byte, error) {
 	return b[lowestIndex], nil // No error occurred, return nil
 }
 
-// SeedAddress - generated shard address from seeds
+// SeedAddress - generated shard address from seeds (e.g. 1a2b3::1.2.3.4,::1)
 func SeedAddress(seeds []string, shardID string) (string, error) {
 	if len(seeds) == 0 || len(shardID) == 0 || len(shardID) < len(seeds) { // Check for invalid input
 		return "", errors.New("invalid input") // Return found error
 	}
 
-	seed := shardID[0:5] + "::" + strings.Join(seeds, ":") // Set seed
+	normalized := []string{} // Init seed buffer
+
+	for _, seed := range seeds { // Iterate through seeds
+		address, err := ParsePeerAddress(seed) // Parse seed
+
+		if err != nil { // Check for errors
+			return "", err // Return found error
+		}
+
+		normalized = append(normalized, address.String()) // Append seed
+	}
+
+	seed := shardID[0:5] + "::" + strings.Join(normalized, shardSeedSeparator) // Set seed
 
 	return seed, nil // Return seed
 }
 
 // ParseShardAddress - attempt to fetch node addresses from shard address
 func ParseShardAddress(address string) ([]string, error) {
-	if address == "" || !strings.Contains(address[5:7], "::") { // Check for nil input
+	index := strings.Index(address, "::") // Find end of shard prefix (prefixes never contain colons, so IPv6 seeds can't be mistaken for the separator)
+
+	if index < 1 || index+2 == len(address) { // Check for nil input
 		return []string{}, errors.New("invalid input") // Return found error
 	}
 
-	addresses := strings.Split(address[7:(len(address)-1)], ":") // Split
+	addresses := strings.Split(address[index+2:], shardSeedSeparator) // Split
+
+	for _, seed := range addresses { // Iterate through seeds
+		if _, err := ParsePeerAddress(seed); err != nil { // Check for invalid seed
+			return []string{}, err // Return found error
+		}
+	}
 
 	return addresses, nil // Return addresses
 }
@@ -233,40 +256,27 @@ func Forever() {
 	}
 }
 
-// CheckAddress - check that specified IP address can be pinged, and is available on specified port
+// CheckAddress - check that specified node address (IP address, host name, or transport address such as memory://node) is well-formed and resolvable. Whether a node is actually listening is checked with GoP2P pings (see database.PingNode).
 func CheckAddress(address string) error {
 	if address == "" { // Check for nil address
 		return errors.New("nil address") // Return error
 	}
 
-	p := fastping.NewPinger() // Create new pinger
-
-	ipAddress, err := net.ResolveIPAddr("ip", address) // Resolve address
+	if strings.Contains(address, transportSchemeSeparator) { // Check for transport address
+		_, _, err := ParseTransportAddress(address) // Parse address
 
-	if err != nil {
-		return err
+		return err // Return error (might be nil)
 	}
 
-	p.AddIPAddr(ipAddress) // Add address to pinger
+	parsed, err := ParsePeerAddress(address) // Parse address (stripping IPv6 brackets)
 
-	p.OnRecv = func(addr *net.IPAddr, rtt time.Duration) { // On correct address
-		Printf("IP Addr: %s receive, RTT: %v\n", addr.String(), rtt) // Print address meta
-		Printf("IP %s tested successfully \n", addr.String())        // Print address meta
-	}
-	p.OnIdle = func() { // On address timeout
-		err = errors.New("Timed out with IP " + ipAddress.String() + "\n") // Assign meta to error
-	}
-
-	if err != nil { // Check for error
+	if err != nil { // Check for errors
 		return err // Return found error
 	}
 
-	err = p.Run()   // Assign to error
-	if err != nil { // Check for errors
-		return err // Return error
-	}
+	_, err = net.ResolveIPAddr("ip", parsed.Host) // Resolve address
 
-	return nil // No error found, return nil
+	return err // Return error (might be nil)
 }
 
 // GetExtIPAddrWithUPnP - retrieve the external IP address of the current machine via upnp
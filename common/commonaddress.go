@@ -0,0 +1,159 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// PeerAddress - address of a peer: transport, IP address or hostname (or transport-specific address, e.g. socket path), port
+type PeerAddress struct {
+	Transport string `json:"transport,omitempty"` // Transport - transport scheme (empty for TCP)
+
+	Host string `json:"host"` // Host - IP address (IPv6 addresses without brackets), hostname or transport-specific address
+
+	Port int `json:"port,omitempty"` // Port - port (0 if unspecified)
+}
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// ParsePeerAddress - parse given address (e.g. 1.2.3.4, 1.2.3.4:3000, ::1, [::1]:3000, example.com:3000, unix:///tmp/node.sock:3000). IPv6 addresses with a port must be enclosed in brackets.
+func ParsePeerAddress(address string) (PeerAddress, error) {
+	if address == "" { // Check for nil address
+		return PeerAddress{}, errors.New("nil address") // Return error
+	}
+
+	parsed := PeerAddress{} // Init address buffer
+
+	remaining := address // Init remaining address
+
+	if index := strings.Index(remaining, transportSchemeSeparator); index != -1 { // Check for scheme
+		parsed.Transport, remaining = remaining[:index], remaining[index+len(transportSchemeSeparator):] // Split scheme
+
+		if parsed.Transport == TransportSchemeTCP { // Check for explicit TCP scheme
+			parsed.Transport = "" // Normalize scheme
+		}
+	}
+
+	if parsed.Transport != "" { // Check for transport-specific address
+		parsed.Host = remaining // Set host
+
+		if index := strings.LastIndex(remaining, ":"); index != -1 { // Check for port
+			if port, err := parsePort(remaining[index+1:]); err == nil { // Parse port
+				parsed.Host, parsed.Port = remaining[:index], port // Split port
+			}
+		}
+
+		if parsed.Host == "" { // Check for nil host
+			return PeerAddress{}, fmt.Errorf("missing host in address %s", address) // Return error
+		}
+
+		return parsed, nil // Return address
+	}
+
+	host, port := remaining, "" // Init host, port buffers
+
+	switch {
+	case strings.HasPrefix(remaining, "[") && strings.HasSuffix(remaining, "]"): // Bracketed IPv6 address without port
+		host = remaining[1 : len(remaining)-1] // Strip brackets
+	case strings.HasPrefix(remaining, "["), strings.Count(remaining, ":") == 1: // Host, port
+		var err error // Init error buffer
+
+		host, port, err = net.SplitHostPort(remaining) // Split port
+
+		if err != nil { // Check for errors
+			return PeerAddress{}, err // Return found error
+		}
+	case strings.Contains(remaining, ":") && net.ParseIP(remaining) == nil: // Unbracketed IPv6 address with port
+		return PeerAddress{}, fmt.Errorf("ambiguous address %s (IPv6 addresses with a port must be enclosed in brackets)", address) // Return error
+	}
+
+	if strings.HasPrefix(remaining, "[") && (net.ParseIP(host) == nil || !strings.Contains(host, ":")) { // Check for invalid bracketed address
+		return PeerAddress{}, fmt.Errorf("invalid IPv6 address in %s", address) // Return error
+	}
+
+	parsed.Host = host // Set host
+
+	if port != "" { // Check for port
+		var err error // Init error buffer
+
+		if parsed.Port, err = parsePort(port); err != nil { // Parse port
+			return PeerAddress{}, fmt.Errorf("invalid port in address %s", address) // Return error
+		}
+	}
+
+	if parsed.Host == "" && parsed.Port == 0 { // Check for nil address
+		return PeerAddress{}, fmt.Errorf("missing host in address %s", address) // Return error
+	}
+
+	return parsed, nil // Return address
+}
+
+// String - convert address to transport address (e.g. [::1]:3000), leaving out the port if unspecified
+func (address PeerAddress) String() string {
+	prefix := "" // Init prefix
+
+	if address.Transport != "" { // Check for transport
+		prefix = address.Transport + transportSchemeSeparator // Set prefix
+	}
+
+	if address.Port == 0 { // Check for no port
+		return prefix + address.Host // Return host
+	}
+
+	if address.Transport == "" { // Check for TCP address
+		return net.JoinHostPort(address.Host, strconv.Itoa(address.Port)) // Join host, port (bracketing IPv6 addresses)
+	}
+
+	return prefix + address.Host + ":" + strconv.Itoa(address.Port) // Append port
+}
+
+// WithPort - fetch copy of address with given port
+func (address PeerAddress) WithPort(port int) PeerAddress {
+	address.Port = port // Set port
+
+	return address // Return address
+}
+
+// IP - fetch IP of address (nil for hostnames and non-IP transports)
+func (address PeerAddress) IP() net.IP {
+	if address.Transport != "" { // Check for non-IP transport
+		return nil // No IP
+	}
+
+	return net.ParseIP(address.Host) // Parse IP
+}
+
+// IsIPv6 - check address is an IPv6 address
+func (address PeerAddress) IsIPv6() bool {
+	ip := address.IP() // Fetch IP
+
+	return ip != nil && ip.To4() == nil // Check IP isn't IPv4
+}
+
+/*
+	END EXPORTED METHODS
+*/
+
+/*
+	BEGIN INTERNAL METHODS:
+*/
+
+// parsePort - parse given port number
+func parsePort(port string) (int, error) {
+	parsed, err := strconv.Atoi(port) // Parse port
+
+	if err != nil || parsed < 0 || parsed > 65535 { // Check for invalid port
+		return 0, fmt.Errorf("invalid port %s", port) // Return error
+	}
+
+	return parsed, nil // Return port
+}
+
+/*
+	END INTERNAL METHODS
+*/
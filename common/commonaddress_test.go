@@ -0,0 +1,59 @@
+package common
+
+import "testing"
+
+// TestParsePeerAddress - test functionality of ParsePeerAddress() method
+func TestParsePeerAddress(t *testing.T) {
+	for address, expected := range map[string]PeerAddress{
+		"1.1.1.1":                    {Host: "1.1.1.1"},
+		"1.1.1.1:3000":               {Host: "1.1.1.1", Port: 3000},
+		"::1":                        {Host: "::1"},
+		"[::1]":                      {Host: "::1"},
+		"[2001:db8::1]:3000":         {Host: "2001:db8::1", Port: 3000},
+		"tcp://[::1]:3000":           {Host: "::1", Port: 3000},
+		"example.com:3000":           {Host: "example.com", Port: 3000},
+		":3000":                      {Port: 3000},
+		"unix:///tmp/node.sock:3000": {Transport: TransportSchemeUnix, Host: "/tmp/node.sock", Port: 3000},
+		"memory://node":              {Transport: TransportSchemeMemory, Host: "node"},
+	} { // Iterate through valid addresses
+		parsed, err := ParsePeerAddress(address) // Parse address
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if parsed != expected { // Check for mismatch
+			t.Errorf("expected %+v for address %s, got %+v", expected, address, parsed) // Log found error
+			t.FailNow()                                                                 // Panic
+		}
+	}
+
+	for _, address := range []string{"", "2001:db8::1:3000:", "[1.1.1.1]:3000", "[::1", "1.1.1.1:port", "1.1.1.1:70000", "memory://"} { // Iterate through invalid addresses
+		if _, err := ParsePeerAddress(address); err == nil { // Parse address
+			t.Errorf("expected address %s to be rejected", address) // Log found error
+			t.FailNow()                                             // Panic
+		}
+	}
+}
+
+// TestPeerAddressString - test addresses are converted back to dialable transport addresses
+func TestPeerAddressString(t *testing.T) {
+	for expected, address := range map[string]PeerAddress{
+		"1.1.1.1":                    {Host: "1.1.1.1"},
+		"::1":                        {Host: "::1"},
+		"[::1]:3000":                 {Host: "::1", Port: 3000},
+		"1.1.1.1:3000":               {Host: "1.1.1.1", Port: 3000},
+		"unix:///tmp/node.sock:3000": {Transport: TransportSchemeUnix, Host: "/tmp/node.sock", Port: 3000},
+	} { // Iterate through addresses
+		if address.String() != expected { // Check for mismatch
+			t.Errorf("expected %s, got %s", expected, address.String()) // Log found error
+			t.FailNow()                                                 // Panic
+		}
+	}
+
+	if address := (PeerAddress{Host: "2001:db8::1"}); !address.IsIPv6() || address.WithPort(3000).String() != "[2001:db8::1]:3000" { // Check IPv6 address
+		t.Errorf("invalid IPv6 address %s", address.WithPort(3000).String()) // Log found error
+		t.FailNow()                                                          // Panic
+	}
+}
@@ -0,0 +1,72 @@
+package common
+
+import (
+	"errors"
+	"sync"
+)
+
+// Codec - encoding of peer wire types (connections, events, responses, network messages, envelopes), selected by the dialing peer during handshake
+type Codec string
+
+const (
+	// CodecProtobuf - binary protobuf encoding (see internal/rpc/proto/wire), used by default
+	CodecProtobuf = Codec("protobuf")
+
+	// CodecJSON - JSON encoding, opt-in for debugging (also used by peers that predate codec negotiation)
+	CodecJSON = Codec("json")
+)
+
+var (
+	// SupportedCodecs - codecs accepted from dialing peers
+	SupportedCodecs = []Codec{CodecProtobuf, CodecJSON}
+
+	// ErrUnsupportedCodec - error returned when encoding or decoding with an unknown codec
+	ErrUnsupportedCodec = errors.New("unsupported codec")
+
+	localCodec = CodecProtobuf // localCodec - codec requested by the local node when dialing
+
+	localCodecMutex sync.Mutex // localCodecMutex - guards localCodec
+)
+
+/*
+	BEGIN EXPORTED METHODS
+*/
+
+// IsSupportedCodec - check given codec is supported
+func IsSupportedCodec(codec Codec) bool {
+	for _, supportedCodec := range SupportedCodecs { // Iterate through supported codecs
+		if codec == supportedCodec { // Check for match
+			return true // Supported
+		}
+	}
+
+	return false // Unsupported
+}
+
+// SetLocalCodec - set codec requested by the local node when dialing peers (sessions dialed beforehand keep their codec)
+func SetLocalCodec(codec Codec) error {
+	if !IsSupportedCodec(codec) { // Check for unsupported codec
+		return ErrUnsupportedCodec // Return error
+	}
+
+	localCodecMutex.Lock() // Lock codec
+
+	defer localCodecMutex.Unlock() // Unlock codec
+
+	localCodec = codec // Set codec
+
+	return nil // No error occurred, return nil
+}
+
+// LocalCodec - fetch codec requested by the local node when dialing peers
+func LocalCodec() Codec {
+	localCodecMutex.Lock() // Lock codec
+
+	defer localCodecMutex.Unlock() // Unlock codec
+
+	return localCodec // Return codec
+}
+
+/*
+	END EXPORTED METHODS
+*/
@@ -0,0 +1,151 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dowlandaiello/GoP2P/internal/rpc/proto/wire"
+	"github.com/golang/protobuf/proto"
+)
+
+// EnvelopeKind - kind of payload carried by an envelope
+type EnvelopeKind string
+
+const (
+	// EnvelopeKindConnection - payload contains a serialized connection.Connection
+	EnvelopeKindConnection = EnvelopeKind("connection")
+
+	// EnvelopeKindEvent - payload contains a serialized connection.Event
+	EnvelopeKindEvent = EnvelopeKind("event")
+
+	// EnvelopeKindNetworkMessage - payload contains a serialized database.Message
+	EnvelopeKindNetworkMessage = EnvelopeKind("network message")
+
+	// EnvelopeKindProtobuf - payload contains a JSON-serialized proto.ProtobufMessage
+	EnvelopeKindProtobuf = EnvelopeKind("protobuf")
+
+	// EnvelopeKindStreamChunk - payload contains a serialized connection.Chunk of a chunked transfer
+	EnvelopeKindStreamChunk = EnvelopeKind("stream chunk")
+
+	// EnvelopeKindFindNode - payload contains a serialized database.FindNodeRequest (node or value lookup)
+	EnvelopeKindFindNode = EnvelopeKind("find node")
+
+	// EnvelopeKindStoreRecord - payload contains a serialized database.StoreRequest
+	EnvelopeKindStoreRecord = EnvelopeKind("store record")
+
+	// EnvelopeKindPeerExchange - payload contains a serialized database.PeerExchangeRequest
+	EnvelopeKindPeerExchange = EnvelopeKind("peer exchange")
+
+	// EnvelopeKindPing - payload contains a serialized database.Ping (answered with a database.Pong)
+	EnvelopeKindPing = EnvelopeKind("ping")
+
+	// EnvelopeKindMembership - payload contains a serialized database.MembershipMessage (answered with a database.MembershipAck)
+	EnvelopeKindMembership = EnvelopeKind("membership")
+
+	// EnvelopeKindReplication - payload contains a serialized database.ReplicationRequest (answered with a database.ReplicationResponse)
+	EnvelopeKindReplication = EnvelopeKind("replication")
+
+	// EnvelopeKindRelayRequest - payload contains a serialized nat.Request sent to a rendezvous, relay service (answered with a nat.Response)
+	EnvelopeKindRelayRequest = EnvelopeKind("relay request")
+
+	// EnvelopeKindRelayNotification - payload contains a serialized nat.Notification sent to a reservation holder
+	EnvelopeKindRelayNotification = EnvelopeKind("relay notification")
+)
+
+var (
+	// ErrNilEnvelope - error returned when decoding data that doesn't contain an envelope
+	ErrNilEnvelope = errors.New("data does not contain a message envelope")
+)
+
+// Envelope - container wrapping every payload sent to a node handler, tagging it with the kind of payload it carries
+type Envelope struct {
+	Kind EnvelopeKind `json:"kind"` // Kind - kind of payload
+
+	Payload []byte `json:"payload"` // Payload - serialized payload
+}
+
+/*
+	BEGIN EXPORTED METHODS
+*/
+
+// NewEnvelope - initialize envelope carrying given payload of given kind
+func NewEnvelope(kind EnvelopeKind, payload []byte) (*Envelope, error) {
+	if kind == "" { // Check for nil kind
+		return &Envelope{}, errors.New("invalid envelope kind") // Return error
+	}
+
+	return &Envelope{Kind: kind, Payload: payload}, nil // Return initialized envelope
+}
+
+// Seal - wrap given (encoded) payload in an envelope of given kind, returning envelope encoded with given codec
+func Seal(codec Codec, kind EnvelopeKind, payload []byte) ([]byte, error) {
+	envelope, err := NewEnvelope(kind, payload) // Init envelope
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return envelope.Encode(codec) // Encode envelope
+}
+
+// SealObject - JSON-serialize given object (for payloads without a wire type), wrapping it in an envelope of given kind encoded with given codec
+func SealObject(codec Codec, kind EnvelopeKind, object interface{}) ([]byte, error) {
+	payload, err := SerializeToBytes(object) // Serialize object
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return Seal(codec, kind, payload) // Seal payload
+}
+
+// Encode - encode envelope with given codec
+func (envelope *Envelope) Encode(codec Codec) ([]byte, error) {
+	switch codec {
+	case CodecProtobuf:
+		return proto.Marshal(&wire.Envelope{Kind: string(envelope.Kind), Payload: envelope.Payload}) // Marshal envelope
+	case CodecJSON:
+		return json.Marshal(*envelope) // Marshal envelope
+	default:
+		return nil, ErrUnsupportedCodec // Return error
+	}
+}
+
+// DecodeEnvelope - decode envelope encoded with given codec from given bytes
+func DecodeEnvelope(codec Codec, b []byte) (*Envelope, error) {
+	if len(b) == 0 { // Check for nil input
+		return nil, ErrNilEnvelope // Return error
+	}
+
+	envelope := &Envelope{} // Init envelope buffer
+
+	var err error // Init error buffer
+
+	switch codec {
+	case CodecProtobuf:
+		wireEnvelope := &wire.Envelope{} // Init wire envelope buffer
+
+		if err = proto.Unmarshal(b, wireEnvelope); err == nil { // Decode envelope
+			envelope.Kind, envelope.Payload = EnvelopeKind(wireEnvelope.Kind), wireEnvelope.Payload // Set envelope
+		}
+	case CodecJSON:
+		err = json.Unmarshal(b, envelope) // Decode envelope
+	default:
+		return nil, ErrUnsupportedCodec // Return error
+	}
+
+	if err != nil { // Check for errors
+		return nil, fmt.Errorf("%s: %s", ErrNilEnvelope.Error(), err.Error()) // Return found error
+	}
+
+	if envelope.Kind == "" { // Check for missing kind
+		return nil, ErrNilEnvelope // Return error
+	}
+
+	return envelope, nil // Return decoded envelope
+}
+
+/*
+	END EXPORTED METHODS
+*/
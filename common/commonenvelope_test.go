@@ -0,0 +1,57 @@
+package common
+
+import "testing"
+
+// TestSeal - test functionality of Seal() method
+func TestSeal(t *testing.T) {
+	for _, codec := range SupportedCodecs { // Iterate through codecs
+		b, err := Seal(codec, EnvelopeKindNetworkMessage, []byte(`{"messagetype":"notice"}`)) // Seal payload
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		envelope, err := DecodeEnvelope(codec, b) // Decode envelope
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if envelope.Kind != EnvelopeKindNetworkMessage || string(envelope.Payload) != `{"messagetype":"notice"}` { // Check for mismatch
+			t.Errorf("invalid envelope %v", envelope) // Log found error
+			t.FailNow()                               // Panic
+		}
+
+		t.Logf("decoded %s envelope %v", codec, envelope) // Log success
+	}
+}
+
+// TestDecodeEnvelope - test functionality of DecodeEnvelope() method
+func TestDecodeEnvelope(t *testing.T) {
+	for _, codec := range SupportedCodecs { // Iterate through codecs
+		for _, data := range []string{"", "messagetype", `{"data":"ProtoID"}`} { // Iterate through unenveloped payloads
+			_, err := DecodeEnvelope(codec, []byte(data)) // Decode envelope
+
+			if err == nil { // Check data was rejected
+				t.Errorf("expected unenveloped data %s to be rejected by %s codec", data, codec) // Log found error
+				t.FailNow()                                                                      // Panic
+			}
+		}
+	}
+
+	_, err := NewEnvelope("", []byte("test")) // Init envelope without kind
+
+	if err == nil { // Check envelope was rejected
+		t.Errorf("expected envelope without kind to be rejected") // Log found error
+		t.FailNow()                                               // Panic
+	}
+
+	_, err = Seal(Codec("xml"), EnvelopeKindEvent, []byte("test")) // Seal with unsupported codec
+
+	if err != ErrUnsupportedCodec { // Check codec was rejected
+		t.Errorf("expected unsupported codec error, got %v", err) // Log found error
+		t.FailNow()                                               // Panic
+	}
+}
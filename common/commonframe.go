@@ -0,0 +1,276 @@
+package common
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+)
+
+const (
+	// FrameVersion - current GoP2P wire protocol version (first byte of every frame)
+	FrameVersion = byte(2)
+
+	// FrameHeaderSize - size of frame header (version, message type, uint32 stream ID, uint32 payload length)
+	FrameHeaderSize = 10
+
+	// MaxFrameSize - maximum payload size accepted in a single frame
+	MaxFrameSize = 64 * 1024 * 1024
+)
+
+const (
+	// FrameTypeMessage - one-way message, no response expected
+	FrameTypeMessage = byte(iota + 1)
+
+	// FrameTypeRequest - request message, peer responds with FrameTypeResponse or FrameTypeError
+	FrameTypeRequest
+
+	// FrameTypeResponse - response to FrameTypeRequest
+	FrameTypeResponse
+
+	// FrameTypeError - error response, payload contains error message
+	FrameTypeError
+
+	// FrameTypeHello - handshake message, payload contains encoded Hello
+	FrameTypeHello
+
+	// FrameTypeBusy - rejection of a request (or connection, on stream 0) by a peer at capacity, payload contains encoded BusyError
+	FrameTypeBusy
+
+	// FrameTypeTooLarge - rejection of a request exceeding the peer's maximum message size, payload contains encoded TooLargeError
+	FrameTypeTooLarge
+)
+
+var (
+	// ErrUnframedPeer - error returned when a peer writes data without a GoP2P frame header (e.g. legacy, pre-framing peers)
+	ErrUnframedPeer = errors.New("unframed peer: remote sent data without a GoP2P frame header (legacy peers are not supported)")
+
+	// ErrFrameTooLarge - error returned when a frame's declared payload length exceeds MaxFrameSize
+	ErrFrameTooLarge = fmt.Errorf("frame exceeds maximum size of %d bytes", MaxFrameSize)
+)
+
+// Frame - single length-prefixed GoP2P wire protocol message
+type Frame struct {
+	Version  byte   // Version - wire protocol version
+	Type     byte   // Type - frame message type
+	StreamID uint32 // StreamID - multiplexed stream frame belongs to (responses echo request stream ID)
+	Payload  []byte // Payload - frame contents
+}
+
+// RemoteError - error written by a peer in a FrameTypeError frame
+type RemoteError struct {
+	Message string // Message - error message sent by peer
+}
+
+// BusyError - error written by a peer in a FrameTypeBusy frame, requesting senders back off
+type BusyError struct {
+	Reason string `json:"reason"` // Reason - reason request was rejected (e.g. "rate limited")
+
+	RetryAfter time.Duration `json:"retryAfter"` // RetryAfter - duration sender should wait before retrying
+}
+
+// TooLargeError - error returned when a frame exceeds a maximum message size (written by a peer in a FrameTypeTooLarge frame)
+type TooLargeError struct {
+	StreamID uint32 `json:"-"` // StreamID - stream of rejected frame
+
+	Size int `json:"size"` // Size - declared size of rejected frame
+
+	MaxSize int `json:"maxSize"` // MaxSize - maximum message size
+}
+
+/*
+	BEGIN EXPORTED METHODS
+*/
+
+// Error - implement error interface
+func (err *RemoteError) Error() string {
+	return "remote error: " + err.Message // Return error message
+}
+
+// Error - implement error interface
+func (err *BusyError) Error() string {
+	return fmt.Sprintf("peer busy (%s), retry after %s", err.Reason, err.RetryAfter) // Return error message
+}
+
+// Error - implement error interface
+func (err *TooLargeError) Error() string {
+	return fmt.Sprintf("message of %d bytes exceeds maximum message size of %d bytes", err.Size, err.MaxSize) // Return error message
+}
+
+// WriteBusyFrame - attempt to write busy rejection of given stream to writer
+func WriteBusyFrame(w io.Writer, streamID uint32, busy *BusyError) error {
+	payload, err := json.Marshal(busy) // Encode rejection
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	return WriteStreamFrame(w, FrameTypeBusy, streamID, payload) // Write frame
+}
+
+// WriteTooLargeFrame - attempt to write too large rejection of given frame to writer
+func WriteTooLargeFrame(w io.Writer, tooLarge *TooLargeError) error {
+	payload, err := json.Marshal(tooLarge) // Encode rejection
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	return WriteStreamFrame(w, FrameTypeTooLarge, tooLarge.StreamID, payload) // Write frame
+}
+
+// WriteFrame - attempt to write given payload to writer as a single frame on stream 0
+func WriteFrame(w io.Writer, frameType byte, payload []byte) error {
+	return WriteStreamFrame(w, frameType, 0, payload) // Write frame
+}
+
+// WriteStreamFrame - attempt to write given payload to writer as a single frame on given stream
+func WriteStreamFrame(w io.Writer, frameType byte, streamID uint32, payload []byte) error {
+	if !isValidFrameType(frameType) { // Check for invalid frame type
+		return fmt.Errorf("invalid frame type %d", frameType) // Return found error
+	} else if len(payload) > MaxFrameSize { // Check for oversized payload
+		return ErrFrameTooLarge // Return found error
+	}
+
+	buffer := make([]byte, FrameHeaderSize+len(payload)) // Init buffer
+
+	buffer[0] = FrameVersion                                                    // Set version
+	buffer[1] = frameType                                                       // Set type
+	binary.BigEndian.PutUint32(buffer[2:6], streamID)                           // Set stream ID
+	binary.BigEndian.PutUint32(buffer[6:FrameHeaderSize], uint32(len(payload))) // Set length
+	copy(buffer[FrameHeaderSize:], payload)                                     // Set payload
+
+	_, err := w.Write(buffer) // Write frame in a single call
+
+	return err // Return error (might be nil)
+}
+
+// ReadFrame - attempt to read a single frame from reader
+func ReadFrame(r io.Reader) (*Frame, error) {
+	return ReadFrameLimit(r, MaxFrameSize) // Read frame
+}
+
+// ReadFrameLimit - attempt to read a single frame from reader, rejecting frames with a payload larger than given size before reading them. Payloads of rejected frames no larger than MaxFrameSize are discarded (returning a *TooLargeError), keeping the reader at a frame boundary.
+func ReadFrameLimit(r io.Reader, maxSize int) (*Frame, error) {
+	header := make([]byte, FrameHeaderSize) // Init header buffer
+
+	_, err := io.ReadFull(r, header[:1]) // Read version byte
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if header[0] != FrameVersion { // Check for unknown version
+		if header[0] >= 0x20 { // Check for printable (JSON, plain text) data
+			return nil, ErrUnframedPeer // Return unframed error
+		}
+
+		return nil, fmt.Errorf("unsupported frame version %d", header[0]) // Return found error
+	}
+
+	_, err = io.ReadFull(r, header[1:]) // Read remainder of header
+
+	if err != nil { // Check for errors
+		return nil, unexpectedEOF(err) // Return found error
+	}
+
+	if !isValidFrameType(header[1]) { // Check for invalid type
+		return nil, fmt.Errorf("invalid frame type %d", header[1]) // Return found error
+	}
+
+	length := binary.BigEndian.Uint32(header[6:]) // Fetch payload length
+
+	if length > MaxFrameSize { // Check for oversized frame
+		return nil, ErrFrameTooLarge // Return found error
+	}
+
+	if maxSize >= 0 && int(length) > maxSize { // Check exceeds given size
+		_, err = io.CopyN(ioutil.Discard, r, int64(length)) // Discard payload
+
+		if err != nil { // Check for errors
+			return nil, unexpectedEOF(err) // Return found error
+		}
+
+		return nil, &TooLargeError{StreamID: binary.BigEndian.Uint32(header[2:6]), Size: int(length), MaxSize: maxSize} // Return too large error
+	}
+
+	payload := make([]byte, length) // Init payload buffer
+
+	_, err = io.ReadFull(r, payload) // Read payload
+
+	if err != nil { // Check for errors
+		return nil, unexpectedEOF(err) // Return found error
+	}
+
+	return &Frame{Version: header[0], Type: header[1], StreamID: binary.BigEndian.Uint32(header[2:6]), Payload: payload}, nil // Return read frame
+}
+
+// ReadResponseFrame - attempt to read a response frame from reader, converting error frames to errors
+func ReadResponseFrame(r io.Reader) ([]byte, error) {
+	frame, err := ReadFrame(r) // Read frame
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if frame.Type == FrameTypeResponse { // Check for response
+		return frame.Payload, nil // Return payload
+	}
+
+	return nil, FrameError(frame) // Return error
+}
+
+// FrameError - convert rejection frame (error, busy, too large) to matching error type
+func FrameError(frame *Frame) error {
+	switch frame.Type {
+	case FrameTypeError: // Check for error
+		return &RemoteError{Message: string(frame.Payload)} // Return remote error
+	case FrameTypeBusy: // Check for busy
+		busy := &BusyError{} // Init busy buffer
+
+		if err := json.Unmarshal(frame.Payload, busy); err != nil { // Decode rejection
+			return fmt.Errorf("invalid busy frame: %s", err.Error()) // Return found error
+		}
+
+		return busy // Return busy error
+	case FrameTypeTooLarge: // Check for too large
+		tooLarge := &TooLargeError{StreamID: frame.StreamID} // Init too large buffer
+
+		if err := json.Unmarshal(frame.Payload, tooLarge); err != nil { // Decode rejection
+			return fmt.Errorf("invalid too large frame: %s", err.Error()) // Return found error
+		}
+
+		return tooLarge // Return too large error
+	default:
+		return fmt.Errorf("expected response frame, got frame type %d", frame.Type) // Return found error
+	}
+}
+
+/*
+	END EXPORTED METHODS
+*/
+
+/*
+	BEGIN INTERNAL METHODS
+*/
+
+// isValidFrameType - check frame type is known
+func isValidFrameType(frameType byte) bool {
+	return frameType >= FrameTypeMessage && frameType <= FrameTypeTooLarge // Check in range
+}
+
+// unexpectedEOF - convert io.EOF read mid-frame to io.ErrUnexpectedEOF
+func unexpectedEOF(err error) error {
+	if err == io.EOF { // Check for EOF
+		return io.ErrUnexpectedEOF // Frame was truncated
+	}
+
+	return err // Return error
+}
+
+/*
+	END INTERNAL METHODS
+*/
@@ -0,0 +1,127 @@
+package common
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+	"time"
+)
+
+// TestWriteFrame - test functionality of WriteFrame() method
+func TestWriteFrame(t *testing.T) {
+	buffer := new(bytes.Buffer) // Init buffer
+
+	err := WriteStreamFrame(buffer, FrameTypeRequest, 7, []byte("test")) // Write frame
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	frame, err := ReadFrame(buffer) // Read frame
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if frame.Version != FrameVersion || frame.Type != FrameTypeRequest || frame.StreamID != 7 || string(frame.Payload) != "test" { // Check for mismatch
+		t.Errorf("invalid frame %v", frame) // Log found error
+		t.FailNow()                         // Panic
+	}
+
+	t.Logf("read frame %v", frame) // Log success
+}
+
+// TestReadFrame - test functionality of ReadFrame() method
+func TestReadFrame(t *testing.T) {
+	_, err := ReadFrame(bytes.NewReader([]byte(`{"InitializationNode":{}}`))) // Read unframed data
+
+	if err != ErrUnframedPeer { // Check for unexpected error
+		t.Errorf("expected unframed peer error, got %v", err) // Log found error
+		t.FailNow()                                           // Panic
+	}
+
+	header := make([]byte, FrameHeaderSize) // Init header
+
+	header[0], header[1] = FrameVersion, FrameTypeMessage // Set version, type
+
+	binary.BigEndian.PutUint32(header[6:], MaxFrameSize+1) // Set oversized length
+
+	_, err = ReadFrame(bytes.NewReader(header)) // Read oversized frame
+
+	if err != ErrFrameTooLarge { // Check for unexpected error
+		t.Errorf("expected frame too large error, got %v", err) // Log found error
+		t.FailNow()                                             // Panic
+	}
+
+	binary.BigEndian.PutUint32(header[6:], 8) // Set length larger than payload
+
+	_, err = ReadFrame(bytes.NewReader(append(header, []byte("test")...))) // Read truncated frame
+
+	if err != io.ErrUnexpectedEOF { // Check for unexpected error
+		t.Errorf("expected unexpected EOF, got %v", err) // Log found error
+		t.FailNow()                                      // Panic
+	}
+}
+
+// TestReadResponseFrame - test functionality of ReadResponseFrame() method
+func TestReadResponseFrame(t *testing.T) {
+	buffer := new(bytes.Buffer) // Init buffer
+
+	WriteFrame(buffer, FrameTypeError, []byte("test")) // Write error frame
+
+	_, err := ReadResponseFrame(buffer) // Read response
+
+	if _, isRemote := err.(*RemoteError); !isRemote { // Check for unexpected error
+		t.Errorf("expected remote error, got %v", err) // Log found error
+		t.FailNow()                                    // Panic
+	}
+
+	t.Logf("read remote error %s", err.Error()) // Log success
+}
+
+// TestReadFrameLimit - test functionality of ReadFrameLimit() method
+func TestReadFrameLimit(t *testing.T) {
+	buffer := new(bytes.Buffer) // Init buffer
+
+	WriteStreamFrame(buffer, FrameTypeRequest, 3, []byte("too large")) // Write oversized frame
+	WriteStreamFrame(buffer, FrameTypeRequest, 4, []byte("test"))      // Write frame
+
+	_, err := ReadFrameLimit(buffer, 4) // Read oversized frame
+
+	if tooLarge, isTooLarge := err.(*TooLargeError); !isTooLarge || tooLarge.StreamID != 3 || tooLarge.Size != 9 { // Check for unexpected error
+		t.Errorf("expected too large error, got %v", err) // Log found error
+		t.FailNow()                                       // Panic
+	}
+
+	frame, err := ReadFrameLimit(buffer, 4) // Read next frame
+
+	if err != nil || frame.StreamID != 4 || string(frame.Payload) != "test" { // Check oversized frame was discarded
+		t.Errorf("invalid frame %v (%v)", frame, err) // Log found error
+		t.FailNow()                                   // Panic
+	}
+}
+
+// TestFrameError - test functionality of FrameError() method
+func TestFrameError(t *testing.T) {
+	buffer := new(bytes.Buffer) // Init buffer
+
+	WriteBusyFrame(buffer, 1, &BusyError{Reason: "test", RetryAfter: time.Second}) // Write busy frame
+	WriteTooLargeFrame(buffer, &TooLargeError{StreamID: 2, Size: 9, MaxSize: 4})   // Write too large frame
+
+	_, err := ReadResponseFrame(buffer) // Read busy response
+
+	if busy, isBusy := err.(*BusyError); !isBusy || busy.RetryAfter != time.Second || busy.Reason != "test" { // Check for unexpected error
+		t.Errorf("expected busy error, got %v", err) // Log found error
+		t.FailNow()                                  // Panic
+	}
+
+	_, err = ReadResponseFrame(buffer) // Read too large response
+
+	if tooLarge, isTooLarge := err.(*TooLargeError); !isTooLarge || tooLarge.MaxSize != 4 || tooLarge.StreamID != 2 { // Check for unexpected error
+		t.Errorf("expected too large error, got %v", err) // Log found error
+		t.FailNow()                                       // Panic
+	}
+}
@@ -0,0 +1,309 @@
+package common
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+)
+
+const (
+	// ProtocolVersion - current GoP2P protocol version, exchanged during handshake
+	ProtocolVersion = uint32(1)
+
+	// CapabilityMultiplex - peer multiplexes concurrent request streams over a single connection
+	CapabilityMultiplex = "multiplex"
+
+	// CapabilityProtobuf - peer accepts protobuf messages (see handler.StartProtobufHandler)
+	CapabilityProtobuf = "protobuf"
+)
+
+var (
+	// DefaultCapabilities - capabilities advertised by the local node
+	DefaultCapabilities = []string{CapabilityMultiplex, CapabilityProtobuf}
+
+	localNetworkID uint // localNetworkID - network ID advertised by the local node (0 if unset)
+
+	localNetworkAlias string // localNetworkAlias - network alias advertised by the local node ("" if unset)
+
+	localNetworkMutex sync.Mutex // localNetworkMutex - guards localNetworkID, localNetworkAlias
+)
+
+// Hello - handshake message exchanged by peers before any other frame
+type Hello struct {
+	ProtocolVersion uint32   `json:"version"`      // ProtocolVersion - peer protocol version
+	NetworkID       uint     `json:"networkID"`    // NetworkID - peer network ID (0 if unspecified)
+	NetworkAlias    string   `json:"network"`      // NetworkAlias - peer network alias ("" if unspecified)
+	NodeID          string   `json:"id"`           // NodeID - peer NodeID (must match peer certificate)
+	Capabilities    []string `json:"capabilities"` // Capabilities - capabilities supported by peer
+	Codec           Codec    `json:"codec"`        // Codec - codec requested by dialing peer, echoed by accepting peer ("" for peers predating codec negotiation)
+}
+
+// Handshake - result of a completed handshake
+type Handshake struct {
+	Local  *Hello // Local - hello sent to peer
+	Remote *Hello // Remote - hello received from peer
+
+	Capabilities []string // Capabilities - capabilities supported by both peers
+
+	Codec Codec // Codec - codec used to encode wire types on connection
+}
+
+// HandshakeError - error returned when a handshake is refused
+type HandshakeError struct {
+	Reason string // Reason - reason handshake was refused
+}
+
+/*
+	BEGIN EXPORTED METHODS
+*/
+
+// Error - implement error interface
+func (err *HandshakeError) Error() string {
+	return "handshake refused: " + err.Reason // Return error message
+}
+
+// HasCapability - check both peers support given capability
+func (handshake *Handshake) HasCapability(capability string) bool {
+	return StringInSlice(handshake.Capabilities, capability) // Check negotiated capabilities
+}
+
+// SetLocalNetwork - set network advertised by the local node during handshakes
+func SetLocalNetwork(networkID uint, networkAlias string) {
+	localNetworkMutex.Lock() // Lock network
+
+	defer localNetworkMutex.Unlock() // Unlock network
+
+	localNetworkID, localNetworkAlias = networkID, networkAlias // Set network
+}
+
+// LocalHello - fetch hello advertising local node (NodeID of local certificate, local network, default capabilities and local codec)
+func LocalHello() (*Hello, error) {
+	return NodeHello(nil) // Return hello
+}
+
+// NodeHello - fetch hello advertising node given certificate is bound to (local certificate if nil), along with local network, default capabilities and local codec
+func NodeHello(cert *tls.Certificate) (*Hello, error) {
+	if cert == nil { // Check for no certificate
+		localCert, err := LocalCertificate() // Fetch local certificate
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		cert = localCert // Set certificate
+	}
+
+	nodeID, err := TLSCertificateNodeID(cert) // Fetch NodeID
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	localNetworkMutex.Lock() // Lock network
+
+	defer localNetworkMutex.Unlock() // Unlock network
+
+	return &Hello{
+		ProtocolVersion: ProtocolVersion,                            // Set version
+		NetworkID:       localNetworkID,                             // Set network ID
+		NetworkAlias:    localNetworkAlias,                          // Set network alias
+		NodeID:          nodeID,                                     // Set NodeID
+		Capabilities:    append([]string{}, DefaultCapabilities...), // Set capabilities
+		Codec:           LocalCodec(),                               // Set codec
+	}, nil // Return hello
+}
+
+// ClientHandshake - send given hello over connection, verify and return peer's hello
+func ClientHandshake(conn net.Conn, hello *Hello) (*Handshake, error) {
+	err := writeHello(conn, hello) // Write hello
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	frame, err := ReadFrameWait(conn) // Read response
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if frame.Type == FrameTypeError { // Check for refusal
+		return nil, &HandshakeError{Reason: string(frame.Payload)} // Return refusal
+	} else if frame.Type == FrameTypeBusy { // Check peer at capacity
+		return nil, FrameError(frame) // Return busy error
+	} else if frame.Type != FrameTypeHello { // Check for invalid response
+		return nil, &HandshakeError{Reason: fmt.Sprintf("expected hello, got frame type %d", frame.Type)} // Return error
+	}
+
+	remote := &Hello{} // Init hello buffer
+
+	err = json.Unmarshal(frame.Payload, remote) // Decode hello
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	err = CheckHello(conn, hello, remote) // Verify hello
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	handshake := newHandshake(hello, remote) // Init handshake
+
+	handshake.Codec, err = selectCodec(hello) // Fetch requested codec
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if remote.Codec == "" { // Check peer predates codec negotiation
+		handshake.Codec = CodecJSON // Peer speaks JSON
+	} else if remote.Codec != handshake.Codec { // Check peer selected other codec
+		return nil, &HandshakeError{Reason: fmt.Sprintf("codec mismatch: requested %s, peer selected %s", handshake.Codec, remote.Codec)} // Return error
+	}
+
+	return handshake, nil // Return handshake
+}
+
+// ServerHandshake - read peer's hello from connection, respond with hello returned by accept (or refuse connection on error)
+func ServerHandshake(conn net.Conn, accept func(remote *Hello) (*Hello, error)) (*Handshake, error) {
+	frame, err := ReadFrameWait(conn) // Read hello
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	remote := &Hello{} // Init hello buffer
+
+	if frame.Type != FrameTypeHello { // Check for missing hello
+		err = &HandshakeError{Reason: fmt.Sprintf("expected hello, got frame type %d", frame.Type)} // Set error
+	} else if err = json.Unmarshal(frame.Payload, remote); err != nil { // Decode hello
+		err = &HandshakeError{Reason: "invalid hello: " + err.Error()} // Set error
+	}
+
+	local := &Hello{} // Init local hello buffer
+
+	if err == nil { // Check for errors
+		local, err = accept(remote) // Accept peer
+	}
+
+	if err == nil { // Check for errors
+		err = CheckHello(conn, local, remote) // Verify hello
+	}
+
+	if err == nil { // Check for errors
+		local.Codec, err = selectCodec(remote) // Select codec requested by peer
+	}
+
+	if err != nil { // Check for errors
+		reason := err.Error() // Init reason
+
+		if handshakeErr, isHandshakeErr := err.(*HandshakeError); isHandshakeErr { // Check for refusal
+			reason = handshakeErr.Reason // Use refusal reason
+		}
+
+		WriteFrame(conn, FrameTypeError, []byte(reason)) // Refuse connection
+
+		return nil, err // Return found error
+	}
+
+	err = writeHello(conn, local) // Write hello
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return newHandshake(local, remote), nil // Return handshake
+}
+
+// CheckHello - verify remote hello is compatible with local hello, and matches peer certificate on connection (if any)
+func CheckHello(conn net.Conn, local *Hello, remote *Hello) error {
+	if remote.ProtocolVersion != local.ProtocolVersion { // Check for version mismatch
+		return &HandshakeError{Reason: fmt.Sprintf("protocol version mismatch: local %d, peer %d", local.ProtocolVersion, remote.ProtocolVersion)} // Return error
+	}
+
+	if local.NetworkID != 0 && remote.NetworkID != 0 && local.NetworkID != remote.NetworkID { // Check for network ID mismatch
+		return &HandshakeError{Reason: fmt.Sprintf("network ID mismatch: local %d, peer %d", local.NetworkID, remote.NetworkID)} // Return error
+	}
+
+	if local.NetworkAlias != "" && remote.NetworkAlias != "" && local.NetworkAlias != remote.NetworkAlias { // Check for network alias mismatch
+		return &HandshakeError{Reason: fmt.Sprintf("network mismatch: local %s, peer %s", local.NetworkAlias, remote.NetworkAlias)} // Return error
+	}
+
+	if tlsConn, isTLS := conn.(*tls.Conn); isTLS { // Check peer is authenticated
+		peerID, err := ConnectionPeerID(tlsConn) // Fetch verified peer ID
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		if peerID != remote.NodeID { // Check hello matches certificate
+			return &PeerIdentityMismatchError{Expected: peerID, Actual: remote.NodeID} // Return mismatch
+		}
+	}
+
+	return nil // Hello is compatible
+}
+
+/*
+	END EXPORTED METHODS
+*/
+
+/*
+	BEGIN INTERNAL METHODS
+*/
+
+// dialHello - perform handshake with hello of node given certificate is bound to (local certificate if nil) over newly dialed connection
+func dialHello(conn net.Conn, cert *tls.Certificate) (*Handshake, error) {
+	hello, err := NodeHello(cert) // Fetch hello
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return ClientHandshake(conn, hello) // Perform handshake
+}
+
+// selectCodec - select codec requested in given hello of dialing peer
+func selectCodec(remote *Hello) (Codec, error) {
+	if remote.Codec == "" { // Check peer predates codec negotiation
+		return CodecJSON, nil // Peer speaks JSON
+	}
+
+	if !IsSupportedCodec(remote.Codec) { // Check for unsupported codec
+		return "", &HandshakeError{Reason: "unsupported codec " + string(remote.Codec)} // Return error
+	}
+
+	return remote.Codec, nil // Return requested codec
+}
+
+// newHandshake - init handshake, negotiating capabilities supported by both peers
+func newHandshake(local *Hello, remote *Hello) *Handshake {
+	capabilities := []string{} // Init capabilities buffer
+
+	for _, capability := range local.Capabilities { // Iterate through local capabilities
+		if StringInSlice(remote.Capabilities, capability) { // Check peer supports capability
+			capabilities = append(capabilities, capability) // Append capability
+		}
+	}
+
+	return &Handshake{Local: local, Remote: remote, Capabilities: capabilities, Codec: local.Codec} // Return handshake
+}
+
+// writeHello - write hello frame to connection
+func writeHello(conn net.Conn, hello *Hello) error {
+	payload, err := json.Marshal(hello) // Encode hello
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	return WriteFrame(conn, FrameTypeHello, payload) // Write hello
+}
+
+/*
+	END INTERNAL METHODS
+*/
@@ -0,0 +1,109 @@
+package common
+
+import (
+	"net"
+	"testing"
+)
+
+// TestClientHandshake - test functionality of ClientHandshake() method
+func TestClientHandshake(t *testing.T) {
+	client, server := net.Pipe() // Init connection
+
+	defer client.Close() // Close client
+	defer server.Close() // Close server
+
+	go ServerHandshake(server, func(remote *Hello) (*Hello, error) {
+		return &Hello{ProtocolVersion: ProtocolVersion, NetworkID: GoP2PTestnetID, Capabilities: []string{CapabilityProtobuf, "compression"}}, nil // Return server hello
+	}) // Perform server handshake
+
+	handshake, err := ClientHandshake(client, &Hello{ProtocolVersion: ProtocolVersion, NetworkID: GoP2PTestnetID, Capabilities: DefaultCapabilities}) // Perform client handshake
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if !handshake.HasCapability(CapabilityProtobuf) || handshake.HasCapability(CapabilityMultiplex) || handshake.HasCapability("compression") { // Check negotiated capabilities
+		t.Errorf("invalid negotiated capabilities %v", handshake.Capabilities) // Log found error
+		t.FailNow()                                                            // Panic
+	}
+
+	t.Logf("negotiated capabilities %v", handshake.Capabilities) // Log success
+}
+
+// TestServerHandshake - test functionality of ServerHandshake() method
+func TestServerHandshake(t *testing.T) {
+	client, server := net.Pipe() // Init connection
+
+	defer client.Close() // Close client
+	defer server.Close() // Close server
+
+	serverErr := make(chan error, 1) // Init server error buffer
+
+	go func() {
+		_, err := ServerHandshake(server, func(remote *Hello) (*Hello, error) {
+			return &Hello{ProtocolVersion: ProtocolVersion, NetworkID: 1}, nil // Return server hello
+		}) // Perform server handshake
+
+		serverErr <- err // Set server error
+	}()
+
+	_, err := ClientHandshake(client, &Hello{ProtocolVersion: ProtocolVersion, NetworkID: 2}) // Perform client handshake
+
+	if _, isRefused := err.(*HandshakeError); !isRefused { // Check client was refused
+		t.Errorf("expected handshake error, got %v", err) // Log found error
+		t.FailNow()                                       // Panic
+	}
+
+	if _, isRefused := (<-serverErr).(*HandshakeError); !isRefused { // Check server refused
+		t.Errorf("expected server to refuse network mismatch") // Log found error
+		t.FailNow()                                            // Panic
+	}
+
+	t.Logf("refused peer: %s", err.Error()) // Log success
+}
+
+// TestHandshakeCodec - test selection of codec requested by dialing peer
+func TestHandshakeCodec(t *testing.T) {
+	for _, codec := range []Codec{CodecProtobuf, CodecJSON, Codec("xml")} { // Iterate through requested codecs
+		client, server := net.Pipe() // Init connection
+
+		serverCodec := make(chan Codec, 1) // Init server codec buffer
+
+		go func() {
+			handshake, err := ServerHandshake(server, func(remote *Hello) (*Hello, error) {
+				return &Hello{ProtocolVersion: ProtocolVersion}, nil // Return server hello
+			}) // Perform server handshake
+
+			if err != nil { // Check for errors
+				serverCodec <- "" // Set refused
+			} else {
+				serverCodec <- handshake.Codec // Set server codec
+			}
+		}()
+
+		handshake, err := ClientHandshake(client, &Hello{ProtocolVersion: ProtocolVersion, Codec: codec}) // Perform client handshake
+
+		client.Close() // Close client
+		server.Close() // Close server
+
+		if !IsSupportedCodec(codec) { // Check unsupported codec was refused
+			if _, isRefused := err.(*HandshakeError); !isRefused || <-serverCodec != "" { // Check for refusal
+				t.Errorf("expected codec %s to be refused, got %v", codec, err) // Log found error
+				t.FailNow()                                                     // Panic
+			}
+
+			continue // Check next codec
+		}
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if selected := <-serverCodec; handshake.Codec != codec || selected != codec { // Check both peers selected requested codec
+			t.Errorf("requested codec %s, client selected %s, server selected %s", codec, handshake.Codec, selected) // Log found error
+			t.FailNow()                                                                                              // Panic
+		}
+	}
+}
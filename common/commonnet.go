@@ -1,31 +1,30 @@
 package common
 
 import (
-	"bufio"
-	"bytes"
 	"crypto/tls"
 	"errors"
-	"fmt"
-	"io"
-	"io/ioutil"
 	"net"
-	"strconv"
 	"time"
 )
 
+var (
+	// ConnectionReadTimeout - maximum duration to wait on a peer to write a frame
+	ConnectionReadTimeout = 30 * time.Second
+)
+
 /*
 	BEGIN EXPORTED METHODS
 */
 
 // SendBytes - attempt to send specified bytes to given address
 func SendBytes(b []byte, address string) error {
-	connection, err := tls.Dial("tcp", address, GeneralTLSConfig) // Connect to given address
+	connection, err := dialHandshake(address) // Connect to given address
 
 	if err != nil { // Check for errors
 		return err // Return found error
 	}
 
-	_, err = connection.Write(b) // Write data to connection
+	err = WriteFrame(connection, FrameTypeMessage, b) // Write data to connection
 
 	if err != nil { // Check for errors
 		return err // Return found errors
@@ -42,13 +41,21 @@ func SendBytes(b []byte, address string) error {
 
 // SendBytesNoTLS - send bytes to address without TLS
 func SendBytesNoTLS(b []byte, address string) error {
-	connection, err := net.Dial("tcp", address) // Connect to given address
+	connection, err := DialAddress(address, nil) // Connect to given address
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	defer connection.Close() // Close connection on return
+
+	_, err = dialHello(connection, nil) // Perform handshake
 
 	if err != nil { // Check for errors
 		return err // Return found error
 	}
 
-	_, err = connection.Write(b) // Write data to connection
+	err = WriteFrame(connection, FrameTypeMessage, b) // Write data to connection
 
 	if err != nil { // Check for errors
 		return err // Return found errors
@@ -65,29 +72,21 @@ func SendBytesNoTLS(b []byte, address string) error {
 
 // SendBytesResult - attempt to send specified bytes to given address, returning result
 func SendBytesResult(b []byte, address string) ([]byte, error) {
-	d := net.Dialer{Timeout: 15 * time.Second} // Init dialer with timeout
-
-	connection, err := tls.DialWithDialer(&d, "tcp", address, GeneralTLSConfig) // Connect to given address
+	connection, err := dialHandshake(address) // Connect to given address
 
 	if err != nil { // Check for errors
 		return nil, err // Return found error
 	}
 
-	n, err := connection.Write(b) // Write data to connection
+	defer connection.Close() // Close connection on return
 
-	if err != nil { // Check for errors
-		return nil, err // Return found error
-	} else if n != len(b) { // Check write failed
-		return []byte{}, fmt.Errorf("connection write failed: wrote %s bytes of data of %s bytes of data", strconv.Itoa(n), strconv.Itoa(len(b))) // Log connection write failed
-	}
-
-	result, err := ReadConnectionWaitAsync(connection) // Read connection
+	err = WriteFrame(connection, FrameTypeRequest, b) // Write request to connection
 
 	if err != nil { // Check for errors
 		return nil, err // Return found error
 	}
 
-	err = connection.Close() // Close connection
+	result, err := ReadConnectionWaitAsync(connection) // Read response
 
 	if err != nil { // Check for errors
 		return nil, err // Return found error
@@ -98,13 +97,13 @@ func SendBytesResult(b []byte, address string) ([]byte, error) {
 
 // SendBytesAsync - attempt to send specified bytes to given address in an asynchronous manner
 func SendBytesAsync(b []byte, address string, finished *[]bool) error {
-	connection, err := tls.Dial("tcp", address, GeneralTLSConfig) // Connect to given address
+	connection, err := dialHandshake(address) // Connect to given address
 
 	if err != nil { // Check for errors
 		return err // Return found error
 	}
 
-	_, err = connection.Write(b) // Write data to connection
+	err = WriteFrame(connection, FrameTypeMessage, b) // Write data to connection
 
 	if err != nil { // Check for errors
 		return err // Return found errors
@@ -127,13 +126,13 @@ func SendBytesAsync(b []byte, address string, finished *[]bool) error {
 
 // SendBytesAsyncRoutine - attempt to send specified bytes to given address in an asynchronous, go routine-based manner.
 func SendBytesAsyncRoutine(b []byte, address string, finished chan bool) error {
-	connection, err := tls.Dial("tcp", address, GeneralTLSConfig) // Connect to given address
+	connection, err := dialHandshake(address) // Connect to given address
 
 	if err != nil { // Check for errors
 		return err // Return found error
 	}
 
-	_, err = connection.Write(b) // Write data to connection
+	err = WriteFrame(connection, FrameTypeMessage, b) // Write data to connection
 
 	if err != nil { // Check for errors
 		return err // Return found errors
@@ -152,28 +151,24 @@ func SendBytesAsyncRoutine(b []byte, address string, finished chan bool) error {
 
 // SendBytesResultBufferAsync - attempt to send specified bytes to given address in an asynchronous fashion, reading the result into a given buffer
 func SendBytesResultBufferAsync(b []byte, buffer *[][]byte, address string) error {
-	connection, err := tls.Dial("tcp", address, GeneralTLSConfig) // Connect to given address
+	connection, err := dialHandshake(address) // Connect to given address
 
 	if err != nil { // Check for errors
 		return err // Return found error
 	}
 
-	_, err = connection.Write(b) // Write data to connection
+	defer connection.Close() // Close connection on return
 
-	if err != nil { // Check for errors
-		return err // Return found errors
-	}
-
-	result, err := ReadConnectionWaitAsync(connection) // Read connection
+	err = WriteFrame(connection, FrameTypeRequest, b) // Write request to connection
 
 	if err != nil { // Check for errors
 		return err // Return found errors
 	}
 
-	err = connection.Close() // Close connection
+	result, err := ReadConnectionWaitAsync(connection) // Read response
 
 	if err != nil { // Check for errors
-		return err // Return found error
+		return err // Return found errors
 	}
 
 	*buffer = append(*buffer, result) // Append result
@@ -187,7 +182,7 @@ func SendBytesResultBufferAsync(b []byte, buffer *[][]byte, address string) erro
 
 // SendBytesWithConnection - attempt to send specified bytes to given address via given connection
 func SendBytesWithConnection(connection *tls.Conn, b []byte) error {
-	_, err := (*connection).Write(b) // Write to connection
+	err := WriteFrame(connection, FrameTypeMessage, b) // Write to connection
 
 	if err != nil { // Check for errors
 		return err // Return found error
@@ -198,13 +193,13 @@ func SendBytesWithConnection(connection *tls.Conn, b []byte) error {
 
 // SendBytesReusable - attempt to send specified bytes to given address and return created connection
 func SendBytesReusable(b []byte, address string) (*tls.Conn, error) {
-	connection, err := tls.Dial("tcp", address, GeneralTLSConfig) // Connect to given address
+	connection, err := dialHandshake(address) // Connect to given address
 
 	if err != nil { // Check for errors
 		return nil, err // Return found error
 	}
 
-	_, err = connection.Write(b) // Write data to connection
+	err = WriteFrame(connection, FrameTypeRequest, b) // Write request to connection
 
 	if err != nil { // Check for errors
 		return nil, err // Return found errors
@@ -213,119 +208,63 @@ func SendBytesReusable(b []byte, address string) (*tls.Conn, error) {
 	return connection, nil // No error occurred, return nil
 }
 
-// ReadConnectionDelim - attempt to read connection until occurrence of standard GoP2P connection delimiter
-func ReadConnectionDelim(conn *tls.Conn) ([]byte, error) {
-	reader := bufio.NewReader(conn) // Initialize reader
-
-	data, err := reader.ReadBytes(ConnectionDelimiter) // Read until delimiter
-
-	if err != nil { // Check for errors
-		return []byte{}, err // Return found error
-	}
-
-	return data, nil // Return read data
+// ReadConnectionWaitAsync - attempt to read a single framed response from connection, waiting at most ConnectionReadTimeout for the peer to write
+func ReadConnectionWaitAsync(conn *tls.Conn) ([]byte, error) {
+	return readResponseDeadline(conn) // Read response
 }
 
-// ReadConnectionAsync - attempt to read entirety of specified connection in an asynchronous fashion, returning data byte value
-func ReadConnectionAsync(conn *tls.Conn, buffer chan []byte, finished chan bool, err chan error) {
-	data, readErr := ioutil.ReadAll(conn) // Read connection
-
-	if readErr != nil { // Check for errors
-		err <- readErr   // Set error
-		finished <- true // Set finished
-
-		return
-	}
-
-	buffer <- data // Set read data
-
-	finished <- true // Set finished
-
-	return
+// ReadConnectionWaitAsyncNoTLS - attempt to read a single framed response from connection, waiting at most ConnectionReadTimeout for the peer to write
+func ReadConnectionWaitAsyncNoTLS(conn net.Conn) ([]byte, error) {
+	return readResponseDeadline(conn) // Read response
 }
 
-// ReadConnectionWaitAsync - attempt to read from connection in an asynchronous fashion, after waiting for peer to write
-func ReadConnectionWaitAsync(conn *tls.Conn) ([]byte, error) {
-	data := make(chan []byte) // Init buffer
-	err := make(chan error)   // Init error buffer
-
-	conn.SetReadDeadline(time.Now().Add(4 * time.Second)) // Set read deadline
-
-	go func(data chan []byte, err chan error) {
-		reads := 0 // Init reads buffer
+// ReadFrameWait - attempt to read a single frame from connection, waiting at most ConnectionReadTimeout for the peer to write
+func ReadFrameWait(conn net.Conn) (*Frame, error) {
+	conn.SetReadDeadline(time.Now().Add(ConnectionReadTimeout)) // Set read deadline
 
-		for {
-			reads++ // Increment read
+	defer conn.SetReadDeadline(time.Time{}) // Clear read deadline
 
-			var buffer bytes.Buffer // Init buffer
-
-			readData, readErr := io.Copy(&buffer, conn) // Read connection
+	return ReadFrame(conn) // Read frame
+}
 
-			if readErr != nil && readErr != io.EOF && reads > 3 { // Check for errors
-				err <- readErr // Write read error
-			} else if readData == 0 { // Check for nil readData
-				continue // Continue
-			}
+/*
+	END EXPORTED METHODS
+*/
 
-			data <- buffer.Bytes() // Write read data
-		}
-	}(data, err)
+/*
+	BEGIN INTERNAL METHODS
+*/
 
-	ticker := time.Tick(5 * time.Second) // Init ticker
+// dialHandshake - dial given address over its transport, performing handshake with peer. The peer must present a certificate bound to a valid node identity (address-only senders don't know the peer's NodeID, see ConnectionPool for sends pinned to a NodeID).
+func dialHandshake(address string) (*tls.Conn, error) {
+	conn, err := DialAddress(address, PeerTLSConfig("")) // Connect to given address, verifying peer identity
 
-	for { // Continuously read from connection
-		select {
-		case readData := <-data: // Read data from connection
-			return readData, nil // Return read data
-		case readErr := <-err: // Error on read
-			return []byte{}, readErr // Return error
-		case <-ticker: // Timed out
-			return []byte{}, errors.New("timed out") // Return timed out error
-		}
+	if err != nil { // Check for errors
+		return nil, err // Return found error
 	}
-}
-
-// ReadConnectionWaitAsyncNoTLS - attempt to read from connection in an asynchronous fashion, after waiting for peer to write
-func ReadConnectionWaitAsyncNoTLS(conn net.Conn) ([]byte, error) {
-	data := make(chan []byte) // Init buffer
-	err := make(chan error)   // Init error buffer
 
-	conn.SetReadDeadline(time.Now().Add(2 * time.Second)) // Set read deadline
+	connection := conn.(*tls.Conn) // Fetch TLS connection
 
-	go func(data chan []byte, err chan error) {
-		reads := 0 // Init reads buffer
+	_, err = dialHello(connection, nil) // Perform handshake
 
-		for {
-			reads++ // Increment read
-
-			var buffer bytes.Buffer // Init buffer
+	if err != nil { // Check for errors
+		connection.Close() // Close connection
 
-			readData, readErr := io.Copy(&buffer, conn) // Read connection
+		return nil, err // Return found error
+	}
 
-			if readErr != nil && readErr != io.EOF && reads > 3 { // Check for errors
-				err <- readErr // Write read error
-			} else if readData == 0 { // Check for nil readData
-				continue // Continue
-			}
+	return connection, nil // Return connection
+}
 
-			data <- buffer.Bytes() // Write read data
-		}
-	}(data, err)
+// readResponseDeadline - read response frame from connection with read deadline
+func readResponseDeadline(conn net.Conn) ([]byte, error) {
+	conn.SetReadDeadline(time.Now().Add(ConnectionReadTimeout)) // Set read deadline
 
-	ticker := time.Tick(3 * time.Second) // Init ticker
+	defer conn.SetReadDeadline(time.Time{}) // Clear read deadline
 
-	for { // Continuously read from connection
-		select {
-		case readData := <-data: // Read data from connection
-			return readData, nil // Return read data
-		case readErr := <-err: // Error on read
-			return []byte{}, readErr // Return error
-		case <-ticker: // Timed out
-			return []byte{}, errors.New("timed out") // Return timed out error
-		}
-	}
+	return ReadResponseFrame(conn) // Read response
 }
 
 /*
-	END EXPORTED METHODS
+	END INTERNAL METHODS
 */
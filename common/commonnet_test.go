@@ -7,14 +7,18 @@ import (
 
 // TestSendBytes - test functionality of SendBytes() method
 func TestSendBytes(t *testing.T) {
-	err := SendBytes([]byte("test"), "1.1.1.1:443") // Write to address
+	ln, _ := startEchoServer(t) // Start echo server
+
+	defer ln.Close() // Close listener
+
+	err := SendBytes([]byte("test"), ln.Addr().String()) // Write to address
 
 	if err != nil { // Check for errors
 		t.Errorf(err.Error()) // Log found error
 		t.FailNow()           // Panic
 	}
 
-	t.Logf("wrote to address 1.1.1.1") // Log success
+	t.Logf("wrote to address %s", ln.Addr().String()) // Log success
 }
 
 // TestSendBytesWithConnection - test functionality of SendBytesWithConnection() method
@@ -43,7 +47,11 @@ func TestSendBytesWithConnection(t *testing.T) {
 
 // TestSendBytesReusable - test functionality of SendBytesReusable() method
 func TestSendBytesReusable(t *testing.T) {
-	connection, err := SendBytesReusable([]byte("test"), "1.1.1.1:443") // Attempt to send bytes
+	ln, _ := startEchoServer(t) // Start echo server
+
+	defer ln.Close() // Close listener
+
+	connection, err := SendBytesReusable([]byte("test"), ln.Addr().String()) // Attempt to send bytes
 
 	if err != nil { // Check for errors
 		t.Errorf(err.Error()) // Log found error
@@ -57,3 +65,53 @@ func TestSendBytesReusable(t *testing.T) {
 		t.FailNow()           // Panic
 	}
 }
+
+// TestSendBytesResult - test functionality of SendBytesResult() method
+func TestSendBytesResult(t *testing.T) {
+	ln, _ := startEchoServer(t) // Start echo server
+
+	defer ln.Close() // Close listener
+
+	result, err := SendBytesResult([]byte("test"), ln.Addr().String()) // Send bytes
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if string(result) != "test" { // Check for mismatch
+		t.Errorf("invalid result %s", string(result)) // Log found error
+		t.FailNow()                                   // Panic
+	}
+}
+
+// TestSendBytesUnauthenticatedPeer - test SendBytes() refuses peers that don't present a node identity
+func TestSendBytesUnauthenticatedPeer(t *testing.T) {
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: GeneralTLSConfig.Certificates}) // Listen without node identity
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	defer ln.Close() // Close listener
+
+	go func() {
+		for {
+			conn, err := ln.Accept() // Accept connection
+
+			if err != nil { // Check for errors
+				return // Listener closed
+			}
+
+			conn.(*tls.Conn).Handshake() // Complete handshake
+
+			conn.Close() // Close connection
+		}
+	}()
+
+	if err = SendBytes([]byte("test"), ln.Addr().String()); err != ErrNoPeerIdentity { // Send bytes
+		t.Errorf("expected peer without node identity to be refused, got %v", err) // Log found error
+		t.FailNow()                                                                // Panic
+	}
+}
@@ -0,0 +1,626 @@
+package common
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+var (
+	// DefaultConnectionPool - connection pool of senders not acting on behalf of a specific node (presenting LocalCertificate, see NodeConnectionPool)
+	DefaultConnectionPool = NewConnectionPool(DefaultPoolIdleTimeout)
+
+	// DefaultPoolIdleTimeout - duration after which an unused pooled session is evicted
+	DefaultPoolIdleTimeout = 60 * time.Second
+
+	// ConnectionIdleTimeout - duration a handler keeps a persistent connection open without receiving a frame
+	ConnectionIdleTimeout = 2 * time.Minute
+
+	// ErrSessionClosed - error returned when writing to a closed session
+	ErrSessionClosed = errors.New("session closed")
+
+	// ErrPoolClosed - error returned when requesting a session from a closed pool
+	ErrPoolClosed = errors.New("connection pool closed")
+
+	nodePools = make(map[string]*ConnectionPool) // nodePools - connection pools of nodes in this process (by NodeID, see NodeConnectionPool)
+
+	nodePoolsMutex sync.Mutex // nodePoolsMutex - guards nodePools
+)
+
+// Encodable - payload encodable with the codec negotiated with a peer
+type Encodable interface {
+	Encode(codec Codec) ([]byte, error) // Encode - encode payload with given codec
+}
+
+// ConnectionPool - per-peer manager of persistent, multiplexed TLS sessions
+type ConnectionPool struct {
+	IdleTimeout time.Duration // IdleTimeout - duration after which an unused session is evicted
+
+	DialTimeout time.Duration // DialTimeout - maximum duration to wait on a dial
+
+	Certificate *tls.Certificate // Certificate - certificate presented to peers, identifying the node sessions are dialed on behalf of (LocalCertificate if nil)
+
+	sessions map[string]*Session // sessions - active sessions by address
+
+	dialing map[string]chan struct{} // dialing - in-progress dials by address
+
+	closed bool // closed - pool has been closed
+
+	stop chan struct{} // stop - stops eviction routine
+
+	mutex sync.Mutex // mutex - guards sessions, dialing
+}
+
+// Session - single persistent TLS session with a peer, multiplexing concurrent request streams
+type Session struct {
+	Address string // Address - peer address
+
+	PeerID string // PeerID - verified NodeID of peer
+
+	Handshake *Handshake // Handshake - handshake completed with peer
+
+	conn net.Conn // conn - underlying connection
+
+	streams map[uint32]chan *Frame // streams - pending requests by stream ID
+
+	nextStreamID uint32 // nextStreamID - last allocated stream ID
+
+	lastUsed time.Time // lastUsed - time of last request
+
+	err error // err - error that closed session
+
+	done chan struct{} // done - closed once session is closed
+
+	mutex sync.Mutex // mutex - guards streams, nextStreamID, lastUsed, err
+
+	writeMutex sync.Mutex // writeMutex - serializes frame writes
+}
+
+/*
+	BEGIN EXPORTED METHODS
+*/
+
+// NewConnectionPool - initialize connection pool, evicting sessions idle longer than given duration
+func NewConnectionPool(idleTimeout time.Duration) *ConnectionPool {
+	pool := &ConnectionPool{
+		IdleTimeout: idleTimeout,                    // Set idle timeout
+		DialTimeout: DefaultDialTimeout,             // Set dial timeout
+		sessions:    make(map[string]*Session),      // Init sessions
+		dialing:     make(map[string]chan struct{}), // Init dialing
+		stop:        make(chan struct{}),            // Init stop channel
+	}
+
+	go pool.evictRoutine() // Start evicting idle sessions
+
+	return pool // Return initialized pool
+}
+
+// NodeConnectionPool - fetch connection pool dialing peers on behalf of the node given certificate is bound to, presenting the certificate (shared by every caller acting on behalf of the node)
+func NodeConnectionPool(cert *tls.Certificate) (*ConnectionPool, error) {
+	nodeID, err := TLSCertificateNodeID(cert) // Fetch NodeID
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	nodePoolsMutex.Lock() // Lock pools
+
+	defer nodePoolsMutex.Unlock() // Unlock pools
+
+	if pool, found := nodePools[nodeID]; found { // Check for existing pool
+		return pool, nil // Return pool
+	}
+
+	pool := NewConnectionPool(DefaultPoolIdleTimeout) // Init pool
+
+	pool.Certificate = cert // Present certificate
+
+	nodePools[nodeID] = pool // Set pool
+
+	return pool, nil // Return pool
+}
+
+// Request - send given bytes to address over pooled session, returning response. If nodeID is set, the peer must present a certificate bound to nodeID.
+func (pool *ConnectionPool) Request(address string, nodeID string, b []byte) ([]byte, error) {
+	session, reused, err := pool.session(address, nodeID) // Fetch session
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	result, err := session.Request(b) // Send request
+
+	if err != nil && reused && session.IsClosed() { // Check for stale session
+		pool.Evict(address) // Evict stale session
+
+		if session, _, err = pool.session(address, nodeID); err != nil { // Redial
+			return nil, err // Return found error
+		}
+
+		return session.Request(b) // Retry request
+	}
+
+	return result, err // Return result
+}
+
+// RequestEnvelope - encode given payload with the codec negotiated with peer at address (bound to nodeID, if set), wrap it in an envelope of given kind and send it over pooled session, returning codec, encoded response
+func (pool *ConnectionPool) RequestEnvelope(address string, nodeID string, kind EnvelopeKind, payload Encodable) (Codec, []byte, error) {
+	codec, err := pool.Codec(address, nodeID) // Fetch codec negotiated with peer
+
+	if err != nil { // Check for errors
+		return "", nil, err // Return found error
+	}
+
+	encodedPayload, err := payload.Encode(codec) // Encode payload
+
+	if err != nil { // Check for errors
+		return "", nil, err // Return found error
+	}
+
+	serializedPayload, err := Seal(codec, kind, encodedPayload) // Wrap payload in envelope
+
+	if err != nil { // Check for errors
+		return "", nil, err // Return found error
+	}
+
+	result, err := pool.Request(address, nodeID, serializedPayload) // Send request
+
+	if err != nil { // Check for errors
+		return "", nil, err // Return found error
+	}
+
+	return codec, result, nil // Return codec, response
+}
+
+// Send - send given bytes to address over pooled session without waiting on a response. If nodeID is set, the peer must present a certificate bound to nodeID.
+func (pool *ConnectionPool) Send(address string, nodeID string, b []byte) error {
+	session, reused, err := pool.session(address, nodeID) // Fetch session
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	err = session.Send(b) // Send message
+
+	if err != nil && reused { // Check for stale session
+		pool.Evict(address) // Evict stale session
+
+		if session, _, err = pool.session(address, nodeID); err != nil { // Redial
+			return err // Return found error
+		}
+
+		return session.Send(b) // Retry send
+	}
+
+	return err // Return error (might be nil)
+}
+
+// Capabilities - fetch capabilities negotiated with peer at address (bound to nodeID, if set), connecting if necessary
+func (pool *ConnectionPool) Capabilities(address string, nodeID string) ([]string, error) {
+	session, _, err := pool.session(address, nodeID) // Fetch session
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return session.Handshake.Capabilities, nil // Return capabilities
+}
+
+// Codec - fetch codec negotiated with peer at address (bound to nodeID, if set), connecting if necessary
+func (pool *ConnectionPool) Codec(address string, nodeID string) (Codec, error) {
+	session, _, err := pool.session(address, nodeID) // Fetch session
+
+	if err != nil { // Check for errors
+		return "", err // Return found error
+	}
+
+	return session.Handshake.Codec, nil // Return codec
+}
+
+// RemoteHello - fetch hello sent by peer at address (bound to nodeID, if set) during handshake, connecting if necessary
+func (pool *ConnectionPool) RemoteHello(address string, nodeID string) (*Hello, error) {
+	session, _, err := pool.session(address, nodeID) // Fetch session
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return session.Handshake.Remote, nil // Return hello
+}
+
+// PeerID - fetch verified NodeID of peer at address (bound to nodeID, if set), connecting if necessary
+func (pool *ConnectionPool) PeerID(address string, nodeID string) (string, error) {
+	session, _, err := pool.session(address, nodeID) // Fetch session
+
+	if err != nil { // Check for errors
+		return "", err // Return found error
+	}
+
+	return session.PeerID, nil // Return peer ID
+}
+
+// Evict - close and remove session with given address from pool
+func (pool *ConnectionPool) Evict(address string) {
+	pool.mutex.Lock() // Lock pool
+
+	session, found := pool.sessions[address] // Fetch session
+
+	delete(pool.sessions, address) // Remove session
+
+	pool.mutex.Unlock() // Unlock pool
+
+	if found { // Check session existed
+		session.Close() // Close session
+	}
+}
+
+// Len - fetch number of sessions in pool
+func (pool *ConnectionPool) Len() int {
+	pool.mutex.Lock() // Lock pool
+
+	defer pool.mutex.Unlock() // Unlock pool
+
+	return len(pool.sessions) // Return session count
+}
+
+// Close - close all sessions, stop evicting
+func (pool *ConnectionPool) Close() error {
+	pool.mutex.Lock() // Lock pool
+
+	if pool.closed { // Check already closed
+		pool.mutex.Unlock() // Unlock pool
+
+		return nil // Nothing to do
+	}
+
+	pool.closed = true // Set closed
+
+	close(pool.stop) // Stop eviction routine
+
+	sessions := pool.sessions // Store sessions
+
+	pool.sessions = make(map[string]*Session) // Reset sessions
+
+	pool.mutex.Unlock() // Unlock pool
+
+	for _, session := range sessions { // Iterate through sessions
+		session.Close() // Close session
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// NewSession - initialize session with peer of given NodeID over given (handshaken) connection, reading responses in background
+func NewSession(address string, peerID string, handshake *Handshake, conn net.Conn) *Session {
+	session := &Session{
+		Address:   address,                      // Set address
+		PeerID:    peerID,                       // Set peer ID
+		Handshake: handshake,                    // Set handshake
+		conn:      conn,                         // Set connection
+		streams:   make(map[uint32]chan *Frame), // Init streams
+		lastUsed:  time.Now(),                   // Set last used
+		done:      make(chan struct{}),          // Init done channel
+	}
+
+	go session.readRoutine() // Start reading responses
+
+	return session // Return initialized session
+}
+
+// Request - send given bytes on new stream, waiting at most ConnectionReadTimeout for response
+func (session *Session) Request(b []byte) ([]byte, error) {
+	streamID, response, err := session.openStream(true) // Open stream
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	defer session.closeStream(streamID) // Release stream
+
+	err = session.write(FrameTypeRequest, streamID, b) // Write request
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	timer := time.NewTimer(ConnectionReadTimeout) // Init timeout
+
+	defer timer.Stop() // Stop timer
+
+	select {
+	case frame := <-response: // Check for response
+		if frame.Type != FrameTypeResponse { // Check for rejection
+			return nil, FrameError(frame) // Return remote error
+		}
+
+		return frame.Payload, nil // Return response
+	case <-session.done: // Check session closed
+		return nil, session.Err() // Return session error
+	case <-timer.C: // Check timed out
+		return nil, fmt.Errorf("request to %s timed out", session.Address) // Return timed out
+	}
+}
+
+// Send - send given bytes on new stream without waiting on a response
+func (session *Session) Send(b []byte) error {
+	streamID, _, err := session.openStream(false) // Allocate stream
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	return session.write(FrameTypeMessage, streamID, b) // Write message
+}
+
+// HasCapability - check peer supports given capability
+func (session *Session) HasCapability(capability string) bool {
+	return session.Handshake.HasCapability(capability) // Check capability
+}
+
+// Close - close session, failing pending requests
+func (session *Session) Close() error {
+	session.fail(ErrSessionClosed) // Close session
+
+	return nil // No error occurred, return nil
+}
+
+// IsClosed - check session has been closed
+func (session *Session) IsClosed() bool {
+	select {
+	case <-session.done: // Check done
+		return true // Closed
+	default:
+		return false // Open
+	}
+}
+
+// Err - fetch error that closed session
+func (session *Session) Err() error {
+	session.mutex.Lock() // Lock session
+
+	defer session.mutex.Unlock() // Unlock session
+
+	return session.err // Return error
+}
+
+/*
+	END EXPORTED METHODS
+*/
+
+/*
+	BEGIN INTERNAL METHODS
+*/
+
+// session - fetch open session with address (bound to nodeID, if set), dialing if none exists. Returns true if session was already pooled.
+func (pool *ConnectionPool) session(address string, nodeID string) (*Session, bool, error) {
+	for {
+		pool.mutex.Lock() // Lock pool
+
+		if pool.closed { // Check closed
+			pool.mutex.Unlock() // Unlock pool
+
+			return nil, false, ErrPoolClosed // Return closed error
+		}
+
+		if session, found := pool.sessions[address]; found && !session.IsClosed() { // Check open session exists
+			if nodeID == "" || session.PeerID == nodeID { // Check session is with expected peer
+				pool.mutex.Unlock() // Unlock pool
+
+				return session, true, nil // Return session
+			}
+
+			delete(pool.sessions, address) // Remove session with different peer
+
+			session.Close() // Close session
+		}
+
+		if wait, isDialing := pool.dialing[address]; isDialing { // Check for concurrent dial
+			pool.mutex.Unlock() // Unlock pool
+
+			<-wait // Wait for dial
+
+			continue // Check for dialed session
+		}
+
+		wait := make(chan struct{}) // Init dial wait channel
+
+		pool.dialing[address] = wait // Set dialing
+
+		pool.mutex.Unlock() // Unlock pool
+
+		conn, err := DialAddressTimeout(address, NodeTLSConfig(pool.Certificate, nodeID), pool.DialTimeout) // Connect to given address over its transport, verifying peer identity
+
+		peerID := "" // Init peer ID buffer
+
+		if err == nil { // Check for errors
+			peerID, err = ConnectionPeerID(conn.(*tls.Conn)) // Fetch verified peer ID (TLS dial always yields a TLS connection)
+		}
+
+		var handshake *Handshake // Init handshake buffer
+
+		if err == nil { // Check for errors
+			handshake, err = dialHello(conn, pool.Certificate) // Perform handshake
+		}
+
+		pool.mutex.Lock() // Lock pool
+
+		delete(pool.dialing, address) // Remove dialing
+
+		close(wait) // Notify waiting requests
+
+		defer pool.mutex.Unlock() // Unlock pool
+
+		if err != nil { // Check for errors
+			if conn != nil { // Check for open connection
+				conn.Close() // Close connection
+			}
+
+			return nil, false, err // Return found error
+		}
+
+		if pool.closed { // Check closed while dialing
+			conn.Close() // Close connection
+
+			return nil, false, ErrPoolClosed // Return closed error
+		}
+
+		session := NewSession(address, peerID, handshake, conn) // Init session
+
+		pool.sessions[address] = session // Add session
+
+		return session, false, nil // Return session
+	}
+}
+
+// evictRoutine - periodically evict idle and broken sessions
+func (pool *ConnectionPool) evictRoutine() {
+	ticker := time.NewTicker(pool.IdleTimeout / 2) // Init ticker
+
+	defer ticker.Stop() // Stop ticker
+
+	for {
+		select {
+		case <-ticker.C: // Check tick
+			pool.evictIdle() // Evict sessions
+		case <-pool.stop: // Check stopped
+			return // Stop
+		}
+	}
+}
+
+// evictIdle - evict sessions that are closed or have been idle longer than pool.IdleTimeout
+func (pool *ConnectionPool) evictIdle() {
+	pool.mutex.Lock() // Lock pool
+
+	evicted := []*Session{} // Init evicted buffer
+
+	for address, session := range pool.sessions { // Iterate through sessions
+		if session.IsClosed() || session.idle(pool.IdleTimeout) { // Check broken or idle
+			delete(pool.sessions, address) // Remove session
+
+			evicted = append(evicted, session) // Append evicted
+		}
+	}
+
+	pool.mutex.Unlock() // Unlock pool
+
+	for _, session := range evicted { // Iterate through evicted
+		session.Close() // Close session
+	}
+}
+
+// idle - check session has no pending requests and hasn't been used within given duration
+func (session *Session) idle(timeout time.Duration) bool {
+	session.mutex.Lock() // Lock session
+
+	defer session.mutex.Unlock() // Unlock session
+
+	return len(session.streams) == 0 && time.Since(session.lastUsed) > timeout // Check idle
+}
+
+// openStream - allocate stream ID, registering response channel if a response is expected
+func (session *Session) openStream(expectResponse bool) (uint32, chan *Frame, error) {
+	session.mutex.Lock() // Lock session
+
+	defer session.mutex.Unlock() // Unlock session
+
+	if session.err != nil { // Check closed
+		return 0, nil, session.err // Return session error
+	}
+
+	session.nextStreamID++ // Increment stream ID
+
+	if session.nextStreamID == 0 { // Check for overflow (stream 0 is reserved for unmultiplexed frames)
+		session.nextStreamID++ // Skip 0
+	}
+
+	session.lastUsed = time.Now() // Set last used
+
+	if !expectResponse { // Check no response expected
+		return session.nextStreamID, nil, nil // Return stream ID
+	}
+
+	response := make(chan *Frame, 1) // Init response channel
+
+	session.streams[session.nextStreamID] = response // Register stream
+
+	return session.nextStreamID, response, nil // Return stream
+}
+
+// closeStream - release given stream
+func (session *Session) closeStream(streamID uint32) {
+	session.mutex.Lock() // Lock session
+
+	defer session.mutex.Unlock() // Unlock session
+
+	delete(session.streams, streamID) // Remove stream
+
+	session.lastUsed = time.Now() // Set last used
+}
+
+// write - write frame to session connection
+func (session *Session) write(frameType byte, streamID uint32, b []byte) error {
+	session.writeMutex.Lock() // Lock writes
+
+	defer session.writeMutex.Unlock() // Unlock writes
+
+	session.conn.SetWriteDeadline(time.Now().Add(ConnectionReadTimeout)) // Set write deadline
+
+	err := WriteStreamFrame(session.conn, frameType, streamID, b) // Write frame
+
+	if err != nil { // Check for errors
+		session.fail(err) // Connection is broken, close session
+	}
+
+	return err // Return error (might be nil)
+}
+
+// readRoutine - read response frames, dispatching them to pending streams
+func (session *Session) readRoutine() {
+	for {
+		frame, err := ReadFrame(session.conn) // Read frame
+
+		if err != nil { // Check for errors
+			session.fail(err) // Close session
+
+			return // Stop reading
+		}
+
+		session.mutex.Lock() // Lock session
+
+		response, found := session.streams[frame.StreamID] // Fetch pending stream
+
+		session.mutex.Unlock() // Unlock session
+
+		if found { // Check stream is pending
+			select {
+			case response <- frame: // Deliver response
+			default: // Stream already has a response, drop duplicate
+			}
+		}
+	}
+}
+
+// fail - close session with given error
+func (session *Session) fail(err error) {
+	session.mutex.Lock() // Lock session
+
+	defer session.mutex.Unlock() // Unlock session
+
+	if session.err != nil { // Check already closed
+		return // Nothing to do
+	}
+
+	session.err = err // Set error
+
+	session.conn.Close() // Close connection
+
+	close(session.done) // Notify pending requests
+}
+
+/*
+	END INTERNAL METHODS
+*/
@@ -0,0 +1,181 @@
+package common
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+// TestConnectionPoolRequest - test functionality of ConnectionPool Request() method
+func TestConnectionPoolRequest(t *testing.T) {
+	ln, accepted := startEchoServer(t) // Start echo server
+
+	defer ln.Close() // Close listener
+
+	pool := NewConnectionPool(DefaultPoolIdleTimeout) // Init pool
+
+	defer pool.Close() // Close pool
+
+	wg := sync.WaitGroup{} // Init wait group
+
+	errs := make(chan error, 10) // Init error buffer
+
+	for x := 0; x < 10; x++ { // Send concurrent requests
+		wg.Add(1) // Add request
+
+		go func(x int) {
+			defer wg.Done() // Finish request
+
+			payload := []byte{byte(x)} // Init payload
+
+			result, err := pool.Request(ln.Addr().String(), "", payload) // Send request
+
+			if err != nil { // Check for errors
+				errs <- err // Append error
+			} else if len(result) != 1 || result[0] != byte(x) { // Check for mismatched stream
+				errs <- &RemoteError{Message: "mismatched response"} // Append error
+			}
+		}(x)
+	}
+
+	wg.Wait() // Wait for requests
+
+	close(errs) // Close error buffer
+
+	for err := range errs { // Iterate through errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	capabilities, err := pool.Capabilities(ln.Addr().String(), "") // Fetch negotiated capabilities
+
+	if err != nil || !StringInSlice(capabilities, CapabilityMultiplex) || StringInSlice(capabilities, CapabilityProtobuf) { // Check negotiated capabilities
+		t.Errorf("invalid negotiated capabilities %v (%v)", capabilities, err) // Log found error
+		t.FailNow()                                                            // Panic
+	}
+
+	if atomic.LoadInt32(accepted) != 1 || pool.Len() != 1 { // Check requests shared a single session
+		t.Errorf("expected 1 session, got %d accepted connections and %d pooled sessions", atomic.LoadInt32(accepted), pool.Len()) // Log found error
+		t.FailNow()                                                                                                                // Panic
+	}
+}
+
+// TestConnectionPoolEvict - test eviction of idle and broken sessions
+func TestConnectionPoolEvict(t *testing.T) {
+	ln, accepted := startEchoServer(t) // Start echo server
+
+	defer ln.Close() // Close listener
+
+	pool := NewConnectionPool(50 * time.Millisecond) // Init pool with short idle timeout
+
+	defer pool.Close() // Close pool
+
+	_, err := pool.Request(ln.Addr().String(), "", []byte("test")) // Send request
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	time.Sleep(200 * time.Millisecond) // Wait for eviction
+
+	if pool.Len() != 0 { // Check idle session was evicted
+		t.Errorf("expected idle session to be evicted") // Log found error
+		t.FailNow()                                     // Panic
+	}
+
+	session, _, err := pool.session(ln.Addr().String(), "") // Dial new session
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	session.conn.Close() // Break session
+
+	_, err = pool.Request(ln.Addr().String(), "", []byte("test")) // Send request over broken session
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if atomic.LoadInt32(accepted) != 3 { // Check broken session was replaced
+		t.Errorf("expected 3 accepted connections, got %d", atomic.LoadInt32(accepted)) // Log found error
+		t.FailNow()                                                                     // Panic
+	}
+}
+
+// startEchoServer - start local node TLS server echoing request frames on their stream (after handshake)
+func startEchoServer(t *testing.T) (net.Listener, *int32) {
+	publicKey, identity, err := ed25519.GenerateKey(rand.Reader) // Generate server identity
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	cert, err := GenerateNodeCertificate(identity) // Generate server certificate
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", ServerTLSConfig(cert)) // Listen on local address
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	accepted := new(int32) // Init accepted counter
+
+	go func() {
+		for {
+			conn, err := ln.Accept() // Accept connection
+
+			if err != nil { // Check for errors
+				return // Listener closed
+			}
+
+			atomic.AddInt32(accepted, 1) // Increment accepted
+
+			go func(conn net.Conn) {
+				defer conn.Close() // Close connection
+
+				_, err := ServerHandshake(conn, func(remote *Hello) (*Hello, error) {
+					return &Hello{ProtocolVersion: ProtocolVersion, NodeID: Sha3(publicKey), Capabilities: []string{CapabilityMultiplex}}, nil // Return server hello
+				}) // Perform handshake
+
+				if err != nil { // Check for errors
+					return // Refused
+				}
+
+				writeMutex := sync.Mutex{} // Init write mutex
+
+				for {
+					frame, err := ReadFrame(conn) // Read request
+
+					if err != nil { // Check for errors
+						return // Connection closed
+					}
+
+					go func(frame *Frame) {
+						writeMutex.Lock()                                                        // Lock writes
+						WriteStreamFrame(conn, FrameTypeResponse, frame.StreamID, frame.Payload) // Echo payload
+						writeMutex.Unlock()                                                      // Unlock writes
+					}(frame)
+				}
+			}(conn)
+		}
+	}()
+
+	return ln, accepted // Return listener
+}
@@ -0,0 +1,37 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+// +build linux darwin dragonfly freebsd netbsd openbsd
+
+package common
+
+import (
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+/*
+	BEGIN INTERNAL METHODS
+*/
+
+// reusePortControl - net.ListenConfig, net.Dialer Control implementation letting sockets share their local address, port
+func reusePortControl(network string, address string, conn syscall.RawConn) error {
+	var sockErr error // Init socket error buffer
+
+	err := conn.Control(func(fd uintptr) {
+		sockErr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEADDR, 1) // Reuse address
+
+		if sockErr == nil { // Check for errors
+			sockErr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEPORT, 1) // Reuse port
+		}
+	}) // Set socket options
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	return sockErr // Return socket error (might be nil)
+}
+
+/*
+	END INTERNAL METHODS
+*/
@@ -0,0 +1,21 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+// +build !linux,!darwin,!dragonfly,!freebsd,!netbsd,!openbsd
+
+package common
+
+import (
+	"syscall"
+)
+
+/*
+	BEGIN INTERNAL METHODS
+*/
+
+// reusePortControl - net.ListenConfig, net.Dialer Control implementation (ports can't be shared on this platform, so dials from listening ports fail)
+func reusePortControl(network string, address string, conn syscall.RawConn) error {
+	return nil // Nothing to do
+}
+
+/*
+	END INTERNAL METHODS
+*/
@@ -0,0 +1,337 @@
+package common
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+var (
+	// NodeIdentityExtensionID - x509 extension binding a TLS certificate to a node's Ed25519 identity
+	NodeIdentityExtensionID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 53594, 2, 1}
+
+	// ErrNoPeerIdentity - error returned when a peer presents a certificate without a node identity
+	ErrNoPeerIdentity = errors.New("peer certificate does not contain a node identity")
+
+	localCertificate *tls.Certificate // localCertificate - certificate presented on connections not made on behalf of a specific node
+
+	localCertificateMutex sync.Mutex // localCertificateMutex - guards localCertificate
+
+	nodeCertificates = make(map[string]*tls.Certificate) // nodeCertificates - certificates of nodes listening in this process (by NodeID)
+
+	nodeCertificatesMutex sync.Mutex // nodeCertificatesMutex - guards nodeCertificates
+)
+
+// identitySignaturePrefix - prefix of message signed by node identity to bind a TLS key
+const identitySignaturePrefix = "gop2p-tls-identity:"
+
+// PeerIdentityMismatchError - error returned when a peer presents a certificate for a different NodeID than expected
+type PeerIdentityMismatchError struct {
+	Expected string // Expected - NodeID expected for the dialed peer
+	Actual   string // Actual - NodeID presented by the peer
+}
+
+// certificateIdentity - ASN.1 contents of NodeIdentityExtensionID
+type certificateIdentity struct {
+	PublicKey []byte // PublicKey - node Ed25519 public key
+	Signature []byte // Signature - signature of certificate public key by node identity
+}
+
+/*
+	BEGIN EXPORTED METHODS
+*/
+
+// Error - implement error interface
+func (err *PeerIdentityMismatchError) Error() string {
+	return fmt.Sprintf("peer identity mismatch: expected NodeID %s, peer presented %s", err.Expected, err.Actual) // Return error message
+}
+
+// GenerateNodeCertificate - generate self-signed TLS certificate bound to given node identity
+func GenerateNodeCertificate(identity ed25519.PrivateKey) (*tls.Certificate, error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader) // Generate TLS key
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey) // Marshal TLS public key
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	extension, err := asn1.Marshal(certificateIdentity{
+		PublicKey: []byte(identity.Public().(ed25519.PublicKey)),                                      // Set identity public key
+		Signature: ed25519.Sign(identity, append([]byte(identitySignaturePrefix), publicKeyBytes...)), // Sign TLS key
+	}) // Marshal identity extension
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128)) // Init serial number
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	template := x509.Certificate{
+		SerialNumber:    serialNumber,                                                      // Generate w/serial number
+		Subject:         pkix.Name{Organization: []string{"GoP2P"}},                        // Generate w/subject
+		NotBefore:       time.Now().Add(-time.Hour),                                        // Generate w/not before
+		NotAfter:        time.Now().Add(100 * (365 * (24 * time.Hour))),                    // Generate w/not after
+		ExtraExtensions: []pkix.Extension{{Id: NodeIdentityExtensionID, Value: extension}}, // Generate w/identity
+
+		KeyUsage:              x509.KeyUsageDigitalSignature,                                              // Generate w/key usage
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}, // Generate w/ext key
+		BasicConstraintsValid: true,                                                                       // Generate w/basic constraints
+	} // Init template
+
+	cert, err := x509.CreateCertificate(rand.Reader, &template, &template, &privateKey.PublicKey, privateKey) // Generate certificate
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return &tls.Certificate{Certificate: [][]byte{cert}, PrivateKey: privateKey}, nil // Return certificate
+}
+
+// WriteNodeCertificate - persist given certificate and key in specified path (string)
+func WriteNodeCertificate(path string, cert *tls.Certificate) error {
+	privateKey, isECDSA := cert.PrivateKey.(*ecdsa.PrivateKey) // Fetch private key
+
+	if !isECDSA { // Check for unsupported key
+		return errors.New("unsupported certificate key type") // Return error
+	}
+
+	marshaledPrivateKey, err := x509.MarshalECPrivateKey(privateKey) // Marshal private key
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	err = ioutil.WriteFile(path+filepath.FromSlash("/nodeKey.pem"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: marshaledPrivateKey}), 0600) // Write key
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	return ioutil.WriteFile(path+filepath.FromSlash("/nodeCert.pem"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}), 0644) // Write cert
+}
+
+// ReadNodeCertificate - read certificate and key persisted in specified path
+func ReadNodeCertificate(path string) (*tls.Certificate, error) {
+	cert, err := tls.LoadX509KeyPair(path+filepath.FromSlash("/nodeCert.pem"), path+filepath.FromSlash("/nodeKey.pem")) // Load key pair
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return &cert, nil // Return read certificate
+}
+
+// CertificateNodeID - fetch NodeID of identity given certificate is bound to, verifying binding
+func CertificateNodeID(cert *x509.Certificate) (string, error) {
+	for _, extension := range cert.Extensions { // Iterate through extensions
+		if !extension.Id.Equal(NodeIdentityExtensionID) { // Check for identity extension
+			continue // Skip extension
+		}
+
+		identity := certificateIdentity{} // Init identity buffer
+
+		_, err := asn1.Unmarshal(extension.Value, &identity) // Unmarshal identity
+
+		if err != nil { // Check for errors
+			return "", err // Return found error
+		}
+
+		if len(identity.PublicKey) != ed25519.PublicKeySize || !ed25519.Verify(ed25519.PublicKey(identity.PublicKey), append([]byte(identitySignaturePrefix), cert.RawSubjectPublicKeyInfo...), identity.Signature) { // Verify binding
+			return "", errors.New("invalid node identity signature in peer certificate") // Return error
+		}
+
+		return Sha3(identity.PublicKey), nil // Return NodeID
+	}
+
+	return "", ErrNoPeerIdentity // No identity
+}
+
+// TLSCertificateNodeID - fetch NodeID of identity given TLS certificate is bound to, verifying binding
+func TLSCertificateNodeID(cert *tls.Certificate) (string, error) {
+	if len(cert.Certificate) == 0 { // Check for empty certificate
+		return "", ErrNoPeerIdentity // Return error
+	}
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0]) // Parse certificate
+
+	if err != nil { // Check for errors
+		return "", err // Return found error
+	}
+
+	return CertificateNodeID(leaf) // Return NodeID
+}
+
+// SetLocalCertificate - set certificate presented on connections not made on behalf of a specific node (address-only senders, DefaultConnectionPool). Nodes present their own certificates through NodeConnectionPool.
+func SetLocalCertificate(cert *tls.Certificate) {
+	localCertificateMutex.Lock() // Lock certificate
+
+	defer localCertificateMutex.Unlock() // Unlock certificate
+
+	localCertificate = cert // Set certificate
+}
+
+// LocalCertificate - fetch certificate presented on connections not made on behalf of a specific node (ephemeral identity unless set via SetLocalCertificate)
+func LocalCertificate() (*tls.Certificate, error) {
+	localCertificateMutex.Lock() // Lock certificate
+
+	defer localCertificateMutex.Unlock() // Unlock certificate
+
+	if localCertificate == nil { // Check no certificate set
+		_, identity, err := ed25519.GenerateKey(rand.Reader) // Generate ephemeral identity
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		localCertificate, err = GenerateNodeCertificate(identity) // Generate certificate
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+	}
+
+	return localCertificate, nil // Return certificate
+}
+
+// RegisterNodeCertificate - register certificate of node listening in this process, so transports accepting connections on behalf of the node (e.g. relay listeners) can present it (see NodeCertificate)
+func RegisterNodeCertificate(cert *tls.Certificate) error {
+	nodeID, err := TLSCertificateNodeID(cert) // Fetch NodeID
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	nodeCertificatesMutex.Lock() // Lock certificates
+
+	defer nodeCertificatesMutex.Unlock() // Unlock certificates
+
+	nodeCertificates[nodeID] = cert // Set certificate
+
+	return nil // No error occurred, return nil
+}
+
+// NodeCertificate - fetch certificate registered by node with given NodeID (see RegisterNodeCertificate)
+func NodeCertificate(nodeID string) (*tls.Certificate, bool) {
+	nodeCertificatesMutex.Lock() // Lock certificates
+
+	defer nodeCertificatesMutex.Unlock() // Unlock certificates
+
+	cert, found := nodeCertificates[nodeID] // Fetch certificate
+
+	return cert, found // Return certificate
+}
+
+// PeerTLSConfig - TLS config for dialing a node, presenting local certificate and verifying the peer is bound to expectedNodeID (any valid node identity if empty)
+func PeerTLSConfig(expectedNodeID string) *tls.Config {
+	return NodeTLSConfig(nil, expectedNodeID) // Return config
+}
+
+// NodeTLSConfig - TLS config for dialing a node on behalf of the node given certificate is bound to (local certificate if nil), verifying the peer is bound to expectedNodeID (any valid node identity if empty)
+func NodeTLSConfig(cert *tls.Certificate, expectedNodeID string) *tls.Config {
+	getClientCertificate := getLocalClientCertificate // Present local certificate by default
+
+	if cert != nil { // Check for certificate
+		getClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return cert, nil // Present certificate
+		}
+	}
+
+	return &tls.Config{
+		GetClientCertificate:  getClientCertificate,               // Present certificate
+		InsecureSkipVerify:    true,                               // Peer certificates are self-signed, verified against node identity below
+		VerifyPeerCertificate: verifyPeerIdentity(expectedNodeID), // Verify peer identity
+	} // Return config
+}
+
+// ServerTLSConfig - TLS config for node listeners, requiring clients to present certificates bound to a valid node identity
+func ServerTLSConfig(cert *tls.Certificate) *tls.Config {
+	return &tls.Config{
+		Certificates:          []tls.Certificate{*cert}, // Present given certificate
+		ClientAuth:            tls.RequireAnyClientCert, // Require client certificates
+		VerifyPeerCertificate: verifyPeerIdentity(""),   // Verify client identity
+	} // Return config
+}
+
+// ConnectionPeerID - fetch verified NodeID of peer on other side of given connection
+func ConnectionPeerID(conn *tls.Conn) (string, error) {
+	err := conn.Handshake() // Ensure handshake has completed
+
+	if err != nil { // Check for errors
+		return "", err // Return found error
+	}
+
+	state := conn.ConnectionState() // Fetch connection state
+
+	if len(state.PeerCertificates) == 0 { // Check for no certificate
+		return "", ErrNoPeerIdentity // Return error
+	}
+
+	return CertificateNodeID(state.PeerCertificates[0]) // Return NodeID
+}
+
+/*
+	END EXPORTED METHODS
+*/
+
+/*
+	BEGIN INTERNAL METHODS
+*/
+
+// getLocalClientCertificate - tls.Config GetClientCertificate implementation presenting local certificate
+func getLocalClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return LocalCertificate() // Return local certificate
+}
+
+// verifyPeerIdentity - init tls.Config VerifyPeerCertificate implementation checking peer is bound to expectedNodeID
+func verifyPeerIdentity(expectedNodeID string) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 { // Check for no certificate
+			return ErrNoPeerIdentity // Return error
+		}
+
+		cert, err := x509.ParseCertificate(rawCerts[0]) // Parse peer certificate
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		nodeID, err := CertificateNodeID(cert) // Fetch peer NodeID
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		if expectedNodeID != "" && nodeID != expectedNodeID { // Check for mismatch
+			return &PeerIdentityMismatchError{Expected: expectedNodeID, Actual: nodeID} // Return mismatch
+		}
+
+		return nil // Peer verified
+	}
+}
+
+/*
+	END INTERNAL METHODS
+*/
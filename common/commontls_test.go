@@ -0,0 +1,104 @@
+package common
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+// TestGenerateNodeCertificate - test functionality of GenerateNodeCertificate() method
+func TestGenerateNodeCertificate(t *testing.T) {
+	publicKey, identity, err := ed25519.GenerateKey(rand.Reader) // Generate identity
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	cert, err := GenerateNodeCertificate(identity) // Generate certificate
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0]) // Parse certificate
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	nodeID, err := CertificateNodeID(leaf) // Fetch NodeID
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if nodeID != Sha3(publicKey) { // Check for mismatch
+		t.Errorf("certificate bound to %s, expected %s", nodeID, Sha3(publicKey)) // Log found error
+		t.FailNow()                                                               // Panic
+	}
+}
+
+// TestPeerTLSConfig - test functionality of PeerTLSConfig() method
+func TestPeerTLSConfig(t *testing.T) {
+	publicKey, identity, err := ed25519.GenerateKey(rand.Reader) // Generate server identity
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	cert, err := GenerateNodeCertificate(identity) // Generate server certificate
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", ServerTLSConfig(cert)) // Listen on local address
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	defer ln.Close() // Close listener
+
+	go func() {
+		for {
+			conn, err := ln.Accept() // Accept connection
+
+			if err != nil { // Check for errors
+				return // Listener closed
+			}
+
+			conn.(*tls.Conn).Handshake() // Complete handshake
+
+			conn.Close() // Close connection
+		}
+	}()
+
+	conn, err := tls.Dial("tcp", ln.Addr().String(), PeerTLSConfig(Sha3(publicKey))) // Dial expected peer
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	conn.Close() // Close connection
+
+	_, err = tls.Dial("tcp", ln.Addr().String(), PeerTLSConfig(Sha3([]byte("test")))) // Dial unexpected peer
+
+	if _, isMismatch := err.(*PeerIdentityMismatchError); !isMismatch { // Check for mismatch error
+		t.Errorf("expected peer identity mismatch, got %v", err) // Log found error
+		t.FailNow()                                              // Panic
+	}
+
+	t.Logf("rejected peer: %s", err.Error()) // Log success
+}
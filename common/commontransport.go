@@ -0,0 +1,528 @@
+package common
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// TransportSchemeTCP - scheme of TCPTransport addresses (default for addresses without a scheme, e.g. 1.2.3.4:3000)
+	TransportSchemeTCP = "tcp"
+
+	// TransportSchemeMemory - scheme of MemoryTransport addresses (e.g. memory://node1:3000)
+	TransportSchemeMemory = "memory"
+
+	// TransportSchemeUnix - scheme of UnixTransport addresses (e.g. unix:///tmp/node1.sock)
+	TransportSchemeUnix = "unix"
+
+	// TransportSchemeRelay - scheme of addresses relayed through a rendezvous, relay service (registered by the nat package, e.g. relay://1.2.3.4:3001/p2p/<NodeID>:3000)
+	TransportSchemeRelay = "relay"
+
+	// transportSchemeSeparator - separator between transport scheme and transport-specific address
+	transportSchemeSeparator = "://"
+)
+
+var (
+	// DefaultDialTimeout - maximum duration to wait on a dial (including TLS handshake)
+	DefaultDialTimeout = 15 * time.Second
+
+	// DefaultTCPTransport - transport registered for tcp:// (and scheme-less) addresses
+	DefaultTCPTransport = &TCPTransport{}
+
+	// DefaultMemoryTransport - in-process transport registered for memory:// addresses
+	DefaultMemoryTransport = NewMemoryTransport()
+
+	// ErrTransportClosed - error returned when accepting on a closed in-process listener
+	ErrTransportClosed = errors.New("transport listener closed")
+
+	transports = map[string]Transport{
+		TransportSchemeTCP:    DefaultTCPTransport,    // Register TCP transport
+		TransportSchemeMemory: DefaultMemoryTransport, // Register memory transport
+		TransportSchemeUnix:   &UnixTransport{},       // Register unix transport
+	} // transports - registered transports by scheme
+
+	transportsMutex sync.RWMutex // transportsMutex - guards transports
+)
+
+// Transport - means of dialing peers and accepting connections from them. Connections are secured with TLS by DialAddress and ListenAddress, regardless of transport.
+type Transport interface {
+	Scheme() string // Scheme - scheme prefixing addresses of transport (e.g. "tcp")
+
+	ParseAddress(address string) (string, error) // ParseAddress - validate and normalize given address (without scheme)
+
+	Dial(address string, timeout time.Duration) (net.Conn, error) // Dial - open connection to given (parsed) address
+
+	Listen(address string) (net.Listener, error) // Listen - accept connections on given (parsed) address
+}
+
+// TCPTransport - TLS over TCP transport (default)
+type TCPTransport struct {
+	ReusePort bool // ReusePort - let outgoing connections share listening ports (see DialFrom, required for TCP hole punching)
+}
+
+// UnixTransport - TLS over Unix-domain socket transport, for multiple nodes on a single host
+type UnixTransport struct{}
+
+// MemoryTransport - in-process TLS over net.Pipe transport, for tests and simulations
+type MemoryTransport struct {
+	listeners map[string]*memoryListener // listeners - open listeners by address
+
+	mutex sync.Mutex // mutex - guards listeners
+}
+
+// memoryListener - in-process listener accepting connections dialed via MemoryTransport
+type memoryListener struct {
+	address string // address - address listener is bound to
+
+	transport *MemoryTransport // transport - transport listener is registered with
+
+	conns chan net.Conn // conns - dialed connections waiting to be accepted
+
+	done chan struct{} // done - closed once listener is closed
+
+	closeOnce sync.Once // closeOnce - ensures listener is closed once
+}
+
+// memoryConn - net.Pipe connection reporting in-process addresses
+type memoryConn struct {
+	net.Conn // Conn - underlying pipe
+
+	local net.Addr // local - local address
+
+	remote net.Addr // remote - remote address
+}
+
+// memoryAddr - net.Addr implementation for in-process addresses
+type memoryAddr string
+
+// dualStackListener - listener accepting connections on separate IPv4, IPv6 sockets (for systems without IPv4-mapped IPv6 sockets)
+type dualStackListener struct {
+	listeners []net.Listener // listeners - underlying listeners
+
+	accepted chan acceptResult // accepted - connections (or errors) accepted on underlying listeners
+
+	done chan struct{} // done - closed once listener is closed
+
+	closeOnce sync.Once // closeOnce - ensures listener is closed once
+}
+
+// acceptResult - result of accepting on an underlying listener
+type acceptResult struct {
+	conn net.Conn // conn - accepted connection
+
+	err error // err - accept error
+}
+
+/*
+	BEGIN EXPORTED METHODS
+*/
+
+// RegisterTransport - register given transport for addresses with its scheme (replacing any transport registered for scheme)
+func RegisterTransport(transport Transport) {
+	transportsMutex.Lock() // Lock transports
+
+	defer transportsMutex.Unlock() // Unlock transports
+
+	transports[transport.Scheme()] = transport // Register transport
+}
+
+// GetTransport - fetch transport registered for given scheme
+func GetTransport(scheme string) (Transport, error) {
+	transportsMutex.RLock() // Lock transports
+
+	defer transportsMutex.RUnlock() // Unlock transports
+
+	transport, found := transports[scheme] // Fetch transport
+
+	if !found { // Check for unknown scheme
+		return nil, fmt.Errorf("no transport registered for scheme %s", scheme) // Return error
+	}
+
+	return transport, nil // Return transport
+}
+
+// ParseTransportAddress - split given address (scheme://address, or host:port for TCP) into its transport and normalized transport-specific address
+func ParseTransportAddress(address string) (Transport, string, error) {
+	scheme := TransportSchemeTCP // Default to TCP
+
+	if index := strings.Index(address, transportSchemeSeparator); index != -1 { // Check for scheme
+		scheme, address = address[:index], address[index+len(transportSchemeSeparator):] // Split scheme
+	}
+
+	transport, err := GetTransport(scheme) // Fetch transport
+
+	if err != nil { // Check for errors
+		return nil, "", err // Return found error
+	}
+
+	parsedAddress, err := transport.ParseAddress(address) // Parse address
+
+	if err != nil { // Check for errors
+		return nil, "", err // Return found error
+	}
+
+	return transport, parsedAddress, nil // Return transport, address
+}
+
+// DialAddress - dial given address over its transport, securing connection with given TLS config (unless nil) within DefaultDialTimeout
+func DialAddress(address string, config *tls.Config) (net.Conn, error) {
+	return DialAddressTimeout(address, config, DefaultDialTimeout) // Dial address
+}
+
+// DialAddressTimeout - dial given address over its transport, securing connection with given TLS config (unless nil) within given timeout
+func DialAddressTimeout(address string, config *tls.Config, timeout time.Duration) (net.Conn, error) {
+	transport, parsedAddress, err := ParseTransportAddress(address) // Parse address
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	conn, err := transport.Dial(parsedAddress, timeout) // Dial address
+
+	if err != nil || config == nil { // Check for errors, no TLS
+		return conn, err // Return connection
+	}
+
+	tlsConn := tls.Client(conn, config) // Secure connection
+
+	tlsConn.SetDeadline(time.Now().Add(timeout)) // Set handshake deadline
+
+	err = tlsConn.Handshake() // Perform TLS handshake
+
+	if err != nil { // Check for errors
+		conn.Close() // Close connection
+
+		return nil, err // Return found error
+	}
+
+	tlsConn.SetDeadline(time.Time{}) // Clear handshake deadline
+
+	return tlsConn, nil // Return connection
+}
+
+// ListenAddress - listen on given address over its transport, securing accepted connections with given TLS config (unless nil)
+func ListenAddress(address string, config *tls.Config) (net.Listener, error) {
+	transport, parsedAddress, err := ParseTransportAddress(address) // Parse address
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	ln, err := transport.Listen(parsedAddress) // Listen on address
+
+	if err != nil || config == nil { // Check for errors, no TLS
+		return ln, err // Return listener
+	}
+
+	return tls.NewListener(ln, config), nil // Return secured listener
+}
+
+// Scheme - implement Transport interface
+func (transport *TCPTransport) Scheme() string {
+	return TransportSchemeTCP // Return scheme
+}
+
+// ParseAddress - validate given host:port address
+func (transport *TCPTransport) ParseAddress(address string) (string, error) {
+	_, port, err := net.SplitHostPort(address) // Split address
+
+	if err != nil { // Check for errors
+		return "", err // Return found error
+	}
+
+	if port == "" { // Check for missing port
+		return "", fmt.Errorf("missing port in address %s", address) // Return error
+	}
+
+	return address, nil // Return address
+}
+
+// Dial - open TCP connection to given address
+func (transport *TCPTransport) Dial(address string, timeout time.Duration) (net.Conn, error) {
+	return net.DialTimeout("tcp", address, timeout) // Dial address
+}
+
+// Listen - accept TCP connections on given address (over both IPv4 and IPv6 if the host is empty)
+func (transport *TCPTransport) Listen(address string) (net.Listener, error) {
+	ln, err := transport.listen("tcp", address) // Listen on address (dual-stack where IPv4-mapped IPv6 sockets are supported)
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if host, _, _ := net.SplitHostPort(address); host != "" || !ln.Addr().(*net.TCPAddr).IP.Equal(net.IPv4zero) { // Check listener isn't an IPv4-only wildcard listener
+		return ln, nil // Return listener
+	}
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String()) // Fetch bound port
+
+	ln6, err := transport.listen("tcp6", net.JoinHostPort("::", port)) // Listen on IPv6 separately
+
+	if err != nil { // Check for errors
+		return ln, nil // No IPv6 support, return IPv4 listener
+	}
+
+	return newDualStackListener(ln, ln6), nil // Return listener accepting on both sockets
+}
+
+// DialFrom - open TCP connection to given address from given local port (shared with a listener on that port if transport.ReusePort is set)
+func (transport *TCPTransport) DialFrom(localPort uint, address string, timeout time.Duration) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: timeout, LocalAddr: &net.TCPAddr{Port: int(localPort)}, Control: reusePortControl} // Init dialer
+
+	return dialer.Dial("tcp", address) // Dial address
+}
+
+// Scheme - implement Transport interface
+func (transport *UnixTransport) Scheme() string {
+	return TransportSchemeUnix // Return scheme
+}
+
+// ParseAddress - validate given socket path
+func (transport *UnixTransport) ParseAddress(address string) (string, error) {
+	if address == "" { // Check for nil path
+		return "", errors.New("missing socket path") // Return error
+	}
+
+	return address, nil // Return address
+}
+
+// Dial - open Unix-domain socket connection to given path
+func (transport *UnixTransport) Dial(address string, timeout time.Duration) (net.Conn, error) {
+	return net.DialTimeout("unix", address, timeout) // Dial address
+}
+
+// Listen - accept Unix-domain socket connections on given path (removing stale socket file, if any)
+func (transport *UnixTransport) Listen(address string) (net.Listener, error) {
+	if info, err := os.Stat(address); err == nil && info.Mode()&os.ModeSocket != 0 { // Check for existing socket
+		if conn, err := net.DialTimeout("unix", address, time.Second); err == nil { // Check socket is in use
+			conn.Close() // Close connection
+		} else {
+			os.Remove(address) // Remove stale socket
+		}
+	}
+
+	return net.Listen("unix", address) // Listen on address
+}
+
+// NewMemoryTransport - initialize in-process transport with no listeners
+func NewMemoryTransport() *MemoryTransport {
+	return &MemoryTransport{listeners: make(map[string]*memoryListener)} // Return initialized transport
+}
+
+// Scheme - implement Transport interface
+func (transport *MemoryTransport) Scheme() string {
+	return TransportSchemeMemory // Return scheme
+}
+
+// ParseAddress - validate given in-process address (any non-empty name)
+func (transport *MemoryTransport) ParseAddress(address string) (string, error) {
+	if address == "" { // Check for nil name
+		return "", errors.New("missing memory address") // Return error
+	}
+
+	return address, nil // Return address
+}
+
+// Dial - open in-process connection to listener on given address
+func (transport *MemoryTransport) Dial(address string, timeout time.Duration) (net.Conn, error) {
+	transport.mutex.Lock() // Lock transport
+
+	ln, found := transport.listeners[address] // Fetch listener
+
+	transport.mutex.Unlock() // Unlock transport
+
+	if !found { // Check for no listener
+		return nil, &net.OpError{Op: "dial", Net: TransportSchemeMemory, Addr: memoryAddr(address), Err: errors.New("connection refused")} // Return error
+	}
+
+	client, server := net.Pipe() // Init connection
+
+	timer := time.NewTimer(timeout) // Init timeout
+
+	defer timer.Stop() // Stop timer
+
+	select {
+	case ln.conns <- &memoryConn{Conn: server, local: memoryAddr(address), remote: memoryAddr(address + "-peer")}: // Check accepted
+		return &memoryConn{Conn: client, local: memoryAddr(address + "-peer"), remote: memoryAddr(address)}, nil // Return connection
+	case <-ln.done: // Check listener closed
+		client.Close() // Close connection
+		server.Close() // Close connection
+
+		return nil, ErrTransportClosed // Return error
+	case <-timer.C: // Check timed out
+		client.Close() // Close connection
+		server.Close() // Close connection
+
+		return nil, fmt.Errorf("dial %s timed out", address) // Return error
+	}
+}
+
+// Listen - accept in-process connections on given address
+func (transport *MemoryTransport) Listen(address string) (net.Listener, error) {
+	transport.mutex.Lock() // Lock transport
+
+	defer transport.mutex.Unlock() // Unlock transport
+
+	if _, found := transport.listeners[address]; found { // Check address in use
+		return nil, fmt.Errorf("memory address %s already in use", address) // Return error
+	}
+
+	ln := &memoryListener{
+		address:   address,             // Set address
+		transport: transport,           // Set transport
+		conns:     make(chan net.Conn), // Init connection channel
+		done:      make(chan struct{}), // Init done channel
+	} // Init listener
+
+	transport.listeners[address] = ln // Register listener
+
+	return ln, nil // Return listener
+}
+
+// Accept - implement net.Listener interface
+func (ln *memoryListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-ln.conns: // Check for connection
+		return conn, nil // Return connection
+	case <-ln.done: // Check closed
+		return nil, ErrTransportClosed // Return error
+	}
+}
+
+// Close - implement net.Listener interface
+func (ln *memoryListener) Close() error {
+	ln.closeOnce.Do(func() {
+		ln.transport.mutex.Lock() // Lock transport
+
+		delete(ln.transport.listeners, ln.address) // Unregister listener
+
+		ln.transport.mutex.Unlock() // Unlock transport
+
+		close(ln.done) // Notify pending accepts, dials
+	})
+
+	return nil // No error occurred, return nil
+}
+
+// Addr - implement net.Listener interface
+func (ln *memoryListener) Addr() net.Addr {
+	return memoryAddr(ln.address) // Return address
+}
+
+// Network - implement net.Addr interface
+func (addr memoryAddr) Network() string {
+	return TransportSchemeMemory // Return network
+}
+
+// String - implement net.Addr interface
+func (addr memoryAddr) String() string {
+	return TransportSchemeMemory + transportSchemeSeparator + string(addr) // Return address
+}
+
+// LocalAddr - implement net.Conn interface
+func (conn *memoryConn) LocalAddr() net.Addr {
+	return conn.local // Return local address
+}
+
+// RemoteAddr - implement net.Conn interface
+func (conn *memoryConn) RemoteAddr() net.Addr {
+	return conn.remote // Return remote address
+}
+
+// Accept - implement net.Listener interface
+func (ln *dualStackListener) Accept() (net.Conn, error) {
+	select {
+	case result := <-ln.accepted: // Check for connection
+		return result.conn, result.err // Return connection
+	case <-ln.done: // Check closed
+		return nil, ErrTransportClosed // Return error
+	}
+}
+
+// Close - implement net.Listener interface
+func (ln *dualStackListener) Close() error {
+	var err error // Init error buffer
+
+	ln.closeOnce.Do(func() {
+		close(ln.done) // Notify pending accepts
+
+		for _, listener := range ln.listeners { // Iterate through listeners
+			if closeErr := listener.Close(); closeErr != nil { // Close listener
+				err = closeErr // Set error
+			}
+		}
+	})
+
+	return err // Return error (might be nil)
+}
+
+// Addr - implement net.Listener interface (returns address of IPv4 listener)
+func (ln *dualStackListener) Addr() net.Addr {
+	return ln.listeners[0].Addr() // Return address
+}
+
+/*
+	END EXPORTED METHODS
+*/
+
+/*
+	BEGIN INTERNAL METHODS
+*/
+
+// listen - accept TCP connections on given network, address
+func (transport *TCPTransport) listen(network string, address string) (net.Listener, error) {
+	if transport.ReusePort { // Check port is shared with outgoing connections
+		config := &net.ListenConfig{Control: reusePortControl} // Init listen config
+
+		return config.Listen(context.Background(), network, address) // Listen on address
+	}
+
+	return net.Listen(network, address) // Listen on address
+}
+
+// newDualStackListener - initialize listener accepting connections on each of given listeners
+func newDualStackListener(listeners ...net.Listener) *dualStackListener {
+	ln := &dualStackListener{
+		listeners: listeners,               // Set listeners
+		accepted:  make(chan acceptResult), // Init accepted
+		done:      make(chan struct{}),     // Init done
+	} // Init listener
+
+	for _, listener := range listeners { // Iterate through listeners
+		go ln.acceptFrom(listener) // Accept on listener
+	}
+
+	return ln // Return listener
+}
+
+// acceptFrom - forward connections accepted on given listener until it fails (or the dual-stack listener is closed)
+func (ln *dualStackListener) acceptFrom(listener net.Listener) {
+	for {
+		conn, err := listener.Accept() // Accept connection
+
+		select {
+		case ln.accepted <- acceptResult{conn: conn, err: err}: // Forward connection
+		case <-ln.done: // Check closed
+			if conn != nil { // Check for connection
+				conn.Close() // Close connection
+			}
+
+			return // Stop
+		}
+
+		if netErr, isNetErr := err.(net.Error); err != nil && (!isNetErr || !netErr.Temporary()) { // Check listener failed
+			return // Stop
+		}
+	}
+}
+
+/*
+	END INTERNAL METHODS
+*/
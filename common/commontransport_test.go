@@ -0,0 +1,225 @@
+package common
+
+import (
+	"crypto/rand"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ed25519"
+)
+
+// TestParseTransportAddress - test functionality of ParseTransportAddress() method
+func TestParseTransportAddress(t *testing.T) {
+	for address, scheme := range map[string]string{"1.1.1.1:3000": TransportSchemeTCP, "tcp://[::1]:3000": TransportSchemeTCP, "unix:///tmp/node.sock": TransportSchemeUnix, "memory://node:3000": TransportSchemeMemory} { // Iterate through valid addresses
+		transport, _, err := ParseTransportAddress(address) // Parse address
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if transport.Scheme() != scheme { // Check for mismatch
+			t.Errorf("expected %s transport for address %s, got %s", scheme, address, transport.Scheme()) // Log found error
+			t.FailNow()                                                                                   // Panic
+		}
+	}
+
+	for _, address := range []string{"1.1.1.1", "udp://1.1.1.1:3000", "memory://", "unix://"} { // Iterate through invalid addresses
+		if _, _, err := ParseTransportAddress(address); err == nil { // Parse address
+			t.Errorf("expected address %s to be rejected", address) // Log found error
+			t.FailNow()                                             // Panic
+		}
+	}
+}
+
+// TestMemoryTransport - test handshake over in-process transport
+func TestMemoryTransport(t *testing.T) {
+	testTransport(t, "memory://transport-test:3000") // Test transport
+}
+
+// TestUnixTransport - test handshake over Unix-domain socket transport
+func TestUnixTransport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gop2p") // Init socket directory
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	defer os.RemoveAll(dir) // Remove socket directory
+
+	testTransport(t, "unix://"+filepath.Join(dir, "node.sock")) // Test transport
+}
+
+// TestTCPTransportDialFrom - test dialing from a port shared with a listener
+func TestTCPTransportDialFrom(t *testing.T) {
+	transport := &TCPTransport{ReusePort: true} // Init transport
+
+	shared, err := transport.Listen("127.0.0.1:0") // Listen on any port
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	defer shared.Close() // Stop listening
+
+	remote, err := net.Listen("tcp", "127.0.0.1:0") // Init remote listener
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	defer remote.Close() // Stop listening
+
+	port := shared.Addr().(*net.TCPAddr).Port // Fetch shared port
+
+	conn, err := transport.DialFrom(uint(port), remote.Addr().String(), DefaultDialTimeout) // Dial from shared port
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	defer conn.Close() // Close connection
+
+	if conn.LocalAddr().(*net.TCPAddr).Port != port { // Check connection shares port
+		t.Errorf("expected dial from port %d, found %s", port, conn.LocalAddr()) // Log found error
+		t.FailNow()                                                              // Panic
+	}
+}
+
+// TestTCPTransportDualStack - test listening on all addresses accepts both IPv4, IPv6 connections
+func TestTCPTransportDualStack(t *testing.T) {
+	ln, err := (&TCPTransport{}).Listen(":0") // Listen on random port of all addresses
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	defer ln.Close() // Close listener
+
+	_, port, _ := net.SplitHostPort(ln.Addr().String()) // Fetch port
+
+	go func() {
+		for {
+			conn, err := ln.Accept() // Accept connection
+
+			if err != nil { // Check for errors
+				return // Listener closed
+			}
+
+			conn.Write([]byte{1}) // Write byte
+			conn.Close()          // Close connection
+		}
+	}()
+
+	for _, host := range []string{"127.0.0.1", "::1"} { // Iterate through loopback addresses
+		if host == "::1" { // Check for IPv6
+			if probe, err := net.Listen("tcp6", "[::1]:0"); err != nil { // Check IPv6 is supported
+				continue // Skip IPv6
+			} else {
+				probe.Close() // Close probe
+			}
+		}
+
+		conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, port), time.Second) // Dial listener
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		buffer := make([]byte, 1) // Init read buffer
+
+		conn.SetDeadline(time.Now().Add(time.Second)) // Set deadline
+
+		if _, err = conn.Read(buffer); err != nil { // Read byte
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		conn.Close() // Close connection
+	}
+}
+
+// testTransport - test identity-verified handshake and request over given transport address
+func testTransport(t *testing.T, address string) {
+	publicKey, identity, err := ed25519.GenerateKey(rand.Reader) // Generate server identity
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	cert, err := GenerateNodeCertificate(identity) // Generate server certificate
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	ln, err := ListenAddress(address, ServerTLSConfig(cert)) // Listen on address
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	defer ln.Close() // Close listener
+
+	go func() {
+		for {
+			conn, err := ln.Accept() // Accept connection
+
+			if err != nil { // Check for errors
+				return // Listener closed
+			}
+
+			go func(conn net.Conn) {
+				defer conn.Close() // Close connection
+
+				_, err := ServerHandshake(conn, func(remote *Hello) (*Hello, error) {
+					return &Hello{ProtocolVersion: ProtocolVersion, NodeID: Sha3(publicKey)}, nil // Return server hello
+				}) // Perform handshake
+
+				if err != nil { // Check for errors
+					return // Refused
+				}
+
+				frame, err := ReadFrame(conn) // Read request
+
+				if err == nil { // Check for errors
+					WriteStreamFrame(conn, FrameTypeResponse, frame.StreamID, frame.Payload) // Echo payload
+				}
+			}(conn)
+		}
+	}()
+
+	pool := NewConnectionPool(DefaultPoolIdleTimeout) // Init pool
+
+	defer pool.Close() // Close pool
+
+	result, err := pool.Request(address, Sha3(publicKey), []byte("test")) // Send request, verifying server identity
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if string(result) != "test" { // Check for mismatch
+		t.Errorf("invalid response %s", string(result)) // Log found error
+		t.FailNow()                                     // Panic
+	}
+
+	if _, err = DialAddress(address, PeerTLSConfig(Sha3([]byte("test")))); err == nil { // Dial unexpected peer
+		t.Errorf("expected identity mismatch over %s", address) // Log found error
+		t.FailNow()                                             // Panic
+	}
+}
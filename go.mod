@@ -13,11 +13,10 @@ require (
 	github.com/mattn/go-isatty v0.0.3 // indirect
 	github.com/mitchellh/mapstructure v0.0.0-20180715050151-f15292f7a699
 	github.com/pkg/errors v0.8.1 // indirect
-	github.com/tatsushid/go-fastping v0.0.0-20160109021039-d7bb493dee3e
 	github.com/twitchtv/twirp v5.4.2+incompatible
 	golang.org/x/crypto v0.0.0-20180802221240-56440b844dfe
-	golang.org/x/net v0.0.0-20180801234040-f4c29de78a2a // indirect
+	golang.org/x/net v0.0.0-20180801234040-f4c29de78a2a
 	golang.org/x/sync v0.0.0-20190227155943-e225da77a7e6 // indirect
-	golang.org/x/sys v0.0.0-20180802203216-0ffbfd41fbef // indirect
+	golang.org/x/sys v0.0.0-20180802203216-0ffbfd41fbef
 	golang.org/x/text v0.3.0
 )
@@ -7,7 +7,7 @@ import (
 
 // SendToAddress - common.SendBytes() wrapper
 func (protoMessage *ProtobufMessage) SendToAddress(address string) error {
-	serialized, err := protoMessage.ToBytes() // Serialize to bytes
+	serialized, err := protoMessage.seal() // Serialize to bytes
 
 	if err != nil { // Check for errors
 		return err // Return found error
@@ -18,7 +18,7 @@ func (protoMessage *ProtobufMessage) SendToAddress(address string) error {
 
 // SendToAddressResult - common.SendBytesResult() wrapper
 func (protoMessage *ProtobufMessage) SendToAddressResult(address string) ([]byte, error) {
-	serialized, err := protoMessage.ToBytes() // Serialize to bytes
+	serialized, err := protoMessage.seal() // Serialize to bytes
 
 	if err != nil { // Check for errors
 		return []byte{}, err // Return found error
@@ -35,7 +35,7 @@ func (protoMessage *ProtobufMessage) SendToShard(shardAddress string, port int)
 		return err // Return found error
 	}
 
-	return shard.SendBytesShard(serialized, shardAddress, port) // Send to address
+	return shard.SendBytesShard(common.EnvelopeKindProtobuf, serialized, shardAddress, port) // Send to address
 }
 
 // SendToShardResult - shard.SendBytesShardResult() wrapper
@@ -46,5 +46,16 @@ func (protoMessage *ProtobufMessage) SendToShardResult(shardAddress string, port
 		return []byte{}, err // Return found error
 	}
 
-	return shard.SendBytesShardResult(serialized, shardAddress, port) // Send to address
+	return shard.SendBytesShardResult(common.EnvelopeKindProtobuf, serialized, shardAddress, port) // Send to address
+}
+
+// seal - serialize protobuf message, wrapping it in a protobuf envelope
+func (protoMessage *ProtobufMessage) seal() ([]byte, error) {
+	serialized, err := protoMessage.ToBytes() // Serialize to bytes
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return common.Seal(common.LocalCodec(), common.EnvelopeKindProtobuf, serialized) // Wrap in envelope (dialing peer selects codec)
 }
@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 
@@ -59,7 +60,7 @@ func (server *Server) NewDatabase(ctx context.Context, req *databaseProto.Genera
 	return &databaseProto.GeneralResponse{Message: fmt.Sprintf("\n%s", string(marshaledVal))}, nil // Return response
 }
 
-// AddNode - database.AddNode RPC handler
+// AddNode - database.AddNode RPC handler (adds node at request address if specified, e.g. /ip4/1.2.3.4/tcp/3000/tls/p2p/<NodeID>, local node otherwise)
 func (server *Server) AddNode(ctx context.Context, req *databaseProto.GeneralRequest) (*databaseProto.GeneralResponse, error) {
 	currentDir, err := common.GetCurrentDir() // Fetch current dir
 
@@ -67,39 +68,57 @@ func (server *Server) AddNode(ctx context.Context, req *databaseProto.GeneralReq
 		return &databaseProto.GeneralResponse{}, err // Return found error
 	}
 
-	env, err := getLocalEnvironment(currentDir) // Attempt to read environment from current directory
+	localNode, err := getLocalNode(currentDir) // Fetch local node
 
 	if err != nil { // Check for errors
 		return &databaseProto.GeneralResponse{}, err // Return found error
 	}
 
-	database, err := database.ReadDatabaseFromMemory(env, req.NetworkName) // Attempt to read database from environment
+	destNode := localNode // Init node to add
 
-	if err != nil { // Check for errors
-		return &databaseProto.GeneralResponse{}, err // Return found error
+	if req.Address != "" { // Check for remote node
+		destNode, err = node.NodeFromAddress(req.Address) // Init node at address
+
+		if err != nil { // Check for errors
+			return &databaseProto.GeneralResponse{}, err // Return found error
+		}
 	}
 
-	node, err := getLocalNode(currentDir) // Fetch local node
+	addedNode := *destNode // Init added node buffer (kept if node was cached as a routing table replacement)
 
-	if err != nil { // Check for errors
-		return &databaseProto.GeneralResponse{}, err // Return found error
-	}
+	err = database.UpdateInMemory(localNode.Environment, req.NetworkName, func(db *database.NodeDatabase) error {
+		db.SetLocalNode(localNode) // Record addition on behalf of local node
 
-	err = database.WriteToMemory(env) // Write to environment memory
+		err := db.AddNode(destNode) // Add node to database
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		query := destNode.NodeID // Look up node by NodeID
+
+		if query == "" { // Check for anonymous node
+			query = destNode.Address // Look up node by address
+		}
+
+		if index, err := db.QueryForAddress(query); err == nil { // Fetch index of added (or updated) node
+			addedNode = (*db.Nodes)[index] // Set added node
+		}
+
+		return nil // Write db
+	}) // Add node to database in environment memory
 
 	if err != nil { // Check for errors
 		return &databaseProto.GeneralResponse{}, err // Return found error
 	}
 
-	err = node.WriteToMemory(currentDir) // Write node to memory
+	err = localNode.WriteToMemory(currentDir) // Write node to memory
 
 	if err != nil { // Check for errors
 		return &databaseProto.GeneralResponse{}, err // Return found error
 	}
 
-	database.AddNode(node) // Add node to database
-
-	marshaledVal, err := json.Marshal((*database.Nodes)[len(*database.Nodes)-1]) // Marshal added node
+	marshaledVal, err := json.Marshal(addedNode) // Marshal added node
 
 	if err != nil { // Check for errors
 		return &databaseProto.GeneralResponse{}, err // Return found error
@@ -124,31 +143,23 @@ func (server *Server) RemoveNode(ctx context.Context, req *databaseProto.General
 		return &databaseProto.GeneralResponse{}, err // Return found error
 	}
 
-	node, env, err := getLocalNodeEnvironment(currentDir) // Attempt to read environment from current directory
-
-	if err != nil { // Check for errors
-		return &databaseProto.GeneralResponse{}, err // Return found error
-	}
-
-	database, err := database.ReadDatabaseFromMemory(env, req.NetworkName) // Attempt to read database from environment
+	localNode, err := getLocalNode(currentDir) // Fetch local node
 
 	if err != nil { // Check for errors
 		return &databaseProto.GeneralResponse{}, err // Return found error
 	}
 
-	err = database.RemoveNode(req.Address) // Add node to database
+	err = database.UpdateInMemory(localNode.Environment, req.NetworkName, func(db *database.NodeDatabase) error {
+		db.SetLocalNode(localNode) // Record removal on behalf of local node
 
-	if err != nil { // Check for errors
-		return &databaseProto.GeneralResponse{}, err // Return found error
-	}
-
-	err = database.WriteToMemory(env) // Write to environment memory
+		return db.RemoveNode(req.Address) // Remove node from database
+	}) // Remove node from database in environment memory
 
 	if err != nil { // Check for errors
 		return &databaseProto.GeneralResponse{}, err // Return found error
 	}
 
-	err = node.WriteToMemory(currentDir) // Write node to memory
+	err = localNode.WriteToMemory(currentDir) // Write node to memory
 
 	if err != nil { // Check for errors
 		return &databaseProto.GeneralResponse{}, err // Return found error
@@ -272,21 +283,25 @@ func (server *Server) UpdateRemoteDatabase(ctx context.Context, req *databasePro
 		return &databaseProto.GeneralResponse{}, err // Return found error
 	}
 
-	env, err := environment.ReadEnvironmentFromMemory(currentDir) // Attempt to read environment from request path
+	localNode, err := getLocalNode(currentDir) // Fetch local node
 
 	if err != nil { // Check for errors
 		return &databaseProto.GeneralResponse{}, err // Return found error
 	}
 
-	database, err := database.ReadDatabaseFromMemory(env, req.NetworkName) // Attempt to read database from environment
+	err = (&database.NodeDatabase{NetworkAlias: req.NetworkName}).AntiEntropyInMemory(localNode, database.DefaultReplicationPort, database.DefaultAntiEntropyFanout) // Synchronize database in environment memory with remote database instances
 
-	if err != nil { // Check for errors
+	if err != nil && err != database.ErrNoContacts && err != database.ErrNotReplicated { // Check for errors (nothing to synchronize without remote instances, in DHT mode)
 		return &databaseProto.GeneralResponse{}, err // Return found error
 	}
 
-	database.UpdateRemoteDatabase() // Update remote database instances
+	err = localNode.WriteToMemory(currentDir) // Write node to memory
+
+	if err != nil { // Check for errors
+		return &databaseProto.GeneralResponse{}, err // Return found error
+	}
 
-	return &databaseProto.GeneralResponse{Message: fmt.Sprintf("\nUpdating instances of database with network alias %s and ID %s", database.NetworkAlias, strconv.Itoa(int(database.NetworkID)))}, nil // Return response
+	return &databaseProto.GeneralResponse{Message: fmt.Sprintf("\nUpdated instances of database with network alias %s", req.NetworkName)}, nil // Return response
 }
 
 // JoinDatabase - database.JoinDatabase RPC handler
@@ -418,6 +433,169 @@ func (server *Server) FromBytes(ctx context.Context, req *databaseProto.GeneralR
 	return &databaseProto.GeneralResponse{Message: fmt.Sprintf("\n%s", string(marshaledVal))}, nil // Return response
 }
 
+// Put - database.Put RPC handler
+func (server *Server) Put(ctx context.Context, req *databaseProto.GeneralRequest) (*databaseProto.GeneralResponse, error) {
+	if len(req.StringVals) != 1 { // Check for invalid key
+		return &databaseProto.GeneralResponse{}, errors.New("invalid key") // Return error
+	}
+
+	currentDir, node, db, err := getLocalDatabase(req.NetworkName) // Fetch local node, database
+
+	if err != nil { // Check for errors
+		return &databaseProto.GeneralResponse{}, err // Return found error
+	}
+
+	err = db.Put(node, req.StringVals[0], req.ByteVal, uint(req.Port)) // Store value
+
+	if err != nil { // Check for errors
+		return &databaseProto.GeneralResponse{}, err // Return found error
+	}
+
+	err = writeLocalDatabase(currentDir, node, db) // Write database, records
+
+	if err != nil { // Check for errors
+		return &databaseProto.GeneralResponse{}, err // Return found error
+	}
+
+	return &databaseProto.GeneralResponse{Message: fmt.Sprintf("\nStored %d bytes with key %s", len(req.ByteVal), req.StringVals[0])}, nil // Return response
+}
+
+// Get - database.Get RPC handler
+func (server *Server) Get(ctx context.Context, req *databaseProto.GeneralRequest) (*databaseProto.GeneralResponse, error) {
+	if len(req.StringVals) != 1 { // Check for invalid key
+		return &databaseProto.GeneralResponse{}, errors.New("invalid key") // Return error
+	}
+
+	currentDir, node, db, err := getLocalDatabase(req.NetworkName) // Fetch local node, database
+
+	if err != nil { // Check for errors
+		return &databaseProto.GeneralResponse{}, err // Return found error
+	}
+
+	value, err := db.Get(node, req.StringVals[0], uint(req.Port)) // Fetch value
+
+	if err != nil { // Check for errors
+		return &databaseProto.GeneralResponse{}, err // Return found error
+	}
+
+	err = writeLocalDatabase(currentDir, node, db) // Write database
+
+	if err != nil { // Check for errors
+		return &databaseProto.GeneralResponse{}, err // Return found error
+	}
+
+	return &databaseProto.GeneralResponse{Message: fmt.Sprintf("\n%s", string(value))}, nil // Return response
+}
+
+// Provide - database.Provide RPC handler
+func (server *Server) Provide(ctx context.Context, req *databaseProto.GeneralRequest) (*databaseProto.GeneralResponse, error) {
+	if len(req.StringVals) != 1 { // Check for invalid key
+		return &databaseProto.GeneralResponse{}, errors.New("invalid key") // Return error
+	}
+
+	currentDir, node, db, err := getLocalDatabase(req.NetworkName) // Fetch local node, database
+
+	if err != nil { // Check for errors
+		return &databaseProto.GeneralResponse{}, err // Return found error
+	}
+
+	err = db.Provide(node, req.StringVals[0], uint(req.Port)) // Advertise local node
+
+	if err != nil { // Check for errors
+		return &databaseProto.GeneralResponse{}, err // Return found error
+	}
+
+	err = writeLocalDatabase(currentDir, node, db) // Write database, records
+
+	if err != nil { // Check for errors
+		return &databaseProto.GeneralResponse{}, err // Return found error
+	}
+
+	return &databaseProto.GeneralResponse{Message: fmt.Sprintf("\nProviding key %s", req.StringVals[0])}, nil // Return response
+}
+
+// FindProviders - database.FindProviders RPC handler
+func (server *Server) FindProviders(ctx context.Context, req *databaseProto.GeneralRequest) (*databaseProto.GeneralResponse, error) {
+	if len(req.StringVals) != 1 { // Check for invalid key
+		return &databaseProto.GeneralResponse{}, errors.New("invalid key") // Return error
+	}
+
+	currentDir, node, db, err := getLocalDatabase(req.NetworkName) // Fetch local node, database
+
+	if err != nil { // Check for errors
+		return &databaseProto.GeneralResponse{}, err // Return found error
+	}
+
+	providers, err := db.FindProviders(node, req.StringVals[0], uint(req.Port)) // Fetch providers
+
+	if err != nil { // Check for errors
+		return &databaseProto.GeneralResponse{}, err // Return found error
+	}
+
+	err = writeLocalDatabase(currentDir, node, db) // Write database
+
+	if err != nil { // Check for errors
+		return &databaseProto.GeneralResponse{}, err // Return found error
+	}
+
+	marshaledVal, err := json.MarshalIndent(providers, "", "  ") // Marshal providers
+
+	if err != nil { // Check for errors
+		return &databaseProto.GeneralResponse{}, err // Return found error
+	}
+
+	return &databaseProto.GeneralResponse{Message: fmt.Sprintf("\n%s", string(marshaledVal))}, nil // Return response
+}
+
+// Ping - database.PingPeer, database.PingPeers RPC handler (pings every peer if no address is given)
+func (server *Server) Ping(ctx context.Context, req *databaseProto.GeneralRequest) (*databaseProto.GeneralResponse, error) {
+	currentDir, localNode, db, err := getLocalDatabase(req.NetworkName) // Fetch local node, database
+
+	if err != nil { // Check for errors
+		return &databaseProto.GeneralResponse{}, err // Return found error
+	}
+
+	results := []database.PingResult{} // Init results buffer
+
+	if req.Address == "" { // Check for no address
+		results = db.PingPeers(localNode, uint(req.Port)) // Ping every peer
+	} else {
+		peer := &node.Node{Address: req.Address} // Init peer
+
+		if nodeIndex, err := db.QueryForAddress(req.Address); err == nil { // Check peer is known
+			peer = &(*db.Nodes)[nodeIndex] // Set peer
+		}
+
+		result, err := db.PingPeer(localNode, peer, uint(req.Port)) // Ping peer
+
+		if err != nil { // Check for errors
+			writeLocalDatabase(currentDir, localNode, db) // Write failed ping
+
+			return &databaseProto.GeneralResponse{}, err // Return found error
+		}
+
+		results = append(results, *result) // Append result
+	}
+
+	err = writeLocalDatabase(currentDir, localNode, db) // Write database
+
+	if err != nil { // Check for errors
+		return &databaseProto.GeneralResponse{}, err // Return found error
+	}
+
+	message := "" // Init message buffer
+
+	for _, result := range results { // Iterate through results
+		message += "\n" + result.String() // Append result
+	}
+
+	if req.Address == "" { // Check pinged every peer
+		message += fmt.Sprintf("\n%d of %d peers alive", len(results), len(*db.Nodes)) // Append summary
+	}
+
+	return &databaseProto.GeneralResponse{Message: message}, nil // Return response
+}
+
 /* END EXPORTED METHODS */
 
 /* BEGIN INTERNAL METHODS */
@@ -447,6 +625,40 @@ func getLocalNodeEnvironment(path string) (*node.Node, *environment.Environment,
 	return node, node.Environment, nil // No error occurred, return found environment
 }
 
+// getLocalDatabase - fetch working directory, local node and its database with given network alias
+func getLocalDatabase(networkName string) (string, *node.Node, *database.NodeDatabase, error) {
+	currentDir, err := common.GetCurrentDir() // Fetch current dir
+
+	if err != nil { // Check for errors
+		return "", nil, nil, err // Return found error
+	}
+
+	node, err := node.ReadNodeFromMemory(currentDir) // Read node from memory
+
+	if err != nil { // Check for errors
+		return "", nil, nil, err // Return found error
+	}
+
+	db, err := database.ReadDatabaseFromMemory(node.Environment, networkName) // Read database from environment
+
+	if err != nil { // Check for errors
+		return "", nil, nil, err // Return found error
+	}
+
+	return currentDir, node, db, nil // Return node, database
+}
+
+// writeLocalDatabase - write database to local node environment, writing node to given path
+func writeLocalDatabase(path string, node *node.Node, db *database.NodeDatabase) error {
+	err := db.WriteToMemory(node.Environment) // Write database to environment
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	return node.WriteToMemory(path) // Write node
+}
+
 func getLocalEnvironment(path string) (*environment.Environment, error) {
 	node, err := node.ReadNodeFromMemory(path) // Read node from memory
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"sync"
 
 	"github.com/dowlandaiello/GoP2P/common"
 	handlerProto "github.com/dowlandaiello/GoP2P/internal/rpc/proto/handler"
@@ -13,7 +14,11 @@ import (
 )
 
 // Server - GoP2P RPC server
-type Server struct{}
+type Server struct {
+	handlers map[uint32]*handler.Handler // handlers - handlers started via RPC, by port
+
+	mutex sync.Mutex // mutex - guards handlers
+}
 
 // StartHandler - handler.StartHandler RPC handler
 func (server *Server) StartHandler(ctx context.Context, req *handlerProto.GeneralRequest) (*handlerProto.GeneralResponse, error) {
@@ -29,13 +34,108 @@ func (server *Server) StartHandler(ctx context.Context, req *handlerProto.Genera
 		return &handlerProto.GeneralResponse{}, errors.New("Node not attached") // Return found error
 	}
 
-	listener, err := node.StartListener(int(req.Port)) // Start Listener on specified port
+	server.mutex.Lock() // Lock server
+
+	defer server.mutex.Unlock() // Unlock server
+
+	if foundHandler, found := server.handlers[req.Port]; found && foundHandler.IsRunning() { // Check for running handler
+		return &handlerProto.GeneralResponse{}, fmt.Errorf("handler already running on port %s", strconv.Itoa(int(req.Port))) // Return error
+	}
+
+	nodeHandler, err := handler.NewHandler(node, currentDir, ":"+strconv.Itoa(int(req.Port))) // Init handler on specified port
 
 	if err != nil { // Check for errors
 		return &handlerProto.GeneralResponse{}, err // Return found error
 	}
 
-	go handler.StartHandler(node, listener) // Start node handler on port
+	err = start(nodeHandler) // Start handler
+
+	if err != nil { // Check for errors
+		return &handlerProto.GeneralResponse{}, err // Return found error
+	}
+
+	if server.handlers == nil { // Check for nil handlers
+		server.handlers = make(map[uint32]*handler.Handler) // Init handlers
+	}
+
+	server.handlers[req.Port] = nodeHandler // Set handler
 
 	return &handlerProto.GeneralResponse{Message: fmt.Sprintf("\nStarted handler with host :%s", strconv.Itoa(int(req.Port)))}, nil // Return response
 }
+
+// StopHandler - handler.Shutdown RPC handler
+func (server *Server) StopHandler(ctx context.Context, req *handlerProto.GeneralRequest) (*handlerProto.GeneralResponse, error) {
+	nodeHandler, err := server.handler(req.Port) // Fetch handler
+
+	if err != nil { // Check for errors
+		return &handlerProto.GeneralResponse{}, err // Return found error
+	}
+
+	err = shutdown(ctx, nodeHandler) // Shut down handler
+
+	if err != nil { // Check for errors
+		return &handlerProto.GeneralResponse{}, err // Return found error
+	}
+
+	return &handlerProto.GeneralResponse{Message: fmt.Sprintf("\nStopped handler with host :%s", strconv.Itoa(int(req.Port)))}, nil // Return response
+}
+
+// RestartHandler - handler.Shutdown, handler.Start RPC handler
+func (server *Server) RestartHandler(ctx context.Context, req *handlerProto.GeneralRequest) (*handlerProto.GeneralResponse, error) {
+	nodeHandler, err := server.handler(req.Port) // Fetch handler
+
+	if err != nil { // Check for errors
+		return &handlerProto.GeneralResponse{}, err // Return found error
+	}
+
+	err = shutdown(ctx, nodeHandler) // Shut down handler
+
+	if err != nil { // Check for errors
+		return &handlerProto.GeneralResponse{}, err // Return found error
+	}
+
+	err = start(nodeHandler) // Start handler
+
+	if err != nil { // Check for errors
+		return &handlerProto.GeneralResponse{}, err // Return found error
+	}
+
+	return &handlerProto.GeneralResponse{Message: fmt.Sprintf("\nRestarted handler with host :%s", strconv.Itoa(int(req.Port)))}, nil // Return response
+}
+
+// handler - fetch handler started on given port
+func (server *Server) handler(port uint32) (*handler.Handler, error) {
+	server.mutex.Lock() // Lock server
+
+	defer server.mutex.Unlock() // Unlock server
+
+	foundHandler, found := server.handlers[port] // Fetch handler
+
+	if !found { // Check for nil handler
+		return nil, fmt.Errorf("no handler started on port %s", strconv.Itoa(int(port))) // Return error
+	}
+
+	return foundHandler, nil // Return handler
+}
+
+// start - listen on handler addresses, accepting connections in the background
+func start(nodeHandler *handler.Handler) error {
+	err := nodeHandler.Listen() // Listen on handler addresses
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	go nodeHandler.Start(context.Background()) // Start node handler
+
+	return nil // No error occurred, return nil
+}
+
+// shutdown - gracefully shut down handler within handler.DefaultShutdownTimeout
+func shutdown(ctx context.Context, nodeHandler *handler.Handler) error {
+	ctx, cancel := context.WithTimeout(ctx, handler.DefaultShutdownTimeout) // Init shutdown deadline
+
+	defer cancel() // Release deadline
+
+	return nodeHandler.Shutdown(ctx) // Shut down handler
+}
@@ -32,7 +32,7 @@ func (server *Server) NewNode(ctx context.Context, req *nodeProto.GeneralRequest
 		return &nodeProto.GeneralResponse{}, err // Return found error
 	}
 
-	go upnp.ForwardPortSilent(3000) // Forward node port
+	go upnp.DefaultManager.Forward(3000) // Forward node port, renewing mapping until removed
 
 	currentDir, err := common.GetCurrentDir() // Fetch working directory
 
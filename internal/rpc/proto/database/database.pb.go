@@ -178,32 +178,34 @@ func init() {
 func init() { proto.RegisterFile("database.proto", fileDescriptor_b90fe3356ea5df07) }
 
 var fileDescriptor_b90fe3356ea5df07 = []byte{
-	// 420 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x54, 0x4d, 0x6f, 0xd3, 0x40,
-	0x10, 0xc5, 0x6d, 0x49, 0xe2, 0x49, 0x9b, 0x8a, 0x2d, 0x87, 0xa5, 0x42, 0xc8, 0xca, 0x29, 0x12,
-	0xa8, 0x07, 0xf8, 0x03, 0xb4, 0x18, 0x97, 0x8f, 0x24, 0x02, 0x53, 0xca, 0x79, 0x9d, 0x1d, 0xa5,
-	0x16, 0xb6, 0xd7, 0xec, 0x8e, 0x5b, 0xf9, 0xbf, 0xf1, 0x9b, 0xf8, 0x0d, 0x68, 0xb7, 0xb6, 0x5b,
-	0x04, 0x27, 0x9b, 0x9b, 0xdf, 0xbc, 0x79, 0x6f, 0x47, 0xf3, 0x46, 0x86, 0x99, 0x14, 0x24, 0x12,
-	0x61, 0xf0, 0xa4, 0xd4, 0x8a, 0x14, 0x9b, 0xb4, 0x78, 0xfe, 0x73, 0x07, 0x66, 0xe7, 0x58, 0xa0,
-	0x16, 0x59, 0x8c, 0x3f, 0x2a, 0x34, 0xc4, 0x8e, 0xc1, 0xd1, 0x9f, 0x04, 0x5d, 0x71, 0x2f, 0xf0,
-	0x16, 0x7e, 0xdc, 0x61, 0x16, 0xc0, 0xb4, 0x40, 0xba, 0x51, 0xfa, 0xfb, 0x5a, 0xe4, 0xc8, 0x77,
-	0x1c, 0x7d, 0xbf, 0xc4, 0x9e, 0x82, 0xdf, 0xc0, 0xf7, 0x21, 0xdf, 0x0d, 0xbc, 0xc5, 0x41, 0x7c,
-	0x57, 0x60, 0x2f, 0xe0, 0x91, 0xd8, 0x6c, 0xb0, 0x24, 0x91, 0x64, 0x78, 0x91, 0xe6, 0xa8, 0x2a,
-	0xe2, 0x7b, 0xae, 0xeb, 0x6f, 0x82, 0x31, 0xd8, 0x2b, 0x95, 0x26, 0xfe, 0xd0, 0x35, 0xb8, 0x6f,
-	0xc6, 0x61, 0x2c, 0xa4, 0xd4, 0x68, 0x0c, 0x1f, 0xb9, 0xd7, 0x5b, 0xc8, 0x9e, 0x01, 0x94, 0x3a,
-	0xbd, 0x16, 0x84, 0x1f, 0xb1, 0xe6, 0x63, 0x47, 0xde, 0xab, 0x58, 0x65, 0x52, 0x13, 0x5e, 0x8a,
-	0x8c, 0x4f, 0x02, 0x6f, 0xb1, 0x1f, 0xb7, 0xd0, 0x2a, 0x0d, 0xe9, 0xb4, 0xd8, 0x5e, 0x8a, 0xcc,
-	0x70, 0x3f, 0xd8, 0xb5, 0xca, 0xbb, 0x8a, 0x55, 0x56, 0x69, 0x41, 0x56, 0x09, 0x6e, 0x94, 0x16,
-	0xce, 0x9f, 0xc3, 0x61, 0xb7, 0x3d, 0x53, 0xaa, 0xc2, 0xa0, 0x6d, 0xce, 0xd1, 0x18, 0xb1, 0xc5,
-	0x66, 0x7b, 0x2d, 0x7c, 0xf9, 0x6b, 0x04, 0x93, 0xb0, 0x59, 0x3c, 0x0b, 0x61, 0xba, 0xc6, 0x9b,
-	0x0e, 0xf2, 0x93, 0x2e, 0xa2, 0x3f, 0xe3, 0x38, 0x7e, 0xf2, 0x0f, 0xe6, 0xf6, 0xa9, 0xf9, 0x03,
-	0xf6, 0x1a, 0xc6, 0xa7, 0x52, 0xae, 0x95, 0xec, 0xed, 0xf0, 0x06, 0x20, 0xc6, 0x5c, 0x5d, 0xe3,
-	0x10, 0x93, 0x77, 0x70, 0xf8, 0xb9, 0x42, 0x5d, 0x47, 0x4a, 0x9f, 0x36, 0x69, 0xf4, 0x74, 0x8a,
-	0xe0, 0xe0, 0x9b, 0x4e, 0x09, 0x2f, 0xd4, 0x0a, 0x73, 0xa5, 0xeb, 0xbe, 0x3e, 0xe7, 0x30, 0x8b,
-	0x51, 0xc8, 0x48, 0xab, 0x7c, 0x98, 0xd1, 0x0a, 0x1e, 0x7f, 0x2d, 0xa5, 0x20, 0xb4, 0x5b, 0x22,
-	0x1c, 0x1a, 0xd8, 0x5b, 0xd8, 0xff, 0xa0, 0xd2, 0x62, 0xa8, 0xcd, 0x12, 0x8e, 0x22, 0xa4, 0xcd,
-	0xd5, 0xff, 0x19, 0x6a, 0x09, 0x47, 0x5f, 0xb0, 0x90, 0xad, 0xcd, 0xea, 0xf6, 0x5e, 0xfb, 0xba,
-	0x85, 0x30, 0x5d, 0xaa, 0xed, 0xd0, 0x99, 0xce, 0xc0, 0xb7, 0xe1, 0x9d, 0xd5, 0x84, 0x7d, 0x8f,
-	0x29, 0x19, 0xb9, 0xbf, 0xdd, 0xab, 0xdf, 0x01, 0x00, 0x00, 0xff, 0xff, 0xd9, 0x34, 0x89, 0x57,
-	0xff, 0x04, 0x00, 0x00,
+	// 454 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x55, 0x4f, 0x6f, 0xd3, 0x4e,
+	0x10, 0xfd, 0xa5, 0xc9, 0xaf, 0x89, 0x27, 0x4d, 0x2a, 0xb6, 0x1c, 0x96, 0x0a, 0x21, 0x2b, 0xa7,
+	0x48, 0xa0, 0x1e, 0xe0, 0x0a, 0x12, 0x2d, 0xc1, 0xe1, 0x4f, 0x12, 0x05, 0x53, 0xca, 0x79, 0x93,
+	0x1d, 0xa5, 0x2b, 0xe2, 0x5d, 0xb3, 0x3b, 0x4e, 0xe5, 0xcf, 0xc5, 0x95, 0x0f, 0x87, 0xbc, 0xb5,
+	0xd3, 0x22, 0x38, 0xd9, 0xdc, 0xfc, 0x66, 0xe6, 0xbd, 0x1d, 0xbd, 0xb7, 0x2b, 0xc3, 0x50, 0x0a,
+	0x12, 0x2b, 0xe1, 0xf0, 0x2c, 0xb5, 0x86, 0x0c, 0xeb, 0x55, 0x78, 0xf4, 0xf3, 0x00, 0x86, 0x53,
+	0xd4, 0x68, 0xc5, 0x36, 0xc6, 0xef, 0x19, 0x3a, 0x62, 0xa7, 0xe0, 0xdb, 0x4b, 0x41, 0xd7, 0xbc,
+	0x15, 0xb6, 0xc6, 0x41, 0xbc, 0xc7, 0x2c, 0x84, 0xbe, 0x46, 0xba, 0x31, 0xf6, 0xdb, 0x42, 0x24,
+	0xc8, 0x0f, 0x7c, 0xfb, 0x7e, 0x89, 0x3d, 0x86, 0xa0, 0x84, 0xef, 0x27, 0xbc, 0x1d, 0xb6, 0xc6,
+	0x83, 0xf8, 0xae, 0xc0, 0x9e, 0xc1, 0x03, 0xb1, 0x5e, 0x63, 0x4a, 0x62, 0xb5, 0xc5, 0x4b, 0x95,
+	0xa0, 0xc9, 0x88, 0x77, 0xfc, 0xd4, 0x9f, 0x0d, 0xc6, 0xa0, 0x93, 0x1a, 0x4b, 0xfc, 0x7f, 0x3f,
+	0xe0, 0xbf, 0x19, 0x87, 0xae, 0x90, 0xd2, 0xa2, 0x73, 0xfc, 0xd0, 0x9f, 0x5e, 0x41, 0xf6, 0x04,
+	0x20, 0xb5, 0x6a, 0x27, 0x08, 0x3f, 0x62, 0xce, 0xbb, 0xbe, 0x79, 0xaf, 0x52, 0x30, 0x57, 0x39,
+	0xe1, 0x95, 0xd8, 0xf2, 0x5e, 0xd8, 0x1a, 0x1f, 0xc5, 0x15, 0x2c, 0x98, 0x8e, 0xac, 0xd2, 0x9b,
+	0x2b, 0xb1, 0x75, 0x3c, 0x08, 0xdb, 0x05, 0xf3, 0xae, 0x52, 0x30, 0x33, 0xa5, 0xa9, 0x60, 0x82,
+	0x5f, 0xa5, 0x82, 0xa3, 0xa7, 0x70, 0xbc, 0x77, 0xcf, 0xa5, 0x46, 0x3b, 0x2c, 0x86, 0x13, 0x74,
+	0x4e, 0x6c, 0xb0, 0x74, 0xaf, 0x82, 0xcf, 0x7f, 0x04, 0xd0, 0x9b, 0x94, 0xc6, 0xb3, 0x09, 0xf4,
+	0x17, 0x78, 0xb3, 0x87, 0xfc, 0x6c, 0x1f, 0xd1, 0xef, 0x71, 0x9c, 0x3e, 0xfa, 0x4b, 0xe7, 0xf6,
+	0xa8, 0xd1, 0x7f, 0xec, 0x35, 0x74, 0xcf, 0xa5, 0x5c, 0x18, 0x59, 0x5b, 0xe1, 0x0d, 0x40, 0x8c,
+	0x89, 0xd9, 0x61, 0x13, 0x91, 0x77, 0x70, 0xfc, 0x29, 0x43, 0x9b, 0x47, 0xc6, 0x9e, 0x97, 0x69,
+	0xd4, 0x54, 0x8a, 0x60, 0xf0, 0xd5, 0x2a, 0xc2, 0x4b, 0x33, 0xc7, 0xc4, 0xd8, 0xbc, 0xae, 0xce,
+	0x14, 0x86, 0x31, 0x0a, 0x19, 0x59, 0x93, 0x34, 0x13, 0x9a, 0xc3, 0xc3, 0x2f, 0xa9, 0x14, 0x84,
+	0x85, 0x4b, 0x84, 0x4d, 0x03, 0x7b, 0x0b, 0x47, 0x1f, 0x8c, 0xd2, 0x4d, 0x65, 0x66, 0x70, 0x12,
+	0x21, 0xad, 0xaf, 0xff, 0xcd, 0x52, 0x33, 0x38, 0xf9, 0x8c, 0x5a, 0x56, 0x32, 0xf3, 0xdb, 0xfb,
+	0x5a, 0x57, 0x6d, 0x02, 0xfd, 0x99, 0xd9, 0x34, 0xdd, 0xe9, 0x02, 0x82, 0x22, 0xbc, 0x8b, 0x9c,
+	0xb0, 0xf6, 0x65, 0x7a, 0x09, 0xed, 0x65, 0x46, 0x0d, 0xd8, 0x53, 0xa4, 0x06, 0x2f, 0x73, 0x69,
+	0xcd, 0x4e, 0xd5, 0x7f, 0x54, 0x11, 0x0c, 0x22, 0xa5, 0x65, 0xa9, 0x62, 0x6b, 0xbb, 0xf0, 0x0a,
+	0x3a, 0x4b, 0xa5, 0x37, 0x35, 0xe9, 0xab, 0x43, 0xff, 0xcb, 0x78, 0xf1, 0x6b, 0x00, 0xa4, 0xeb,
+	0xbd, 0x35, 0x44, 0x06, 0x00, 0x00,
 }
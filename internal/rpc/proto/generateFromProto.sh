@@ -1 +1,2 @@
-protoc --proto_path=$GOPATH/src:../../types/handler --twirp_out=. --go_out=. ../../types/handler/handler.proto
\ No newline at end of file
+protoc --proto_path=$GOPATH/src:../../types/handler --twirp_out=. --go_out=. ../../types/handler/handler.proto
+protoc --proto_path=$GOPATH/src:../../types/connection --go_out=./wire ../../types/connection/wire.proto
\ No newline at end of file
@@ -106,14 +106,15 @@ func init() {
 func init() { proto.RegisterFile("handler.proto", fileDescriptor_515968b8e1a22554) }
 
 var fileDescriptor_515968b8e1a22554 = []byte{
-	// 143 bytes of a gzipped FileDescriptorProto
+	// 160 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0xcd, 0x48, 0xcc, 0x4b,
 	0xc9, 0x49, 0x2d, 0xd2, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x05, 0x53, 0x4a, 0x2a, 0x5c,
 	0x7c, 0xee, 0xa9, 0x79, 0xa9, 0x45, 0x89, 0x39, 0x41, 0xa9, 0x85, 0xa5, 0xa9, 0xc5, 0x25, 0x42,
 	0x42, 0x5c, 0x2c, 0x05, 0xf9, 0x45, 0x25, 0x12, 0x8c, 0x0a, 0x8c, 0x1a, 0xbc, 0x41, 0x60, 0xb6,
 	0x92, 0x36, 0x17, 0x3f, 0x5c, 0x55, 0x71, 0x41, 0x7e, 0x5e, 0x71, 0xaa, 0x90, 0x04, 0x17, 0x7b,
-	0x6e, 0x6a, 0x71, 0x71, 0x62, 0x7a, 0x2a, 0x58, 0x25, 0x67, 0x10, 0x8c, 0x6b, 0xe4, 0xc5, 0xc5,
-	0xee, 0x01, 0xb1, 0x4a, 0xc8, 0x9e, 0x8b, 0x27, 0xb8, 0x24, 0xb1, 0xa8, 0x04, 0xc6, 0x17, 0x85,
-	0x58, 0xae, 0x87, 0x6a, 0xa5, 0x94, 0x18, 0xba, 0x30, 0xc4, 0x0e, 0x25, 0x86, 0x24, 0x36, 0xb0,
-	0x84, 0x31, 0x20, 0x00, 0x00, 0xff, 0xff, 0x84, 0x8f, 0xd4, 0xe8, 0xbd, 0x00, 0x00, 0x00,
+	0x6e, 0x6a, 0x71, 0x71, 0x62, 0x7a, 0x2a, 0x58, 0x25, 0x67, 0x10, 0x8c, 0x6b, 0x74, 0x96, 0x91,
+	0x8b, 0xdd, 0x03, 0x62, 0x97, 0x90, 0x3d, 0x17, 0x4f, 0x70, 0x49, 0x62, 0x51, 0x09, 0x8c, 0x2f,
+	0x0a, 0xb1, 0x5d, 0x0f, 0xd5, 0x4e, 0x29, 0x31, 0x74, 0x61, 0x88, 0x25, 0x4a, 0x0c, 0x42, 0x76,
+	0x5c, 0xdc, 0xc1, 0x25, 0xf9, 0x05, 0x64, 0xeb, 0x77, 0xe4, 0xe2, 0x0b, 0x4a, 0x2d, 0xa6, 0xc4,
+	0x09, 0x49, 0x6c, 0x60, 0x09, 0x63, 0xc0, 0x00, 0x32, 0x09, 0x28, 0x55, 0x41, 0x01, 0x00, 0x00,
 }
@@ -34,6 +34,10 @@ import url "net/url"
 
 type Handler interface {
 	StartHandler(context.Context, *GeneralRequest) (*GeneralResponse, error)
+
+	StopHandler(context.Context, *GeneralRequest) (*GeneralResponse, error)
+
+	RestartHandler(context.Context, *GeneralRequest) (*GeneralResponse, error)
 }
 
 // =======================
@@ -42,15 +46,17 @@ type Handler interface {
 
 type handlerProtobufClient struct {
 	client HTTPClient
-	urls   [1]string
+	urls   [3]string
 }
 
 // NewHandlerProtobufClient creates a Protobuf client that implements the Handler interface.
 // It communicates using Protobuf and can be configured with a custom HTTPClient.
 func NewHandlerProtobufClient(addr string, client HTTPClient) Handler {
 	prefix := urlBase(addr) + HandlerPathPrefix
-	urls := [1]string{
+	urls := [3]string{
 		prefix + "StartHandler",
+		prefix + "StopHandler",
+		prefix + "RestartHandler",
 	}
 	if httpClient, ok := client.(*http.Client); ok {
 		return &handlerProtobufClient{
@@ -76,21 +82,47 @@ func (c *handlerProtobufClient) StartHandler(ctx context.Context, in *GeneralReq
 	return out, nil
 }
 
+func (c *handlerProtobufClient) StopHandler(ctx context.Context, in *GeneralRequest) (*GeneralResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "proto")
+	ctx = ctxsetters.WithServiceName(ctx, "Handler")
+	ctx = ctxsetters.WithMethodName(ctx, "StopHandler")
+	out := new(GeneralResponse)
+	err := doProtobufRequest(ctx, c.client, c.urls[1], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *handlerProtobufClient) RestartHandler(ctx context.Context, in *GeneralRequest) (*GeneralResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "proto")
+	ctx = ctxsetters.WithServiceName(ctx, "Handler")
+	ctx = ctxsetters.WithMethodName(ctx, "RestartHandler")
+	out := new(GeneralResponse)
+	err := doProtobufRequest(ctx, c.client, c.urls[2], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ===================
 // Handler JSON Client
 // ===================
 
 type handlerJSONClient struct {
 	client HTTPClient
-	urls   [1]string
+	urls   [3]string
 }
 
 // NewHandlerJSONClient creates a JSON client that implements the Handler interface.
 // It communicates using JSON and can be configured with a custom HTTPClient.
 func NewHandlerJSONClient(addr string, client HTTPClient) Handler {
 	prefix := urlBase(addr) + HandlerPathPrefix
-	urls := [1]string{
+	urls := [3]string{
 		prefix + "StartHandler",
+		prefix + "StopHandler",
+		prefix + "RestartHandler",
 	}
 	if httpClient, ok := client.(*http.Client); ok {
 		return &handlerJSONClient{
@@ -116,6 +148,30 @@ func (c *handlerJSONClient) StartHandler(ctx context.Context, in *GeneralRequest
 	return out, nil
 }
 
+func (c *handlerJSONClient) StopHandler(ctx context.Context, in *GeneralRequest) (*GeneralResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "proto")
+	ctx = ctxsetters.WithServiceName(ctx, "Handler")
+	ctx = ctxsetters.WithMethodName(ctx, "StopHandler")
+	out := new(GeneralResponse)
+	err := doJSONRequest(ctx, c.client, c.urls[1], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *handlerJSONClient) RestartHandler(ctx context.Context, in *GeneralRequest) (*GeneralResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "proto")
+	ctx = ctxsetters.WithServiceName(ctx, "Handler")
+	ctx = ctxsetters.WithMethodName(ctx, "RestartHandler")
+	out := new(GeneralResponse)
+	err := doJSONRequest(ctx, c.client, c.urls[2], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ======================
 // Handler Server Handler
 // ======================
@@ -167,6 +223,12 @@ func (s *handlerServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 	case "/twirp/proto.Handler/StartHandler":
 		s.serveStartHandler(ctx, resp, req)
 		return
+	case "/twirp/proto.Handler/StopHandler":
+		s.serveStopHandler(ctx, resp, req)
+		return
+	case "/twirp/proto.Handler/RestartHandler":
+		s.serveRestartHandler(ctx, resp, req)
+		return
 	default:
 		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
 		err = badRouteError(msg, req.Method, req.URL.Path)
@@ -319,6 +381,294 @@ func (s *handlerServer) serveStartHandlerProtobuf(ctx context.Context, resp http
 	callResponseSent(ctx, s.hooks)
 }
 
+func (s *handlerServer) serveStopHandler(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	header := req.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveStopHandlerJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveStopHandlerProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *handlerServer) serveStopHandlerJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "StopHandler")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	reqContent := new(GeneralRequest)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *GeneralResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Handler.StopHandler(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *GeneralResponse and nil error while calling StopHandler. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+
+	respBytes := buf.Bytes()
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *handlerServer) serveStopHandlerProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "StopHandler")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(GeneralRequest)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *GeneralResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Handler.StopHandler(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *GeneralResponse and nil error while calling StopHandler. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *handlerServer) serveRestartHandler(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	header := req.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveRestartHandlerJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveRestartHandlerProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *handlerServer) serveRestartHandlerJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "RestartHandler")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	reqContent := new(GeneralRequest)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *GeneralResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Handler.RestartHandler(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *GeneralResponse and nil error while calling RestartHandler. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+
+	respBytes := buf.Bytes()
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *handlerServer) serveRestartHandlerProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "RestartHandler")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(GeneralRequest)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *GeneralResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Handler.RestartHandler(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *GeneralResponse and nil error while calling RestartHandler. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
 func (s *handlerServer) ServiceDescriptor() ([]byte, int) {
 	return twirpFileDescriptor0, 0
 }
@@ -748,14 +1098,15 @@ func callError(ctx context.Context, h *twirp.ServerHooks, err twirp.Error) conte
 }
 
 var twirpFileDescriptor0 = []byte{
-	// 143 bytes of a gzipped FileDescriptorProto
+	// 160 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0xcd, 0x48, 0xcc, 0x4b,
 	0xc9, 0x49, 0x2d, 0xd2, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x05, 0x53, 0x4a, 0x2a, 0x5c,
 	0x7c, 0xee, 0xa9, 0x79, 0xa9, 0x45, 0x89, 0x39, 0x41, 0xa9, 0x85, 0xa5, 0xa9, 0xc5, 0x25, 0x42,
 	0x42, 0x5c, 0x2c, 0x05, 0xf9, 0x45, 0x25, 0x12, 0x8c, 0x0a, 0x8c, 0x1a, 0xbc, 0x41, 0x60, 0xb6,
 	0x92, 0x36, 0x17, 0x3f, 0x5c, 0x55, 0x71, 0x41, 0x7e, 0x5e, 0x71, 0xaa, 0x90, 0x04, 0x17, 0x7b,
-	0x6e, 0x6a, 0x71, 0x71, 0x62, 0x7a, 0x2a, 0x58, 0x25, 0x67, 0x10, 0x8c, 0x6b, 0xe4, 0xc5, 0xc5,
-	0xee, 0x01, 0xb1, 0x4a, 0xc8, 0x9e, 0x8b, 0x27, 0xb8, 0x24, 0xb1, 0xa8, 0x04, 0xc6, 0x17, 0x85,
-	0x58, 0xae, 0x87, 0x6a, 0xa5, 0x94, 0x18, 0xba, 0x30, 0xc4, 0x0e, 0x25, 0x86, 0x24, 0x36, 0xb0,
-	0x84, 0x31, 0x20, 0x00, 0x00, 0xff, 0xff, 0x84, 0x8f, 0xd4, 0xe8, 0xbd, 0x00, 0x00, 0x00,
+	0x6e, 0x6a, 0x71, 0x71, 0x62, 0x7a, 0x2a, 0x58, 0x25, 0x67, 0x10, 0x8c, 0x6b, 0x74, 0x96, 0x91,
+	0x8b, 0xdd, 0x03, 0x62, 0x97, 0x90, 0x3d, 0x17, 0x4f, 0x70, 0x49, 0x62, 0x51, 0x09, 0x8c, 0x2f,
+	0x0a, 0xb1, 0x5d, 0x0f, 0xd5, 0x4e, 0x29, 0x31, 0x74, 0x61, 0x88, 0x25, 0x4a, 0x0c, 0x42, 0x76,
+	0x5c, 0xdc, 0xc1, 0x25, 0xf9, 0x05, 0x64, 0xeb, 0x77, 0xe4, 0xe2, 0x0b, 0x4a, 0x2d, 0xa6, 0xc4,
+	0x09, 0x49, 0x6c, 0x60, 0x09, 0x63, 0xc0, 0x00, 0x32, 0x09, 0x28, 0x55, 0x41, 0x01, 0x00, 0x00,
 }
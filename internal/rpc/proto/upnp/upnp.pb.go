@@ -106,18 +106,19 @@ func init() {
 func init() { proto.RegisterFile("upnp.proto", fileDescriptor_6afd328382fff2d5) }
 
 var fileDescriptor_6afd328382fff2d5 = []byte{
-	// 201 bytes of a gzipped FileDescriptorProto
+	// 212 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x2a, 0x2d, 0xc8, 0x2b,
 	0xd0, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x01, 0xb1, 0x95, 0x0c, 0xb8, 0xf8, 0xdc, 0x53,
 	0xf3, 0x52, 0x8b, 0x12, 0x73, 0x82, 0x52, 0x0b, 0x4b, 0x53, 0x8b, 0x4b, 0x84, 0xe4, 0xb8, 0xb8,
 	0x0a, 0xf2, 0x8b, 0x4a, 0xfc, 0x4a, 0x73, 0x93, 0x52, 0x8b, 0x24, 0x18, 0x15, 0x18, 0x35, 0x78,
 	0x83, 0x90, 0x44, 0x94, 0xb4, 0xb9, 0xf8, 0xe1, 0x3a, 0x8a, 0x0b, 0xf2, 0xf3, 0x8a, 0x53, 0x85,
 	0x24, 0xb8, 0xd8, 0x73, 0x53, 0x8b, 0x8b, 0x13, 0xd3, 0x53, 0xc1, 0xea, 0x39, 0x83, 0x60, 0x5c,
-	0xa3, 0x0e, 0x26, 0x2e, 0x96, 0xd0, 0x82, 0xbc, 0x02, 0x21, 0x6b, 0x2e, 0x2e, 0xf7, 0xd4, 0x12,
-	0xf7, 0xc4, 0x92, 0xd4, 0xf2, 0xc4, 0x4a, 0x21, 0x11, 0x3d, 0xb0, 0x43, 0x50, 0x6d, 0x96, 0x12,
-	0x45, 0x13, 0x85, 0x98, 0xae, 0xc4, 0x20, 0xe4, 0xc4, 0x25, 0xe8, 0x96, 0x5f, 0x54, 0x9e, 0x58,
-	0x94, 0x12, 0x90, 0x5f, 0x54, 0x12, 0x9c, 0x99, 0x93, 0x9a, 0x57, 0x42, 0xaa, 0x19, 0x36, 0x5c,
-	0xdc, 0x48, 0x66, 0x90, 0xaa, 0xdb, 0x91, 0x4b, 0x20, 0x28, 0x35, 0x37, 0xbf, 0x2c, 0x15, 0x6a,
-	0x46, 0x66, 0x5e, 0x3a, 0x89, 0x46, 0x24, 0xb1, 0x81, 0x83, 0xdd, 0x18, 0x10, 0x00, 0x00, 0xff,
-	0xff, 0xf6, 0xd0, 0x8e, 0x39, 0x84, 0x01, 0x00, 0x00,
+	0xa3, 0x63, 0x4c, 0x5c, 0x2c, 0xa1, 0x05, 0x79, 0x05, 0x42, 0xd6, 0x5c, 0x5c, 0xee, 0xa9, 0x25,
+	0xee, 0x89, 0x25, 0xa9, 0xe5, 0x89, 0x95, 0x42, 0x22, 0x7a, 0x60, 0x87, 0xa0, 0xda, 0x2c, 0x25,
+	0x8a, 0x26, 0x0a, 0x31, 0x5d, 0x89, 0x41, 0xc8, 0x89, 0x4b, 0xd0, 0x2d, 0xbf, 0xa8, 0x3c, 0xb1,
+	0x28, 0x25, 0x20, 0xbf, 0xa8, 0x24, 0x38, 0x33, 0x27, 0x35, 0xaf, 0x84, 0x54, 0x33, 0x6c, 0xb8,
+	0xb8, 0x91, 0xcc, 0x20, 0x55, 0xb7, 0x23, 0x97, 0x40, 0x50, 0x6a, 0x6e, 0x7e, 0x59, 0x2a, 0xd4,
+	0x8c, 0xcc, 0xbc, 0x74, 0x32, 0x1c, 0xe0, 0x9e, 0x5a, 0xe2, 0x9b, 0x58, 0x50, 0x90, 0x99, 0x97,
+	0x5e, 0x4c, 0xa2, 0xee, 0x24, 0x36, 0x70, 0xa4, 0x19, 0x03, 0x06, 0x00, 0x19, 0x72, 0xc8, 0x54,
+	0xc2, 0x01, 0x00, 0x00,
 }
@@ -40,6 +40,8 @@ type Upnp interface {
 	ForwardPort(context.Context, *GeneralRequest) (*GeneralResponse, error)
 
 	RemoveForwarding(context.Context, *GeneralRequest) (*GeneralResponse, error)
+
+	GetMappings(context.Context, *GeneralRequest) (*GeneralResponse, error)
 }
 
 // ====================
@@ -48,18 +50,19 @@ type Upnp interface {
 
 type upnpProtobufClient struct {
 	client HTTPClient
-	urls   [4]string
+	urls   [5]string
 }
 
 // NewUpnpProtobufClient creates a Protobuf client that implements the Upnp interface.
 // It communicates using Protobuf and can be configured with a custom HTTPClient.
 func NewUpnpProtobufClient(addr string, client HTTPClient) Upnp {
 	prefix := urlBase(addr) + UpnpPathPrefix
-	urls := [4]string{
+	urls := [5]string{
 		prefix + "GetGateway",
 		prefix + "ForwardPortSilent",
 		prefix + "ForwardPort",
 		prefix + "RemoveForwarding",
+		prefix + "GetMappings",
 	}
 	if httpClient, ok := client.(*http.Client); ok {
 		return &upnpProtobufClient{
@@ -121,24 +124,37 @@ func (c *upnpProtobufClient) RemoveForwarding(ctx context.Context, in *GeneralRe
 	return out, nil
 }
 
+func (c *upnpProtobufClient) GetMappings(ctx context.Context, in *GeneralRequest) (*GeneralResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "upnp")
+	ctx = ctxsetters.WithServiceName(ctx, "Upnp")
+	ctx = ctxsetters.WithMethodName(ctx, "GetMappings")
+	out := new(GeneralResponse)
+	err := doProtobufRequest(ctx, c.client, c.urls[4], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ================
 // Upnp JSON Client
 // ================
 
 type upnpJSONClient struct {
 	client HTTPClient
-	urls   [4]string
+	urls   [5]string
 }
 
 // NewUpnpJSONClient creates a JSON client that implements the Upnp interface.
 // It communicates using JSON and can be configured with a custom HTTPClient.
 func NewUpnpJSONClient(addr string, client HTTPClient) Upnp {
 	prefix := urlBase(addr) + UpnpPathPrefix
-	urls := [4]string{
+	urls := [5]string{
 		prefix + "GetGateway",
 		prefix + "ForwardPortSilent",
 		prefix + "ForwardPort",
 		prefix + "RemoveForwarding",
+		prefix + "GetMappings",
 	}
 	if httpClient, ok := client.(*http.Client); ok {
 		return &upnpJSONClient{
@@ -200,6 +216,18 @@ func (c *upnpJSONClient) RemoveForwarding(ctx context.Context, in *GeneralReques
 	return out, nil
 }
 
+func (c *upnpJSONClient) GetMappings(ctx context.Context, in *GeneralRequest) (*GeneralResponse, error) {
+	ctx = ctxsetters.WithPackageName(ctx, "upnp")
+	ctx = ctxsetters.WithServiceName(ctx, "Upnp")
+	ctx = ctxsetters.WithMethodName(ctx, "GetMappings")
+	out := new(GeneralResponse)
+	err := doJSONRequest(ctx, c.client, c.urls[4], in, out)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ===================
 // Upnp Server Handler
 // ===================
@@ -260,6 +288,9 @@ func (s *upnpServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 	case "/twirp/upnp.Upnp/RemoveForwarding":
 		s.serveRemoveForwarding(ctx, resp, req)
 		return
+	case "/twirp/upnp.Upnp/GetMappings":
+		s.serveGetMappings(ctx, resp, req)
+		return
 	default:
 		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
 		err = badRouteError(msg, req.Method, req.URL.Path)
@@ -844,6 +875,150 @@ func (s *upnpServer) serveRemoveForwardingProtobuf(ctx context.Context, resp htt
 	callResponseSent(ctx, s.hooks)
 }
 
+func (s *upnpServer) serveGetMappings(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	header := req.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveGetMappingsJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveGetMappingsProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *upnpServer) serveGetMappingsJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "GetMappings")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	reqContent := new(GeneralRequest)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *GeneralResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Upnp.GetMappings(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *GeneralResponse and nil error while calling GetMappings. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+
+	respBytes := buf.Bytes()
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *upnpServer) serveGetMappingsProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "GetMappings")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(GeneralRequest)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *GeneralResponse
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Upnp.GetMappings(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *GeneralResponse and nil error while calling GetMappings. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if n, err := resp.Write(respBytes); err != nil {
+		msg := fmt.Sprintf("failed to write response, %d of %d bytes written: %s", n, len(respBytes), err.Error())
+		twerr := twirp.NewError(twirp.Unknown, msg)
+		callError(ctx, s.hooks, twerr)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
 func (s *upnpServer) ServiceDescriptor() ([]byte, int) {
 	return twirpFileDescriptor0, 0
 }
@@ -1273,18 +1448,19 @@ func callError(ctx context.Context, h *twirp.ServerHooks, err twirp.Error) conte
 }
 
 var twirpFileDescriptor0 = []byte{
-	// 201 bytes of a gzipped FileDescriptorProto
+	// 212 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x2a, 0x2d, 0xc8, 0x2b,
 	0xd0, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x01, 0xb1, 0x95, 0x0c, 0xb8, 0xf8, 0xdc, 0x53,
 	0xf3, 0x52, 0x8b, 0x12, 0x73, 0x82, 0x52, 0x0b, 0x4b, 0x53, 0x8b, 0x4b, 0x84, 0xe4, 0xb8, 0xb8,
 	0x0a, 0xf2, 0x8b, 0x4a, 0xfc, 0x4a, 0x73, 0x93, 0x52, 0x8b, 0x24, 0x18, 0x15, 0x18, 0x35, 0x78,
 	0x83, 0x90, 0x44, 0x94, 0xb4, 0xb9, 0xf8, 0xe1, 0x3a, 0x8a, 0x0b, 0xf2, 0xf3, 0x8a, 0x53, 0x85,
 	0x24, 0xb8, 0xd8, 0x73, 0x53, 0x8b, 0x8b, 0x13, 0xd3, 0x53, 0xc1, 0xea, 0x39, 0x83, 0x60, 0x5c,
-	0xa3, 0x0e, 0x26, 0x2e, 0x96, 0xd0, 0x82, 0xbc, 0x02, 0x21, 0x6b, 0x2e, 0x2e, 0xf7, 0xd4, 0x12,
-	0xf7, 0xc4, 0x92, 0xd4, 0xf2, 0xc4, 0x4a, 0x21, 0x11, 0x3d, 0xb0, 0x43, 0x50, 0x6d, 0x96, 0x12,
-	0x45, 0x13, 0x85, 0x98, 0xae, 0xc4, 0x20, 0xe4, 0xc4, 0x25, 0xe8, 0x96, 0x5f, 0x54, 0x9e, 0x58,
-	0x94, 0x12, 0x90, 0x5f, 0x54, 0x12, 0x9c, 0x99, 0x93, 0x9a, 0x57, 0x42, 0xaa, 0x19, 0x36, 0x5c,
-	0xdc, 0x48, 0x66, 0x90, 0xaa, 0xdb, 0x91, 0x4b, 0x20, 0x28, 0x35, 0x37, 0xbf, 0x2c, 0x15, 0x6a,
-	0x46, 0x66, 0x5e, 0x3a, 0x89, 0x46, 0x24, 0xb1, 0x81, 0x83, 0xdd, 0x18, 0x10, 0x00, 0x00, 0xff,
-	0xff, 0xf6, 0xd0, 0x8e, 0x39, 0x84, 0x01, 0x00, 0x00,
+	0xa3, 0x63, 0x4c, 0x5c, 0x2c, 0xa1, 0x05, 0x79, 0x05, 0x42, 0xd6, 0x5c, 0x5c, 0xee, 0xa9, 0x25,
+	0xee, 0x89, 0x25, 0xa9, 0xe5, 0x89, 0x95, 0x42, 0x22, 0x7a, 0x60, 0x87, 0xa0, 0xda, 0x2c, 0x25,
+	0x8a, 0x26, 0x0a, 0x31, 0x5d, 0x89, 0x41, 0xc8, 0x89, 0x4b, 0xd0, 0x2d, 0xbf, 0xa8, 0x3c, 0xb1,
+	0x28, 0x25, 0x20, 0xbf, 0xa8, 0x24, 0x38, 0x33, 0x27, 0x35, 0xaf, 0x84, 0x54, 0x33, 0x6c, 0xb8,
+	0xb8, 0x91, 0xcc, 0x20, 0x55, 0xb7, 0x23, 0x97, 0x40, 0x50, 0x6a, 0x6e, 0x7e, 0x59, 0x2a, 0xd4,
+	0x8c, 0xcc, 0xbc, 0x74, 0x32, 0x1c, 0xe0, 0x9e, 0x5a, 0xe2, 0x9b, 0x58, 0x50, 0x90, 0x99, 0x97,
+	0x5e, 0x4c, 0xa2, 0xee, 0x24, 0x36, 0x70, 0xa4, 0x19, 0x03, 0x06, 0x00, 0x19, 0x72, 0xc8, 0x54,
+	0xc2, 0x01, 0x00, 0x00,
 }
@@ -0,0 +1,2139 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: wire.proto
+
+package wire
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type Envelope struct {
+	Kind                 string   `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Payload              []byte   `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Envelope) Reset()         { *m = Envelope{} }
+func (m *Envelope) String() string { return proto.CompactTextString(m) }
+func (*Envelope) ProtoMessage()    {}
+func (*Envelope) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{0}
+}
+
+func (m *Envelope) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Envelope.Unmarshal(m, b)
+}
+func (m *Envelope) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Envelope.Marshal(b, m, deterministic)
+}
+func (m *Envelope) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Envelope.Merge(m, src)
+}
+func (m *Envelope) XXX_Size() int {
+	return xxx_messageInfo_Envelope.Size(m)
+}
+func (m *Envelope) XXX_DiscardUnknown() {
+	xxx_messageInfo_Envelope.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Envelope proto.InternalMessageInfo
+
+func (m *Envelope) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *Envelope) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+type Variable struct {
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Identifier           string   `protobuf:"bytes,2,opt,name=identifier,proto3" json:"identifier,omitempty"`
+	Data                 []byte   `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Serialized           string   `protobuf:"bytes,4,opt,name=serialized,proto3" json:"serialized,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Variable) Reset()         { *m = Variable{} }
+func (m *Variable) String() string { return proto.CompactTextString(m) }
+func (*Variable) ProtoMessage()    {}
+func (*Variable) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{1}
+}
+
+func (m *Variable) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Variable.Unmarshal(m, b)
+}
+func (m *Variable) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Variable.Marshal(b, m, deterministic)
+}
+func (m *Variable) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Variable.Merge(m, src)
+}
+func (m *Variable) XXX_Size() int {
+	return xxx_messageInfo_Variable.Size(m)
+}
+func (m *Variable) XXX_DiscardUnknown() {
+	xxx_messageInfo_Variable.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Variable proto.InternalMessageInfo
+
+func (m *Variable) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Variable) GetIdentifier() string {
+	if m != nil {
+		return m.Identifier
+	}
+	return ""
+}
+
+func (m *Variable) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *Variable) GetSerialized() string {
+	if m != nil {
+		return m.Serialized
+	}
+	return ""
+}
+
+type Environment struct {
+	Variables            []*Variable `protobuf:"bytes,1,rep,name=variables,proto3" json:"variables,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *Environment) Reset()         { *m = Environment{} }
+func (m *Environment) String() string { return proto.CompactTextString(m) }
+func (*Environment) ProtoMessage()    {}
+func (*Environment) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{2}
+}
+
+func (m *Environment) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Environment.Unmarshal(m, b)
+}
+func (m *Environment) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Environment.Marshal(b, m, deterministic)
+}
+func (m *Environment) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Environment.Merge(m, src)
+}
+func (m *Environment) XXX_Size() int {
+	return xxx_messageInfo_Environment.Size(m)
+}
+func (m *Environment) XXX_DiscardUnknown() {
+	xxx_messageInfo_Environment.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Environment proto.InternalMessageInfo
+
+func (m *Environment) GetVariables() []*Variable {
+	if m != nil {
+		return m.Variables
+	}
+	return nil
+}
+
+type Node struct {
+	Id                   string       `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PublicKey            []byte       `protobuf:"bytes,2,opt,name=publicKey,proto3" json:"publicKey,omitempty"`
+	Address              string       `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	Reputation           uint32       `protobuf:"varint,4,opt,name=reputation,proto3" json:"reputation,omitempty"`
+	LastPingTime         int64        `protobuf:"varint,5,opt,name=lastPingTime,proto3" json:"lastPingTime,omitempty"`
+	IsBootstrap          bool         `protobuf:"varint,6,opt,name=isBootstrap,proto3" json:"isBootstrap,omitempty"`
+	Environment          *Environment `protobuf:"bytes,7,opt,name=environment,proto3" json:"environment,omitempty"`
+	Reachability         string       `protobuf:"bytes,8,opt,name=reachability,proto3" json:"reachability,omitempty"`
+	Addresses            []string     `protobuf:"bytes,9,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *Node) Reset()         { *m = Node{} }
+func (m *Node) String() string { return proto.CompactTextString(m) }
+func (*Node) ProtoMessage()    {}
+func (*Node) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{3}
+}
+
+func (m *Node) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Node.Unmarshal(m, b)
+}
+func (m *Node) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Node.Marshal(b, m, deterministic)
+}
+func (m *Node) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Node.Merge(m, src)
+}
+func (m *Node) XXX_Size() int {
+	return xxx_messageInfo_Node.Size(m)
+}
+func (m *Node) XXX_DiscardUnknown() {
+	xxx_messageInfo_Node.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Node proto.InternalMessageInfo
+
+func (m *Node) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Node) GetPublicKey() []byte {
+	if m != nil {
+		return m.PublicKey
+	}
+	return nil
+}
+
+func (m *Node) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *Node) GetReputation() uint32 {
+	if m != nil {
+		return m.Reputation
+	}
+	return 0
+}
+
+func (m *Node) GetLastPingTime() int64 {
+	if m != nil {
+		return m.LastPingTime
+	}
+	return 0
+}
+
+func (m *Node) GetIsBootstrap() bool {
+	if m != nil {
+		return m.IsBootstrap
+	}
+	return false
+}
+
+func (m *Node) GetEnvironment() *Environment {
+	if m != nil {
+		return m.Environment
+	}
+	return nil
+}
+
+func (m *Node) GetReachability() string {
+	if m != nil {
+		return m.Reachability
+	}
+	return ""
+}
+
+func (m *Node) GetAddresses() []string {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+type ModifierSet struct {
+	Type                 string    `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Value                []byte    `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Variable             *Variable `protobuf:"bytes,3,opt,name=variable,proto3" json:"variable,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ModifierSet) Reset()         { *m = ModifierSet{} }
+func (m *ModifierSet) String() string { return proto.CompactTextString(m) }
+func (*ModifierSet) ProtoMessage()    {}
+func (*ModifierSet) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{4}
+}
+
+func (m *ModifierSet) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ModifierSet.Unmarshal(m, b)
+}
+func (m *ModifierSet) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ModifierSet.Marshal(b, m, deterministic)
+}
+func (m *ModifierSet) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ModifierSet.Merge(m, src)
+}
+func (m *ModifierSet) XXX_Size() int {
+	return xxx_messageInfo_ModifierSet.Size(m)
+}
+func (m *ModifierSet) XXX_DiscardUnknown() {
+	xxx_messageInfo_ModifierSet.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ModifierSet proto.InternalMessageInfo
+
+func (m *ModifierSet) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *ModifierSet) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *ModifierSet) GetVariable() *Variable {
+	if m != nil {
+		return m.Variable
+	}
+	return nil
+}
+
+type Command struct {
+	Command              string       `protobuf:"bytes,1,opt,name=command,proto3" json:"command,omitempty"`
+	Modifiers            *ModifierSet `protobuf:"bytes,2,opt,name=modifiers,proto3" json:"modifiers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *Command) Reset()         { *m = Command{} }
+func (m *Command) String() string { return proto.CompactTextString(m) }
+func (*Command) ProtoMessage()    {}
+func (*Command) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{5}
+}
+
+func (m *Command) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Command.Unmarshal(m, b)
+}
+func (m *Command) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Command.Marshal(b, m, deterministic)
+}
+func (m *Command) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Command.Merge(m, src)
+}
+func (m *Command) XXX_Size() int {
+	return xxx_messageInfo_Command.Size(m)
+}
+func (m *Command) XXX_DiscardUnknown() {
+	xxx_messageInfo_Command.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Command proto.InternalMessageInfo
+
+func (m *Command) GetCommand() string {
+	if m != nil {
+		return m.Command
+	}
+	return ""
+}
+
+func (m *Command) GetModifiers() *ModifierSet {
+	if m != nil {
+		return m.Modifiers
+	}
+	return nil
+}
+
+type Resolution struct {
+	Data                 []byte   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Guide                []byte   `protobuf:"bytes,2,opt,name=guide,proto3" json:"guide,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Resolution) Reset()         { *m = Resolution{} }
+func (m *Resolution) String() string { return proto.CompactTextString(m) }
+func (*Resolution) ProtoMessage()    {}
+func (*Resolution) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{6}
+}
+
+func (m *Resolution) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Resolution.Unmarshal(m, b)
+}
+func (m *Resolution) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Resolution.Marshal(b, m, deterministic)
+}
+func (m *Resolution) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Resolution.Merge(m, src)
+}
+func (m *Resolution) XXX_Size() int {
+	return xxx_messageInfo_Resolution.Size(m)
+}
+func (m *Resolution) XXX_DiscardUnknown() {
+	xxx_messageInfo_Resolution.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Resolution proto.InternalMessageInfo
+
+func (m *Resolution) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *Resolution) GetGuide() []byte {
+	if m != nil {
+		return m.Guide
+	}
+	return nil
+}
+
+type Event struct {
+	Type                 string      `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Resolution           *Resolution `protobuf:"bytes,2,opt,name=resolution,proto3" json:"resolution,omitempty"`
+	Command              *Command    `protobuf:"bytes,3,opt,name=command,proto3" json:"command,omitempty"`
+	Destination          *Node       `protobuf:"bytes,4,opt,name=destination,proto3" json:"destination,omitempty"`
+	Port                 int64       `protobuf:"varint,5,opt,name=port,proto3" json:"port,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *Event) Reset()         { *m = Event{} }
+func (m *Event) String() string { return proto.CompactTextString(m) }
+func (*Event) ProtoMessage()    {}
+func (*Event) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{7}
+}
+
+func (m *Event) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Event.Unmarshal(m, b)
+}
+func (m *Event) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Event.Marshal(b, m, deterministic)
+}
+func (m *Event) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Event.Merge(m, src)
+}
+func (m *Event) XXX_Size() int {
+	return xxx_messageInfo_Event.Size(m)
+}
+func (m *Event) XXX_DiscardUnknown() {
+	xxx_messageInfo_Event.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Event proto.InternalMessageInfo
+
+func (m *Event) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Event) GetResolution() *Resolution {
+	if m != nil {
+		return m.Resolution
+	}
+	return nil
+}
+
+func (m *Event) GetCommand() *Command {
+	if m != nil {
+		return m.Command
+	}
+	return nil
+}
+
+func (m *Event) GetDestination() *Node {
+	if m != nil {
+		return m.Destination
+	}
+	return nil
+}
+
+func (m *Event) GetPort() int64 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+type Connection struct {
+	Destination          *Node    `protobuf:"bytes,1,opt,name=destination,proto3" json:"destination,omitempty"`
+	Initializer          *Node    `protobuf:"bytes,2,opt,name=initializer,proto3" json:"initializer,omitempty"`
+	Data                 []byte   `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Port                 int64    `protobuf:"varint,4,opt,name=port,proto3" json:"port,omitempty"`
+	Type                 string   `protobuf:"bytes,5,opt,name=type,proto3" json:"type,omitempty"`
+	Stack                []*Event `protobuf:"bytes,6,rep,name=stack,proto3" json:"stack,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Connection) Reset()         { *m = Connection{} }
+func (m *Connection) String() string { return proto.CompactTextString(m) }
+func (*Connection) ProtoMessage()    {}
+func (*Connection) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{8}
+}
+
+func (m *Connection) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Connection.Unmarshal(m, b)
+}
+func (m *Connection) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Connection.Marshal(b, m, deterministic)
+}
+func (m *Connection) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Connection.Merge(m, src)
+}
+func (m *Connection) XXX_Size() int {
+	return xxx_messageInfo_Connection.Size(m)
+}
+func (m *Connection) XXX_DiscardUnknown() {
+	xxx_messageInfo_Connection.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Connection proto.InternalMessageInfo
+
+func (m *Connection) GetDestination() *Node {
+	if m != nil {
+		return m.Destination
+	}
+	return nil
+}
+
+func (m *Connection) GetInitializer() *Node {
+	if m != nil {
+		return m.Initializer
+	}
+	return nil
+}
+
+func (m *Connection) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *Connection) GetPort() int64 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *Connection) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Connection) GetStack() []*Event {
+	if m != nil {
+		return m.Stack
+	}
+	return nil
+}
+
+type Response struct {
+	Value                [][]byte `protobuf:"bytes,1,rep,name=value,proto3" json:"value,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Response) Reset()         { *m = Response{} }
+func (m *Response) String() string { return proto.CompactTextString(m) }
+func (*Response) ProtoMessage()    {}
+func (*Response) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{9}
+}
+
+func (m *Response) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Response.Unmarshal(m, b)
+}
+func (m *Response) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Response.Marshal(b, m, deterministic)
+}
+func (m *Response) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Response.Merge(m, src)
+}
+func (m *Response) XXX_Size() int {
+	return xxx_messageInfo_Response.Size(m)
+}
+func (m *Response) XXX_DiscardUnknown() {
+	xxx_messageInfo_Response.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Response proto.InternalMessageInfo
+
+func (m *Response) GetValue() [][]byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+type Message struct {
+	Message              string   `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Priority             uint32   `protobuf:"varint,2,opt,name=priority,proto3" json:"priority,omitempty"`
+	Type                 string   `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Network              string   `protobuf:"bytes,4,opt,name=network,proto3" json:"network,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Message) Reset()         { *m = Message{} }
+func (m *Message) String() string { return proto.CompactTextString(m) }
+func (*Message) ProtoMessage()    {}
+func (*Message) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{10}
+}
+
+func (m *Message) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Message.Unmarshal(m, b)
+}
+func (m *Message) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Message.Marshal(b, m, deterministic)
+}
+func (m *Message) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Message.Merge(m, src)
+}
+func (m *Message) XXX_Size() int {
+	return xxx_messageInfo_Message.Size(m)
+}
+func (m *Message) XXX_DiscardUnknown() {
+	xxx_messageInfo_Message.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Message proto.InternalMessageInfo
+
+func (m *Message) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *Message) GetPriority() uint32 {
+	if m != nil {
+		return m.Priority
+	}
+	return 0
+}
+
+func (m *Message) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Message) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+type StreamHeader struct {
+	Sink                 string   `protobuf:"bytes,1,opt,name=sink,proto3" json:"sink,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Size                 int64    `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Initializer          *Node    `protobuf:"bytes,4,opt,name=initializer,proto3" json:"initializer,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StreamHeader) Reset()         { *m = StreamHeader{} }
+func (m *StreamHeader) String() string { return proto.CompactTextString(m) }
+func (*StreamHeader) ProtoMessage()    {}
+func (*StreamHeader) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{11}
+}
+
+func (m *StreamHeader) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreamHeader.Unmarshal(m, b)
+}
+func (m *StreamHeader) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreamHeader.Marshal(b, m, deterministic)
+}
+func (m *StreamHeader) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamHeader.Merge(m, src)
+}
+func (m *StreamHeader) XXX_Size() int {
+	return xxx_messageInfo_StreamHeader.Size(m)
+}
+func (m *StreamHeader) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamHeader.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamHeader proto.InternalMessageInfo
+
+func (m *StreamHeader) GetSink() string {
+	if m != nil {
+		return m.Sink
+	}
+	return ""
+}
+
+func (m *StreamHeader) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *StreamHeader) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *StreamHeader) GetInitializer() *Node {
+	if m != nil {
+		return m.Initializer
+	}
+	return nil
+}
+
+type Chunk struct {
+	Transfer             string        `protobuf:"bytes,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	Offset               int64         `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Data                 []byte        `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	Checksum             string        `protobuf:"bytes,4,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	Final                bool          `protobuf:"varint,5,opt,name=final,proto3" json:"final,omitempty"`
+	Header               *StreamHeader `protobuf:"bytes,6,opt,name=header,proto3" json:"header,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *Chunk) Reset()         { *m = Chunk{} }
+func (m *Chunk) String() string { return proto.CompactTextString(m) }
+func (*Chunk) ProtoMessage()    {}
+func (*Chunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{12}
+}
+
+func (m *Chunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Chunk.Unmarshal(m, b)
+}
+func (m *Chunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Chunk.Marshal(b, m, deterministic)
+}
+func (m *Chunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Chunk.Merge(m, src)
+}
+func (m *Chunk) XXX_Size() int {
+	return xxx_messageInfo_Chunk.Size(m)
+}
+func (m *Chunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_Chunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Chunk proto.InternalMessageInfo
+
+func (m *Chunk) GetTransfer() string {
+	if m != nil {
+		return m.Transfer
+	}
+	return ""
+}
+
+func (m *Chunk) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *Chunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *Chunk) GetChecksum() string {
+	if m != nil {
+		return m.Checksum
+	}
+	return ""
+}
+
+func (m *Chunk) GetFinal() bool {
+	if m != nil {
+		return m.Final
+	}
+	return false
+}
+
+func (m *Chunk) GetHeader() *StreamHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+type ChunkAck struct {
+	Transfer             string   `protobuf:"bytes,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	Offset               int64    `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Complete             bool     `protobuf:"varint,3,opt,name=complete,proto3" json:"complete,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChunkAck) Reset()         { *m = ChunkAck{} }
+func (m *ChunkAck) String() string { return proto.CompactTextString(m) }
+func (*ChunkAck) ProtoMessage()    {}
+func (*ChunkAck) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{13}
+}
+
+func (m *ChunkAck) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChunkAck.Unmarshal(m, b)
+}
+func (m *ChunkAck) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChunkAck.Marshal(b, m, deterministic)
+}
+func (m *ChunkAck) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChunkAck.Merge(m, src)
+}
+func (m *ChunkAck) XXX_Size() int {
+	return xxx_messageInfo_ChunkAck.Size(m)
+}
+func (m *ChunkAck) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChunkAck.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChunkAck proto.InternalMessageInfo
+
+func (m *ChunkAck) GetTransfer() string {
+	if m != nil {
+		return m.Transfer
+	}
+	return ""
+}
+
+func (m *ChunkAck) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *ChunkAck) GetComplete() bool {
+	if m != nil {
+		return m.Complete
+	}
+	return false
+}
+
+type FindNodeRequest struct {
+	Network              string   `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	Target               string   `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	Count                uint32   `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	Sender               *Node    `protobuf:"bytes,4,opt,name=sender,proto3" json:"sender,omitempty"`
+	Key                  string   `protobuf:"bytes,5,opt,name=key,proto3" json:"key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FindNodeRequest) Reset()         { *m = FindNodeRequest{} }
+func (m *FindNodeRequest) String() string { return proto.CompactTextString(m) }
+func (*FindNodeRequest) ProtoMessage()    {}
+func (*FindNodeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{14}
+}
+
+func (m *FindNodeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FindNodeRequest.Unmarshal(m, b)
+}
+func (m *FindNodeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FindNodeRequest.Marshal(b, m, deterministic)
+}
+func (m *FindNodeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FindNodeRequest.Merge(m, src)
+}
+func (m *FindNodeRequest) XXX_Size() int {
+	return xxx_messageInfo_FindNodeRequest.Size(m)
+}
+func (m *FindNodeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_FindNodeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FindNodeRequest proto.InternalMessageInfo
+
+func (m *FindNodeRequest) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *FindNodeRequest) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+func (m *FindNodeRequest) GetCount() uint32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *FindNodeRequest) GetSender() *Node {
+	if m != nil {
+		return m.Sender
+	}
+	return nil
+}
+
+func (m *FindNodeRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+type FindNodeResponse struct {
+	Nodes                []*Node  `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	Record               *Record  `protobuf:"bytes,2,opt,name=record,proto3" json:"record,omitempty"`
+	Providers            []*Node  `protobuf:"bytes,3,rep,name=providers,proto3" json:"providers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FindNodeResponse) Reset()         { *m = FindNodeResponse{} }
+func (m *FindNodeResponse) String() string { return proto.CompactTextString(m) }
+func (*FindNodeResponse) ProtoMessage()    {}
+func (*FindNodeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{15}
+}
+
+func (m *FindNodeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FindNodeResponse.Unmarshal(m, b)
+}
+func (m *FindNodeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FindNodeResponse.Marshal(b, m, deterministic)
+}
+func (m *FindNodeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FindNodeResponse.Merge(m, src)
+}
+func (m *FindNodeResponse) XXX_Size() int {
+	return xxx_messageInfo_FindNodeResponse.Size(m)
+}
+func (m *FindNodeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_FindNodeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FindNodeResponse proto.InternalMessageInfo
+
+func (m *FindNodeResponse) GetNodes() []*Node {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+func (m *FindNodeResponse) GetRecord() *Record {
+	if m != nil {
+		return m.Record
+	}
+	return nil
+}
+
+func (m *FindNodeResponse) GetProviders() []*Node {
+	if m != nil {
+		return m.Providers
+	}
+	return nil
+}
+
+type Record struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value                []byte   `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Provider             *Node    `protobuf:"bytes,3,opt,name=provider,proto3" json:"provider,omitempty"`
+	Publisher            string   `protobuf:"bytes,4,opt,name=publisher,proto3" json:"publisher,omitempty"`
+	Published            int64    `protobuf:"varint,5,opt,name=published,proto3" json:"published,omitempty"`
+	Ttl                  int64    `protobuf:"varint,6,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Record) Reset()         { *m = Record{} }
+func (m *Record) String() string { return proto.CompactTextString(m) }
+func (*Record) ProtoMessage()    {}
+func (*Record) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{16}
+}
+
+func (m *Record) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Record.Unmarshal(m, b)
+}
+func (m *Record) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Record.Marshal(b, m, deterministic)
+}
+func (m *Record) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Record.Merge(m, src)
+}
+func (m *Record) XXX_Size() int {
+	return xxx_messageInfo_Record.Size(m)
+}
+func (m *Record) XXX_DiscardUnknown() {
+	xxx_messageInfo_Record.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Record proto.InternalMessageInfo
+
+func (m *Record) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *Record) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *Record) GetProvider() *Node {
+	if m != nil {
+		return m.Provider
+	}
+	return nil
+}
+
+func (m *Record) GetPublisher() string {
+	if m != nil {
+		return m.Publisher
+	}
+	return ""
+}
+
+func (m *Record) GetPublished() int64 {
+	if m != nil {
+		return m.Published
+	}
+	return 0
+}
+
+func (m *Record) GetTtl() int64 {
+	if m != nil {
+		return m.Ttl
+	}
+	return 0
+}
+
+type StoreRequest struct {
+	Record               *Record  `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StoreRequest) Reset()         { *m = StoreRequest{} }
+func (m *StoreRequest) String() string { return proto.CompactTextString(m) }
+func (*StoreRequest) ProtoMessage()    {}
+func (*StoreRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{17}
+}
+
+func (m *StoreRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StoreRequest.Unmarshal(m, b)
+}
+func (m *StoreRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StoreRequest.Marshal(b, m, deterministic)
+}
+func (m *StoreRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StoreRequest.Merge(m, src)
+}
+func (m *StoreRequest) XXX_Size() int {
+	return xxx_messageInfo_StoreRequest.Size(m)
+}
+func (m *StoreRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StoreRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StoreRequest proto.InternalMessageInfo
+
+func (m *StoreRequest) GetRecord() *Record {
+	if m != nil {
+		return m.Record
+	}
+	return nil
+}
+
+type PeerExchangeRequest struct {
+	Network              string   `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	Sender               *Node    `protobuf:"bytes,2,opt,name=sender,proto3" json:"sender,omitempty"`
+	Peers                []*Node  `protobuf:"bytes,3,rep,name=peers,proto3" json:"peers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PeerExchangeRequest) Reset()         { *m = PeerExchangeRequest{} }
+func (m *PeerExchangeRequest) String() string { return proto.CompactTextString(m) }
+func (*PeerExchangeRequest) ProtoMessage()    {}
+func (*PeerExchangeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{18}
+}
+
+func (m *PeerExchangeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeerExchangeRequest.Unmarshal(m, b)
+}
+func (m *PeerExchangeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeerExchangeRequest.Marshal(b, m, deterministic)
+}
+func (m *PeerExchangeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeerExchangeRequest.Merge(m, src)
+}
+func (m *PeerExchangeRequest) XXX_Size() int {
+	return xxx_messageInfo_PeerExchangeRequest.Size(m)
+}
+func (m *PeerExchangeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeerExchangeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeerExchangeRequest proto.InternalMessageInfo
+
+func (m *PeerExchangeRequest) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *PeerExchangeRequest) GetSender() *Node {
+	if m != nil {
+		return m.Sender
+	}
+	return nil
+}
+
+func (m *PeerExchangeRequest) GetPeers() []*Node {
+	if m != nil {
+		return m.Peers
+	}
+	return nil
+}
+
+type PeerExchangeResponse struct {
+	Peers                []*Node  `protobuf:"bytes,1,rep,name=peers,proto3" json:"peers,omitempty"`
+	Responder            *Node    `protobuf:"bytes,2,opt,name=responder,proto3" json:"responder,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PeerExchangeResponse) Reset()         { *m = PeerExchangeResponse{} }
+func (m *PeerExchangeResponse) String() string { return proto.CompactTextString(m) }
+func (*PeerExchangeResponse) ProtoMessage()    {}
+func (*PeerExchangeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{19}
+}
+
+func (m *PeerExchangeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeerExchangeResponse.Unmarshal(m, b)
+}
+func (m *PeerExchangeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeerExchangeResponse.Marshal(b, m, deterministic)
+}
+func (m *PeerExchangeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeerExchangeResponse.Merge(m, src)
+}
+func (m *PeerExchangeResponse) XXX_Size() int {
+	return xxx_messageInfo_PeerExchangeResponse.Size(m)
+}
+func (m *PeerExchangeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeerExchangeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeerExchangeResponse proto.InternalMessageInfo
+
+func (m *PeerExchangeResponse) GetPeers() []*Node {
+	if m != nil {
+		return m.Peers
+	}
+	return nil
+}
+
+func (m *PeerExchangeResponse) GetResponder() *Node {
+	if m != nil {
+		return m.Responder
+	}
+	return nil
+}
+
+type Ping struct {
+	Network              string   `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	Sender               *Node    `protobuf:"bytes,2,opt,name=sender,proto3" json:"sender,omitempty"`
+	Nonce                string   `protobuf:"bytes,3,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Sent                 int64    `protobuf:"varint,4,opt,name=sent,proto3" json:"sent,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Ping) Reset()         { *m = Ping{} }
+func (m *Ping) String() string { return proto.CompactTextString(m) }
+func (*Ping) ProtoMessage()    {}
+func (*Ping) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{20}
+}
+
+func (m *Ping) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Ping.Unmarshal(m, b)
+}
+func (m *Ping) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Ping.Marshal(b, m, deterministic)
+}
+func (m *Ping) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Ping.Merge(m, src)
+}
+func (m *Ping) XXX_Size() int {
+	return xxx_messageInfo_Ping.Size(m)
+}
+func (m *Ping) XXX_DiscardUnknown() {
+	xxx_messageInfo_Ping.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Ping proto.InternalMessageInfo
+
+func (m *Ping) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *Ping) GetSender() *Node {
+	if m != nil {
+		return m.Sender
+	}
+	return nil
+}
+
+func (m *Ping) GetNonce() string {
+	if m != nil {
+		return m.Nonce
+	}
+	return ""
+}
+
+func (m *Ping) GetSent() int64 {
+	if m != nil {
+		return m.Sent
+	}
+	return 0
+}
+
+type Pong struct {
+	Responder            *Node    `protobuf:"bytes,1,opt,name=responder,proto3" json:"responder,omitempty"`
+	Nonce                string   `protobuf:"bytes,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Sent                 int64    `protobuf:"varint,3,opt,name=sent,proto3" json:"sent,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Pong) Reset()         { *m = Pong{} }
+func (m *Pong) String() string { return proto.CompactTextString(m) }
+func (*Pong) ProtoMessage()    {}
+func (*Pong) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{21}
+}
+
+func (m *Pong) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Pong.Unmarshal(m, b)
+}
+func (m *Pong) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Pong.Marshal(b, m, deterministic)
+}
+func (m *Pong) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Pong.Merge(m, src)
+}
+func (m *Pong) XXX_Size() int {
+	return xxx_messageInfo_Pong.Size(m)
+}
+func (m *Pong) XXX_DiscardUnknown() {
+	xxx_messageInfo_Pong.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Pong proto.InternalMessageInfo
+
+func (m *Pong) GetResponder() *Node {
+	if m != nil {
+		return m.Responder
+	}
+	return nil
+}
+
+func (m *Pong) GetNonce() string {
+	if m != nil {
+		return m.Nonce
+	}
+	return ""
+}
+
+func (m *Pong) GetSent() int64 {
+	if m != nil {
+		return m.Sent
+	}
+	return 0
+}
+
+type MembershipUpdate struct {
+	Node                 *Node    `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	State                string   `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	Incarnation          uint64   `protobuf:"varint,3,opt,name=incarnation,proto3" json:"incarnation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MembershipUpdate) Reset()         { *m = MembershipUpdate{} }
+func (m *MembershipUpdate) String() string { return proto.CompactTextString(m) }
+func (*MembershipUpdate) ProtoMessage()    {}
+func (*MembershipUpdate) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{22}
+}
+
+func (m *MembershipUpdate) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MembershipUpdate.Unmarshal(m, b)
+}
+func (m *MembershipUpdate) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MembershipUpdate.Marshal(b, m, deterministic)
+}
+func (m *MembershipUpdate) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MembershipUpdate.Merge(m, src)
+}
+func (m *MembershipUpdate) XXX_Size() int {
+	return xxx_messageInfo_MembershipUpdate.Size(m)
+}
+func (m *MembershipUpdate) XXX_DiscardUnknown() {
+	xxx_messageInfo_MembershipUpdate.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MembershipUpdate proto.InternalMessageInfo
+
+func (m *MembershipUpdate) GetNode() *Node {
+	if m != nil {
+		return m.Node
+	}
+	return nil
+}
+
+func (m *MembershipUpdate) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *MembershipUpdate) GetIncarnation() uint64 {
+	if m != nil {
+		return m.Incarnation
+	}
+	return 0
+}
+
+type MembershipMessage struct {
+	Network              string              `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	Kind                 string              `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Sender               *Node               `protobuf:"bytes,3,opt,name=sender,proto3" json:"sender,omitempty"`
+	Target               *Node               `protobuf:"bytes,4,opt,name=target,proto3" json:"target,omitempty"`
+	Nonce                string              `protobuf:"bytes,5,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Updates              []*MembershipUpdate `protobuf:"bytes,6,rep,name=updates,proto3" json:"updates,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *MembershipMessage) Reset()         { *m = MembershipMessage{} }
+func (m *MembershipMessage) String() string { return proto.CompactTextString(m) }
+func (*MembershipMessage) ProtoMessage()    {}
+func (*MembershipMessage) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{23}
+}
+
+func (m *MembershipMessage) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MembershipMessage.Unmarshal(m, b)
+}
+func (m *MembershipMessage) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MembershipMessage.Marshal(b, m, deterministic)
+}
+func (m *MembershipMessage) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MembershipMessage.Merge(m, src)
+}
+func (m *MembershipMessage) XXX_Size() int {
+	return xxx_messageInfo_MembershipMessage.Size(m)
+}
+func (m *MembershipMessage) XXX_DiscardUnknown() {
+	xxx_messageInfo_MembershipMessage.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MembershipMessage proto.InternalMessageInfo
+
+func (m *MembershipMessage) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *MembershipMessage) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *MembershipMessage) GetSender() *Node {
+	if m != nil {
+		return m.Sender
+	}
+	return nil
+}
+
+func (m *MembershipMessage) GetTarget() *Node {
+	if m != nil {
+		return m.Target
+	}
+	return nil
+}
+
+func (m *MembershipMessage) GetNonce() string {
+	if m != nil {
+		return m.Nonce
+	}
+	return ""
+}
+
+func (m *MembershipMessage) GetUpdates() []*MembershipUpdate {
+	if m != nil {
+		return m.Updates
+	}
+	return nil
+}
+
+type MembershipAck struct {
+	Responder            *Node               `protobuf:"bytes,1,opt,name=responder,proto3" json:"responder,omitempty"`
+	Nonce                string              `protobuf:"bytes,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Acked                bool                `protobuf:"varint,3,opt,name=acked,proto3" json:"acked,omitempty"`
+	Updates              []*MembershipUpdate `protobuf:"bytes,4,rep,name=updates,proto3" json:"updates,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *MembershipAck) Reset()         { *m = MembershipAck{} }
+func (m *MembershipAck) String() string { return proto.CompactTextString(m) }
+func (*MembershipAck) ProtoMessage()    {}
+func (*MembershipAck) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{24}
+}
+
+func (m *MembershipAck) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MembershipAck.Unmarshal(m, b)
+}
+func (m *MembershipAck) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MembershipAck.Marshal(b, m, deterministic)
+}
+func (m *MembershipAck) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MembershipAck.Merge(m, src)
+}
+func (m *MembershipAck) XXX_Size() int {
+	return xxx_messageInfo_MembershipAck.Size(m)
+}
+func (m *MembershipAck) XXX_DiscardUnknown() {
+	xxx_messageInfo_MembershipAck.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MembershipAck proto.InternalMessageInfo
+
+func (m *MembershipAck) GetResponder() *Node {
+	if m != nil {
+		return m.Responder
+	}
+	return nil
+}
+
+func (m *MembershipAck) GetNonce() string {
+	if m != nil {
+		return m.Nonce
+	}
+	return ""
+}
+
+func (m *MembershipAck) GetAcked() bool {
+	if m != nil {
+		return m.Acked
+	}
+	return false
+}
+
+func (m *MembershipAck) GetUpdates() []*MembershipUpdate {
+	if m != nil {
+		return m.Updates
+	}
+	return nil
+}
+
+type Operation struct {
+	Origin               string   `protobuf:"bytes,1,opt,name=origin,proto3" json:"origin,omitempty"`
+	Sequence             uint64   `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Kind                 string   `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	Node                 *Node    `protobuf:"bytes,4,opt,name=node,proto3" json:"node,omitempty"`
+	Shard                []byte   `protobuf:"bytes,5,opt,name=shard,proto3" json:"shard,omitempty"`
+	Address              string   `protobuf:"bytes,6,opt,name=address,proto3" json:"address,omitempty"`
+	Time                 int64    `protobuf:"varint,7,opt,name=time,proto3" json:"time,omitempty"`
+	PublicKey            []byte   `protobuf:"bytes,8,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	Signature            []byte   `protobuf:"bytes,9,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Operation) Reset()         { *m = Operation{} }
+func (m *Operation) String() string { return proto.CompactTextString(m) }
+func (*Operation) ProtoMessage()    {}
+func (*Operation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{25}
+}
+
+func (m *Operation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Operation.Unmarshal(m, b)
+}
+func (m *Operation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Operation.Marshal(b, m, deterministic)
+}
+func (m *Operation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Operation.Merge(m, src)
+}
+func (m *Operation) XXX_Size() int {
+	return xxx_messageInfo_Operation.Size(m)
+}
+func (m *Operation) XXX_DiscardUnknown() {
+	xxx_messageInfo_Operation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Operation proto.InternalMessageInfo
+
+func (m *Operation) GetOrigin() string {
+	if m != nil {
+		return m.Origin
+	}
+	return ""
+}
+
+func (m *Operation) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *Operation) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *Operation) GetNode() *Node {
+	if m != nil {
+		return m.Node
+	}
+	return nil
+}
+
+func (m *Operation) GetShard() []byte {
+	if m != nil {
+		return m.Shard
+	}
+	return nil
+}
+
+func (m *Operation) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *Operation) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *Operation) GetPublicKey() []byte {
+	if m != nil {
+		return m.PublicKey
+	}
+	return nil
+}
+
+func (m *Operation) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type Version struct {
+	Origin               string   `protobuf:"bytes,1,opt,name=origin,proto3" json:"origin,omitempty"`
+	Sequence             uint64   `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Version) Reset()         { *m = Version{} }
+func (m *Version) String() string { return proto.CompactTextString(m) }
+func (*Version) ProtoMessage()    {}
+func (*Version) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{26}
+}
+
+func (m *Version) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Version.Unmarshal(m, b)
+}
+func (m *Version) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Version.Marshal(b, m, deterministic)
+}
+func (m *Version) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Version.Merge(m, src)
+}
+func (m *Version) XXX_Size() int {
+	return xxx_messageInfo_Version.Size(m)
+}
+func (m *Version) XXX_DiscardUnknown() {
+	xxx_messageInfo_Version.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Version proto.InternalMessageInfo
+
+func (m *Version) GetOrigin() string {
+	if m != nil {
+		return m.Origin
+	}
+	return ""
+}
+
+func (m *Version) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+type ReplicationRequest struct {
+	Network              string       `protobuf:"bytes,1,opt,name=network,proto3" json:"network,omitempty"`
+	Operations           []*Operation `protobuf:"bytes,2,rep,name=operations,proto3" json:"operations,omitempty"`
+	Versions             []*Version   `protobuf:"bytes,3,rep,name=versions,proto3" json:"versions,omitempty"`
+	CatchUp              bool         `protobuf:"varint,4,opt,name=catch_up,json=catchUp,proto3" json:"catch_up,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ReplicationRequest) Reset()         { *m = ReplicationRequest{} }
+func (m *ReplicationRequest) String() string { return proto.CompactTextString(m) }
+func (*ReplicationRequest) ProtoMessage()    {}
+func (*ReplicationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{27}
+}
+
+func (m *ReplicationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReplicationRequest.Unmarshal(m, b)
+}
+func (m *ReplicationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReplicationRequest.Marshal(b, m, deterministic)
+}
+func (m *ReplicationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplicationRequest.Merge(m, src)
+}
+func (m *ReplicationRequest) XXX_Size() int {
+	return xxx_messageInfo_ReplicationRequest.Size(m)
+}
+func (m *ReplicationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplicationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplicationRequest proto.InternalMessageInfo
+
+func (m *ReplicationRequest) GetNetwork() string {
+	if m != nil {
+		return m.Network
+	}
+	return ""
+}
+
+func (m *ReplicationRequest) GetOperations() []*Operation {
+	if m != nil {
+		return m.Operations
+	}
+	return nil
+}
+
+func (m *ReplicationRequest) GetVersions() []*Version {
+	if m != nil {
+		return m.Versions
+	}
+	return nil
+}
+
+func (m *ReplicationRequest) GetCatchUp() bool {
+	if m != nil {
+		return m.CatchUp
+	}
+	return false
+}
+
+type ReplicationResponse struct {
+	Operations           []*Operation `protobuf:"bytes,1,rep,name=operations,proto3" json:"operations,omitempty"`
+	Versions             []*Version   `protobuf:"bytes,2,rep,name=versions,proto3" json:"versions,omitempty"`
+	Snapshot             bool         `protobuf:"varint,3,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	Changes              []*Operation `protobuf:"bytes,4,rep,name=changes,proto3" json:"changes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *ReplicationResponse) Reset()         { *m = ReplicationResponse{} }
+func (m *ReplicationResponse) String() string { return proto.CompactTextString(m) }
+func (*ReplicationResponse) ProtoMessage()    {}
+func (*ReplicationResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{28}
+}
+
+func (m *ReplicationResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReplicationResponse.Unmarshal(m, b)
+}
+func (m *ReplicationResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReplicationResponse.Marshal(b, m, deterministic)
+}
+func (m *ReplicationResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplicationResponse.Merge(m, src)
+}
+func (m *ReplicationResponse) XXX_Size() int {
+	return xxx_messageInfo_ReplicationResponse.Size(m)
+}
+func (m *ReplicationResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplicationResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplicationResponse proto.InternalMessageInfo
+
+func (m *ReplicationResponse) GetOperations() []*Operation {
+	if m != nil {
+		return m.Operations
+	}
+	return nil
+}
+
+func (m *ReplicationResponse) GetVersions() []*Version {
+	if m != nil {
+		return m.Versions
+	}
+	return nil
+}
+
+func (m *ReplicationResponse) GetSnapshot() bool {
+	if m != nil {
+		return m.Snapshot
+	}
+	return false
+}
+
+func (m *ReplicationResponse) GetChanges() []*Operation {
+	if m != nil {
+		return m.Changes
+	}
+	return nil
+}
+
+type RelayRequest struct {
+	Kind                 string   `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Target               string   `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
+	Port                 uint32   `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
+	Circuit              string   `protobuf:"bytes,4,opt,name=circuit,proto3" json:"circuit,omitempty"`
+	Addresses            []string `protobuf:"bytes,5,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	Punchable            bool     `protobuf:"varint,6,opt,name=punchable,proto3" json:"punchable,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RelayRequest) Reset()         { *m = RelayRequest{} }
+func (m *RelayRequest) String() string { return proto.CompactTextString(m) }
+func (*RelayRequest) ProtoMessage()    {}
+func (*RelayRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{29}
+}
+
+func (m *RelayRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RelayRequest.Unmarshal(m, b)
+}
+func (m *RelayRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RelayRequest.Marshal(b, m, deterministic)
+}
+func (m *RelayRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RelayRequest.Merge(m, src)
+}
+func (m *RelayRequest) XXX_Size() int {
+	return xxx_messageInfo_RelayRequest.Size(m)
+}
+func (m *RelayRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RelayRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RelayRequest proto.InternalMessageInfo
+
+func (m *RelayRequest) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *RelayRequest) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+func (m *RelayRequest) GetPort() uint32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func (m *RelayRequest) GetCircuit() string {
+	if m != nil {
+		return m.Circuit
+	}
+	return ""
+}
+
+func (m *RelayRequest) GetAddresses() []string {
+	if m != nil {
+		return m.Addresses
+	}
+	return nil
+}
+
+func (m *RelayRequest) GetPunchable() bool {
+	if m != nil {
+		return m.Punchable
+	}
+	return false
+}
+
+type RelayResponse struct {
+	Observed             string   `protobuf:"bytes,1,opt,name=observed,proto3" json:"observed,omitempty"`
+	Reachable            []string `protobuf:"bytes,2,rep,name=reachable,proto3" json:"reachable,omitempty"`
+	Peer                 string   `protobuf:"bytes,3,opt,name=peer,proto3" json:"peer,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RelayResponse) Reset()         { *m = RelayResponse{} }
+func (m *RelayResponse) String() string { return proto.CompactTextString(m) }
+func (*RelayResponse) ProtoMessage()    {}
+func (*RelayResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{30}
+}
+
+func (m *RelayResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RelayResponse.Unmarshal(m, b)
+}
+func (m *RelayResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RelayResponse.Marshal(b, m, deterministic)
+}
+func (m *RelayResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RelayResponse.Merge(m, src)
+}
+func (m *RelayResponse) XXX_Size() int {
+	return xxx_messageInfo_RelayResponse.Size(m)
+}
+func (m *RelayResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RelayResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RelayResponse proto.InternalMessageInfo
+
+func (m *RelayResponse) GetObserved() string {
+	if m != nil {
+		return m.Observed
+	}
+	return ""
+}
+
+func (m *RelayResponse) GetReachable() []string {
+	if m != nil {
+		return m.Reachable
+	}
+	return nil
+}
+
+func (m *RelayResponse) GetPeer() string {
+	if m != nil {
+		return m.Peer
+	}
+	return ""
+}
+
+type RelayNotification struct {
+	Kind                 string   `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Circuit              string   `protobuf:"bytes,2,opt,name=circuit,proto3" json:"circuit,omitempty"`
+	PeerId               string   `protobuf:"bytes,3,opt,name=peer_id,json=peerId,proto3" json:"peer_id,omitempty"`
+	Peer                 string   `protobuf:"bytes,4,opt,name=peer,proto3" json:"peer,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RelayNotification) Reset()         { *m = RelayNotification{} }
+func (m *RelayNotification) String() string { return proto.CompactTextString(m) }
+func (*RelayNotification) ProtoMessage()    {}
+func (*RelayNotification) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f2dcdddcdf68d8e0, []int{31}
+}
+
+func (m *RelayNotification) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RelayNotification.Unmarshal(m, b)
+}
+func (m *RelayNotification) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RelayNotification.Marshal(b, m, deterministic)
+}
+func (m *RelayNotification) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RelayNotification.Merge(m, src)
+}
+func (m *RelayNotification) XXX_Size() int {
+	return xxx_messageInfo_RelayNotification.Size(m)
+}
+func (m *RelayNotification) XXX_DiscardUnknown() {
+	xxx_messageInfo_RelayNotification.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RelayNotification proto.InternalMessageInfo
+
+func (m *RelayNotification) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *RelayNotification) GetCircuit() string {
+	if m != nil {
+		return m.Circuit
+	}
+	return ""
+}
+
+func (m *RelayNotification) GetPeerId() string {
+	if m != nil {
+		return m.PeerId
+	}
+	return ""
+}
+
+func (m *RelayNotification) GetPeer() string {
+	if m != nil {
+		return m.Peer
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*Envelope)(nil), "wire.Envelope")
+	proto.RegisterType((*Variable)(nil), "wire.Variable")
+	proto.RegisterType((*Environment)(nil), "wire.Environment")
+	proto.RegisterType((*Node)(nil), "wire.Node")
+	proto.RegisterType((*ModifierSet)(nil), "wire.ModifierSet")
+	proto.RegisterType((*Command)(nil), "wire.Command")
+	proto.RegisterType((*Resolution)(nil), "wire.Resolution")
+	proto.RegisterType((*Event)(nil), "wire.Event")
+	proto.RegisterType((*Connection)(nil), "wire.Connection")
+	proto.RegisterType((*Response)(nil), "wire.Response")
+	proto.RegisterType((*Message)(nil), "wire.Message")
+	proto.RegisterType((*StreamHeader)(nil), "wire.StreamHeader")
+	proto.RegisterType((*Chunk)(nil), "wire.Chunk")
+	proto.RegisterType((*ChunkAck)(nil), "wire.ChunkAck")
+	proto.RegisterType((*FindNodeRequest)(nil), "wire.FindNodeRequest")
+	proto.RegisterType((*FindNodeResponse)(nil), "wire.FindNodeResponse")
+	proto.RegisterType((*Record)(nil), "wire.Record")
+	proto.RegisterType((*StoreRequest)(nil), "wire.StoreRequest")
+	proto.RegisterType((*PeerExchangeRequest)(nil), "wire.PeerExchangeRequest")
+	proto.RegisterType((*PeerExchangeResponse)(nil), "wire.PeerExchangeResponse")
+	proto.RegisterType((*Ping)(nil), "wire.Ping")
+	proto.RegisterType((*Pong)(nil), "wire.Pong")
+	proto.RegisterType((*MembershipUpdate)(nil), "wire.MembershipUpdate")
+	proto.RegisterType((*MembershipMessage)(nil), "wire.MembershipMessage")
+	proto.RegisterType((*MembershipAck)(nil), "wire.MembershipAck")
+	proto.RegisterType((*Operation)(nil), "wire.Operation")
+	proto.RegisterType((*Version)(nil), "wire.Version")
+	proto.RegisterType((*ReplicationRequest)(nil), "wire.ReplicationRequest")
+	proto.RegisterType((*ReplicationResponse)(nil), "wire.ReplicationResponse")
+	proto.RegisterType((*RelayRequest)(nil), "wire.RelayRequest")
+	proto.RegisterType((*RelayResponse)(nil), "wire.RelayResponse")
+	proto.RegisterType((*RelayNotification)(nil), "wire.RelayNotification")
+}
+
+func init() { proto.RegisterFile("wire.proto", fileDescriptor_f2dcdddcdf68d8e0) }
+
+var fileDescriptor_f2dcdddcdf68d8e0 = []byte{
+	// 1487 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x58, 0x4d, 0x8f, 0x1c, 0x35,
+	0x13, 0x56, 0xcf, 0x67, 0x77, 0xcd, 0x6e, 0xb2, 0xe9, 0xac, 0xf2, 0xf6, 0x1b, 0xe5, 0x7d, 0x35,
+	0x58, 0x08, 0x26, 0x51, 0x94, 0x44, 0x1b, 0x84, 0x90, 0x10, 0x07, 0x88, 0x16, 0x81, 0x50, 0x42,
+	0xe4, 0x7c, 0x1c, 0x22, 0xa1, 0xc8, 0xdb, 0xed, 0x9d, 0x31, 0xd3, 0x63, 0x37, 0xb6, 0x67, 0x92,
+	0xcd, 0x99, 0x3b, 0x47, 0xfe, 0x00, 0x70, 0x41, 0xdc, 0xf9, 0x07, 0x9c, 0xf9, 0x2f, 0xfc, 0x00,
+	0xe4, 0xaf, 0xee, 0x9e, 0xdd, 0x99, 0x25, 0x24, 0x37, 0x57, 0xd9, 0x2e, 0x3f, 0xf5, 0x54, 0xb9,
+	0xba, 0xdc, 0x00, 0x2f, 0x98, 0xa4, 0xb7, 0x2a, 0x29, 0xb4, 0x48, 0x7b, 0x66, 0x8c, 0x3e, 0x82,
+	0xf8, 0x90, 0xaf, 0x68, 0x29, 0x2a, 0x9a, 0xa6, 0xd0, 0x9b, 0x33, 0x5e, 0x64, 0xd1, 0x38, 0x9a,
+	0x24, 0xd8, 0x8e, 0xd3, 0x0c, 0x86, 0x15, 0x39, 0x29, 0x05, 0x29, 0xb2, 0xce, 0x38, 0x9a, 0xec,
+	0xe0, 0x20, 0x22, 0x09, 0xf1, 0x53, 0x22, 0x19, 0x39, 0x2a, 0xed, 0x4e, 0x7d, 0x52, 0xd1, 0xb0,
+	0xd3, 0x8c, 0xd3, 0xff, 0x03, 0xb0, 0x82, 0x72, 0xcd, 0x8e, 0x19, 0x95, 0x76, 0x73, 0x82, 0x5b,
+	0x1a, 0xb3, 0xa7, 0x20, 0x9a, 0x64, 0x5d, 0x6b, 0xd6, 0x8e, 0xcd, 0x1e, 0x45, 0x25, 0x23, 0x25,
+	0x7b, 0x45, 0x8b, 0xac, 0xe7, 0xf6, 0x34, 0x1a, 0xf4, 0x31, 0x8c, 0x0e, 0xf9, 0x8a, 0x49, 0xc1,
+	0x17, 0x94, 0xeb, 0xf4, 0x26, 0x24, 0x2b, 0x0f, 0x41, 0x65, 0xd1, 0xb8, 0x3b, 0x19, 0x1d, 0x5c,
+	0xb8, 0x65, 0x5d, 0x0c, 0xc8, 0x70, 0xb3, 0x00, 0xfd, 0xda, 0x81, 0xde, 0x03, 0x51, 0xd0, 0xf4,
+	0x02, 0x74, 0x58, 0xf0, 0xb2, 0xc3, 0x8a, 0xf4, 0x1a, 0x24, 0xd5, 0xf2, 0xa8, 0x64, 0xf9, 0x57,
+	0xf4, 0xc4, 0x7b, 0xd9, 0x28, 0x0c, 0x03, 0xa4, 0x28, 0x24, 0x55, 0xca, 0x42, 0x4d, 0x70, 0x10,
+	0x0d, 0x5a, 0x49, 0xab, 0xa5, 0x26, 0x9a, 0x09, 0x6e, 0xd1, 0xee, 0xe2, 0x96, 0x26, 0x45, 0xb0,
+	0x53, 0x12, 0xa5, 0x1f, 0x32, 0x3e, 0x7d, 0xcc, 0x16, 0x34, 0xeb, 0x8f, 0xa3, 0x49, 0x17, 0xaf,
+	0xe9, 0xd2, 0x31, 0x8c, 0x98, 0xfa, 0x4c, 0x08, 0xad, 0xb4, 0x24, 0x55, 0x36, 0x18, 0x47, 0x93,
+	0x18, 0xb7, 0x55, 0xe9, 0x5d, 0x18, 0xd1, 0xc6, 0xe7, 0x6c, 0x38, 0x8e, 0x26, 0xa3, 0x83, 0x4b,
+	0xce, 0xcd, 0x16, 0x19, 0xb8, 0xbd, 0xca, 0x1c, 0x2d, 0x29, 0xc9, 0x67, 0xe4, 0x88, 0x95, 0x4c,
+	0x9f, 0x64, 0xb1, 0x45, 0xbe, 0xa6, 0x33, 0x6e, 0x7b, 0x4f, 0xa8, 0xca, 0x92, 0x71, 0x77, 0x92,
+	0xe0, 0x46, 0x81, 0x72, 0x18, 0xdd, 0x17, 0x85, 0x0d, 0xd5, 0x23, 0xaa, 0x37, 0x46, 0x78, 0x1f,
+	0xfa, 0x2b, 0x52, 0x2e, 0xa9, 0xe7, 0xcc, 0x09, 0xe9, 0x0d, 0x88, 0x03, 0xe7, 0x96, 0xb0, 0xb3,
+	0x31, 0xa9, 0xe7, 0xd1, 0x63, 0x18, 0xde, 0x13, 0x8b, 0x05, 0x71, 0x89, 0x96, 0xbb, 0xa1, 0x3f,
+	0x23, 0x88, 0xe9, 0x6d, 0x48, 0x16, 0x1e, 0x89, 0xca, 0x3a, 0x6d, 0xf7, 0x5b, 0x00, 0x71, 0xb3,
+	0x06, 0x7d, 0x08, 0x80, 0xa9, 0x12, 0xe5, 0xd2, 0x46, 0x21, 0xe4, 0x59, 0xd4, 0xca, 0xb3, 0x7d,
+	0xe8, 0x4f, 0x97, 0xac, 0xa8, 0x91, 0x5b, 0x01, 0xfd, 0x1e, 0x41, 0xff, 0x70, 0x45, 0xf9, 0x66,
+	0x6f, 0xef, 0x98, 0x68, 0x07, 0xab, 0x1e, 0xc7, 0x9e, 0xc3, 0xd1, 0x9c, 0x86, 0x5b, 0x6b, 0xd2,
+	0xf7, 0x1b, 0x97, 0x1c, 0x11, 0xbb, 0x6e, 0xb9, 0x77, 0xb9, 0xf1, 0xf0, 0x26, 0x8c, 0x0a, 0xaa,
+	0x34, 0xe3, 0x4d, 0x26, 0x8d, 0x0e, 0xc0, 0x2d, 0x36, 0x19, 0x8b, 0xdb, 0xd3, 0x06, 0x5c, 0x25,
+	0xa4, 0xf6, 0xe9, 0x64, 0xc7, 0xe8, 0x8f, 0x08, 0xe0, 0x9e, 0xe0, 0x9c, 0xe6, 0x76, 0xc9, 0x29,
+	0x83, 0xd1, 0xf9, 0x06, 0x6f, 0xc2, 0x88, 0x71, 0xa6, 0xdd, 0x25, 0x93, 0x59, 0xe7, 0xec, 0xea,
+	0xd6, 0xf4, 0xc6, 0x7b, 0x1b, 0x20, 0xf5, 0x1a, 0x48, 0x35, 0x87, 0xfd, 0x16, 0x87, 0xef, 0x40,
+	0x5f, 0x69, 0x92, 0xcf, 0xb3, 0x81, 0xbd, 0xac, 0x23, 0x9f, 0xc5, 0x86, 0x73, 0xec, 0x66, 0xd0,
+	0x18, 0x62, 0x4c, 0x55, 0x25, 0xb8, 0x6a, 0x25, 0x98, 0xb9, 0xdb, 0x21, 0xc1, 0xd0, 0x02, 0x86,
+	0xf7, 0xa9, 0x52, 0x64, 0x4a, 0x4d, 0xd2, 0x2c, 0xdc, 0x30, 0x24, 0x8d, 0x17, 0xd3, 0xab, 0x10,
+	0x57, 0x92, 0x09, 0x69, 0x92, 0xbf, 0x63, 0x6f, 0x66, 0x2d, 0xd7, 0xc8, 0xba, 0x2d, 0x64, 0x19,
+	0x0c, 0x39, 0xd5, 0x2f, 0x84, 0x9c, 0xfb, 0xb2, 0x13, 0x44, 0xf4, 0x12, 0x76, 0x1e, 0x69, 0x49,
+	0xc9, 0xe2, 0x0b, 0x4a, 0x0a, 0xe7, 0xbf, 0x62, 0x7c, 0x1e, 0x72, 0xc3, 0x8c, 0x8d, 0x8e, 0x93,
+	0x05, 0xf5, 0x55, 0xce, 0x8e, 0xdd, 0xba, 0x57, 0xee, 0x94, 0x2e, 0xb6, 0xe3, 0xd3, 0x4c, 0xf7,
+	0xce, 0x65, 0x1a, 0xfd, 0x16, 0x41, 0xff, 0xde, 0x6c, 0xc9, 0xe7, 0xc6, 0x1b, 0x2d, 0x09, 0x57,
+	0xc7, 0x54, 0xfa, 0x73, 0x6b, 0x39, 0xbd, 0x02, 0x03, 0x71, 0x7c, 0xac, 0xa8, 0xb6, 0xa7, 0x77,
+	0xb1, 0x97, 0x36, 0xc6, 0xe9, 0x2a, 0xc4, 0xf9, 0x8c, 0xe6, 0x73, 0xb5, 0x5c, 0x78, 0x37, 0x6b,
+	0xd9, 0x90, 0x7d, 0xcc, 0x38, 0x29, 0x6d, 0xc0, 0x62, 0xec, 0x84, 0xf4, 0x06, 0x0c, 0x66, 0xd6,
+	0x6f, 0x5b, 0x9a, 0x46, 0x07, 0xa9, 0x03, 0xdb, 0x66, 0x04, 0xfb, 0x15, 0xe8, 0x19, 0xc4, 0x16,
+	0xee, 0xa7, 0xf9, 0x9b, 0x21, 0x36, 0xe8, 0xc4, 0xa2, 0x2a, 0xa9, 0x76, 0xac, 0xc5, 0xb8, 0x96,
+	0xd1, 0x0f, 0x11, 0x5c, 0xfc, 0x9c, 0xf1, 0xc2, 0xb2, 0x44, 0xbf, 0x5b, 0x52, 0xa5, 0xdb, 0x31,
+	0x8b, 0xd6, 0x62, 0x66, 0x4e, 0xd0, 0x44, 0x4e, 0xfd, 0x09, 0x09, 0xf6, 0x92, 0xf1, 0x31, 0x17,
+	0x4b, 0xae, 0xad, 0xf9, 0x5d, 0xec, 0x84, 0x14, 0xc1, 0x40, 0x51, 0x5e, 0x6c, 0x0c, 0x88, 0x9f,
+	0x49, 0xf7, 0xa0, 0x3b, 0xa7, 0x27, 0x3e, 0x99, 0xcd, 0x10, 0x7d, 0x1f, 0xc1, 0x5e, 0x83, 0xc8,
+	0x67, 0xec, 0x18, 0xfa, 0x5c, 0x14, 0xf5, 0xd7, 0xa8, 0x6d, 0xc9, 0x4d, 0xa4, 0xef, 0xc2, 0x40,
+	0xd2, 0x5c, 0xc8, 0xc2, 0xdf, 0xb3, 0x9d, 0x50, 0x42, 0x8c, 0x0e, 0xfb, 0xb9, 0x74, 0x02, 0x49,
+	0x25, 0xc5, 0x8a, 0x15, 0xa6, 0xe6, 0x75, 0xcf, 0xd8, 0x6a, 0x26, 0xd1, 0x2f, 0x11, 0x0c, 0xdc,
+	0xe6, 0x80, 0x31, 0xaa, 0x31, 0x6e, 0xa9, 0xd0, 0xef, 0x41, 0x1c, 0xf6, 0xfb, 0xc2, 0xd4, 0xb6,
+	0x5d, 0xcf, 0xd5, 0xdf, 0x45, 0x35, 0xf3, 0xd4, 0x24, 0xb8, 0x51, 0xb4, 0x67, 0x0b, 0x5f, 0x8b,
+	0x1a, 0x85, 0xc1, 0xa2, 0x75, 0x69, 0x93, 0xa6, 0x8b, 0xcd, 0x10, 0x7d, 0x60, 0xee, 0x91, 0x90,
+	0x75, 0xf4, 0x1a, 0x22, 0xa2, 0xed, 0x44, 0xa0, 0x25, 0x5c, 0x7e, 0x48, 0xa9, 0x3c, 0x7c, 0x99,
+	0xcf, 0x08, 0x9f, 0xbe, 0x46, 0xe8, 0x9b, 0x60, 0x76, 0xb6, 0x06, 0x73, 0x0c, 0xfd, 0x8a, 0x6e,
+	0x66, 0xd6, 0x4d, 0xa0, 0x23, 0xd8, 0x5f, 0x3f, 0xb6, 0x89, 0xaf, 0xdb, 0x19, 0x6d, 0xd9, 0x69,
+	0x22, 0x27, 0xed, 0xea, 0xcd, 0x10, 0x9a, 0x49, 0xc4, 0xa1, 0x67, 0xda, 0x80, 0xb7, 0xf4, 0x65,
+	0xdf, 0x64, 0x1c, 0xcf, 0x43, 0x35, 0x73, 0x82, 0x2d, 0x3e, 0xa6, 0x5b, 0xf0, 0x05, 0xd9, 0x8c,
+	0xd1, 0x33, 0xe8, 0x3d, 0x14, 0x7c, 0xba, 0x8e, 0x30, 0x3a, 0x07, 0x61, 0x63, 0xbb, 0xb3, 0xc9,
+	0x76, 0xb7, 0x65, 0xfb, 0x5b, 0xd8, 0xbb, 0x4f, 0x17, 0x47, 0x54, 0xaa, 0x19, 0xab, 0x9e, 0x54,
+	0x05, 0xd1, 0xa6, 0x01, 0xec, 0x99, 0x94, 0xdf, 0x70, 0x84, 0xd5, 0x1b, 0xeb, 0x4a, 0x13, 0x5d,
+	0x5b, 0xb7, 0x82, 0x6d, 0x88, 0x78, 0x4e, 0xa4, 0xff, 0x74, 0x99, 0x43, 0x7a, 0xb8, 0xad, 0x42,
+	0x7f, 0x46, 0x70, 0xa9, 0x39, 0xac, 0xf5, 0x29, 0xd8, 0xc2, 0x62, 0x68, 0x6b, 0x3b, 0xad, 0xb6,
+	0xb6, 0x61, 0xb6, 0xbb, 0x95, 0x59, 0x54, 0x17, 0x91, 0x0d, 0x65, 0xa1, 0x29, 0x28, 0x8e, 0xa1,
+	0x7e, 0x9b, 0xa1, 0x3b, 0x30, 0x5c, 0x5a, 0x0e, 0x94, 0xff, 0xd0, 0x5d, 0xf1, 0xfd, 0xca, 0x29,
+	0x8a, 0x70, 0x58, 0x86, 0x7e, 0x8c, 0x60, 0xb7, 0x99, 0x35, 0x05, 0xf4, 0x6d, 0xa3, 0xb4, 0x0f,
+	0x7d, 0x92, 0xcf, 0x69, 0xe1, 0x2b, 0xa9, 0x13, 0xda, 0xc8, 0x7a, 0xaf, 0x87, 0xec, 0xaf, 0x08,
+	0x92, 0xaf, 0x2b, 0x2a, 0x5d, 0xab, 0x60, 0x4a, 0xb7, 0x64, 0x53, 0xc6, 0x3d, 0xc9, 0x5e, 0x32,
+	0xa5, 0x5b, 0x99, 0xab, 0x19, 0x60, 0xf4, 0x70, 0x2d, 0xd7, 0xfc, 0x77, 0x5b, 0xfc, 0x87, 0xdc,
+	0xe8, 0x9d, 0x93, 0x1b, 0x33, 0x22, 0x5d, 0x61, 0xd9, 0xc1, 0x4e, 0x68, 0xb7, 0xe2, 0x83, 0xf5,
+	0x56, 0xdc, 0x7c, 0xd2, 0x4d, 0x8b, 0x3d, 0x74, 0x39, 0x69, 0xc6, 0xe9, 0xff, 0x00, 0x5c, 0x17,
+	0xff, 0x7c, 0x4e, 0x5d, 0x07, 0xbc, 0xd6, 0xd7, 0x5f, 0x83, 0x44, 0xb1, 0x29, 0x27, 0x7a, 0x29,
+	0x69, 0x96, 0xb8, 0xd9, 0x5a, 0x81, 0x3e, 0x81, 0xe1, 0x53, 0x2a, 0xd5, 0x1b, 0xfa, 0x8c, 0x7e,
+	0x8e, 0x20, 0xc5, 0xb4, 0x2a, 0x59, 0x6e, 0x79, 0xfb, 0xe7, 0xb2, 0x75, 0x1b, 0x40, 0x04, 0x96,
+	0x4d, 0x97, 0x6b, 0x62, 0x73, 0xd1, 0xd1, 0x52, 0xb3, 0x8f, 0x5b, 0x4b, 0xd2, 0xeb, 0x10, 0xaf,
+	0x1c, 0xc0, 0x50, 0xc6, 0x7c, 0x77, 0xe9, 0x61, 0xe3, 0x7a, 0x3a, 0xfd, 0x2f, 0xc4, 0x39, 0xd1,
+	0xf9, 0xec, 0xf9, 0xb2, 0xb2, 0x84, 0xc7, 0x78, 0x68, 0xe5, 0x27, 0x95, 0x69, 0x79, 0x2f, 0xaf,
+	0xe1, 0xf4, 0x75, 0x6e, 0x1d, 0x4e, 0xf4, 0xef, 0xe0, 0x74, 0xce, 0x87, 0x63, 0x78, 0xe3, 0xa4,
+	0x52, 0x33, 0xa1, 0xc3, 0x67, 0x3e, 0xc8, 0xe9, 0x75, 0x18, 0xba, 0x8a, 0x1b, 0xf2, 0xf3, 0xcc,
+	0xa1, 0x61, 0x1e, 0xfd, 0x14, 0xc1, 0x0e, 0xa6, 0x25, 0x39, 0x09, 0xe4, 0x6e, 0x7a, 0xbe, 0x6e,
+	0x6b, 0x04, 0x42, 0xc3, 0xea, 0xfa, 0x00, 0x3b, 0xb6, 0x2f, 0x10, 0x26, 0xf3, 0x25, 0xd3, 0xa1,
+	0x05, 0xf4, 0xe2, 0xfa, 0x4b, 0xa9, 0x7f, 0xea, 0xa5, 0xe4, 0x3e, 0x84, 0xdc, 0xbc, 0xab, 0x4a,
+	0xea, 0x1f, 0x70, 0x8d, 0x02, 0x7d, 0x03, 0xbb, 0x1e, 0xa5, 0xa7, 0xf6, 0x2a, 0xc4, 0xe2, 0x48,
+	0x51, 0xb9, 0xa2, 0x01, 0x6a, 0x2d, 0x1b, 0x53, 0xfe, 0x89, 0x56, 0x52, 0x4b, 0x63, 0x82, 0x1b,
+	0x85, 0x05, 0x4d, 0x7d, 0xc9, 0x4a, 0xb0, 0x1d, 0x23, 0x0e, 0x97, 0xac, 0xf9, 0x07, 0xc2, 0x3c,
+	0xab, 0xf3, 0xfa, 0x85, 0xb0, 0xe9, 0x21, 0x1f, 0xbc, 0xeb, 0xac, 0x7b, 0xf7, 0x1f, 0x18, 0x1a,
+	0x53, 0xcf, 0x59, 0xb8, 0xa2, 0x03, 0x23, 0x7e, 0x59, 0xd4, 0xe7, 0xf5, 0x9a, 0xf3, 0x8e, 0x06,
+	0xf6, 0xe7, 0xc1, 0xdd, 0xbf, 0x07, 0x00, 0x8d, 0xef, 0xe6, 0x39, 0x4a, 0x10, 0x00, 0x00,
+}
@@ -48,6 +48,8 @@ func (server *Server) NewShard(ctx context.Context, req *shardProto.GeneralReque
 		return &shardProto.GeneralResponse{}, err // Return found error
 	}
 
+	db.SetLocalNode(node) // Record addition on behalf of local node
+
 	err = db.AddShard(shard) // Add shard
 
 	if err != nil { // Check for errors
@@ -109,6 +111,8 @@ func (server *Server) NewShardWithNodes(ctx context.Context, req *shardProto.Gen
 		return &shardProto.GeneralResponse{}, err // Return found error
 	}
 
+	db.SetLocalNode(localNode) // Record addition on behalf of local node
+
 	err = db.AddShard(shard) // Append new shard
 
 	if err != nil { // Check for errors
@@ -252,9 +256,9 @@ func (server *Server) CalculateQuadraticExponent(ctx context.Context, req *shard
 	return &shardProto.GeneralResponse{Message: fmt.Sprintf("\n%f", shard.CalculateQuadraticExponent(float64(req.Exponent)))}, nil // Return response
 }
 
-// SendBytesShardResult - shard.SendBytesShardResult RPC handler
+// SendBytesShardResult - shard.SendBytesShardResult RPC handler (request bytes are sent as a serialized protobuf message)
 func (server *Server) SendBytesShardResult(ctx context.Context, req *shardProto.GeneralRequest) (*shardProto.GeneralResponse, error) {
-	result, err := shard.SendBytesShardResult(req.Bytes, req.Address, int(req.Port)) // Send bytes, store response
+	result, err := shard.SendBytesShardResult(common.EnvelopeKindProtobuf, req.Bytes, req.Address, int(req.Port)) // Send bytes, store response
 
 	if err != nil { // Check for errors
 		return &shardProto.GeneralResponse{}, err // Return found error
@@ -263,9 +267,9 @@ func (server *Server) SendBytesShardResult(ctx context.Context, req *shardProto.
 	return &shardProto.GeneralResponse{Message: fmt.Sprintf("\n%s", string(result))}, nil // Return response
 }
 
-// SendBytesShard - shard.SendBytesShard RPC handler
+// SendBytesShard - shard.SendBytesShard RPC handler (request bytes are sent as a serialized protobuf message)
 func (server *Server) SendBytesShard(ctx context.Context, req *shardProto.GeneralRequest) (*shardProto.GeneralResponse, error) {
-	err := shard.SendBytesShard(req.Bytes, req.Address, int(req.Port)) // Send bytes
+	err := shard.SendBytesShard(common.EnvelopeKindProtobuf, req.Bytes, req.Address, int(req.Port)) // Send bytes
 
 	if err != nil { // Check for errors
 		return &shardProto.GeneralResponse{}, err // Return found error
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 
 	upnpProto "github.com/dowlandaiello/GoP2P/internal/rpc/proto/upnp"
 	"github.com/dowlandaiello/GoP2P/upnp"
@@ -32,29 +33,29 @@ func (server *Server) GetGateway(ctx context.Context, req *upnpProto.GeneralRequ
 
 // ForwardPortSilent - upnp.ForwardPortSilent RPC handler
 func (server *Server) ForwardPortSilent(ctx context.Context, req *upnpProto.GeneralRequest) (*upnpProto.GeneralResponse, error) {
-	err := upnp.ForwardPortSilent(uint(req.PortNumber)) // Forward specified port
+	mapping, err := upnp.DefaultManager.Forward(uint(req.PortNumber)) // Forward specified port, renewing mapping until removed
 
 	if err != nil { // Check for errors
 		return &upnpProto.GeneralResponse{}, err // Return found error
 	}
 
-	return &upnpProto.GeneralResponse{Message: fmt.Sprintf("\nForwarded port %s", strconv.Itoa(int(req.PortNumber)))}, nil // No error occurred, return nil
+	return &upnpProto.GeneralResponse{Message: fmt.Sprintf("\nForwarded port %s (%s)", strconv.Itoa(int(req.PortNumber)), mapping.String())}, nil // No error occurred, return nil
 }
 
 // ForwardPort - upnp.ForwardPort RPC handler
 func (server *Server) ForwardPort(ctx context.Context, req *upnpProto.GeneralRequest) (*upnpProto.GeneralResponse, error) {
-	err := upnp.ForwardPort(uint(req.PortNumber)) // Forward specified port
+	mapping, err := upnp.DefaultManager.Forward(uint(req.PortNumber)) // Forward specified port, renewing mapping until removed
 
 	if err != nil { // Check for errors
 		return &upnpProto.GeneralResponse{}, err // Return found error
 	}
 
-	return &upnpProto.GeneralResponse{Message: fmt.Sprintf("\nForwarded port %s", strconv.Itoa(int(req.PortNumber)))}, nil // No error occurred, return nil
+	return &upnpProto.GeneralResponse{Message: fmt.Sprintf("\nForwarded port %s (%s)", strconv.Itoa(int(req.PortNumber)), mapping.String())}, nil // No error occurred, return nil
 }
 
 // RemoveForwarding - upnp.RemovePortForward RPC handler
 func (server *Server) RemoveForwarding(ctx context.Context, req *upnpProto.GeneralRequest) (*upnpProto.GeneralResponse, error) {
-	err := upnp.RemovePortForward(uint(req.PortNumber)) // Remove forwarding on specified port
+	err := upnp.DefaultManager.Remove(uint(req.PortNumber)) // Stop renewing, remove forwarding on specified port
 
 	if err != nil { // Check for errors
 		return &upnpProto.GeneralResponse{}, err // Return found error
@@ -62,3 +63,20 @@ func (server *Server) RemoveForwarding(ctx context.Context, req *upnpProto.Gener
 
 	return &upnpProto.GeneralResponse{Message: fmt.Sprintf("\nRemoved forwarding %s", strconv.Itoa(int(req.PortNumber)))}, nil // No error occurred, return nil
 }
+
+// GetMappings - upnp.DefaultManager.Status RPC handler
+func (server *Server) GetMappings(ctx context.Context, req *upnpProto.GeneralRequest) (*upnpProto.GeneralResponse, error) {
+	statuses := []string{} // Init status buffer
+
+	for _, status := range upnp.DefaultManager.Status() { // Iterate through managed mappings
+		if req.PortNumber == 0 || status.Port == uint(req.PortNumber) { // Check status was requested
+			statuses = append(statuses, status.String()) // Append status
+		}
+	}
+
+	if len(statuses) == 0 { // Check no mappings
+		return &upnpProto.GeneralResponse{Message: "\nNo managed port mappings"}, nil // No error occurred, return nil
+	}
+
+	return &upnpProto.GeneralResponse{Message: fmt.Sprintf("\n%s", strings.Join(statuses, "\n"))}, nil // No error occurred, return nil
+}
@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"net/http"
+	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
 
+	"github.com/dowlandaiello/GoP2P/addressing"
 	"github.com/dowlandaiello/GoP2P/cli"
 	"github.com/dowlandaiello/GoP2P/common"
 	commonServer "github.com/dowlandaiello/GoP2P/internal/rpc/common"
@@ -25,6 +30,9 @@ import (
 	protoServer "github.com/dowlandaiello/GoP2P/internal/rpc/protobuf"
 	shardServer "github.com/dowlandaiello/GoP2P/internal/rpc/shard"
 	upnpServer "github.com/dowlandaiello/GoP2P/internal/rpc/upnp"
+	"github.com/dowlandaiello/GoP2P/mdns"
+	"github.com/dowlandaiello/GoP2P/nat"
+	nodeDatabase "github.com/dowlandaiello/GoP2P/types/database"
 	"github.com/dowlandaiello/GoP2P/types/handler"
 	"github.com/dowlandaiello/GoP2P/types/node"
 	"github.com/dowlandaiello/GoP2P/upnp"
@@ -34,11 +42,19 @@ import (
 var (
 	terminalFlag   = flag.Bool("terminal", false, "launch GoP2P in terminal mode")                                                                                    // Init term flag
 	upnpFlag       = flag.Bool("no-upnp", false, "launch GoP2P without automatic UPnP port forwarding")                                                               // Init upnp flag
+	mdnsFlag       = flag.Bool("mdns", false, "advertise node and discover peers on the local network via multicast DNS")                                             // Init mDNS flag
+	networkFlag    = flag.String("network", "GoP2P_TestNet", "alias of network database maintained by the node (advertised, discovered via multicast DNS)")           // Init network flag
+	portFlag       = flag.Int("port", 3000, "TCP port to accept peers on (advertised in the node's self-describing addresses)")                                       // Init port flag
 	rpcPortFlag    = flag.Int("rpc-port", 8080, "launch GoP2P with specified RPC port")                                                                               // Init RPC port flag
 	noColorFlag    = flag.Bool("no-color", false, "disables GoP2P terminal colored output")                                                                           // Init color flag
 	forwardRPCFlag = flag.Bool("forward-rpc", false, "enables forwarding of GoP2P RPC terminal ports")                                                                // Init forward RPC flag
 	rpcAddrFlag    = flag.String("rpc-address", fmt.Sprintf("localhost:%s", strconv.Itoa(*rpcPortFlag)), "connects to remote RPC terminal (default: localhost:8080)") // Init remote rpc addr flag
 	silentMode     = flag.Bool("s", false, "launches gop2p in silent mode (silences prints)")                                                                         // Init silent flag
+	jsonCodecFlag  = flag.Bool("json-codec", false, "encode messages sent to peers as JSON instead of protobuf (debugging only)")                                     // Init JSON codec flag
+	listenFlag     = flag.String("listen", "", "comma-separated additional transport addresses to accept peers on (e.g. unix:///tmp/gop2p.sock:3000)")                // Init listen flag
+	relayFlag      = flag.Bool("relay", false, "volunteer as hole punching rendezvous, circuit relay for peers behind NAT (port 3001)")                               // Init relay flag
+	dhtFlag        = flag.Bool("dht", false, "run network database in DHT mode, keeping a Kademlia routing table instead of every node in the network")               // Init DHT flag
+	rendezvousFlag = flag.String("rendezvous", "", "comma-separated relay addresses (e.g. 1.2.3.4:3001) to accept peers through if behind NAT")                       // Init rendezvous flag
 )
 
 func main() {
@@ -46,12 +62,22 @@ func main() {
 
 	common.Silent = *silentMode // Set silent
 
+	nodeDatabase.DefaultReplicationPort = uint(*portFlag) // Replicate databases to peers listening on node port
+
+	if *jsonCodecFlag { // Check for JSON codec
+		common.SetLocalCodec(common.CodecJSON) // Request JSON codec when dialing peers
+	}
+
 	if !*upnpFlag { // Check for UPnP
 		if *forwardRPCFlag {
-			go upnp.ForwardPortSilent(uint(*rpcPortFlag)) // Forward RPC port
+			go forwardPort(uint(*rpcPortFlag)) // Forward RPC port
+		}
+
+		if *relayFlag {
+			go forwardPort(nat.DefaultServicePort) // Forward relay port
 		}
 
-		go upnp.ForwardPortSilent(3000) // Forward port 3000
+		go forwardPort(uint(*portFlag)) // Forward node port
 	}
 
 	if *noColorFlag { // Check for no colors
@@ -63,7 +89,9 @@ func main() {
 	}
 
 	if *terminalFlag { // Check for terminal
-		*rpcAddrFlag = strings.Split(*rpcAddrFlag, ":")[0] // Remove port
+		if rpcAddress, err := common.ParsePeerAddress(*rpcAddrFlag); err == nil { // Parse RPC address
+			*rpcAddrFlag = rpcAddress.Host // Remove port
+		}
 
 		cli.NewTerminal(uint(*rpcPortFlag), *rpcAddrFlag) // Initialize terminal
 	}
@@ -116,19 +144,302 @@ func startNode() {
 		panic(err) // Panic
 	}
 
-	ln, err := node.StartListener(3000) // Start listener
+	addresses := []string{common.PeerAddress{Port: *portFlag}.String()} // Init listener addresses
+
+	for _, address := range strings.Split(*listenFlag, ",") { // Iterate through additional transport addresses
+		if address != "" { // Check for nil address
+			addresses = append(addresses, address) // Append address
+		}
+	}
+
+	if *relayFlag { // Check for relay
+		startRelay(node) // Start relay service
+	}
+
+	if *rendezvousFlag != "" { // Check for rendezvous services
+		addresses = append(addresses, detectReachability(node)...) // Accept peers through relay if unreachable
+	}
+
+	advertiseAddresses(node, addresses, currentDir) // Advertise listener addresses
+
+	nodeHandler, err := handler.NewHandler(node, currentDir, addresses...) // Init handler
 
 	if err != nil { // Check for errors
 		panic(err) // Panic
 	}
 
-	err = handler.StartHandler(node, ln) // Start handler
+	if *dhtFlag { // Check for DHT mode
+		startDHT(nodeHandler, node, currentDir) // Enable routing table
+	}
+
+	startMaintenance(nodeHandler, node) // Maintain network database
+
+	startMembership(nodeHandler, node) // Track network members
+
+	if *mdnsFlag { // Check for mDNS
+		ctx, cancel := context.WithCancel(context.Background()) // Init discovery context
+
+		defer cancel() // Stop discovery when handler stops
+
+		startDiscovery(ctx, node) // Start discovery
+	}
+
+	stopped := make(chan error) // Init shutdown channel
+
+	go func() {
+		signals := make(chan os.Signal, 1) // Init signal buffer
+
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM) // Notify on interrupt
+
+		<-signals // Wait for interrupt
+
+		ctx, cancel := context.WithTimeout(context.Background(), handler.DefaultShutdownTimeout) // Init shutdown deadline
+
+		defer cancel() // Release deadline
+
+		err := nodeHandler.Shutdown(ctx) // Gracefully shut down handler
+
+		if mappingErr := upnp.DefaultManager.Close(); mappingErr != nil { // Remove port mappings
+			common.Printf("\n-- UPnP -- couldn't remove port mappings: %s", mappingErr.Error()) // Log failure
+		}
+
+		stopped <- err // Mark shut down
+	}()
+
+	err = nodeHandler.Start(context.Background()) // Start handler
+
+	if err == handler.ErrHandlerClosed { // Check shut down
+		err = <-stopped // Wait for shutdown
+	}
 
 	if err != nil { // Check for errors
 		panic(err) // Panic
 	}
 }
 
+// forwardPort - forward given port via upnp.DefaultManager (renewing the mapping until shutdown), logging failures
+func forwardPort(port uint) {
+	mapping, err := upnp.DefaultManager.Forward(port) // Forward port
+
+	if err != nil { // Check for errors
+		common.Printf("\n-- UPnP -- couldn't forward port %d (retrying every %s): %s", port, upnp.DefaultRetryInterval, err.Error()) // Log failure
+
+		return // Stop
+	}
+
+	common.Printf("\n-- UPnP -- forwarded %s", mapping.String()) // Log mapping
+}
+
+// startDiscovery - advertise node, discover peers of network specified by -network flag on the local network
+func startDiscovery(ctx context.Context, localNode *node.Node) {
+	networkID := uint(common.GoP2PTestnetID) // Init network ID
+
+	if db, err := nodeDatabase.ReadDatabaseFromMemory(localNode.Environment, *networkFlag); err == nil { // Check for existing network database
+		networkID = db.NetworkID // Set network ID
+	}
+
+	service, err := mdns.NewService(localNode, uint(*portFlag), *networkFlag, networkID) // Init mDNS service
+
+	if err != nil { // Check for errors
+		common.Printf("\n-- MDNS -- discovery disabled: %s", err.Error()) // Log failure
+
+		return // Stop
+	}
+
+	go func() {
+		if err := service.Start(ctx); err != nil { // Start service
+			common.Printf("\n-- MDNS -- discovery stopped: %s", err.Error()) // Log failure
+		}
+	}()
+}
+
+// startMaintenance - exchange peers, ping peers, synchronize operations, decay reputations of network database specified by -network flag (once joined) while given handler is running
+func startMaintenance(nodeHandler *handler.Handler, localNode *node.Node) {
+	db := &nodeDatabase.NodeDatabase{NetworkAlias: *networkFlag} // Init network reference
+
+	nodeHandler.AddService(func(ctx context.Context) {
+		db.GossipRoutine(ctx, localNode, uint(*portFlag), nodeDatabase.DefaultPeerExchangeInterval) // Exchange peers
+	}) // Exchange peers while handler is running
+
+	nodeHandler.AddService(func(ctx context.Context) {
+		db.LivenessRoutine(ctx, localNode, uint(*portFlag), nodeDatabase.DefaultPingInterval) // Ping peers
+	}) // Ping peers while handler is running
+
+	nodeHandler.AddService(func(ctx context.Context) {
+		db.AntiEntropyRoutine(ctx, localNode, uint(*portFlag), nodeDatabase.DefaultAntiEntropyInterval) // Synchronize operations
+	}) // Synchronize operations while handler is running
+
+	nodeHandler.AddService(func(ctx context.Context) {
+		db.ReputationRoutine(ctx, localNode, nodeDatabase.DefaultReputationInterval) // Decay reputations
+	}) // Decay reputations while handler is running
+}
+
+// startMembership - join membership list of network specified by -network flag through peers of its database, probing members while given handler is running and leaving the network once it stops
+func startMembership(nodeHandler *handler.Handler, localNode *node.Node) {
+	membership, err := nodeDatabase.NewMembership(localNode, *networkFlag, uint(*portFlag)) // Init membership
+
+	if err != nil { // Check for errors
+		common.Printf("\n-- MEMBERSHIP -- membership disabled: %s", err.Error()) // Log failure
+
+		return // Stop
+	}
+
+	nodeHandler.AddService(func(ctx context.Context) {
+		if db, err := nodeDatabase.SnapshotFromMemory(localNode.Environment, *networkFlag); err == nil && db.Nodes != nil { // Check network joined
+			seeds := []string{} // Init seeds buffer
+
+			for _, peer := range *db.Nodes { // Iterate through peers
+				if peer.NodeID != localNode.NodeID { // Check peer isn't local node
+					seeds = append(seeds, peer.Address) // Append seed
+				}
+			}
+
+			if _, err = membership.Join(seeds...); err != nil { // Exchange membership lists
+				common.Printf("\n-- MEMBERSHIP -- couldn't join members: %s", err.Error()) // Log failure
+			}
+		}
+
+		membership.Start(ctx) // Probe members
+
+		if err := membership.Leave(); err != nil { // Announce leave
+			common.Printf("\n-- MEMBERSHIP -- couldn't announce leave: %s", err.Error()) // Log failure
+		}
+	}) // Track members while handler is running
+}
+
+// startDHT - run network database specified by -network flag in DHT mode, refreshing its routing table and republishing records stored in the node environment (persisted to given path) while given handler is running
+func startDHT(nodeHandler *handler.Handler, localNode *node.Node, currentDir string) {
+	err := nodeDatabase.UpdateInMemory(localNode.Environment, *networkFlag, func(db *nodeDatabase.NodeDatabase) error {
+		if db.RoutingTable != nil { // Check already in DHT mode
+			return nil // Nothing to do
+		}
+
+		return db.EnableRoutingTable(localNode, nodeDatabase.DefaultBucketSize) // Enable routing table
+	}) // Enable routing table of network database
+
+	if err != nil { // Check for errors
+		common.Printf("\n-- DHT -- DHT mode disabled: %s", err.Error()) // Log failure
+
+		return // Stop
+	}
+
+	db := &nodeDatabase.NodeDatabase{NetworkAlias: *networkFlag} // Init network reference
+
+	nodeHandler.AddService(func(ctx context.Context) {
+		db.RefreshRoutine(ctx, localNode, uint(*portFlag), nodeDatabase.DefaultRefreshInterval) // Refresh stale buckets
+	}) // Refresh routing table while handler is running
+
+	nodeHandler.AddService(func(ctx context.Context) {
+		db.RepublishRoutine(ctx, localNode, currentDir, uint(*portFlag), nodeDatabase.DefaultRepublishInterval) // Republish records
+	}) // Republish records while handler is running
+}
+
+// startRelay - volunteer as hole punching rendezvous, circuit relay for peers behind NAT
+func startRelay(localNode *node.Node) {
+	service, err := nat.NewService(localNode, ":"+strconv.Itoa(nat.DefaultServicePort)) // Init relay service
+
+	if err != nil { // Check for errors
+		common.Printf("\n-- RELAY -- relay disabled: %s", err.Error()) // Log failure
+
+		return // Stop
+	}
+
+	go func() {
+		if err := service.Start(context.Background()); err != nil { // Start service
+			common.Printf("\n-- RELAY -- relay stopped: %s", err.Error()) // Log failure
+		}
+	}()
+}
+
+// detectReachability - detect whether node can be dialed directly through rendezvous services specified by -rendezvous flag, returning relayed addresses to accept peers on if it can't
+func detectReachability(localNode *node.Node) []string {
+	services := strings.Split(*rendezvousFlag, ",") // Split service addresses
+
+	common.DefaultTCPTransport.ReusePort = true      // Share listening port with hole punching dials
+	nat.DefaultTransport.LocalPort = uint(*portFlag) // Punch holes from listening port
+
+	reachability, err := nat.DetectReachability(localNode, services, common.PeerAddress{Port: *portFlag}.String(), nat.DefaultProbeTimeout) // Detect reachability
+
+	if err != nil { // Check for errors
+		common.Printf("\n-- NAT -- couldn't detect reachability: %s", err.Error()) // Log failure
+
+		return []string{} // Assume reachable
+	}
+
+	localNode.Reachability = reachability // Advertise reachability
+
+	common.Printf("\n-- NAT -- node is %s", reachability) // Log reachability
+
+	if reachability != node.ReachabilityPrivate { // Check reachable
+		return []string{} // Nothing to do
+	}
+
+	relayed, err := addressing.FromTransportAddress(nat.RelayAddress(services[0], localNode.NodeID), localNode.NodeID) // Init self-describing relayed address
+
+	if err != nil { // Check for errors
+		common.Printf("\n-- NAT -- couldn't relay through %s: %s", services[0], err.Error()) // Log failure
+
+		return []string{} // Nothing to do
+	}
+
+	relayed.Port, relayed.TLS = *portFlag, true // Accept peers on relayed port
+
+	listenAddress, err := relayed.TransportAddress() // Convert to transport address of relayed listener
+
+	if err != nil { // Check for errors
+		common.Printf("\n-- NAT -- couldn't relay through %s: %s", services[0], err.Error()) // Log failure
+
+		return []string{} // Nothing to do
+	}
+
+	localNode.Address = relayed.PeerAddress().WithPort(0).String() // Advertise relayed address (without port)
+
+	return []string{listenAddress} // Accept peers through relay
+}
+
+// advertiseAddresses - advertise self-describing addresses of given listener addresses (listeners on all addresses are advertised at the node's address), persisting them so that databases the node joins learn them. Nodes only reachable through a relay advertise their relayed address instead.
+func advertiseAddresses(localNode *node.Node, listenerAddresses []string, currentDir string) {
+	advertised := []string{} // Init address buffer
+
+	if localNode.Reachability == node.ReachabilityPrivate { // Check node can't be dialed directly
+		relayed := []string{} // Init relayed address buffer
+
+		for _, address := range listenerAddresses { // Iterate through listener addresses
+			if strings.HasPrefix(address, common.TransportSchemeRelay+"://") { // Check for relayed listener
+				relayed = append(relayed, address) // Append address
+			}
+		}
+
+		listenerAddresses = relayed // Advertise relayed addresses only
+	}
+
+	for _, address := range listenerAddresses { // Iterate through listener addresses
+		if peerAddress, err := common.ParsePeerAddress(address); err == nil && peerAddress.Transport == "" && peerAddress.Host == "" { // Check for listener on all addresses
+			address = localNode.PeerAddress(peerAddress.Port).String() // Advertise node address
+		}
+
+		converted, err := addressing.FromTransportAddress(address, localNode.NodeID) // Convert address
+
+		if err != nil { // Check for errors
+			common.Printf("\n-- ADDRESSING -- couldn't advertise %s: %s", address, err.Error()) // Log failure
+
+			continue // Skip address
+		}
+
+		advertised = append(advertised, converted.String()) // Append address
+	}
+
+	if err := localNode.SetAddresses(advertised); err != nil { // Set addresses
+		common.Printf("\n-- ADDRESSING -- couldn't advertise addresses: %s", err.Error()) // Log failure
+
+		return // Stop
+	}
+
+	if err := localNode.WriteToMemory(currentDir); err != nil { // Persist addresses
+		common.Printf("\n-- ADDRESSING -- couldn't persist addresses: %s", err.Error()) // Log failure
+	}
+}
+
 /* TODO:
 - Fix readme (or lack thereof)
 - Add -v flag (silence common.Println)
@@ -0,0 +1,509 @@
+package mdns
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/types/database"
+	"github.com/dowlandaiello/GoP2P/types/node"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	// ServiceName - DNS-SD service type advertised by GoP2P nodes
+	ServiceName = "_gop2p._tcp.local."
+
+	// DefaultInterval - default duration between announcements, queries
+	DefaultInterval = 30 * time.Second
+
+	// RecordTTL - TTL of advertised records in seconds
+	RecordTTL = 120
+
+	// maxPacketSize - size of receive buffer (mDNS messages fit in a single unfragmented packet)
+	maxPacketSize = 9000
+)
+
+var (
+	// DefaultGroup - mDNS multicast group, port
+	DefaultGroup = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+
+	// ErrServiceRunning - error returned when starting a service that is already running
+	ErrServiceRunning = errors.New("mDNS service already running")
+)
+
+// Peer - peer on the local network advertised via mDNS
+type Peer struct {
+	Node node.Node `json:"node"` // Node - peer contact (NodeID, public key, address)
+
+	Port uint `json:"port"` // Port - port peer accepts connections on
+
+	NetworkAlias string `json:"network"` // NetworkAlias - alias of network peer belongs to
+
+	NetworkID uint `json:"networkID"` // NetworkID - ID of network peer belongs to
+}
+
+// Service - mDNS/DNS-SD announcer, browser advertising the local node and discovering peers on the same network
+type Service struct {
+	Node *node.Node // Node - local node (must have an identity)
+
+	Port uint // Port - advertised port node accepts connections on
+
+	NetworkAlias string // NetworkAlias - alias of advertised network (peers of other networks are ignored)
+
+	NetworkID uint // NetworkID - ID of advertised network (peers of other networks are ignored)
+
+	Group *net.UDPAddr // Group - multicast group, port (DefaultGroup by default)
+
+	Interface *net.Interface // Interface - interface to announce, browse on (system default if nil)
+
+	Interval time.Duration // Interval - duration between announcements, queries
+
+	Found func(peer *Peer) // Found - called for each peer advertised on the network (adds peer to network database of node by default)
+
+	conn *net.UDPConn // conn - multicast listener
+
+	sender *net.UDPConn // sender - socket messages are sent from
+
+	mutex sync.Mutex // mutex - guards conn, sender
+}
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// NewService - initialize service advertising given node (accepting connections on given port) as a member of given network, adding discovered peers to the node's database of that network
+func NewService(localNode *node.Node, port uint, networkAlias string, networkID uint) (*Service, error) {
+	if localNode == nil || localNode.NodeID == "" || len(localNode.PublicKey) == 0 || networkAlias == "" { // Check for invalid parameters
+		return nil, errors.New("invalid parameters") // Return error
+	}
+
+	service := &Service{Node: localNode, Port: port, NetworkAlias: networkAlias, NetworkID: networkID, Group: DefaultGroup, Interval: DefaultInterval} // Init service
+
+	service.Found = service.AddPeer // Add discovered peers to database
+
+	return service, nil // Return initialized service
+}
+
+// Start - announce local node and query for peers every service.Interval, answering queries and reporting advertised peers until ctx is cancelled
+func (service *Service) Start(ctx context.Context) error {
+	err := service.listen() // Open sockets
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	defer service.close() // Close sockets
+
+	errs := make(chan error, 1) // Init error buffer
+
+	go func() {
+		errs <- service.serve() // Handle messages
+	}()
+
+	ticker := time.NewTicker(service.Interval) // Init ticker
+
+	defer ticker.Stop() // Stop ticker
+
+	for {
+		if err = service.Announce(); err == nil { // Announce local node
+			err = service.Query() // Query for peers
+		}
+
+		if err != nil { // Check for errors
+			common.Printf("\n-- MDNS -- announcement failed: %s", err.Error()) // Log failure
+		}
+
+		select {
+		case <-ticker.C: // Check tick
+		case err = <-errs: // Check listener failed
+			return err // Return found error
+		case <-ctx.Done(): // Check cancelled
+			return nil // Stop
+		}
+	}
+}
+
+// Announce - send unsolicited response advertising the local node
+func (service *Service) Announce() error {
+	message, err := service.response() // Init response
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	return service.send(message) // Send response
+}
+
+// Query - ask nodes on the local network to advertise themselves
+func (service *Service) Query() error {
+	name, err := dnsmessage.NewName(ServiceName) // Init service name
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	message := dnsmessage.Message{Questions: []dnsmessage.Question{{Name: name, Type: dnsmessage.TypePTR, Class: dnsmessage.ClassINET}}} // Init query
+
+	return service.send(message) // Send query
+}
+
+// AddPeer - add given peer to the local node's database of the service network (creating the database if it doesn't exist)
+func (service *Service) AddPeer(peer *Peer) {
+	db, err := database.ReadDatabaseFromMemory(service.Node.Environment, service.NetworkAlias) // Read network database
+
+	if err != nil { // Check for missing database
+		db = &database.NodeDatabase{NetworkAlias: service.NetworkAlias, NetworkID: service.NetworkID} // Init database
+	}
+
+	if db.MergePeers(service.Node, []node.Node{peer.Node}, 1) == 0 { // Check peer already known
+		return // Nothing to do
+	}
+
+	err = db.WriteToMemory(service.Node.Environment) // Write db to memory
+
+	if err != nil { // Check for errors
+		common.Printf("\n-- MDNS -- failed to add peer %s: %s", peer.Node.NodeID, err.Error()) // Log failure
+
+		return // Stop
+	}
+
+	common.Printf("\n-- MDNS -- discovered peer %s at %s", peer.Node.NodeID, peer.Node.PeerAddress(int(peer.Port)).String()) // Log discovery
+}
+
+/*
+	END EXPORTED METHODS
+*/
+
+/*
+	BEGIN INTERNAL METHODS:
+*/
+
+// listen - join multicast group, open socket to send messages from
+func (service *Service) listen() error {
+	service.mutex.Lock() // Lock service
+
+	defer service.mutex.Unlock() // Unlock service
+
+	if service.conn != nil { // Check already listening
+		return ErrServiceRunning // Return error
+	}
+
+	conn, err := net.ListenMulticastUDP("udp4", service.Interface, service.Group) // Join group
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	sender, err := net.ListenUDP("udp4", &net.UDPAddr{IP: interfaceIP(service.Interface)}) // Open sending socket on interface
+
+	if err != nil { // Check for errors
+		conn.Close() // Leave group
+
+		return err // Return found error
+	}
+
+	service.conn, service.sender = conn, sender // Set sockets
+
+	return nil // No error occurred, return nil
+}
+
+// close - leave multicast group, close sending socket
+func (service *Service) close() {
+	service.mutex.Lock() // Lock service
+
+	defer service.mutex.Unlock() // Unlock service
+
+	if service.conn != nil { // Check listening
+		service.conn.Close()   // Leave group
+		service.sender.Close() // Close sending socket
+	}
+
+	service.conn, service.sender = nil, nil // Reset sockets
+}
+
+// send - send given message to multicast group
+func (service *Service) send(message dnsmessage.Message) error {
+	b, err := message.Pack() // Pack message
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	service.mutex.Lock() // Lock service
+
+	sender := service.sender // Fetch sending socket
+
+	service.mutex.Unlock() // Unlock service
+
+	if sender == nil { // Check not running
+		return errors.New("mDNS service isn't running") // Return error
+	}
+
+	_, err = sender.WriteToUDP(b, service.Group) // Send message
+
+	return err // Return error (might be nil)
+}
+
+// serve - answer queries for service, reporting advertised peers until listener is closed
+func (service *Service) serve() error {
+	service.mutex.Lock() // Lock service
+
+	conn := service.conn // Fetch listener
+
+	service.mutex.Unlock() // Unlock service
+
+	buffer := make([]byte, maxPacketSize) // Init read buffer
+
+	for {
+		n, source, err := conn.ReadFromUDP(buffer) // Read packet
+
+		if err != nil { // Check for errors
+			if service.isClosed(conn) { // Check listener closed
+				return nil // Stop
+			}
+
+			return err // Return found error
+		}
+
+		err = service.handleMessage(buffer[:n], source) // Handle message
+
+		if err != nil { // Check for errors
+			common.Printf("\n-- MDNS -- ignored message from %s: %s", source.String(), err.Error()) // Log invalid message
+		}
+	}
+}
+
+// isClosed - check given listener has been closed
+func (service *Service) isClosed(conn *net.UDPConn) bool {
+	service.mutex.Lock() // Lock service
+
+	defer service.mutex.Unlock() // Unlock service
+
+	return service.conn != conn // Check listener was replaced
+}
+
+// handleMessage - answer query or report peer advertised in response
+func (service *Service) handleMessage(b []byte, source *net.UDPAddr) error {
+	var parser dnsmessage.Parser // Init parser
+
+	header, err := parser.Start(b) // Parse header
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	if !header.Response { // Check for query
+		questions, err := parser.AllQuestions() // Parse questions
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		for _, question := range questions { // Iterate through questions
+			if strings.EqualFold(question.Name.String(), ServiceName) && (question.Type == dnsmessage.TypePTR || question.Type == dnsmessage.TypeALL) { // Check for service query
+				return service.Announce() // Answer query
+			}
+		}
+
+		return nil // Not queried
+	}
+
+	err = parser.SkipAllQuestions() // Skip questions
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	instances := make(map[string]*Peer) // Init advertised instances buffer
+
+	for { // Parse answers, additionals
+		resourceHeader, err := parser.AnswerHeader() // Parse answer
+
+		if err == dnsmessage.ErrSectionDone { // Check answers parsed
+			if err = parser.SkipAllAuthorities(); err != nil { // Skip authorities
+				return err // Return found error
+			}
+
+			break // Parse additionals
+		}
+
+		if err = parseResource(&parser, resourceHeader, instances, parser.SkipAnswer); err != nil { // Parse resource
+			return err // Return found error
+		}
+	}
+
+	for {
+		resourceHeader, err := parser.AdditionalHeader() // Parse additional
+
+		if err == dnsmessage.ErrSectionDone { // Check additionals parsed
+			break // Stop parsing
+		}
+
+		if err = parseResource(&parser, resourceHeader, instances, parser.SkipAdditional); err != nil { // Parse resource
+			return err // Return found error
+		}
+	}
+
+	for _, peer := range instances { // Iterate through advertised peers
+		if peer.Node.NodeID == "" || peer.Node.NodeID == service.Node.NodeID || peer.NetworkAlias != service.NetworkAlias || peer.NetworkID != service.NetworkID { // Check for local node, other network
+			continue // Skip peer
+		}
+
+		if peer.Node.VerifyIdentity() != nil { // Check for invalid identity
+			continue // Skip peer
+		}
+
+		peer.Node.Address = source.IP.String() // Set address
+
+		if service.Found != nil { // Check for callback
+			service.Found(peer) // Report peer
+		}
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// response - init response advertising local node
+func (service *Service) response() (dnsmessage.Message, error) {
+	label := service.Node.NodeID // Init instance label
+
+	if len(label) > 16 { // Check label too long
+		label = label[:16] // Truncate label
+	}
+
+	serviceName, err := dnsmessage.NewName(ServiceName) // Init service name
+
+	if err != nil { // Check for errors
+		return dnsmessage.Message{}, err // Return found error
+	}
+
+	instanceName, err := dnsmessage.NewName(label + "." + ServiceName) // Init instance name
+
+	if err != nil { // Check for errors
+		return dnsmessage.Message{}, err // Return found error
+	}
+
+	hostName, err := dnsmessage.NewName(label + ".local.") // Init host name
+
+	if err != nil { // Check for errors
+		return dnsmessage.Message{}, err // Return found error
+	}
+
+	txt := []string{
+		"id=" + service.Node.NodeID,                         // Set NodeID
+		"pk=" + hex.EncodeToString(service.Node.PublicKey),  // Set public key
+		"network=" + service.NetworkAlias,                   // Set network alias
+		"networkid=" + strconv.Itoa(int(service.NetworkID)), // Set network ID
+	} // Init TXT record
+
+	message := dnsmessage.Message{
+		Header: dnsmessage.Header{Response: true, Authoritative: true}, // Set response
+		Answers: []dnsmessage.Resource{
+			{Header: dnsmessage.ResourceHeader{Name: serviceName, Type: dnsmessage.TypePTR, Class: dnsmessage.ClassINET, TTL: RecordTTL}, Body: &dnsmessage.PTRResource{PTR: instanceName}}, // Set PTR record
+		},
+		Additionals: []dnsmessage.Resource{
+			{Header: dnsmessage.ResourceHeader{Name: instanceName, Type: dnsmessage.TypeSRV, Class: dnsmessage.ClassINET, TTL: RecordTTL}, Body: &dnsmessage.SRVResource{Port: uint16(service.Port), Target: hostName}}, // Set SRV record
+			{Header: dnsmessage.ResourceHeader{Name: instanceName, Type: dnsmessage.TypeTXT, Class: dnsmessage.ClassINET, TTL: RecordTTL}, Body: &dnsmessage.TXTResource{TXT: txt}},                                     // Set TXT record
+		},
+	} // Init response
+
+	if ip := net.ParseIP(service.Node.Address).To4(); ip != nil { // Check node has IPv4 address
+		a := dnsmessage.AResource{} // Init A record
+
+		copy(a.A[:], ip) // Set address
+
+		message.Additionals = append(message.Additionals, dnsmessage.Resource{Header: dnsmessage.ResourceHeader{Name: hostName, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET, TTL: RecordTTL}, Body: &a}) // Append A record
+	}
+
+	return message, nil // Return response
+}
+
+// parseResource - parse SRV, TXT record of advertised instance into given instances buffer, skipping other records
+func parseResource(parser *dnsmessage.Parser, header dnsmessage.ResourceHeader, instances map[string]*Peer, skip func() error) error {
+	name := strings.ToLower(header.Name.String()) // Fetch record name
+
+	if header.Type != dnsmessage.TypeSRV && header.Type != dnsmessage.TypeTXT || !strings.HasSuffix(name, "."+ServiceName) { // Check for record of other service
+		return skip() // Skip record
+	}
+
+	peer, found := instances[name] // Fetch instance
+
+	if !found { // Check for new instance
+		peer = &Peer{} // Init peer
+
+		instances[name] = peer // Set instance
+	}
+
+	if header.Type == dnsmessage.TypeSRV { // Check for SRV record
+		srv, err := parser.SRVResource() // Parse SRV record
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		peer.Port = uint(srv.Port) // Set port
+
+		return nil // No error occurred, return nil
+	}
+
+	txt, err := parser.TXTResource() // Parse TXT record
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	for _, entry := range txt.TXT { // Iterate through TXT entries
+		keyValue := strings.SplitN(entry, "=", 2) // Split entry
+
+		if len(keyValue) != 2 { // Check for invalid entry
+			continue // Skip entry
+		}
+
+		switch keyValue[0] {
+		case "id":
+			peer.Node.NodeID = keyValue[1] // Set NodeID
+		case "pk":
+			peer.Node.PublicKey, _ = hex.DecodeString(keyValue[1]) // Set public key
+		case "network":
+			peer.NetworkAlias = keyValue[1] // Set network alias
+		case "networkid":
+			networkID, _ := strconv.Atoi(keyValue[1]) // Parse network ID
+
+			peer.NetworkID = uint(networkID) // Set network ID
+		}
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// interfaceIP - fetch IPv4 address of given interface (nil if interface is nil or has no IPv4 address)
+func interfaceIP(iface *net.Interface) net.IP {
+	if iface == nil { // Check for default interface
+		return nil // Any address
+	}
+
+	addresses, err := iface.Addrs() // Fetch addresses
+
+	if err != nil { // Check for errors
+		return nil // Any address
+	}
+
+	for _, address := range addresses { // Iterate through addresses
+		if ipNet, ok := address.(*net.IPNet); ok && ipNet.IP.To4() != nil { // Check for IPv4 address
+			return ipNet.IP // Return address
+		}
+	}
+
+	return nil // Any address
+}
+
+/*
+	END INTERNAL METHODS
+*/
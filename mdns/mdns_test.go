@@ -0,0 +1,188 @@
+package mdns
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/types/database"
+	"github.com/dowlandaiello/GoP2P/types/environment"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+// testGroup - loopback test group (avoids conflicting with a system mDNS responder)
+var testGroup = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 53530}
+
+// TestDiscoverPeers - test functionality of mDNS announcer, browser over loopback multicast
+func TestDiscoverPeers(t *testing.T) {
+	loopback, err := loopbackInterface() // Fetch loopback interface
+
+	if err != nil { // Check for errors
+		t.Logf("WARNING: mDNS testing requires loopback multicast support") // Log warning
+
+		return // Skip test
+	}
+
+	ctx, cancel := context.WithCancel(context.Background()) // Init context
+
+	defer cancel() // Stop services
+
+	first := newTestService(t, loopback, "GoP2P_TestNet", 0)  // Init first service
+	second := newTestService(t, loopback, "GoP2P_TestNet", 0) // Init second service
+	other := newTestService(t, loopback, "GoP2P_OtherNet", 1) // Init service of other network
+
+	errs := make(chan error, 3) // Init error buffer
+
+	for _, service := range []*Service{first, second, other} { // Iterate through services
+		go func(service *Service) {
+			errs <- service.Start(ctx) // Start service
+		}(service)
+	}
+
+	deadline := time.Now().Add(10 * time.Second) // Init deadline
+
+	for time.Now().Before(deadline) { // Wait for discovery
+		select {
+		case err = <-errs: // Check service failed
+			if err != nil { // Check for errors
+				t.Logf("WARNING: mDNS testing requires loopback multicast support (%s)", err.Error()) // Log warning
+
+				return // Skip test
+			}
+		case <-time.After(100 * time.Millisecond): // Check tick
+		}
+
+		if knows(t, first, second) && knows(t, second, first) { // Check peers discovered each other
+			break // Stop waiting
+		}
+	}
+
+	if !knows(t, first, second) || !knows(t, second, first) { // Check peers not discovered
+		t.Errorf("peers on the same network weren't discovered") // Log found error
+		t.FailNow()                                              // Panic
+	}
+
+	if knows(t, first, other) || knows(t, other, first) { // Check peer of other network was added
+		t.Errorf("peer on another network was added") // Log found error
+		t.FailNow()                                   // Panic
+	}
+}
+
+// TestResponse - test functionality of response(), handleMessage() methods
+func TestResponse(t *testing.T) {
+	advertiser := newTestService(t, nil, "GoP2P_TestNet", 0) // Init advertising service
+	browser := newTestService(t, nil, "GoP2P_TestNet", 0)    // Init browsing service
+
+	var found *Peer // Init found peer buffer
+
+	browser.Found = func(peer *Peer) { found = peer } // Set callback
+
+	message, err := advertiser.response() // Init response
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	b, err := message.Pack() // Pack response
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	err = browser.handleMessage(b, &net.UDPAddr{IP: net.IPv4(192, 168, 1, 2), Port: 5353}) // Handle response
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if found == nil || found.Node.NodeID != advertiser.Node.NodeID || found.Node.Address != "192.168.1.2" || found.Port != 3000 || found.NetworkAlias != "GoP2P_TestNet" { // Check for invalid peer
+		t.Errorf("invalid advertised peer %v", found) // Log found error
+		t.FailNow()                                   // Panic
+	}
+
+	err = advertiser.handleMessage(b, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 5353}) // Handle own response
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if db, err := database.ReadDatabaseFromMemory(advertiser.Node.Environment, "GoP2P_TestNet"); err == nil && len(*db.Nodes) != 0 { // Check local node was added
+		t.Errorf("local node was added to its own database") // Log found error
+		t.FailNow()                                          // Panic
+	}
+}
+
+/*
+	BEGIN HELPER METHODS:
+*/
+
+// newTestService - init service advertising a new node as a member of given network on given interface
+func newTestService(t *testing.T, iface *net.Interface, networkAlias string, networkID uint) *Service {
+	identity, err := node.NewIdentity() // Init identity
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	env, err := environment.NewEnvironment() // Init environment
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	localNode := &node.Node{Address: "127.0.0.1", Environment: env} // Init node
+
+	localNode.SetIdentity(identity) // Set identity
+
+	service, err := NewService(localNode, 3000, networkAlias, networkID) // Init service
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	service.Group, service.Interface, service.Interval = testGroup, iface, 200*time.Millisecond // Announce on loopback
+
+	return service // Return service
+}
+
+// knows - check given service has added given peer to its network database
+func knows(t *testing.T, service *Service, peer *Service) bool {
+	db, err := database.ReadDatabaseFromMemory(service.Node.Environment, service.NetworkAlias) // Read network database
+
+	if err != nil { // Check for missing database
+		return false // Peer not known
+	}
+
+	_, err = db.QueryForNodeID(peer.Node.NodeID) // Query for peer
+
+	return err == nil // Check peer found
+}
+
+// loopbackInterface - fetch multicast-capable loopback interface
+func loopbackInterface() (*net.Interface, error) {
+	interfaces, err := net.Interfaces() // Fetch interfaces
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	for _, iface := range interfaces { // Iterate through interfaces
+		if iface.Flags&net.FlagLoopback != 0 && iface.Flags&net.FlagUp != 0 { // Check for loopback
+			return &iface, nil // Return interface
+		}
+	}
+
+	return nil, net.UnknownNetworkError("loopback") // No loopback interface
+}
+
+/*
+	END HELPER METHODS
+*/
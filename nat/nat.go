@@ -0,0 +1,436 @@
+package nat
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/internal/rpc/proto/wire"
+	"github.com/dowlandaiello/GoP2P/types/node"
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	// DefaultServicePort - default port rendezvous, relay services accept connections on
+	DefaultServicePort = 3001
+
+	// RequestReserve - keep connection open as control channel, notifying requester of circuits, hole punching attempts
+	RequestReserve = RequestKind("reserve")
+
+	// RequestConnect - open circuit relayed to target
+	RequestConnect = RequestKind("connect")
+
+	// RequestAccept - accept circuit relayed to requester
+	RequestAccept = RequestKind("accept")
+
+	// RequestDialBack - dial requester back on given addresses (used to detect reachability)
+	RequestDialBack = RequestKind("dialback")
+
+	// RequestPunch - exchange observed addresses of requester, target so that both can open NAT mappings towards each other
+	RequestPunch = RequestKind("punch")
+
+	// NotificationCircuit - notification of circuit waiting to be accepted
+	NotificationCircuit = NotificationKind("circuit")
+
+	// NotificationPunch - notification of peer attempting to punch a hole towards reservation holder
+	NotificationPunch = NotificationKind("punch")
+
+	// maxDialBackAddresses - maximum number of addresses dialed back per request
+	maxDialBackAddresses = 8
+)
+
+var (
+	// DefaultProbeTimeout - default maximum duration to wait on a dial back (including the service dialing each address)
+	DefaultProbeTimeout = 10 * time.Second
+
+	// ErrNoReservation - error returned when requesting a circuit to (or hole punching towards) a peer that doesn't hold a reservation
+	ErrNoReservation = errors.New("peer has no relay reservation")
+)
+
+// RequestKind - kind of request sent to a rendezvous, relay service
+type RequestKind string
+
+// NotificationKind - kind of notification sent to reservation holders
+type NotificationKind string
+
+// Request - request sent to a rendezvous, relay service (requester is identified by its TLS certificate)
+type Request struct {
+	Kind RequestKind `json:"kind"` // Kind - kind of request
+
+	Target string `json:"target,omitempty"` // Target - NodeID of peer to connect, punch to
+
+	Port uint `json:"port,omitempty"` // Port - port of reservation (port peers dial target on)
+
+	Circuit string `json:"circuit,omitempty"` // Circuit - ID of circuit to accept
+
+	Addresses []string `json:"addresses,omitempty"` // Addresses - transport addresses to dial requester back on (TCP addresses without host are dialed on the observed host)
+
+	Punchable bool `json:"punchable,omitempty"` // Punchable - reservation was dialed from requester's listening port (hole punching is possible)
+}
+
+// Response - response of a rendezvous, relay service
+type Response struct {
+	Observed string `json:"observed"` // Observed - address of requester as seen by service
+
+	Reachable []string `json:"reachable,omitempty"` // Reachable - dialed back addresses requester accepted connections on
+
+	Peer string `json:"peer,omitempty"` // Peer - address of target as seen by service (punch requests only)
+}
+
+// Notification - notification sent to reservation holders over their control channel
+type Notification struct {
+	Kind NotificationKind `json:"kind"` // Kind - kind of notification
+
+	Circuit string `json:"circuit,omitempty"` // Circuit - ID of circuit to accept
+
+	PeerID string `json:"peerID"` // PeerID - NodeID of peer opening circuit, punching hole
+
+	Peer string `json:"peer,omitempty"` // Peer - address of peer as seen by service (punch notifications only)
+}
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// RelayAddress - address of node with given NodeID, relayed through service at given address (e.g. relay://1.2.3.4:3001/p2p/<NodeID>). Peers append the port they dial as usual.
+func RelayAddress(serviceAddress string, nodeID string) string {
+	return (&relayAddress{service: serviceAddress, nodeID: nodeID}).String() // Return address
+}
+
+// DialBack - ask service at given address to dial local node back on given addresses, returning response listing addresses that accepted connections
+func DialBack(serviceAddress string, addresses []string, timeout time.Duration) (*Response, error) {
+	return dialBack(serviceAddress, nil, addresses, timeout) // Request dial back
+}
+
+// DetectReachability - detect whether given node accepts connections dialed directly on given address (e.g. :3000), by listening on it while services at given addresses dial back. The address must not be in use (unless shared via common.TCPTransport.ReusePort).
+func DetectReachability(localNode *node.Node, serviceAddresses []string, listenAddress string, timeout time.Duration) (node.Reachability, error) {
+	ln, err := localNode.Listen(listenAddress) // Listen for dial backs
+
+	if err != nil { // Check for errors
+		return node.ReachabilityUnknown, err // Return found error
+	}
+
+	defer (*ln).Close() // Stop listening
+
+	go acceptProbes(*ln) // Accept dial backs
+
+	cert, err := localNode.Certificate() // Fetch certificate presented by listener (services dial back the identity requesting it)
+
+	if err != nil { // Check for errors
+		return node.ReachabilityUnknown, err // Return found error
+	}
+
+	answered := false // Init answered buffer
+
+	err = errors.New("no rendezvous services") // Init error buffer
+
+	for _, serviceAddress := range serviceAddresses { // Iterate through services
+		response, dialErr := dialBack(serviceAddress, cert, []string{listenAddress}, timeout) // Request dial back
+
+		if dialErr != nil { // Check for errors
+			err = dialErr // Set error
+
+			continue // Try next service
+		}
+
+		if len(response.Reachable) != 0 { // Check dialed back
+			return node.ReachabilityPublic, nil // Node is reachable
+		}
+
+		answered = true // Set answered
+	}
+
+	if answered { // Check a service couldn't dial back
+		return node.ReachabilityPrivate, nil // Node is unreachable
+	}
+
+	return node.ReachabilityUnknown, err // No service answered
+}
+
+// Encode - encode request with given codec
+func (request *Request) Encode(codec common.Codec) ([]byte, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		return proto.Marshal(request.ToWire()) // Marshal request
+	case common.CodecJSON:
+		return json.Marshal(request) // Serialize request
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// DecodeRequest - decode request encoded with given codec
+func DecodeRequest(codec common.Codec, b []byte) (*Request, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		wireRequest := &wire.RelayRequest{} // Init wire request buffer
+
+		err := proto.Unmarshal(b, wireRequest) // Unmarshal request
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return RequestFromWire(wireRequest), nil // Return request
+	case common.CodecJSON:
+		request := &Request{} // Init request buffer
+
+		err := json.Unmarshal(b, request) // Decode request
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return request, nil // Return request
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// ToWire - convert request to protobuf wire type
+func (request *Request) ToWire() *wire.RelayRequest {
+	return &wire.RelayRequest{Kind: string(request.Kind), Target: request.Target, Port: uint32(request.Port), Circuit: request.Circuit, Addresses: request.Addresses, Punchable: request.Punchable} // Return wire request
+}
+
+// RequestFromWire - convert protobuf wire type to request
+func RequestFromWire(wireRequest *wire.RelayRequest) *Request {
+	return &Request{Kind: RequestKind(wireRequest.Kind), Target: wireRequest.Target, Port: uint(wireRequest.Port), Circuit: wireRequest.Circuit, Addresses: wireRequest.Addresses, Punchable: wireRequest.Punchable} // Return request
+}
+
+// Encode - encode response with given codec
+func (response *Response) Encode(codec common.Codec) ([]byte, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		return proto.Marshal(&wire.RelayResponse{Observed: response.Observed, Reachable: response.Reachable, Peer: response.Peer}) // Marshal response
+	case common.CodecJSON:
+		return json.Marshal(response) // Serialize response
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// DecodeResponse - decode response encoded with given codec
+func DecodeResponse(codec common.Codec, b []byte) (*Response, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		wireResponse := &wire.RelayResponse{} // Init wire response buffer
+
+		err := proto.Unmarshal(b, wireResponse) // Unmarshal response
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return &Response{Observed: wireResponse.Observed, Reachable: wireResponse.Reachable, Peer: wireResponse.Peer}, nil // Return response
+	case common.CodecJSON:
+		response := &Response{} // Init response buffer
+
+		err := json.Unmarshal(b, response) // Decode response
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return response, nil // Return response
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// Encode - encode notification with given codec
+func (notification *Notification) Encode(codec common.Codec) ([]byte, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		return proto.Marshal(&wire.RelayNotification{Kind: string(notification.Kind), Circuit: notification.Circuit, PeerId: notification.PeerID, Peer: notification.Peer}) // Marshal notification
+	case common.CodecJSON:
+		return json.Marshal(notification) // Serialize notification
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// DecodeNotification - decode notification encoded with given codec
+func DecodeNotification(codec common.Codec, b []byte) (*Notification, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		wireNotification := &wire.RelayNotification{} // Init wire notification buffer
+
+		err := proto.Unmarshal(b, wireNotification) // Unmarshal notification
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return &Notification{Kind: NotificationKind(wireNotification.Kind), Circuit: wireNotification.Circuit, PeerID: wireNotification.PeerId, Peer: wireNotification.Peer}, nil // Return notification
+	case common.CodecJSON:
+		notification := &Notification{} // Init notification buffer
+
+		err := json.Unmarshal(b, notification) // Decode notification
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return notification, nil // Return notification
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+/*
+	END EXPORTED METHODS
+*/
+
+/*
+	BEGIN INTERNAL METHODS:
+*/
+
+// request - send given request to service at given address (dialing from given local TCP port, unless 0), presenting given certificate (local certificate if nil). The returned connection is left open (e.g. as a reservation control channel, relayed circuit), along with the codec negotiated with the service.
+func request(serviceAddress string, localPort uint, cert *tls.Certificate, request *Request, timeout time.Duration) (net.Conn, *Response, common.Codec, error) {
+	hello, err := serviceHello(cert) // Init hello
+
+	if err != nil { // Check for errors
+		return nil, nil, "", err // Return found error
+	}
+
+	conn, err := dialService(serviceAddress, localPort, serviceTLSConfig(cert), timeout) // Dial service
+
+	if err != nil { // Check for errors
+		return nil, nil, "", err // Return found error
+	}
+
+	conn.SetDeadline(time.Now().Add(timeout)) // Set request deadline
+
+	handshake, err := common.ClientHandshake(conn, hello) // Perform handshake
+
+	var payload []byte // Init payload buffer
+
+	if err == nil { // Check for errors
+		payload, err = request.Encode(handshake.Codec) // Encode request
+	}
+
+	if err == nil { // Check for errors
+		payload, err = common.Seal(handshake.Codec, common.EnvelopeKindRelayRequest, payload) // Wrap request in envelope
+	}
+
+	if err == nil { // Check for errors
+		err = common.WriteFrame(conn, common.FrameTypeRequest, payload) // Send request
+	}
+
+	if err == nil { // Check for errors
+		payload, err = common.ReadResponseFrame(conn) // Read response
+	}
+
+	var response *Response // Init response buffer
+
+	if err == nil { // Check for errors
+		response, err = DecodeResponse(handshake.Codec, payload) // Decode response
+	}
+
+	if err != nil { // Check for errors
+		conn.Close() // Close connection
+
+		return nil, nil, "", err // Return found error
+	}
+
+	conn.SetDeadline(time.Time{}) // Clear deadline
+
+	return conn, response, handshake.Codec, nil // Return connection, response, codec
+}
+
+// dialBack - ask service at given address to dial node given certificate is bound to (local certificate if nil) back on given addresses, returning response listing addresses that accepted connections
+func dialBack(serviceAddress string, cert *tls.Certificate, addresses []string, timeout time.Duration) (*Response, error) {
+	conn, response, _, err := request(serviceAddress, 0, cert, &Request{Kind: RequestDialBack, Addresses: addresses}, timeout) // Request dial back
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	conn.Close() // Close connection
+
+	return response, nil // Return response
+}
+
+// serviceHello - hello of node presenting given certificate (local certificate if nil) to services
+func serviceHello(cert *tls.Certificate) (*common.Hello, error) {
+	return common.NodeHello(cert) // Return hello
+}
+
+// dialService - dial service at given address (from given local TCP port, unless 0 or the service isn't reachable over TCP), securing connection with given TLS config
+func dialService(serviceAddress string, localPort uint, config *tls.Config, timeout time.Duration) (net.Conn, error) {
+	transport, parsedAddress, err := common.ParseTransportAddress(serviceAddress) // Parse address
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	tcpTransport, isTCP := transport.(*common.TCPTransport) // Check for TCP transport
+
+	if localPort == 0 || !isTCP { // Check any local port will do
+		return common.DialAddressTimeout(serviceAddress, config, timeout) // Dial service
+	}
+
+	conn, err := tcpTransport.DialFrom(localPort, parsedAddress, timeout) // Dial service from local port
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	tlsConn := tls.Client(conn, config) // Secure connection
+
+	tlsConn.SetDeadline(time.Now().Add(timeout)) // Set handshake deadline
+
+	if err = tlsConn.Handshake(); err != nil { // Perform TLS handshake
+		conn.Close() // Close connection
+
+		return nil, err // Return found error
+	}
+
+	tlsConn.SetDeadline(time.Time{}) // Clear handshake deadline
+
+	return tlsConn, nil // Return connection
+}
+
+// serviceTLSConfig - TLS config for dialing services, presenting given certificate (local certificate if nil)
+func serviceTLSConfig(cert *tls.Certificate) *tls.Config {
+	return common.NodeTLSConfig(cert, "") // Accept any service identity
+}
+
+// acceptProbes - complete TLS handshakes of dial backs accepted on given listener until it is closed
+func acceptProbes(ln net.Listener) {
+	for {
+		conn, err := ln.Accept() // Accept dial back
+
+		if err != nil { // Check for errors
+			return // Listener closed
+		}
+
+		go func() {
+			defer conn.Close() // Close connection
+
+			conn.SetDeadline(time.Now().Add(DefaultProbeTimeout)) // Set handshake deadline
+
+			if tlsConn, isTLS := conn.(*tls.Conn); isTLS && tlsConn.Handshake() == nil { // Complete handshake
+				io.Copy(ioutil.Discard, conn) // Wait on service to close connection
+			}
+		}()
+	}
+}
+
+// release - wait at most given duration on other side to close given connection (consuming its close notification, so that closing doesn't block on unbuffered in-process transports), closing it
+func release(conn net.Conn, timeout time.Duration) {
+	conn.SetReadDeadline(time.Now().Add(timeout)) // Set read deadline
+
+	io.Copy(ioutil.Discard, conn) // Wait on other side to close connection
+
+	conn.Close() // Close connection
+}
+
+/*
+	END INTERNAL METHODS
+*/
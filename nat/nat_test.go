@@ -0,0 +1,185 @@
+package nat
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/types/environment"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+// TestDetectReachability - test functionality of dial backs, reachability detection
+func TestDetectReachability(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background()) // Init context
+
+	defer cancel() // Stop service
+
+	serviceAddress := "127.0.0.1:" + strconv.Itoa(freePort(t)) // Init service address
+
+	startTestService(ctx, t, serviceAddress) // Start service
+
+	localNode := newTestNode(t, "127.0.0.1") // Init local node
+
+	reachability, err := DetectReachability(localNode, []string{"memory://nat-reachability-missing:3001", serviceAddress}, "127.0.0.1:"+strconv.Itoa(freePort(t)), DefaultProbeTimeout) // Detect reachability
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if reachability != node.ReachabilityPublic { // Check dialed back
+		t.Errorf("expected public node, found %s node", reachability) // Log found error
+		t.FailNow()                                                   // Panic
+	}
+
+	response, err := DialBack(serviceAddress, []string{"127.0.0.1:" + strconv.Itoa(freePort(t)), "memory://nat-reachability-unreachable:3000"}, DefaultProbeTimeout) // Request dial back of addresses nobody listens on
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if len(response.Reachable) != 0 { // Check unreachable
+		t.Errorf("unreachable address dialed back: %v", response.Reachable) // Log found error
+		t.FailNow()                                                         // Panic
+	}
+
+	if reachability, err = DetectReachability(localNode, []string{"memory://nat-reachability-missing:3001"}, "127.0.0.1:"+strconv.Itoa(freePort(t)), DefaultProbeTimeout); err == nil || reachability != node.ReachabilityUnknown { // Check no service answered
+		t.Errorf("expected unknown reachability, found %s", reachability) // Log found error
+		t.FailNow()                                                       // Panic
+	}
+}
+
+// TestResolveDialBackAddress - test functionality of dial back address resolution
+func TestResolveDialBackAddress(t *testing.T) {
+	if address, err := resolveDialBackAddress(":3000", "1.2.3.4:5555"); err != nil || address != "1.2.3.4:3000" { // Check host resolved
+		t.Errorf("invalid resolved address %s (%v)", address, err) // Log found error
+		t.FailNow()                                                // Panic
+	}
+
+	if _, err := resolveDialBackAddress("5.6.7.8:3000", "1.2.3.4:5555"); err == nil { // Check other hosts are rejected
+		t.Errorf("dial back of other host allowed") // Log found error
+		t.FailNow()                                 // Panic
+	}
+
+	if address, err := resolveDialBackAddress("/ip6/::1/tcp/3000/tls", "[::1]:5555"); err != nil || address != "[::1]:3000" { // Check self-describing IP addresses are resolved
+		t.Errorf("invalid resolved address %s (%v)", address, err) // Log found error
+		t.FailNow()                                                // Panic
+	}
+
+	for _, address := range []string{"memory://node:3000", "unix:///tmp/node.sock", "/memory/node:3000", "relay://1.2.3.4:3001/p2p/node:3000", "localhost:3000"} { // Iterate through addresses that must not be dialed back
+		if _, err := resolveDialBackAddress(address, "1.2.3.4:5555"); err == nil { // Check address is rejected
+			t.Errorf("dial back of %s allowed", address) // Log found error
+			t.FailNow()                                  // Panic
+		}
+	}
+
+	if _, err := resolveDialBackAddress(":3000", "memory://node-peer"); err == nil { // Check requesters connected over other transports are rejected
+		t.Errorf("dial back of non-IP requester allowed") // Log found error
+		t.FailNow()                                       // Panic
+	}
+}
+
+// TestEncodeRequest - test functionality of Request, Response, Notification Encode(), Decode() methods
+func TestEncodeRequest(t *testing.T) {
+	for _, codec := range []common.Codec{common.CodecJSON, common.CodecProtobuf} { // Iterate through codecs
+		request := &Request{Kind: RequestDialBack, Target: "target", Port: 3000, Circuit: "circuit", Addresses: []string{":3000"}, Punchable: true} // Init request
+
+		encoded, err := request.Encode(codec) // Encode request
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		decoded, err := DecodeRequest(codec, encoded) // Decode request
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if decoded.Kind != request.Kind || decoded.Target != request.Target || decoded.Port != request.Port || decoded.Circuit != request.Circuit || len(decoded.Addresses) != 1 || !decoded.Punchable { // Check for mismatch
+			t.Errorf("invalid decoded %s request %v", codec, decoded) // Log found error
+			t.FailNow()                                               // Panic
+		}
+
+		encoded, err = (&Response{Observed: "1.2.3.4:5555", Reachable: []string{"1.2.3.4:3000"}, Peer: "5.6.7.8:3000"}).Encode(codec) // Encode response
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if response, err := DecodeResponse(codec, encoded); err != nil || response.Observed != "1.2.3.4:5555" || len(response.Reachable) != 1 || response.Peer != "5.6.7.8:3000" { // Check for mismatch
+			t.Errorf("invalid decoded %s response %v (%v)", codec, response, err) // Log found error
+			t.FailNow()                                                           // Panic
+		}
+
+		encoded, err = (&Notification{Kind: NotificationPunch, Circuit: "circuit", PeerID: "peer", Peer: "5.6.7.8:3000"}).Encode(codec) // Encode notification
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if notification, err := DecodeNotification(codec, encoded); err != nil || notification.Kind != NotificationPunch || notification.Circuit != "circuit" || notification.PeerID != "peer" || notification.Peer != "5.6.7.8:3000" { // Check for mismatch
+			t.Errorf("invalid decoded %s notification %v (%v)", codec, notification, err) // Log found error
+			t.FailNow()                                                                   // Panic
+		}
+	}
+}
+
+/*
+	BEGIN HELPER METHODS:
+*/
+
+// newTestNode - initialize node with a new identity at given address
+func newTestNode(t *testing.T, address string) *node.Node {
+	env, err := environment.NewEnvironment() // Init environment
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	identity, err := node.NewIdentity() // Init identity
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	testNode := &node.Node{Address: address, Environment: env} // Init node
+
+	testNode.SetIdentity(identity) // Set identity
+
+	return testNode // Return node
+}
+
+// startTestService - start service of new node on given address until ctx is cancelled
+func startTestService(ctx context.Context, t *testing.T, address string) *Service {
+	service, err := NewService(newTestNode(t, address), address) // Init service
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	err = service.Listen() // Listen on address
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	go service.Start(ctx) // Start service
+
+	return service // Return service
+}
+
+/*
+	END HELPER METHODS
+*/
@@ -0,0 +1,627 @@
+package nat
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/addressing"
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+var (
+	// DefaultCircuitTimeout - default maximum duration to wait on a target to accept a circuit
+	DefaultCircuitTimeout = 10 * time.Second
+
+	// DefaultServiceDialTimeout - default maximum duration to wait on each dial back
+	DefaultServiceDialTimeout = 5 * time.Second
+)
+
+// Service - rendezvous, relay service volunteered by a publicly reachable node. Nodes behind NAT hold reservations with the service, through which peers open relayed circuits to them, coordinate hole punching and detect their reachability.
+type Service struct {
+	Node *node.Node // Node - local node (must have an identity)
+
+	Address string // Address - transport address to accept connections on (e.g. :3001)
+
+	CircuitTimeout time.Duration // CircuitTimeout - maximum duration to wait on a target to accept a circuit
+
+	DialTimeout time.Duration // DialTimeout - maximum duration to wait on each dial back
+
+	listener net.Listener // listener - open listener
+
+	conns map[net.Conn]struct{} // conns - open connections
+
+	reservations map[string]*reservation // reservations - reservations by NodeID, port
+
+	circuits map[string]*circuit // circuits - circuits waiting to be accepted by ID
+
+	mutex sync.Mutex // mutex - guards listener, conns, reservations, circuits
+}
+
+// reservation - control channel of node accepting circuits through service
+type reservation struct {
+	conn net.Conn // conn - control connection
+
+	observed string // observed - address of reservation holder as seen by service
+
+	punchable bool // punchable - reservation was dialed from holder's listening port
+
+	codec common.Codec // codec - codec negotiated with holder
+
+	mutex sync.Mutex // mutex - guards writes to conn
+}
+
+// circuit - circuit waiting to be accepted by target
+type circuit struct {
+	target string // target - NodeID of target
+
+	conns chan net.Conn // conns - accepted target connection
+
+	done chan struct{} // done - closed once circuit is closed
+}
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// NewService - initialize service accepting connections on given transport address on behalf of given node
+func NewService(localNode *node.Node, address string) (*Service, error) {
+	if localNode == nil || localNode.NodeID == "" || address == "" { // Check for invalid parameters
+		return nil, errors.New("invalid parameters") // Return error
+	}
+
+	return &Service{Node: localNode, Address: address, CircuitTimeout: DefaultCircuitTimeout, DialTimeout: DefaultServiceDialTimeout}, nil // Return initialized service
+}
+
+// Listen - listen on service address, requiring clients to authenticate with node identity certificates
+func (service *Service) Listen() error {
+	service.mutex.Lock() // Lock service
+
+	defer service.mutex.Unlock() // Unlock service
+
+	if service.listener != nil { // Check already listening
+		return nil // Nothing to do
+	}
+
+	ln, err := service.Node.Listen(service.Address) // Listen on address
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	service.listener = *ln                                                                                                                        // Set listener
+	service.conns, service.reservations, service.circuits = make(map[net.Conn]struct{}), make(map[string]*reservation), make(map[string]*circuit) // Init state
+
+	return nil // No error occurred, return nil
+}
+
+// Start - listen on service address (unless already listening), handling requests until ctx is cancelled (closing all reservations, circuits)
+func (service *Service) Start(ctx context.Context) error {
+	err := service.Listen() // Listen on address
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	service.mutex.Lock() // Lock service
+
+	ln := service.listener // Fetch listener
+
+	service.mutex.Unlock() // Unlock service
+
+	stopped := make(chan struct{}) // Init stopped channel
+
+	defer close(stopped) // Stop watching context
+
+	go func() {
+		select {
+		case <-ctx.Done(): // Check cancelled
+			service.close() // Close listener, connections
+		case <-stopped: // Check listener failed
+		}
+	}()
+
+	for {
+		conn, err := ln.Accept() // Accept connection
+
+		if err != nil { // Check for errors
+			if netErr, isNetErr := err.(net.Error); isNetErr && netErr.Temporary() { // Check for temporary error
+				continue // Accept next connection
+			}
+
+			if ctx.Err() != nil { // Check cancelled
+				return nil // Stop
+			}
+
+			service.close() // Close connections
+
+			return err // Return found error
+		}
+
+		go service.handleConnection(conn) // Handle connection
+	}
+}
+
+/*
+	END EXPORTED METHODS
+*/
+
+/*
+	BEGIN INTERNAL METHODS:
+*/
+
+// handleConnection - read, handle single request sent over given connection, closing it once handled
+func (service *Service) handleConnection(conn net.Conn) {
+	if !service.track(conn) { // Track connection
+		conn.Close() // Close connection
+
+		return // Service closed
+	}
+
+	defer service.untrack(conn) // Close, untrack connection once handled
+
+	tlsConn, isTLS := conn.(*tls.Conn) // Check for TLS connection
+
+	if !isTLS { // Check for plain connection
+		return // Requester can't be identified
+	}
+
+	conn.SetDeadline(time.Now().Add(service.CircuitTimeout)) // Set request deadline
+
+	peerID, err := common.ConnectionPeerID(tlsConn) // Fetch requester identity
+
+	if err != nil { // Check for errors
+		return // Handshake failed
+	}
+
+	handshake, err := common.ServerHandshake(conn, service.hello) // Perform handshake
+
+	if err != nil { // Check for errors
+		return // Handshake refused
+	}
+
+	frame, err := common.ReadFrame(conn) // Read request
+
+	if err != nil || frame.Type != common.FrameTypeRequest { // Check for errors
+		return // Invalid request
+	}
+
+	request, err := decodeRequest(handshake.Codec, frame.Payload) // Decode request
+
+	if err == nil { // Check for errors
+		conn.SetDeadline(time.Time{}) // Clear deadline
+
+		switch request.Kind {
+		case RequestReserve: // Check for reservation
+			err = service.reserve(conn, handshake.Codec, peerID, request) // Hold reservation
+		case RequestConnect: // Check for circuit
+			err = service.connect(conn, handshake.Codec, peerID, request) // Open circuit
+		case RequestAccept: // Check for accepted circuit
+			err = service.accept(conn, handshake.Codec, peerID, request) // Accept circuit
+		case RequestDialBack: // Check for dial back
+			err = service.dialBack(conn, handshake.Codec, peerID, request) // Dial back
+		case RequestPunch: // Check for hole punching attempt
+			err = service.punch(conn, handshake.Codec, peerID, request) // Coordinate hole punching
+		default:
+			err = fmt.Errorf("unknown request kind %s", request.Kind) // Set error
+		}
+	}
+
+	if err != nil { // Check for errors
+		conn.SetWriteDeadline(time.Now().Add(service.CircuitTimeout)) // Set write deadline
+
+		common.WriteFrame(conn, common.FrameTypeError, []byte(err.Error())) // Write error
+	}
+}
+
+// hello - answer hello of requester with hello advertising service node (services serve peers of any network)
+func (service *Service) hello(remote *common.Hello) (*common.Hello, error) {
+	cert, err := service.Node.Certificate() // Fetch certificate presented by listener
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	hello, err := common.NodeHello(cert) // Fetch hello of service node
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	hello.NetworkID, hello.NetworkAlias = 0, "" // Serve peers of any network
+
+	return hello, nil // Return hello
+}
+
+// reserve - register given connection as control channel of requester, holding reservation until connection is closed
+func (service *Service) reserve(conn net.Conn, codec common.Codec, peerID string, request *Request) error {
+	holder := &reservation{conn: conn, observed: conn.RemoteAddr().String(), punchable: request.Punchable, codec: codec} // Init reservation
+
+	err := respond(conn, codec, &Response{Observed: holder.observed}) // Respond before notifications can be sent
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	key := reservationKey(peerID, request.Port) // Init reservation key
+
+	service.mutex.Lock() // Lock service
+
+	previous := service.reservations[key] // Fetch previous reservation
+
+	service.reservations[key] = holder // Set reservation
+
+	service.mutex.Unlock() // Unlock service
+
+	if previous != nil { // Check for stale reservation
+		previous.conn.Close() // Close stale reservation
+	}
+
+	common.Printf("\n-- RELAY -- reserved circuits to %s:%d", peerID, request.Port) // Log reservation
+
+	io.Copy(ioutil.Discard, conn) // Wait on holder to close connection
+
+	service.mutex.Lock() // Lock service
+
+	if service.reservations[key] == holder { // Check reservation wasn't replaced
+		delete(service.reservations, key) // Remove reservation
+	}
+
+	service.mutex.Unlock() // Unlock service
+
+	return nil // No error occurred, return nil
+}
+
+// connect - open circuit from given connection to target of request, relaying traffic until either side closes the circuit
+func (service *Service) connect(conn net.Conn, codec common.Codec, peerID string, request *Request) error {
+	holder, err := service.reservation(request.Target, request.Port) // Fetch target reservation
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	id, err := newCircuitID() // Generate circuit ID
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	pending := &circuit{target: request.Target, conns: make(chan net.Conn), done: make(chan struct{})} // Init circuit
+
+	service.mutex.Lock() // Lock service
+
+	service.circuits[id] = pending // Register circuit
+
+	service.mutex.Unlock() // Unlock service
+
+	defer func() {
+		service.mutex.Lock() // Lock service
+
+		delete(service.circuits, id) // Unregister circuit
+
+		service.mutex.Unlock() // Unlock service
+
+		close(pending.done) // Release accepted connection
+	}()
+
+	err = holder.notify(&Notification{Kind: NotificationCircuit, Circuit: id, PeerID: peerID}, service.CircuitTimeout) // Notify target
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	timer := time.NewTimer(service.CircuitTimeout) // Init timeout
+
+	defer timer.Stop() // Stop timer
+
+	var targetConn net.Conn // Init target connection buffer
+
+	select {
+	case targetConn = <-pending.conns: // Check accepted
+	case <-timer.C: // Check timed out
+		return fmt.Errorf("circuit to %s wasn't accepted", request.Target) // Return error
+	}
+
+	err = respond(conn, codec, &Response{Observed: conn.RemoteAddr().String()}) // Respond
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	splice(conn, targetConn) // Relay traffic
+
+	return nil // No error occurred, return nil
+}
+
+// accept - hand given connection to circuit of request, waiting on circuit to be closed
+func (service *Service) accept(conn net.Conn, codec common.Codec, peerID string, request *Request) error {
+	service.mutex.Lock() // Lock service
+
+	pending, found := service.circuits[request.Circuit] // Fetch circuit
+
+	service.mutex.Unlock() // Unlock service
+
+	if !found || pending.target != peerID { // Check for unknown circuit
+		return fmt.Errorf("unknown circuit %s", request.Circuit) // Return error
+	}
+
+	err := respond(conn, codec, &Response{Observed: conn.RemoteAddr().String()}) // Respond before traffic is relayed
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	select {
+	case pending.conns <- conn: // Hand connection to circuit
+		<-pending.done // Wait on circuit to close
+	case <-pending.done: // Check circuit closed
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// dialBack - dial requester back on addresses of request, responding with addresses that accepted connections
+func (service *Service) dialBack(conn net.Conn, codec common.Codec, peerID string, request *Request) error {
+	response := &Response{Observed: conn.RemoteAddr().String()} // Init response
+
+	for x, address := range request.Addresses { // Iterate through addresses
+		if x == maxDialBackAddresses { // Check for too many addresses
+			break // Stop dialing
+		}
+
+		address, err := resolveDialBackAddress(address, response.Observed) // Resolve address
+
+		if err != nil { // Check for errors
+			continue // Skip address
+		}
+
+		peerConn, err := common.DialAddressTimeout(address, common.PeerTLSConfig(peerID), service.DialTimeout) // Dial requester
+
+		if err != nil { // Check for errors
+			continue // Unreachable
+		}
+
+		peerConn.Close() // Close connection
+
+		response.Reachable = append(response.Reachable, address) // Append reachable address
+	}
+
+	return respond(conn, codec, response) // Respond
+}
+
+// punch - exchange observed addresses of requester, target of request so that both can dial each other
+func (service *Service) punch(conn net.Conn, codec common.Codec, peerID string, request *Request) error {
+	holder, err := service.reservation(request.Target, request.Port) // Fetch target reservation
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	if !holder.punchable { // Check target can't punch holes
+		return fmt.Errorf("%s doesn't support hole punching", request.Target) // Return error
+	}
+
+	observed := conn.RemoteAddr().String() // Fetch requester address
+
+	err = holder.notify(&Notification{Kind: NotificationPunch, PeerID: peerID, Peer: observed}, service.CircuitTimeout) // Notify target
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	return respond(conn, codec, &Response{Observed: observed, Peer: holder.observed}) // Respond with target address
+}
+
+// reservation - fetch reservation of given NodeID on given port
+func (service *Service) reservation(nodeID string, port uint) (*reservation, error) {
+	service.mutex.Lock() // Lock service
+
+	defer service.mutex.Unlock() // Unlock service
+
+	holder, found := service.reservations[reservationKey(nodeID, port)] // Fetch reservation
+
+	if !found { // Check for no reservation
+		return nil, ErrNoReservation // Return error
+	}
+
+	return holder, nil // Return reservation
+}
+
+// track - register given connection, returning false if service is closed
+func (service *Service) track(conn net.Conn) bool {
+	service.mutex.Lock() // Lock service
+
+	defer service.mutex.Unlock() // Unlock service
+
+	if service.conns == nil { // Check closed
+		return false // Service closed
+	}
+
+	service.conns[conn] = struct{}{} // Register connection
+
+	return true // Connection tracked
+}
+
+// untrack - close, unregister given connection (once requester closes it)
+func (service *Service) untrack(conn net.Conn) {
+	release(conn, service.CircuitTimeout) // Wait on requester to close connection
+
+	service.mutex.Lock() // Lock service
+
+	defer service.mutex.Unlock() // Unlock service
+
+	delete(service.conns, conn) // Unregister connection
+}
+
+// close - close listener, all connections
+func (service *Service) close() {
+	service.mutex.Lock() // Lock service
+
+	defer service.mutex.Unlock() // Unlock service
+
+	if service.listener != nil { // Check listening
+		service.listener.Close() // Close listener
+	}
+
+	for conn := range service.conns { // Iterate through connections
+		conn.Close() // Close connection
+	}
+
+	service.listener, service.conns = nil, nil // Reset state
+}
+
+// notify - send given notification to reservation holder within given timeout
+func (holder *reservation) notify(notification *Notification, timeout time.Duration) error {
+	payload, err := notification.Encode(holder.codec) // Encode notification
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	payload, err = common.Seal(holder.codec, common.EnvelopeKindRelayNotification, payload) // Wrap notification in envelope
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	holder.mutex.Lock() // Lock reservation
+
+	defer holder.mutex.Unlock() // Unlock reservation
+
+	holder.conn.SetWriteDeadline(time.Now().Add(timeout)) // Set write deadline
+
+	defer holder.conn.SetWriteDeadline(time.Time{}) // Clear write deadline
+
+	return common.WriteFrame(holder.conn, common.FrameTypeMessage, payload) // Write notification
+}
+
+// respond - write given response encoded with given codec to given connection
+func respond(conn net.Conn, codec common.Codec, response *Response) error {
+	payload, err := response.Encode(codec) // Encode response
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	return common.WriteFrame(conn, common.FrameTypeResponse, payload) // Write response
+}
+
+// splice - copy traffic between given connections until either is closed
+func splice(a net.Conn, b net.Conn) {
+	done := make(chan struct{}, 2) // Init done buffer
+
+	go func() {
+		io.Copy(a, b)      // Relay traffic from b
+		done <- struct{}{} // Signal done
+	}()
+
+	go func() {
+		io.Copy(b, a)      // Relay traffic from a
+		done <- struct{}{} // Signal done
+	}()
+
+	<-done // Wait on either side to close
+
+	a.Close() // Close connection
+	b.Close() // Close connection
+
+	<-done // Wait on other side
+}
+
+// decodeRequest - decode request wrapped in envelope encoded with given codec
+func decodeRequest(codec common.Codec, b []byte) (*Request, error) {
+	envelope, err := common.DecodeEnvelope(codec, b) // Decode envelope
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if envelope.Kind != common.EnvelopeKindRelayRequest { // Check for other payload
+		return nil, fmt.Errorf("unsupported envelope kind %s", envelope.Kind) // Return error
+	}
+
+	return DecodeRequest(codec, envelope.Payload) // Decode request
+}
+
+// resolveDialBackAddress - resolve TCP address (or self-describing IP address) without host (e.g. :3000) against given observed address, rejecting addresses of other hosts and of any other transport (unix, in-process and relayed addresses must never be dialed on behalf of a requester)
+func resolveDialBackAddress(address string, observed string) (string, error) {
+	if addressing.IsAddress(address) { // Check for self-describing address
+		parsed, err := addressing.Parse(address) // Parse address
+
+		if err != nil { // Check for errors
+			return "", err // Return found error
+		}
+
+		if parsed.Network != addressing.ProtocolIP4 && parsed.Network != addressing.ProtocolIP6 { // Check for non-IP address
+			return "", fmt.Errorf("can't dial back %s address %s", parsed.Network, address) // Return error
+		}
+
+		if address, err = parsed.TransportAddress(); err != nil { // Convert to transport address
+			return "", err // Return found error
+		}
+	}
+
+	transport, parsedAddress, err := common.ParseTransportAddress(address) // Parse address
+
+	if err != nil { // Check for errors
+		return "", err // Return found error
+	}
+
+	if _, isTCP := transport.(*common.TCPTransport); !isTCP { // Check for non-TCP address
+		return "", fmt.Errorf("can't dial back non-TCP address %s", address) // Return error
+	}
+
+	host, port, err := net.SplitHostPort(parsedAddress) // Split address
+
+	if err != nil { // Check for errors
+		return "", err // Return found error
+	}
+
+	observedHost, _, err := net.SplitHostPort(observed) // Split observed address
+
+	if err != nil { // Check for errors
+		return "", err // Return found error
+	}
+
+	observedIP := net.ParseIP(observedHost) // Parse observed host
+
+	if observedIP == nil { // Check requester didn't connect over IP
+		return "", fmt.Errorf("can't dial back requester at non-IP address %s", observed) // Return error
+	}
+
+	if host != "" && !observedIP.Equal(net.ParseIP(host)) { // Check for other host (hostnames are never resolved)
+		return "", fmt.Errorf("can't dial back %s from %s", address, observed) // Return error
+	}
+
+	return net.JoinHostPort(observedIP.String(), port), nil // Return resolved address
+}
+
+// reservationKey - key of reservation of given NodeID on given port
+func reservationKey(nodeID string, port uint) string {
+	return nodeID + ":" + strconv.Itoa(int(port)) // Return key
+}
+
+// newCircuitID - generate random circuit ID
+func newCircuitID() (string, error) {
+	id := make([]byte, 16) // Init ID buffer
+
+	if _, err := rand.Read(id); err != nil { // Generate ID
+		return "", err // Return found error
+	}
+
+	return hex.EncodeToString(id), nil // Return ID
+}
+
+/*
+	END INTERNAL METHODS
+*/
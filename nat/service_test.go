@@ -0,0 +1,62 @@
+package nat
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dowlandaiello/GoP2P/types/database"
+	"github.com/dowlandaiello/GoP2P/types/handler"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+// TestRelay - test functionality of circuits relayed to a node that is only reachable through a service
+func TestRelay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background()) // Init context
+
+	defer cancel() // Stop service
+
+	startTestService(ctx, t, "memory://nat-relay-service:3001") // Start service
+
+	relayedNode := newTestNode(t, "") // Init relayed node
+
+	relayedNode.Address, relayedNode.Reachability = RelayAddress("memory://nat-relay-service:3001", relayedNode.NodeID), node.ReachabilityPrivate // Advertise relayed address
+
+	ln, err := relayedNode.Listen(relayedNode.Address + ":3000") // Reserve circuits
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	defer (*ln).Close() // Release reservation
+
+	go handler.StartHandler(relayedNode, ln) // Start handler
+
+	dialingNode := newTestNode(t, "memory://nat-relay-dialer") // Init dialing node
+
+	dialingLn, err := dialingNode.Listen(dialingNode.Address + ":3000") // Present dialing node certificate on outgoing connections
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	defer (*dialingLn).Close() // Stop listening
+
+	result, err := database.PingNode(dialingNode, &node.Node{NodeID: relayedNode.NodeID, Address: relayedNode.Address}, "GoP2P_TestNet", 3000) // Ping relayed node
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if result.Responder.NodeID != relayedNode.NodeID || result.Responder.Reachability != node.ReachabilityPrivate { // Check relayed node answered
+		t.Errorf("invalid responder %v", result.Responder) // Log found error
+		t.FailNow()                                        // Panic
+	}
+
+	if _, err = database.PingNode(dialingNode, &node.Node{Address: RelayAddress("memory://nat-relay-service:3001", dialingNode.NodeID)}, "GoP2P_TestNet", 3000); err == nil { // Ping node without reservation
+		t.Errorf("circuit opened to node without reservation") // Log found error
+		t.FailNow()                                            // Panic
+	}
+}
@@ -0,0 +1,432 @@
+package nat
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/common"
+)
+
+const (
+	// TransportScheme - scheme of relayed addresses (e.g. relay://1.2.3.4:3001/p2p/<NodeID>:3000)
+	TransportScheme = common.TransportSchemeRelay
+
+	// relayAddressSeparator - separator between service address, NodeID of relayed addresses
+	relayAddressSeparator = "/p2p/"
+)
+
+var (
+	// DefaultPunchTimeout - default maximum duration to spend hole punching before falling back to a relayed circuit
+	DefaultPunchTimeout = 5 * time.Second
+
+	// DefaultReservationRetryInterval - default duration between attempts to restore a lost reservation
+	DefaultReservationRetryInterval = 5 * time.Second
+
+	// DefaultTransport - relay transport registered for relay:// addresses
+	DefaultTransport = &Transport{PunchTimeout: DefaultPunchTimeout, ReservationRetryInterval: DefaultReservationRetryInterval}
+
+	// punchRetryInterval - duration between dials while hole punching
+	punchRetryInterval = 100 * time.Millisecond
+)
+
+// Transport - transport dialing nodes behind NAT through rendezvous, relay services (attempting TCP hole punching first if LocalPort is set), and accepting connections relayed to the local node. Relayed connections are end-to-end TLS secured by common.DialAddress and common.ListenAddress like any other connection; services only see ciphertext. Only TCP hole punching is supported: every GoP2P transport is a TLS-secured stream, so UDP hole punching (which would need a datagram transport such as QUIC) is out of scope.
+type Transport struct {
+	LocalPort uint // LocalPort - listening TCP port hole punching dials, reservations are made from (requires common.TCPTransport.ReusePort). Hole punching is disabled if 0.
+
+	PunchTimeout time.Duration // PunchTimeout - maximum duration to spend hole punching before falling back to a relayed circuit
+
+	ReservationRetryInterval time.Duration // ReservationRetryInterval - duration between attempts to restore a lost reservation
+}
+
+// relayAddress - parsed relayed address
+type relayAddress struct {
+	service string // service - transport address of service
+
+	nodeID string // nodeID - NodeID of relayed node
+
+	port uint // port - port of relayed node
+}
+
+// relayListener - listener accepting circuits relayed to the local node
+type relayListener struct {
+	transport *Transport // transport - transport listener was opened by
+
+	address *relayAddress // address - relayed address of local node
+
+	cert *tls.Certificate // cert - local certificate presented to service
+
+	conns chan net.Conn // conns - accepted circuits
+
+	control net.Conn // control - reservation control channel
+
+	done chan struct{} // done - closed once listener is closed
+
+	closeOnce sync.Once // closeOnce - ensures listener is closed once
+
+	mutex sync.Mutex // mutex - guards control
+}
+
+// relayConn - relayed connection reporting relayed addresses
+type relayConn struct {
+	net.Conn // Conn - connection to service (or peer, if hole punched)
+
+	local net.Addr // local - local address
+
+	remote net.Addr // remote - remote address
+}
+
+// Addr - net.Addr implementation for relayed addresses
+type Addr string
+
+func init() {
+	common.RegisterTransport(DefaultTransport) // Register relay transport
+}
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// Scheme - implement common.Transport interface
+func (transport *Transport) Scheme() string {
+	return TransportScheme // Return scheme
+}
+
+// ParseAddress - validate given service/p2p/NodeID[:port] address
+func (transport *Transport) ParseAddress(address string) (string, error) {
+	parsedAddress, err := parseRelayAddress(address) // Parse address
+
+	if err != nil { // Check for errors
+		return "", err // Return found error
+	}
+
+	return parsedAddress.String()[len(TransportScheme+"://"):], nil // Return address
+}
+
+// Dial - open connection to relayed node at given address, hole punching if possible and falling back to a circuit relayed by the service
+func (transport *Transport) Dial(address string, timeout time.Duration) (net.Conn, error) {
+	target, err := parseRelayAddress(address) // Parse address
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if transport.LocalPort != 0 { // Check hole punching enabled
+		conn, err := transport.punch(target, timeout) // Punch hole
+
+		if err == nil { // Check for success
+			return conn, nil // Return direct connection
+		}
+
+		common.Printf("\n-- RELAY -- hole punching to %s failed, relaying: %s", target.nodeID, err.Error()) // Log failure
+	}
+
+	conn, _, _, err := request(target.service, 0, nil, &Request{Kind: RequestConnect, Target: target.nodeID, Port: target.port}, timeout) // Open circuit
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return &relayConn{Conn: conn, local: conn.LocalAddr(), remote: Addr(target.String())}, nil // Return relayed connection
+}
+
+// Listen - reserve circuits to local node at given address (NodeID must belong to a node listening in this process, see node.Listen) with its service, accepting relayed connections until closed. Lost reservations are restored every ReservationRetryInterval.
+func (transport *Transport) Listen(address string) (net.Listener, error) {
+	local, err := parseRelayAddress(address) // Parse address
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	cert, found := common.NodeCertificate(local.nodeID) // Fetch certificate of listening node
+
+	if !found { // Check address belongs to a node listening in this process
+		return nil, fmt.Errorf("can't listen on relayed address of %s", local.nodeID) // Return error
+	}
+
+	ln := &relayListener{transport: transport, address: local, cert: cert, conns: make(chan net.Conn), done: make(chan struct{})} // Init listener
+
+	control, codec, err := ln.reserve() // Reserve circuits
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	go ln.serve(control, codec) // Handle notifications
+
+	return ln, nil // Return listener
+}
+
+// Accept - implement net.Listener interface
+func (ln *relayListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-ln.conns: // Check for circuit
+		return conn, nil // Return circuit
+	case <-ln.done: // Check closed
+		return nil, common.ErrTransportClosed // Return error
+	}
+}
+
+// Close - implement net.Listener interface
+func (ln *relayListener) Close() error {
+	ln.closeOnce.Do(func() {
+		close(ln.done) // Notify pending accepts
+
+		ln.mutex.Lock() // Lock listener
+
+		if ln.control != nil { // Check for reservation
+			ln.control.Close() // Release reservation
+		}
+
+		ln.mutex.Unlock() // Unlock listener
+	})
+
+	return nil // No error occurred, return nil
+}
+
+// Addr - implement net.Listener interface
+func (ln *relayListener) Addr() net.Addr {
+	return Addr(ln.address.String()) // Return address
+}
+
+// LocalAddr - implement net.Conn interface
+func (conn *relayConn) LocalAddr() net.Addr {
+	return conn.local // Return local address
+}
+
+// RemoteAddr - implement net.Conn interface
+func (conn *relayConn) RemoteAddr() net.Addr {
+	return conn.remote // Return remote address
+}
+
+// Network - implement net.Addr interface
+func (addr Addr) Network() string {
+	return TransportScheme // Return network
+}
+
+// String - implement net.Addr interface
+func (addr Addr) String() string {
+	return string(addr) // Return address
+}
+
+/*
+	END EXPORTED METHODS
+*/
+
+/*
+	BEGIN INTERNAL METHODS:
+*/
+
+// punch - coordinate hole punching with given target through its service, dialing target's observed address from transport.LocalPort until transport.PunchTimeout
+func (transport *Transport) punch(target *relayAddress, timeout time.Duration) (net.Conn, error) {
+	serviceTransport, _, err := common.ParseTransportAddress(target.service) // Parse service address
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if _, isTCP := serviceTransport.(*common.TCPTransport); !isTCP { // Check service isn't reachable over TCP
+		return nil, errors.New("service isn't reachable over TCP") // Return error
+	}
+
+	conn, response, _, err := request(target.service, transport.LocalPort, nil, &Request{Kind: RequestPunch, Target: target.nodeID, Port: target.port}, timeout) // Exchange addresses
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	conn.Close() // Close connection
+
+	deadline := time.Now().Add(transport.PunchTimeout) // Init deadline
+
+	for {
+		conn, err = common.DefaultTCPTransport.DialFrom(transport.LocalPort, response.Peer, transport.PunchTimeout) // Dial target
+
+		if err == nil { // Check for success
+			return conn, nil // Return connection
+		}
+
+		if time.Now().Add(punchRetryInterval).After(deadline) { // Check deadline exceeded
+			return nil, err // Return found error
+		}
+
+		time.Sleep(punchRetryInterval) // Wait on target to open its NAT mapping
+	}
+}
+
+// reserve - open reservation control channel with service of listener address, returning it along with the codec notifications are encoded with
+func (ln *relayListener) reserve() (net.Conn, common.Codec, error) {
+	control, _, codec, err := request(ln.address.service, ln.transport.LocalPort, ln.cert, &Request{Kind: RequestReserve, Port: ln.address.port, Punchable: ln.transport.LocalPort != 0}, common.DefaultDialTimeout) // Reserve circuits
+
+	if err != nil { // Check for errors
+		return nil, "", err // Return found error
+	}
+
+	ln.mutex.Lock() // Lock listener
+
+	defer ln.mutex.Unlock() // Unlock listener
+
+	select {
+	case <-ln.done: // Check closed
+		control.Close() // Release reservation
+
+		return nil, "", common.ErrTransportClosed // Return error
+	default:
+	}
+
+	ln.control = control // Set control channel
+
+	return control, codec, nil // Return control channel, codec
+}
+
+// serve - handle notifications encoded with given codec sent over given control channel, restoring reservation once lost until listener is closed
+func (ln *relayListener) serve(control net.Conn, codec common.Codec) {
+	for {
+		for {
+			frame, err := common.ReadFrame(control) // Read notification
+
+			if err != nil { // Check for errors
+				break // Reservation lost
+			}
+
+			if frame.Type != common.FrameTypeMessage { // Check for invalid frame
+				continue // Skip frame
+			}
+
+			notification, err := decodeNotification(codec, frame.Payload) // Decode notification
+
+			if err != nil { // Check for errors
+				continue // Skip invalid notification
+			}
+
+			switch notification.Kind {
+			case NotificationCircuit: // Check for circuit
+				go ln.accept(notification) // Accept circuit
+			case NotificationPunch: // Check for hole punching attempt
+				go ln.punch(notification) // Open NAT mapping towards peer
+			}
+		}
+
+		control.Close() // Close control channel
+
+		interval := ln.transport.ReservationRetryInterval // Fetch retry interval
+
+		if interval == 0 { // Check for unset interval
+			interval = DefaultReservationRetryInterval // Set default interval
+		}
+
+		for {
+			select {
+			case <-ln.done: // Check closed
+				return // Stop
+			case <-time.After(interval): // Wait before retrying
+			}
+
+			var err error // Init error buffer
+
+			if control, codec, err = ln.reserve(); err == nil { // Restore reservation
+				break // Handle notifications
+			}
+
+			common.Printf("\n-- RELAY -- couldn't restore reservation with %s: %s", ln.address.service, err.Error()) // Log failure
+		}
+	}
+}
+
+// accept - accept circuit of given notification, queueing it to be returned by Accept
+func (ln *relayListener) accept(notification *Notification) {
+	conn, _, _, err := request(ln.address.service, 0, ln.cert, &Request{Kind: RequestAccept, Circuit: notification.Circuit}, common.DefaultDialTimeout) // Accept circuit
+
+	if err != nil { // Check for errors
+		common.Printf("\n-- RELAY -- couldn't accept circuit from %s: %s", notification.PeerID, err.Error()) // Log failure
+
+		return // Stop
+	}
+
+	relayed := &relayConn{Conn: conn, local: ln.Addr(), remote: Addr((&relayAddress{service: ln.address.service, nodeID: notification.PeerID}).String())} // Init relayed connection
+
+	select {
+	case ln.conns <- relayed: // Queue circuit
+	case <-ln.done: // Check closed
+		conn.Close() // Close circuit
+	}
+}
+
+// punch - dial peer of given notification from transport.LocalPort, opening a NAT mapping towards it (the connection itself is discarded)
+func (ln *relayListener) punch(notification *Notification) {
+	if ln.transport.LocalPort == 0 || notification.Peer == "" { // Check hole punching disabled
+		return // Nothing to do
+	}
+
+	conn, err := common.DefaultTCPTransport.DialFrom(ln.transport.LocalPort, notification.Peer, punchRetryInterval) // Dial peer
+
+	if err == nil { // Check for success
+		conn.Close() // Close connection
+	}
+}
+
+// decodeNotification - decode notification wrapped in envelope encoded with given codec
+func decodeNotification(codec common.Codec, b []byte) (*Notification, error) {
+	envelope, err := common.DecodeEnvelope(codec, b) // Decode envelope
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if envelope.Kind != common.EnvelopeKindRelayNotification { // Check for other payload
+		return nil, fmt.Errorf("unsupported envelope kind %s", envelope.Kind) // Return error
+	}
+
+	return DecodeNotification(codec, envelope.Payload) // Decode notification
+}
+
+// parseRelayAddress - parse given service/p2p/NodeID[:port] address
+func parseRelayAddress(address string) (*relayAddress, error) {
+	index := strings.LastIndex(address, relayAddressSeparator) // Find NodeID
+
+	if index <= 0 { // Check for missing service, NodeID
+		return nil, fmt.Errorf("invalid relay address %s", address) // Return error
+	}
+
+	parsed := &relayAddress{service: address[:index], nodeID: address[index+len(relayAddressSeparator):]} // Init address
+
+	if portIndex := strings.LastIndex(parsed.nodeID, ":"); portIndex != -1 { // Check for port
+		port, err := strconv.ParseUint(parsed.nodeID[portIndex+1:], 10, 16) // Parse port
+
+		if err != nil { // Check for errors
+			return nil, fmt.Errorf("invalid port in relay address %s", address) // Return error
+		}
+
+		parsed.nodeID, parsed.port = parsed.nodeID[:portIndex], uint(port) // Set NodeID, port
+	}
+
+	if parsed.nodeID == "" { // Check for missing NodeID
+		return nil, fmt.Errorf("missing NodeID in relay address %s", address) // Return error
+	}
+
+	if _, _, err := common.ParseTransportAddress(parsed.service); err != nil { // Check for invalid service address
+		return nil, err // Return found error
+	}
+
+	return parsed, nil // Return parsed address
+}
+
+// String - convert relayed address to relay://service/p2p/NodeID[:port] string
+func (address *relayAddress) String() string {
+	relayed := TransportScheme + "://" + address.service + relayAddressSeparator + address.nodeID // Init address
+
+	if address.port != 0 { // Check for port
+		relayed += ":" + strconv.Itoa(int(address.port)) // Append port
+	}
+
+	return relayed // Return address
+}
+
+/*
+	END INTERNAL METHODS
+*/
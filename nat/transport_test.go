@@ -0,0 +1,136 @@
+package nat
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/types/handler"
+)
+
+// TestParseRelayAddress - test functionality of relayed address parsing
+func TestParseRelayAddress(t *testing.T) {
+	address, err := parseRelayAddress("memory://service:3001/p2p/abcd:3000") // Parse address
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if address.service != "memory://service:3001" || address.nodeID != "abcd" || address.port != 3000 || address.String() != "relay://memory://service:3001/p2p/abcd:3000" { // Check for mismatch
+		t.Errorf("invalid parsed address %v", address) // Log found error
+		t.FailNow()                                    // Panic
+	}
+
+	for _, invalid := range []string{"1.2.3.4:3001", "1.2.3.4:3001/p2p/", "/p2p/abcd", "1.2.3.4:3001/p2p/abcd:port", "1.2.3.4/p2p/abcd"} { // Iterate through invalid addresses
+		if _, err := parseRelayAddress(invalid); err == nil { // Check address was rejected
+			t.Errorf("invalid address %s accepted", invalid) // Log found error
+			t.FailNow()                                      // Panic
+		}
+	}
+}
+
+// TestHolePunch - test functionality of TCP hole punching coordinated through a service (over loopback)
+func TestHolePunch(t *testing.T) {
+	common.DefaultTCPTransport.ReusePort = true // Share listening ports with hole punching dials
+
+	defer func() { common.DefaultTCPTransport.ReusePort = false }() // Reset transport
+
+	ctx, cancel := context.WithCancel(context.Background()) // Init context
+
+	defer cancel() // Stop service
+
+	service := startTestService(ctx, t, "127.0.0.1:"+strconv.Itoa(freePort(t))) // Start service
+
+	targetPort, dialerPort := freePort(t), freePort(t) // Fetch free ports
+
+	target := newTestNode(t, "127.0.0.1") // Init target
+
+	targetLn, err := target.Listen("127.0.0.1:" + strconv.Itoa(targetPort)) // Listen on shared port
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	defer (*targetLn).Close() // Stop listening
+
+	go handler.StartHandler(target, targetLn) // Start handler
+
+	targetTransport := &Transport{LocalPort: uint(targetPort), PunchTimeout: DefaultPunchTimeout, ReservationRetryInterval: DefaultReservationRetryInterval} // Init target transport
+
+	relayedAddress := RelayAddress(service.Address, target.NodeID) + ":3000" // Init relayed address
+
+	relayLn, err := targetTransport.Listen(relayedAddress[len(TransportScheme+"://"):]) // Reserve circuits from shared port
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	defer relayLn.Close() // Release reservation
+
+	dialer := newTestNode(t, "127.0.0.1") // Init dialer
+
+	dialerLn, err := dialer.Listen("127.0.0.1:" + strconv.Itoa(dialerPort)) // Listen on shared port
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	defer (*dialerLn).Close() // Stop listening
+
+	go handler.StartHandler(dialer, dialerLn) // Start handler
+
+	dialerTransport := &Transport{LocalPort: uint(dialerPort), PunchTimeout: DefaultPunchTimeout} // Init dialer transport
+
+	conn, err := dialerTransport.Dial(relayedAddress[len(TransportScheme+"://"):], common.DefaultDialTimeout) // Dial target
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	defer conn.Close() // Close connection
+
+	if _, isRelayed := conn.(*relayConn); isRelayed || conn.RemoteAddr().String() != "127.0.0.1:"+strconv.Itoa(targetPort) { // Check connection is direct
+		t.Errorf("expected direct connection, found connection to %s", conn.RemoteAddr()) // Log found error
+		t.FailNow()                                                                       // Panic
+	}
+
+	tlsConn := tls.Client(conn, common.PeerTLSConfig(target.NodeID)) // Authenticate target
+
+	tlsConn.SetDeadline(time.Now().Add(common.DefaultDialTimeout)) // Set handshake deadline
+
+	if err = tlsConn.Handshake(); err != nil { // Perform TLS handshake
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+}
+
+/*
+	BEGIN HELPER METHODS:
+*/
+
+// freePort - fetch free loopback TCP port
+func freePort(t *testing.T) int {
+	ln, err := net.Listen("tcp", "127.0.0.1:0") // Listen on any port
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	defer ln.Close() // Release port
+
+	return ln.Addr().(*net.TCPAddr).Port // Return port
+}
+
+/*
+	END HELPER METHODS
+*/
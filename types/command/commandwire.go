@@ -0,0 +1,70 @@
+package command
+
+import (
+	"encoding/json"
+
+	"github.com/dowlandaiello/GoP2P/internal/rpc/proto/wire"
+	"github.com/dowlandaiello/GoP2P/types/environment"
+)
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// ToWire - convert command to protobuf wire type (modifier value is JSON-encoded, as it is dynamically typed)
+func (command *Command) ToWire() (*wire.Command, error) {
+	if command == nil { // Check for nil command
+		return nil, nil // Return nil
+	}
+
+	wireCommand := &wire.Command{Command: command.Command} // Init wire command
+
+	if command.ModifierSet != nil { // Check for modifiers
+		wireCommand.Modifiers = &wire.ModifierSet{
+			Type:     command.ModifierSet.Type,              // Set type
+			Variable: command.ModifierSet.Variable.ToWire(), // Set variable
+		} // Init wire modifiers
+
+		if command.ModifierSet.Value != nil { // Check for value
+			value, err := json.Marshal(command.ModifierSet.Value) // Encode value
+
+			if err != nil { // Check for errors
+				return nil, err // Return found error
+			}
+
+			wireCommand.Modifiers.Value = value // Set value
+		}
+	}
+
+	return wireCommand, nil // Return wire command
+}
+
+// FromWire - convert protobuf wire type to command
+func FromWire(wireCommand *wire.Command) (*Command, error) {
+	if wireCommand == nil { // Check for nil command
+		return nil, nil // Return nil
+	}
+
+	command := &Command{Command: wireCommand.Command} // Init command
+
+	if wireCommand.Modifiers != nil { // Check for modifiers
+		command.ModifierSet = &ModifierSet{
+			Type:     wireCommand.Modifiers.Type,                                   // Set type
+			Variable: environment.VariableFromWire(wireCommand.Modifiers.Variable), // Set variable
+		} // Init modifiers
+
+		if len(wireCommand.Modifiers.Value) != 0 { // Check for value
+			err := json.Unmarshal(wireCommand.Modifiers.Value, &command.ModifierSet.Value) // Decode value
+
+			if err != nil { // Check for errors
+				return nil, err // Return found error
+			}
+		}
+	}
+
+	return command, nil // Return command
+}
+
+/*
+	END EXPORTED METHODS
+*/
@@ -3,7 +3,6 @@ package connection
 import (
 	"errors"
 	"reflect"
-	"strconv"
 	"strings"
 
 	"github.com/dowlandaiello/GoP2P/common"
@@ -44,7 +43,7 @@ type Resolution struct {
 	BEGIN EXPORTED METHODS:
 */
 
-// NewConnection - creates new Connection{} instance with specified data, peers
+// NewConnection - creates new Connection{} instance with specified data, peers. If the destination advertises self-describing addresses (see node.NodeFromAddress), the first supported address is dialed instead of the destination's address at the given port.
 func NewConnection(sourceNode *node.Node, destinationNode *node.Node, port int, data []byte, connectionType string, connectionStack []Event) (*Connection, error) {
 	if strings.ToLower(connectionType) != "relay" && strings.ToLower(connectionType) != "pointer" { // Check connection type is valid
 		return &Connection{}, errors.New("invalid connection type") // Error occurred, return nil
@@ -52,6 +51,8 @@ func NewConnection(sourceNode *node.Node, destinationNode *node.Node, port int,
 		return &Connection{}, errors.New("invalid peer value") // Peer values nil, return nil constructor
 	} else if len(data) == 0 { // Check that data is being passed trough
 		return &Connection{}, errors.New("invalid data") // Return error
+	} else if err := destinationNode.CheckAddresses(); err != nil { // Check advertised addresses of destination are valid
+		return &Connection{}, err // Return found error
 	}
 
 	return &Connection{DestinationNode: destinationNode, Port: port, InitializationNode: sourceNode, Data: data, ConnectionType: connectionType, ConnectionStack: connectionStack}, nil // No error occurred, return correctly initialized Connection
@@ -66,25 +67,36 @@ func NewResolution(data []byte, guidingType interface{}) (*Resolution, error) {
 	return &Resolution{ResolutionData: data, GuidingType: guidingType}, nil // No error occurred, return initialized Resolution
 }
 
-// Attempt - attempts to carry out connection, if event stack is provided, begins to iterate through list
+// Attempt - attempts to carry out connection, if event stack is provided, begins to iterate through list (returns raw response, encoded with codec negotiated with destination)
 func (connection *Connection) Attempt() ([]byte, error) {
-	return connection.attempt() // Found connection stack, handle respectively
+	result, _, err := connection.attempt() // Found connection stack, handle respectively
+
+	return result, err // Return result
 }
 
-// AttemptVariable - attempts to carry out connection, returning variable response
-func (connection *Connection) AttemptVariable() (*environment.Variable, error) {
-	response, err := connection.attempt() // Attempt connection
+// AttemptResponse - attempts to carry out connection, returning decoded response
+func (connection *Connection) AttemptResponse() (*Response, error) {
+	result, codec, err := connection.attempt() // Attempt connection
 
 	if err != nil { // Check for errors
-		return &environment.Variable{}, err // Return found error
+		return nil, err // Return found error
 	}
 
-	decodedResponse, err := ResponseFromBytes(response) // Fetch decoded result
+	return DecodeResponse(codec, result) // Decode response
+}
+
+// AttemptVariable - attempts to carry out connection, returning variable response
+func (connection *Connection) AttemptVariable() (*environment.Variable, error) {
+	decodedResponse, err := connection.AttemptResponse() // Attempt connection
 
 	if err != nil { // Check for errors
 		return &environment.Variable{}, err // Return found error
 	}
 
+	if len(decodedResponse.Val) == 0 { // Check for empty response
+		return &environment.Variable{}, errors.New("nil response") // Return error
+	}
+
 	return environment.VariableFromBytes(decodedResponse.Val[0]) // Return final decoded response
 }
 
@@ -92,27 +104,43 @@ func (connection *Connection) AttemptVariable() (*environment.Variable, error) {
 
 /* BEGIN INTERNAL METHODS */
 
-// attempt - attempt connection
-func (connection *Connection) attempt() ([]byte, error) {
+// attempt - attempt connection, returning response and codec it is encoded with
+func (connection *Connection) attempt() ([]byte, common.Codec, error) {
 	common.Println("-- CONNECTION -- attempting connection to peer with address " + connection.DestinationNode.Address) // Log connection
 
-	serializedConnection, err := common.SerializeToBytes(*connection) // Serialize connection
+	address := connection.DestinationNode.DialAddress(connection.Port) // Init destination address
+
+	pool, err := connection.InitializationNode.ConnectionPool() // Fetch pool of initializing node
 
 	if err != nil { // Check for errors
-		return nil, err // Return found error
+		return nil, "", err // Return found error
 	}
 
-	result, err := common.SendBytesResult(serializedConnection, connection.DestinationNode.Address+":"+strconv.Itoa(connection.Port)) // Attempt to send event
+	codec, err := pool.Codec(address, connection.DestinationNode.NodeID) // Fetch codec negotiated with destination
 
 	if err != nil { // Check for errors
-		return nil, err // Return found error
+		return nil, "", err // Return found error
 	}
 
-	/*
-		TODO: fix nil read data
-	*/
+	encodedConnection, err := connection.Encode(codec) // Encode connection
+
+	if err != nil { // Check for errors
+		return nil, "", err // Return found error
+	}
+
+	serializedConnection, err := common.Seal(codec, common.EnvelopeKindConnection, encodedConnection) // Wrap connection in envelope
+
+	if err != nil { // Check for errors
+		return nil, "", err // Return found error
+	}
+
+	result, err := pool.Request(address, connection.DestinationNode.NodeID, serializedConnection) // Attempt to send connection over pooled session, verifying destination identity
+
+	if err != nil { // Check for errors
+		return nil, "", err // Return found error
+	}
 
-	return result, nil // No error occurred, return nil
+	return result, codec, nil // No error occurred, return result
 }
 
 /* END INTERNAL METHODS */
@@ -23,6 +23,35 @@ func TestNewConnection(t *testing.T) {
 	t.Logf("created connection with source node %s", connection.InitializationNode.Address) // Log node
 }
 
+// TestNewConnectionToAddress - test connections to nodes initialized from self-describing addresses
+func TestNewConnectionToAddress(t *testing.T) {
+	sourceNode, err := newNodeSafe() // Attempt to create new node
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	destinationNode, err := node.NodeFromAddress("/ip4/127.0.0.1/tcp/3001/tls/p2p/test") // Init destination node
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if _, err = NewConnection(sourceNode, destinationNode, 3000, []byte("test"), "relay", []Event{}); err != nil { // Init connection
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	destinationNode.Addresses = append(destinationNode.Addresses, "/ip4/127.0.0.1/tcp/3002/tls/p2p/other") // Advertise address of other node
+
+	if _, err = NewConnection(sourceNode, destinationNode, 3000, []byte("test"), "relay", []Event{}); err == nil { // Check addresses of other nodes are rejected
+		t.Errorf("expected destination to be rejected") // Log found error
+		t.FailNow()                                     // Panic
+	}
+}
+
 // TestAttemptConnection - test functionality of connection attempt() method
 func TestAttemptConnection(t *testing.T) {
 	connection, err := generateConnection() // Create connection
@@ -36,7 +65,7 @@ func TestAttemptConnection(t *testing.T) {
 
 	_, err = connection.Attempt() // Attempt connection
 
-	if err != nil && err != io.EOF { // Check for errors
+	if err != nil && err != io.EOF && !strings.Contains(err.Error(), "unframed peer") && !strings.Contains(err.Error(), "node identity") { // Check for errors
 		if strings.Contains(err.Error(), "socket") { // Check socket
 			t.Logf("WARNING: socket actions require sudo privileges.") // Log warning
 		} else if strings.Contains(err.Error(), "connection refused") || strings.Contains(err.Error(), "timed out") { // Check time out
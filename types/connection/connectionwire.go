@@ -0,0 +1,370 @@
+package connection
+
+import (
+	"encoding/json"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/internal/rpc/proto/wire"
+	"github.com/dowlandaiello/GoP2P/types/command"
+	"github.com/dowlandaiello/GoP2P/types/node"
+	"github.com/golang/protobuf/proto"
+)
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// Encode - encode connection with given codec
+func (connection *Connection) Encode(codec common.Codec) ([]byte, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		wireConnection, err := connection.ToWire() // Convert to wire type
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return proto.Marshal(wireConnection) // Marshal connection
+	case common.CodecJSON:
+		return common.SerializeToBytes(*connection) // Serialize connection
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// DecodeConnection - decode connection encoded with given codec
+func DecodeConnection(codec common.Codec, b []byte) (*Connection, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		wireConnection := &wire.Connection{} // Init wire connection buffer
+
+		err := proto.Unmarshal(b, wireConnection) // Unmarshal connection
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return FromWire(wireConnection) // Return connection
+	case common.CodecJSON:
+		return FromBytes(b) // Decode connection
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// Encode - encode event with given codec
+func (event *Event) Encode(codec common.Codec) ([]byte, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		wireEvent, err := event.ToWire() // Convert to wire type
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return proto.Marshal(wireEvent) // Marshal event
+	case common.CodecJSON:
+		return common.SerializeToBytes(*event) // Serialize event
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// DecodeEvent - decode event encoded with given codec
+func DecodeEvent(codec common.Codec, b []byte) (*Event, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		wireEvent := &wire.Event{} // Init wire event buffer
+
+		err := proto.Unmarshal(b, wireEvent) // Unmarshal event
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return EventFromWire(wireEvent) // Return event
+	case common.CodecJSON:
+		event := &Event{} // Init event buffer
+
+		err := json.Unmarshal(b, event) // Decode event
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return event, nil // Return event
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// Encode - encode response with given codec
+func (response *Response) Encode(codec common.Codec) ([]byte, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		return proto.Marshal(&wire.Response{Value: response.Val}) // Marshal response
+	case common.CodecJSON:
+		return common.SerializeToBytes(*response) // Serialize response
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// DecodeResponse - decode response encoded with given codec
+func DecodeResponse(codec common.Codec, b []byte) (*Response, error) {
+	response := &Response{} // Init response buffer
+
+	switch codec {
+	case common.CodecProtobuf:
+		wireResponse := &wire.Response{} // Init wire response buffer
+
+		err := proto.Unmarshal(b, wireResponse) // Unmarshal response
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		response.Val = wireResponse.Value // Set value
+	case common.CodecJSON:
+		err := json.Unmarshal(b, response) // Decode response
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+
+	return response, nil // Return response
+}
+
+// ToWire - convert connection to protobuf wire type
+func (connection *Connection) ToWire() (*wire.Connection, error) {
+	wireConnection := &wire.Connection{
+		Destination: connection.DestinationNode.ToWire(),    // Set destination
+		Initializer: connection.InitializationNode.ToWire(), // Set initializer
+		Data:        connection.Data,                        // Set data
+		Port:        int64(connection.Port),                 // Set port
+		Type:        connection.ConnectionType,              // Set type
+	} // Init wire connection
+
+	for x := range connection.ConnectionStack { // Iterate through stack
+		wireEvent, err := connection.ConnectionStack[x].ToWire() // Convert event
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		wireConnection.Stack = append(wireConnection.Stack, wireEvent) // Append event
+	}
+
+	return wireConnection, nil // Return wire connection
+}
+
+// FromWire - convert protobuf wire type to connection
+func FromWire(wireConnection *wire.Connection) (*Connection, error) {
+	connection := &Connection{
+		DestinationNode:    node.NodeFromWire(wireConnection.Destination), // Set destination
+		InitializationNode: node.NodeFromWire(wireConnection.Initializer), // Set initializer
+		Data:               wireConnection.Data,                           // Set data
+		Port:               int(wireConnection.Port),                      // Set port
+		ConnectionType:     wireConnection.Type,                           // Set type
+	} // Init connection
+
+	for _, wireEvent := range wireConnection.Stack { // Iterate through stack
+		event, err := EventFromWire(wireEvent) // Convert event
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		connection.ConnectionStack = append(connection.ConnectionStack, *event) // Append event
+	}
+
+	return connection, nil // Return connection
+}
+
+// ToWire - convert event to protobuf wire type (resolution guide is JSON-encoded, as it is dynamically typed)
+func (event *Event) ToWire() (*wire.Event, error) {
+	wireCommand, err := event.Command.ToWire() // Convert command
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	wireEvent := &wire.Event{
+		Type:        event.EventType,                                         // Set type
+		Resolution:  &wire.Resolution{Data: event.Resolution.ResolutionData}, // Set resolution
+		Command:     wireCommand,                                             // Set command
+		Destination: event.DestinationNode.ToWire(),                          // Set destination
+		Port:        int64(event.Port),                                       // Set port
+	} // Init wire event
+
+	if event.Resolution.GuidingType != nil { // Check for guide
+		wireEvent.Resolution.Guide, err = json.Marshal(event.Resolution.GuidingType) // Encode guide
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+	}
+
+	return wireEvent, nil // Return wire event
+}
+
+// EventFromWire - convert protobuf wire type to event
+func EventFromWire(wireEvent *wire.Event) (*Event, error) {
+	command, err := command.FromWire(wireEvent.Command) // Convert command
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	event := &Event{
+		EventType:       wireEvent.Type,                           // Set type
+		Command:         command,                                  // Set command
+		DestinationNode: node.NodeFromWire(wireEvent.Destination), // Set destination
+		Port:            int(wireEvent.Port),                      // Set port
+	} // Init event
+
+	if wireEvent.Resolution != nil { // Check for resolution
+		event.Resolution.ResolutionData = wireEvent.Resolution.Data // Set resolution data
+
+		if len(wireEvent.Resolution.Guide) != 0 { // Check for guide
+			err = json.Unmarshal(wireEvent.Resolution.Guide, &event.Resolution.GuidingType) // Decode guide
+
+			if err != nil { // Check for errors
+				return nil, err // Return found error
+			}
+		}
+	}
+
+	return event, nil // Return event
+}
+
+// Encode - encode chunk with given codec
+func (chunk *Chunk) Encode(codec common.Codec) ([]byte, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		return proto.Marshal(chunk.ToWire()) // Marshal chunk
+	case common.CodecJSON:
+		return common.SerializeToBytes(*chunk) // Serialize chunk
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// DecodeChunk - decode chunk encoded with given codec
+func DecodeChunk(codec common.Codec, b []byte) (*Chunk, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		wireChunk := &wire.Chunk{} // Init wire chunk buffer
+
+		err := proto.Unmarshal(b, wireChunk) // Unmarshal chunk
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return ChunkFromWire(wireChunk), nil // Return chunk
+	case common.CodecJSON:
+		chunk := &Chunk{} // Init chunk buffer
+
+		err := json.Unmarshal(b, chunk) // Decode chunk
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return chunk, nil // Return chunk
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// Encode - encode chunk acknowledgement with given codec
+func (ack *ChunkAck) Encode(codec common.Codec) ([]byte, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		return proto.Marshal(&wire.ChunkAck{Transfer: ack.TransferID, Offset: ack.Offset, Complete: ack.Complete}) // Marshal acknowledgement
+	case common.CodecJSON:
+		return common.SerializeToBytes(*ack) // Serialize acknowledgement
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// DecodeChunkAck - decode chunk acknowledgement encoded with given codec
+func DecodeChunkAck(codec common.Codec, b []byte) (*ChunkAck, error) {
+	ack := &ChunkAck{} // Init acknowledgement buffer
+
+	switch codec {
+	case common.CodecProtobuf:
+		wireAck := &wire.ChunkAck{} // Init wire acknowledgement buffer
+
+		err := proto.Unmarshal(b, wireAck) // Unmarshal acknowledgement
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		ack.TransferID, ack.Offset, ack.Complete = wireAck.Transfer, wireAck.Offset, wireAck.Complete // Set acknowledgement
+	case common.CodecJSON:
+		err := json.Unmarshal(b, ack) // Decode acknowledgement
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+
+	return ack, nil // Return acknowledgement
+}
+
+// ToWire - convert chunk to protobuf wire type
+func (chunk *Chunk) ToWire() *wire.Chunk {
+	wireChunk := &wire.Chunk{
+		Transfer: chunk.TransferID, // Set transfer ID
+		Offset:   chunk.Offset,     // Set offset
+		Data:     chunk.Data,       // Set data
+		Checksum: chunk.Checksum,   // Set checksum
+		Final:    chunk.Final,      // Set final
+	} // Init wire chunk
+
+	if chunk.Header != nil { // Check for header
+		wireChunk.Header = &wire.StreamHeader{
+			Sink:        string(chunk.Header.Sink),                // Set sink
+			Name:        chunk.Header.Name,                        // Set name
+			Size:        chunk.Header.Size,                        // Set size
+			Initializer: chunk.Header.InitializationNode.ToWire(), // Set initializer
+		} // Set header
+	}
+
+	return wireChunk // Return wire chunk
+}
+
+// ChunkFromWire - convert protobuf wire type to chunk
+func ChunkFromWire(wireChunk *wire.Chunk) *Chunk {
+	chunk := &Chunk{
+		TransferID: wireChunk.Transfer, // Set transfer ID
+		Offset:     wireChunk.Offset,   // Set offset
+		Data:       wireChunk.Data,     // Set data
+		Checksum:   wireChunk.Checksum, // Set checksum
+		Final:      wireChunk.Final,    // Set final
+	} // Init chunk
+
+	if wireChunk.Header != nil { // Check for header
+		chunk.Header = &StreamHeader{
+			Sink:               StreamSink(wireChunk.Header.Sink),               // Set sink
+			Name:               wireChunk.Header.Name,                           // Set name
+			Size:               wireChunk.Header.Size,                           // Set size
+			InitializationNode: node.NodeFromWire(wireChunk.Header.Initializer), // Set initializer
+		} // Set header
+	}
+
+	return chunk // Return chunk
+}
+
+/*
+	END EXPORTED METHODS
+*/
@@ -0,0 +1,104 @@
+package connection
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/types/command"
+	"github.com/dowlandaiello/GoP2P/types/environment"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+// TestEncodeConnection - test functionality of connection Encode(), DecodeConnection() methods
+func TestEncodeConnection(t *testing.T) {
+	env, err := environment.NewEnvironment() // Init environment
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	testNode := &node.Node{NodeID: "test", PublicKey: []byte{0, 1, 2}, Address: "127.0.0.1", Reputation: 2, LastPingTime: time.Unix(0, 42), Environment: env} // Init node
+
+	testCommand, err := command.NewCommand("NewVariable", command.NewModifierSet("string", "test", env.EnvironmentVariables[0])) // Init command
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	resolution, err := NewResolution([]byte{0, 255}, "test") // Init resolution
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	event, err := NewEvent("push", *resolution, testCommand, testNode, 3000) // Init event
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	connection, err := NewConnection(testNode, &node.Node{Address: "127.0.0.2"}, 3000, []byte{0, 1, 2, 255}, "relay", []Event{*event}) // Init connection
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	expected, _ := common.SerializeToBytes(*connection) // Serialize expected connection
+
+	for _, codec := range common.SupportedCodecs { // Iterate through codecs
+		encoded, err := connection.Encode(codec) // Encode connection
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		decoded, err := DecodeConnection(codec, encoded) // Decode connection
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if actual, _ := common.SerializeToBytes(*decoded); !bytes.Equal(actual, expected) { // Check for mismatch
+			t.Errorf("%s codec mismatch: expected %s, got %s", codec, string(expected), string(actual)) // Log found error
+			t.FailNow()                                                                                 // Panic
+		}
+
+		t.Logf("encoded connection in %d bytes with %s codec", len(encoded), codec) // Log success
+	}
+}
+
+// TestEncodeResponse - test functionality of response Encode(), DecodeResponse() methods
+func TestEncodeResponse(t *testing.T) {
+	response := &Response{Val: [][]byte{{0, 255, 254}, []byte("test")}} // Init response
+
+	for _, codec := range common.SupportedCodecs { // Iterate through codecs
+		encoded, err := response.Encode(codec) // Encode response
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		decoded, err := DecodeResponse(codec, encoded) // Decode response
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if !reflect.DeepEqual(decoded, response) { // Check for mismatch
+			t.Errorf("%s codec mismatch: expected %v, got %v", codec, response, decoded) // Log found error
+			t.FailNow()                                                                  // Panic
+		}
+	}
+}
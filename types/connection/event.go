@@ -3,7 +3,6 @@ package connection
 import (
 	"errors"
 	"reflect"
-	"strconv"
 	"strings"
 
 	"github.com/dowlandaiello/GoP2P/common"
@@ -51,30 +50,57 @@ func NewEvent(eventType string, resolution Resolution, command *command.Command,
 	return &Event{EventType: eventType, Resolution: resolution, Command: command, DestinationNode: destinationNode, Port: port}, nil // Return initialized event
 }
 
-// Attempt - attempts to carry out event
+// Attempt - attempts to carry out event (returns raw response, encoded with codec negotiated with destination)
 func (event *Event) Attempt() ([]byte, error) {
-	return event.attempt() // attempt
+	result, _, err := event.attempt() // attempt
+
+	return result, err // Return result
+}
+
+// AttemptResponse - attempts to carry out event, returning decoded response
+func (event *Event) AttemptResponse() (*Response, error) {
+	result, codec, err := event.attempt() // Attempt event
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return DecodeResponse(codec, result) // Decode response
 }
 
 /* END EXPORTED METHODS */
 
 /* BEGIN INTERNAL METHODS */
 
-// attempt - wrapper
-func (event *Event) attempt() ([]byte, error) {
-	serializedEvent, err := common.SerializeToBytes(*event) // Serialize event
+// attempt - wrapper, returning response and codec it is encoded with
+func (event *Event) attempt() ([]byte, common.Codec, error) {
+	address := event.DestinationNode.DialAddress(event.Port) // Init destination address
+
+	codec, err := common.DefaultConnectionPool.Codec(address, event.DestinationNode.NodeID) // Fetch codec negotiated with destination
 
 	if err != nil { // Check for errors
-		return nil, err // Return found error
+		return nil, "", err // Return found error
 	}
 
-	resultBytes, err := common.SendBytesResult(serializedEvent, event.DestinationNode.Address+":"+strconv.Itoa(event.Port)) // Attempt to send event
+	encodedEvent, err := event.Encode(codec) // Encode event
 
 	if err != nil { // Check for errors
-		return nil, err // Return found error
+		return nil, "", err // Return found error
+	}
+
+	serializedEvent, err := common.Seal(codec, common.EnvelopeKindEvent, encodedEvent) // Wrap event in envelope
+
+	if err != nil { // Check for errors
+		return nil, "", err // Return found error
+	}
+
+	resultBytes, err := common.DefaultConnectionPool.Request(address, event.DestinationNode.NodeID, serializedEvent) // Attempt to send event over pooled session, verifying destination identity
+
+	if err != nil { // Check for errors
+		return nil, "", err // Return found error
 	}
 
-	return resultBytes, nil // No error occurred, return nil
+	return resultBytes, codec, nil // No error occurred, return result
 }
 
 /* END INTERNAL METHODS */
@@ -65,7 +65,7 @@ func TestAttemptEvent(t *testing.T) {
 
 	_, err = event.Attempt() // Attempt event
 
-	if err != nil && !strings.Contains(err.Error(), "socket") && !strings.Contains(err.Error(), "timed out") && !strings.Contains(err.Error(), "connection refused") && err != io.EOF { // Check for errors
+	if err != nil && !strings.Contains(err.Error(), "socket") && !strings.Contains(err.Error(), "timed out") && !strings.Contains(err.Error(), "connection refused") && !strings.Contains(err.Error(), "unframed peer") && !strings.Contains(err.Error(), "node identity") && err != io.EOF { // Check for errors
 		t.Errorf(err.Error()) // Log found error
 		t.FailNow()           // Panic
 	} else if err != nil && strings.Contains(err.Error(), "socket") {
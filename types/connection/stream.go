@@ -0,0 +1,268 @@
+package connection
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/types/environment"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+// StreamSink - destination data of a chunked transfer is written to by the receiving handler
+type StreamSink string
+
+const (
+	// StreamSinkFile - write received data to a file in the receiving handler's stream directory
+	StreamSinkFile = StreamSink("file")
+
+	// StreamSinkVariable - write received data to disk, adding an environment variable referencing it (see OpenStreamVariable)
+	StreamSinkVariable = StreamSink("variable")
+
+	// DefaultChunkSize - default number of bytes sent per chunk
+	DefaultChunkSize = 256 * 1024
+)
+
+var (
+	// ErrInvalidChecksum - error returned when a chunk's data doesn't match its checksum
+	ErrInvalidChecksum = errors.New("invalid chunk checksum")
+)
+
+// StreamHeader - metadata describing a chunked transfer, sent with each chunk
+type StreamHeader struct {
+	Sink StreamSink `json:"sink"` // Sink - destination received data is written to
+
+	Name string `json:"name"` // Name - file name (file sink) or variable type (variable sink)
+
+	Size int64 `json:"size"` // Size - total size in bytes (-1 if unknown)
+
+	InitializationNode *node.Node `json:"initializing node"` // InitializationNode - node initializing transfer
+}
+
+// Chunk - single segment of a chunked transfer, sent at given offset
+type Chunk struct {
+	TransferID string `json:"transfer"` // TransferID - ID of transfer chunk belongs to
+
+	Offset int64 `json:"offset"` // Offset - offset of first byte of data in transfer
+
+	Data []byte `json:"data"` // Data - chunk data (empty for status queries)
+
+	Checksum string `json:"checksum"` // Checksum - Sha3 hash of data
+
+	Final bool `json:"final"` // Final - chunk is last in transfer
+
+	Header *StreamHeader `json:"header"` // Header - transfer metadata
+}
+
+// ChunkAck - acknowledgement of chunk, returned by receiving handler
+type ChunkAck struct {
+	TransferID string `json:"transfer"` // TransferID - ID of acknowledged transfer
+
+	Offset int64 `json:"offset"` // Offset - offset of next byte expected by receiver (number of bytes received and verified)
+
+	Complete bool `json:"complete"` // Complete - transfer has been written to its sink
+}
+
+// StreamReference - value of variables added by StreamSinkVariable transfers, referencing received data on disk
+type StreamReference struct {
+	TransferID string `json:"transfer"` // TransferID - ID of transfer
+
+	Path string `json:"path"` // Path - path of received data
+
+	Size int64 `json:"size"` // Size - size of received data in bytes
+}
+
+// Stream - chunked transfer of data from reader to destination node, resumable from last acknowledged offset
+type Stream struct {
+	DestinationNode *node.Node `json:"destination node"` // DestinationNode - node to send data to
+
+	Port int `json:"port"` // Port - port of destination node
+
+	TransferID string `json:"transfer"` // TransferID - ID of transfer (persist to resume transfer in a later process)
+
+	Header StreamHeader `json:"header"` // Header - transfer metadata
+
+	ChunkSize int `json:"chunk size"` // ChunkSize - number of bytes sent per chunk
+
+	Offset int64 `json:"offset"` // Offset - number of bytes acknowledged by destination
+
+	sourceNode *node.Node // sourceNode - node sending data, presenting its certificate to destination (nil for streams resumed in a later process)
+}
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// NewStream - initialize chunked transfer of data with given size (-1 if unknown) to given sink (file name or variable type) on destination node (source node environment is not sent)
+func NewStream(sourceNode *node.Node, destinationNode *node.Node, port int, sink StreamSink, name string, size int64) (*Stream, error) {
+	if sink != StreamSinkFile && sink != StreamSinkVariable { // Check for invalid sink
+		return &Stream{}, fmt.Errorf("invalid stream sink %s", sink) // Return error
+	} else if reflect.ValueOf(destinationNode).IsNil() || reflect.ValueOf(sourceNode).IsNil() { // Check for nil peers
+		return &Stream{}, errors.New("invalid peer value") // Return error
+	} else if name == "" { // Check for nil name
+		return &Stream{}, errors.New("invalid stream name") // Return error
+	}
+
+	transferID := make([]byte, 16) // Init transfer ID buffer
+
+	_, err := rand.Read(transferID) // Generate transfer ID
+
+	if err != nil { // Check for errors
+		return &Stream{}, err // Return found error
+	}
+
+	return &Stream{DestinationNode: destinationNode, Port: port, TransferID: hex.EncodeToString(transferID), Header: StreamHeader{Sink: sink, Name: name, Size: size, InitializationNode: &node.Node{NodeID: sourceNode.NodeID, PublicKey: sourceNode.PublicKey, Address: sourceNode.Address}}, ChunkSize: DefaultChunkSize, sourceNode: sourceNode}, nil // Return initialized stream
+}
+
+// NewChunk - initialize chunk of given transfer containing given data at given offset
+func NewChunk(transferID string, offset int64, data []byte, final bool, header *StreamHeader) *Chunk {
+	return &Chunk{TransferID: transferID, Offset: offset, Data: data, Checksum: common.Sha3(data), Final: final, Header: header} // Return initialized chunk
+}
+
+// Verify - check chunk data matches chunk checksum
+func (chunk *Chunk) Verify() error {
+	if common.Sha3(chunk.Data) != chunk.Checksum { // Check for mismatch
+		return ErrInvalidChecksum // Return error
+	}
+
+	return nil // Valid
+}
+
+// Send - send data read from reader (positioned at stream.Offset) to destination in chunks, until reader is exhausted. On error, stream.Offset holds the last offset acknowledged by the destination.
+func (stream *Stream) Send(r io.Reader) error {
+	if stream.ChunkSize <= 0 { // Check for invalid chunk size
+		stream.ChunkSize = DefaultChunkSize // Set default chunk size
+	}
+
+	buffer := make([]byte, stream.ChunkSize) // Init chunk buffer
+
+	for {
+		n, err := io.ReadFull(r, buffer) // Read chunk
+
+		final := err == io.EOF || err == io.ErrUnexpectedEOF // Check for last chunk
+
+		if err != nil && !final { // Check for errors
+			return err // Return found error
+		}
+
+		ack, err := stream.send(NewChunk(stream.TransferID, stream.Offset, buffer[:n], final, &stream.Header)) // Send chunk
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		if ack.Offset != stream.Offset+int64(n) { // Check destination didn't accept chunk
+			stream.Offset = ack.Offset // Set acknowledged offset
+
+			return fmt.Errorf("destination expects transfer %s to resume at offset %d", stream.TransferID, ack.Offset) // Return error
+		}
+
+		stream.Offset = ack.Offset // Set acknowledged offset
+
+		if final { // Check for last chunk
+			if !ack.Complete { // Check transfer wasn't completed
+				return fmt.Errorf("destination didn't complete transfer %s", stream.TransferID) // Return error
+			}
+
+			return nil // Transfer complete
+		}
+	}
+}
+
+// Status - fetch offset of next byte expected by destination (number of bytes it has received), setting stream.Offset
+func (stream *Stream) Status() (int64, error) {
+	ack, err := stream.send(NewChunk(stream.TransferID, stream.Offset, nil, false, &stream.Header)) // Send status query
+
+	if err != nil { // Check for errors
+		return 0, err // Return found error
+	}
+
+	stream.Offset = ack.Offset // Set acknowledged offset
+
+	return ack.Offset, nil // Return offset
+}
+
+// Resume - resume interrupted transfer, seeking reader to offset expected by destination
+func (stream *Stream) Resume(r io.ReadSeeker) error {
+	offset, err := stream.Status() // Fetch offset
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	_, err = r.Seek(offset, io.SeekStart) // Seek to offset
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	return stream.Send(r) // Send remaining data
+}
+
+// OpenStreamVariable - open data referenced by variable added by a StreamSinkVariable transfer for reading
+func OpenStreamVariable(variable *environment.Variable) (io.ReadCloser, error) {
+	reference := &StreamReference{} // Init reference buffer
+
+	err := json.Unmarshal(variable.VariableData, reference) // Decode reference
+
+	if err != nil || reference.Path == "" { // Check for errors
+		return nil, errors.New("variable doesn't reference streamed data") // Return error
+	}
+
+	return os.Open(reference.Path) // Open data
+}
+
+/*
+	END EXPORTED METHODS
+*/
+
+/*
+	BEGIN INTERNAL METHODS
+*/
+
+// send - send single chunk to destination, returning decoded acknowledgement
+func (stream *Stream) send(chunk *Chunk) (*ChunkAck, error) {
+	address := stream.DestinationNode.DialAddress(stream.Port) // Init destination address
+
+	pool, err := stream.sourceNode.ConnectionPool() // Fetch pool of source node
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	codec, err := pool.Codec(address, stream.DestinationNode.NodeID) // Fetch codec negotiated with destination
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	encodedChunk, err := chunk.Encode(codec) // Encode chunk
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	serializedChunk, err := common.Seal(codec, common.EnvelopeKindStreamChunk, encodedChunk) // Wrap chunk in envelope
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	result, err := pool.Request(address, stream.DestinationNode.NodeID, serializedChunk) // Send chunk over pooled session, verifying destination identity
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return DecodeChunkAck(codec, result) // Decode acknowledgement
+}
+
+/*
+	END INTERNAL METHODS
+*/
@@ -0,0 +1,83 @@
+package connection
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+// TestNewStream - test functionality of NewStream() method
+func TestNewStream(t *testing.T) {
+	testNode := &node.Node{NodeID: "test", Address: "memory://test"} // Init node
+
+	stream, err := NewStream(testNode, testNode, 3000, StreamSinkFile, "test.bin", -1) // Init stream
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if stream.TransferID == "" || stream.Header.InitializationNode.NodeID != "test" { // Check for invalid stream
+		t.Errorf("invalid stream %v", stream) // Log found error
+		t.FailNow()                           // Panic
+	}
+
+	if _, err = NewStream(testNode, testNode, 3000, StreamSink("socket"), "test.bin", -1); err == nil { // Init stream with invalid sink
+		t.Errorf("expected invalid sink to be rejected") // Log found error
+		t.FailNow()                                      // Panic
+	}
+
+	t.Logf("initialized stream with transfer ID %s", stream.TransferID) // Log success
+}
+
+// TestEncodeChunk - test functionality of chunk Encode(), DecodeChunk() methods
+func TestEncodeChunk(t *testing.T) {
+	chunk := NewChunk("00ff", 42, []byte{0, 1, 2, 255}, true, &StreamHeader{Sink: StreamSinkVariable, Name: "test", Size: 46, InitializationNode: &node.Node{NodeID: "test", Address: "127.0.0.1"}}) // Init chunk
+
+	for _, codec := range common.SupportedCodecs { // Iterate through codecs
+		b, err := chunk.Encode(codec) // Encode chunk
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		decodedChunk, err := DecodeChunk(codec, b) // Decode chunk
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if !reflect.DeepEqual(chunk, decodedChunk) { // Check for mismatch
+			t.Errorf("invalid %s chunk %v", codec, decodedChunk) // Log found error
+			t.FailNow()                                          // Panic
+		}
+
+		if err = decodedChunk.Verify(); err != nil { // Verify chunk
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		b, err = (&ChunkAck{TransferID: "00ff", Offset: 46, Complete: true}).Encode(codec) // Encode acknowledgement
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if ack, err := DecodeChunkAck(codec, b); err != nil || *ack != (ChunkAck{TransferID: "00ff", Offset: 46, Complete: true}) { // Check for mismatch
+			t.Errorf("invalid %s acknowledgement %v (%v)", codec, ack, err) // Log found error
+			t.FailNow()                                                     // Panic
+		}
+	}
+
+	chunk.Data[0] = 1 // Corrupt chunk
+
+	if err := chunk.Verify(); err != ErrInvalidChecksum { // Verify corrupted chunk
+		t.Errorf("expected corrupted chunk to be rejected") // Log found error
+		t.FailNow()                                         // Panic
+	}
+}
@@ -5,8 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
-	"strconv"
+	"time"
 
+	"github.com/dowlandaiello/GoP2P/addressing"
 	"github.com/dowlandaiello/GoP2P/common"
 	"github.com/dowlandaiello/GoP2P/types/command"
 	"github.com/dowlandaiello/GoP2P/types/connection"
@@ -27,6 +28,20 @@ type NodeDatabase struct {
 	HashedNetworkMessageKey string // HashedNetworkMessageKey - key used for network-wide messages
 
 	AcceptableTimeout uint `json:"db-wide timeout"` // AcceptableTimeout - database-wide definition for operation timeout
+
+	RoutingTable *RoutingTable `json:"routing table,omitempty"` // RoutingTable - XOR-metric routing table (nil unless running in DHT mode, see EnableRoutingTable)
+
+	Bans map[string]time.Time `json:"bans,omitempty"` // Bans - expiry of temporary bans of misbehaving peers (by NodeID, see RecordEvent)
+
+	Liveness map[string]*PeerLiveness `json:"liveness,omitempty"` // Liveness - ping results of peers (by NodeID, see LivenessRoutine)
+
+	Versions map[string]uint64 `json:"versions,omitempty"` // Versions - sequence number of latest operation applied from each origin (by NodeID, see ApplyOperations)
+
+	Operations []Operation `json:"operations,omitempty"` // Operations - log of most recently applied operations, sent to peers that missed them (see OperationsSince)
+
+	Changes map[string]Operation `json:"changes,omitempty"` // Changes - latest applied operation changing each node, shard (removals are kept as tombstones), deciding concurrent operations of different origins (last writer wins, see ApplyOperations). Nodes learned, removed by peer exchange and membership aren't replicated (see ApplyMembershipEvent).
+
+	localNode *node.Node // localNode - node this database instance is kept by, dialing peers and originating recorded operations (see SetLocalNode)
 }
 
 /*
@@ -37,6 +52,10 @@ type NodeDatabase struct {
 func NewDatabase(bootstrapNode *node.Node, networkName string, networkID uint, acceptableTimeout uint, privateNetworkKey string) (NodeDatabase, error) {
 	db := NodeDatabase{AcceptableTimeout: acceptableTimeout, NetworkAlias: networkName, NetworkID: networkID, HashedNetworkMessageKey: common.Sha3([]byte(privateNetworkKey + networkName))} // Create empty database with specified timeout
 
+	if bootstrapNode.Identity() != nil { // Check database is created by bootstrap node
+		db.SetLocalNode(bootstrapNode) // Keep database on behalf of bootstrap node
+	}
+
 	err := db.AddNode(bootstrapNode) // Attempt to add bootstrapnode
 
 	if err != nil { // Check for errors
@@ -46,28 +65,53 @@ func NewDatabase(bootstrapNode *node.Node, networkName string, networkID uint, a
 	return db, nil // No error occurred, return database
 }
 
+// SetLocalNode - set node (with a private identity) this database instance is kept by. Peers are dialed on behalf of the node, and operations recorded by AddNode, RemoveNode, AddShard and RemoveShard originate from it. Databases read from memory must be given their local node again before recording operations.
+func (db *NodeDatabase) SetLocalNode(localNode *node.Node) {
+	db.localNode = localNode // Set local node
+}
+
 /* BEGIN NODE METHODS */
 
-// AddNode - adds node to specified nodedatabase, after checking address of node
+// AddNode - adds node to specified nodedatabase, after checking identity and addresses of node (see node.NodeFromAddress for adding nodes by self-describing address). If a node with the same NodeID already exists, its entry (e.g. address) is updated. The addition is pushed to remote database instances as an operation (unless running in DHT mode).
 func (db *NodeDatabase) AddNode(destNode *node.Node) error {
-	err := common.CheckAddress(destNode.Address) // Attempt to check specified address
+	if destNode.NodeID != "" && (len(destNode.PublicKey) != 0 || !destNode.IsPinned()) { // Check node has identity (NodeIDs pinned by self-describing addresses are verified once dialed)
+		err := destNode.VerifyIdentity() // Verify NodeID matches public key
+
+		if err != nil { // Check for invalid identity
+			return err // Return found error
+		}
+	}
+
+	err := destNode.CheckAddresses() // Check advertised addresses
+
+	if err != nil { // Check for invalid addresses
+		return err // Return found error
+	}
+
+	err = common.CheckAddress(destNode.Address) // Attempt to check specified address
 
 	if err != nil { // Check for invalid address
 		return err // Return new error
 	}
 
-	if reflect.ValueOf(db.Nodes).IsNil() { // Check if node array is nil
-		db.Nodes = &[]node.Node{*destNode} // Initialize empty array with new array composed of destNode
-	} else { // Array is not nil
-		*db.Nodes = append(*db.Nodes, *destNode) // Append node to node list
+	err = db.insertNode(destNode) // Add node
+
+	if err != nil || db.RoutingTable != nil { // Check for errors, DHT mode (routing tables are local to each node)
+		return err // Return error (might be nil)
+	}
+
+	operation, err := db.record(Operation{Kind: OperationAddNode, Node: contactOf(destNode)}) // Record addition
+
+	if err != nil { // Check for errors
+		return err // Return found error
 	}
 
-	go db.UpdateRemoteDatabase() // Update remote database instances
+	db.replicate(operation) // Push addition to remote database instances
 
 	return nil // No error occurred, return nil
 }
 
-// RemoveNode - removes node with specified address from database
+// RemoveNode - removes node with specified NodeID or address from database, pushing the removal to remote database instances (unless running in DHT mode)
 func (db *NodeDatabase) RemoveNode(address string) error {
 	nodeIndex, err := db.QueryForAddress(address) // Finds index of node with address
 
@@ -75,8 +119,26 @@ func (db *NodeDatabase) RemoveNode(address string) error {
 		return err // Returns error
 	}
 
+	if db.RoutingTable != nil { // Check for DHT mode
+		db.RoutingTable.RemoveNode(NewNodeKey(&(*db.Nodes)[nodeIndex])) // Remove contact, promoting replacement
+
+		db.syncRoutingTable() // Keep only routing table contacts
+
+		return nil // Returns nil, no error
+	}
+
+	removed := (*db.Nodes)[nodeIndex] // Fetch removed node
+
 	db.remove(int(nodeIndex)) // Removes value at index
 
+	operation, err := db.record(Operation{Kind: OperationRemoveNode, Address: livenessKey(&removed)}) // Record removal
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	db.replicate(operation) // Push removal to remote database instances
+
 	return nil // Returns nil, no error
 }
 
@@ -84,40 +146,32 @@ func (db *NodeDatabase) RemoveNode(address string) error {
 
 /* BEGIN SHARD METHODS */
 
-// AddShard - attempt to append shard to current NodeDatabase
+// AddShard - attempt to append shard to current NodeDatabase (replacing shard with same address), pushing the addition to remote database instances
 func (db *NodeDatabase) AddShard(destinationShard *shard.Shard) error {
 	if reflect.ValueOf(destinationShard).IsNil() || len(*destinationShard.ChildNodes) == 0 || destinationShard.Address == "" { // Check for invalid shard
 		return errors.New("invalid shard") // Return found error
 	}
 
-	for _, node := range *destinationShard.Nodes { // Iterate through nodes in database
-		_, err := db.QueryForAddress(node.Address) // Check if node exists in database
+	for x := range *destinationShard.Nodes { // Iterate through nodes in shard
+		shardNode := (*destinationShard.Nodes)[x] // Copy node
 
-		if err != nil { // Check for errors while querying for address
-			go db.AddNode(&node) // Add node
+		if _, err := db.QueryForAddress(shardNode.Address); err != nil { // Check if node exists in database
+			db.AddNode(&shardNode) // Add node (invalid nodes are skipped)
 		}
 	}
 
-	if db.Shards != nil { // Check for non-nil shards
-		for _, xShard := range *db.Shards { // Iterate through shards in database
-			_, err := db.QueryForShardAddress(destinationShard.Address) // Check shard in database
-
-			if err == nil { // Check for errors
-				db.RemoveShard(xShard.Address) // Remove shard
-			}
-		}
+	db.putShard(destinationShard) // Add shard
 
-		*db.Shards = append(*db.Shards, *destinationShard) // Append shard
-	} else {
-		db.Shards = &[]shard.Shard{*destinationShard} // Initialize w/shard
-	}
+	addedShard := *destinationShard // Copy shard
 
-	err := db.UpdateRemoteDatabase() // Update remote database instances
+	operation, err := db.record(Operation{Kind: OperationAddShard, Shard: &addedShard}) // Record addition
 
 	if err != nil { // Check for errors
 		return err // Return found error
 	}
 
+	db.replicate(operation) // Push addition to remote database instances
+
 	currentDir, err := common.GetCurrentDir() // Get working directory
 
 	if err != nil { // Check for errors
@@ -145,7 +199,7 @@ func (db *NodeDatabase) AddShard(destinationShard *shard.Shard) error {
 	return nil // No error occurred, return nil
 }
 
-// RemoveShard - removes shard with specified address from database
+// RemoveShard - removes shard with specified address from database, pushing the removal to remote database instances
 func (db *NodeDatabase) RemoveShard(address string) error {
 	shardIndex, err := db.QueryForShardAddress(address) // Finds index of node with address
 
@@ -155,12 +209,14 @@ func (db *NodeDatabase) RemoveShard(address string) error {
 
 	db.removeShard(int(shardIndex)) // Removes value at index
 
-	err = db.UpdateRemoteDatabase() // Update remote database instances
+	operation, err := db.record(Operation{Kind: OperationRemoveShard, Address: address}) // Record removal
 
 	if err != nil { // Check for errors
 		return err // Return found error
 	}
 
+	db.replicate(operation) // Push removal to remote database instances
+
 	currentDir, err := common.GetCurrentDir() // Get working directory
 
 	if err != nil { // Check for errors
@@ -190,17 +246,52 @@ func (db *NodeDatabase) RemoveShard(address string) error {
 
 /* END SHARD METHODS */
 
-// QueryForAddress - attempts to search specified node database for specified address, returning index of node
+// QueryForAddress - attempts to search specified node database for specified NodeID, address or advertised self-describing address, returning index of node
 func (db *NodeDatabase) QueryForAddress(address string) (uint, error) {
+	nodeIndex, err := db.QueryForNodeID(address) // Check for NodeID match
+
+	if err == nil { // Check found node
+		return nodeIndex, nil // Return index
+	}
+
+	if db.Nodes == nil { // Check for nil nodes
+		return 0, errors.New("no value found") // Return error
+	}
+
+	if parsed, err := addressing.Parse(address); err == nil { // Check for self-describing address
+		address = parsed.String() // Normalize address
+	}
+
 	for x := 0; x != len(*db.Nodes); x++ { // Wait until entire db has been queried
 		if address == (*db.Nodes)[x].Address { // Check for match
 			return uint(x), nil // If provided value matches value of node in list, return index
 		}
+
+		for _, advertised := range (*db.Nodes)[x].Addresses { // Iterate through advertised addresses
+			if address == advertised { // Check for match
+				return uint(x), nil // Return matching index
+			}
+		}
 	}
 
 	return 0, errors.New("no value found") // Could not find index of address, return new error
 }
 
+// QueryForNodeID - attempts to search specified node database for specified NodeID, returning index of node
+func (db *NodeDatabase) QueryForNodeID(nodeID string) (uint, error) {
+	if nodeID == "" || db.Nodes == nil { // Check for invalid query
+		return 0, errors.New("no value found") // Return error
+	}
+
+	for x := 0; x != len(*db.Nodes); x++ { // Wait until entire db has been queried
+		if nodeID == (*db.Nodes)[x].NodeID { // Check for match
+			return uint(x), nil // Return matching index
+		}
+	}
+
+	return 0, errors.New("no value found") // Could not find index of NodeID, return new error
+}
+
 // QueryForShardAddress - attempts to search specified node database for specified address, returning index of shard
 func (db *NodeDatabase) QueryForShardAddress(address string) (uint, error) {
 	if db.Shards != nil {
@@ -216,22 +307,22 @@ func (db *NodeDatabase) QueryForShardAddress(address string) (uint, error) {
 	return 0, fmt.Errorf("no shards in db %v", db) // Return no shards error
 }
 
-// UpdateRemoteDatabase - push database changes to remote network nodes
+// UpdateRemoteDatabase - synchronize database with random remote network nodes, applying operations the local database missed and sending nodes the operations they missed (databases running in DHT mode aren't replicated; peers learn contacts through lookups). Changes made through AddNode, RemoveNode, AddShard and RemoveShard are pushed to every node as they are made.
 func (db *NodeDatabase) UpdateRemoteDatabase() error {
-	serializedDb, err := common.SerializeToBytes(*db) // Serialize database to bytes
-
-	if err != nil { // Check for errors
-		return err // Return found error
+	if db.RoutingTable != nil { // Check for DHT mode
+		return nil // Routing tables are local to each node
 	}
 
-	for node := range *db.Nodes { // Iterate over nodes
-		go common.SendBytes(serializedDb, (*db.Nodes)[node].Address) // Send database to node
+	_, err := db.AntiEntropy(DefaultReplicationPort, DefaultAntiEntropyFanout) // Synchronize with remote database instances
+
+	if err == ErrNoContacts { // Check for no remote database instances
+		return nil // Nothing to synchronize
 	}
 
-	return nil // No error occurred, return nil
+	return err // Return error (might be nil)
 }
 
-// JoinDatabase - attempt to insert local node data into remote database instance
+// JoinDatabase - join network database of given alias through node at given bootstrap address (listening on given port): learn peers through peer exchange (see Bootstrap), announce the local node and catch up on operations replicated by the network. The database isn't fetched from the bootstrap node, so network-wide messages can only be sent by nodes holding the database the network was created with.
 func JoinDatabase(bootstrapAddress string, databasePort uint, databaseAlias string) error {
 	currentDir, err := common.GetCurrentDir() // Fetch working directory
 
@@ -245,12 +336,40 @@ func JoinDatabase(bootstrapAddress string, databasePort uint, databaseAlias stri
 		return err // Return found error
 	}
 
-	db, err := FetchRemoteDatabase(bootstrapAddress, databasePort, databaseAlias) // Fetch remote database
+	bootstrapNode, err := node.NodeFromAddress(bootstrapAddress) // Init bootstrap node (address may be self-describing)
 
 	if err != nil { // Check for errors
 		return err // Return found error
 	}
 
+	db, err := ReadDatabaseFromMemory(localNode.Environment, databaseAlias) // Read previously joined database
+
+	if err == ErrNoDatabase { // Check network not yet joined
+		db, err = &NodeDatabase{NetworkAlias: databaseAlias}, nil // Init database
+	}
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	db.SetLocalNode(localNode) // Keep database on behalf of local node
+
+	err = db.Bootstrap(localNode, bootstrapNode.Address, databasePort, DefaultBootstrapRounds) // Learn peers from bootstrap node
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	if db.NetworkID == 0 { // Check for unknown network ID
+		if pool, err := localNode.ConnectionPool(); err == nil { // Fetch pool of local node
+			if hello, err := pool.RemoteHello(bootstrapNode.DialAddress(int(databasePort)), ""); err == nil { // Fetch hello of bootstrap node
+				db.NetworkID = hello.NetworkID // Set network ID advertised by bootstrap node
+			}
+		}
+	}
+
+	common.SetLocalNetwork(db.NetworkID, db.NetworkAlias) // Advertise joined network in handshakes
+
 	err = db.AddNode(localNode) // Add local node
 
 	if err != nil { // Check for errors
@@ -304,30 +423,34 @@ func FetchRemoteDatabase(bootstrapAddress string, databasePort uint, databaseAli
 		return &NodeDatabase{}, err // Return found error
 	}
 
-	event, err := connection.NewEvent("fetch", *resolution, command, &node.Node{Address: bootstrapAddress}, int(databasePort)) // Init event
+	bootstrapNode, err := node.NodeFromAddress(bootstrapAddress) // Init bootstrap node (address may be self-describing)
 
 	if err != nil { // Check for errors
 		return &NodeDatabase{}, err // Return found error
 	}
 
-	conn, err := connection.NewConnection(localNode, &node.Node{Address: bootstrapAddress}, int(databasePort), []byte("dbFetchRequest"), "relay", []connection.Event{*event}) // Init connection
+	event, err := connection.NewEvent("fetch", *resolution, command, bootstrapNode, int(databasePort)) // Init event
 
 	if err != nil { // Check for errors
 		return &NodeDatabase{}, err // Return found error
 	}
 
-	resultBytes, err := conn.Attempt() // Attempt connection
+	conn, err := connection.NewConnection(localNode, bootstrapNode, int(databasePort), []byte("dbFetchRequest"), "relay", []connection.Event{*event}) // Init connection
 
 	if err != nil { // Check for errors
 		return &NodeDatabase{}, err // Return found error
 	}
 
-	decodedResponse, err := connection.ResponseFromBytes(resultBytes) // Fetch decoded result
+	decodedResponse, err := conn.AttemptResponse() // Attempt connection
 
 	if err != nil { // Check for errors
 		return &NodeDatabase{}, err // Return found error
 	}
 
+	if len(decodedResponse.Val) == 0 { // Check for empty response
+		return &NodeDatabase{}, errors.New("nil response") // Return error
+	}
+
 	decodedVariable, err := environment.VariableFromBytes(decodedResponse.Val[0]) // Attempt to decode response
 
 	if err != nil { // Check for errors
@@ -355,27 +478,35 @@ func FetchRemoteDatabase(bootstrapAddress string, databasePort uint, databaseAli
 	return db, nil // No error occurred, return nil
 }
 
-// SendDatabaseMessage - send announcement message to all nodes in network
+// SendDatabaseMessage - send announcement message to all nodes in network, verifying each node presents a certificate bound to its NodeID. Every node is sent the message; the first error encountered (e.g. a PeerIdentityMismatchError) is returned.
 func (db *NodeDatabase) SendDatabaseMessage(message *Message, messageKey string, databasePort uint) error {
 	if common.Sha3([]byte(messageKey+db.NetworkAlias)) != db.HashedNetworkMessageKey { // Check for matching message private key
 		return errors.New("invalid message private key") // Return found error
 	}
 
-	byteVal, err := message.ToBytes() // Serialize to bytes
+	pool, err := db.localNode.ConnectionPool() // Fetch pool of local node
 
 	if err != nil { // Check for errors
 		return err // Return found error
 	}
 
-	finished := make(chan bool) // Init finished buffer
+	results := make(chan error, len(*db.Nodes)) // Init result buffer
+
+	for _, destNode := range *db.Nodes { // Iterate through nodes
+		go func(destNode node.Node) {
+			_, _, err := pool.RequestEnvelope(destNode.DialAddress(int(databasePort)), destNode.NodeID, common.EnvelopeKindNetworkMessage, message) // Send message, verifying node identity
 
-	for _, node := range *db.Nodes { // Iterate through nodes
-		go common.SendBytesAsyncRoutine(byteVal, node.Address+":"+strconv.Itoa(int(databasePort)), finished) // Send message
+			results <- err // Set result
+		}(destNode)
 	}
 
-	<-finished // Check finished
+	for range *db.Nodes { // Wait for each node
+		if sendErr := <-results; sendErr != nil && err == nil { // Check for first error
+			err = sendErr // Set error
+		}
+	}
 
-	return nil // No error occurred, return nil
+	return err // Return error (might be nil)
 }
 
 // LogDatabase - serialize and print contents of entire database
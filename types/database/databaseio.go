@@ -3,11 +3,21 @@ package database
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"sync"
 
 	"github.com/dowlandaiello/GoP2P/common"
 	"github.com/dowlandaiello/GoP2P/types/environment"
 )
 
+var (
+	// MemoryMutex - serializes read-modify-write cycles of databases stored in node environments (see ReadDatabaseFromMemory, WriteToMemory)
+	MemoryMutex sync.Mutex
+
+	// ErrNoDatabase - error returned when reading a network database that isn't stored in an environment (e.g. before the network has been joined)
+	ErrNoDatabase = errors.New("network database not found in environment")
+)
+
 // WriteToMemory - create serialized instance of specified NodeDatabase in specified path (string)
 func (db *NodeDatabase) WriteToMemory(env *environment.Environment) error {
 	variable, err := environment.NewVariable(db.NetworkAlias+"NodeDatabase", *db)
@@ -30,7 +40,7 @@ func ReadDatabaseFromMemory(env *environment.Environment, networkAlias string) (
 	variable, err := env.QueryType(networkAlias + "NodeDatabase") // Attempt to fetch db
 
 	if err != nil { // Check for errors
-		return &NodeDatabase{}, err // Return found error
+		return &NodeDatabase{}, ErrNoDatabase // Return error
 	}
 
 	database := NodeDatabase{} // Init buffer
@@ -46,6 +56,36 @@ func ReadDatabaseFromMemory(env *environment.Environment, networkAlias string) (
 	return db, nil // No error occurred, return nil error, db
 }
 
+// SnapshotFromMemory - read network database of given alias from given environment while holding MemoryMutex, returning a copy that network requests can be made with (apply their results with UpdateInMemory)
+func SnapshotFromMemory(env *environment.Environment, networkAlias string) (*NodeDatabase, error) {
+	MemoryMutex.Lock() // Lock databases
+
+	defer MemoryMutex.Unlock() // Unlock databases
+
+	return ReadDatabaseFromMemory(env, networkAlias) // Read db
+}
+
+// UpdateInMemory - read network database of given alias from given environment, apply given update and write the updated database back, holding MemoryMutex for the whole cycle. The database isn't written if update fails; update must not make network requests.
+func UpdateInMemory(env *environment.Environment, networkAlias string, update func(db *NodeDatabase) error) error {
+	MemoryMutex.Lock() // Lock databases
+
+	defer MemoryMutex.Unlock() // Unlock databases
+
+	db, err := ReadDatabaseFromMemory(env, networkAlias) // Read db
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	err = update(db) // Update db
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	return db.WriteToMemory(env) // Write db to memory
+}
+
 // FromBytes - attempt to convert specified byte array to db
 func FromBytes(b []byte) (*NodeDatabase, error) {
 	object := NodeDatabase{} // Create empty instance
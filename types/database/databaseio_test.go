@@ -1,10 +1,12 @@
 package database
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
 	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/types/environment"
 )
 
 // TestWriteToMemory - test functionality of WriteToMemory() function
@@ -77,3 +79,54 @@ func TestReadFromMemory(t *testing.T) {
 		}
 	}
 }
+
+// TestUpdateInMemory - test functionality of UpdateInMemory(), SnapshotFromMemory() functions
+func TestUpdateInMemory(t *testing.T) {
+	env, err := environment.NewEnvironment() // Init environment
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if err = UpdateInMemory(env, "GoP2P_TestNet", func(db *NodeDatabase) error { return nil }); err != ErrNoDatabase { // Check missing database isn't updated
+		t.Errorf("expected missing database to fail update") // Log found error
+		t.FailNow()                                          // Panic
+	}
+
+	err = (&NodeDatabase{NetworkAlias: "GoP2P_TestNet"}).WriteToMemory(env) // Write db to memory
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	err = UpdateInMemory(env, "GoP2P_TestNet", func(db *NodeDatabase) error {
+		db.NetworkID = 2 // Set network ID
+
+		return nil // No error occurred, return nil
+	}) // Update db
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	UpdateInMemory(env, "GoP2P_TestNet", func(db *NodeDatabase) error {
+		db.NetworkID = 3 // Set network ID
+
+		return errors.New("update failed") // Fail update
+	}) // Attempt failing update
+
+	db, err := SnapshotFromMemory(env, "GoP2P_TestNet") // Read db
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if db.NetworkID != 2 { // Check only successful update was written
+		t.Errorf("expected network ID 2, found %d", db.NetworkID) // Log found error
+		t.FailNow()                                               // Panic
+	}
+}
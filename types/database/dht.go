@@ -0,0 +1,572 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/internal/rpc/proto/wire"
+	"github.com/dowlandaiello/GoP2P/types/node"
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	// DefaultLookupConcurrency - number of contacts queried in parallel during lookups (alpha)
+	DefaultLookupConcurrency = 3
+
+	// DefaultRefreshInterval - duration after which buckets that haven't been looked up are refreshed
+	DefaultRefreshInterval = time.Hour
+)
+
+var (
+	// ErrNoContacts - error returned when looking up a key in a database without any contacts
+	ErrNoContacts = errors.New("no contacts to query")
+)
+
+// FindNodeRequest - request for contacts closest to target key, sent to peers during lookups
+type FindNodeRequest struct {
+	Network string `json:"network"` // Network - alias of network whose database is queried
+
+	Target NodeKey `json:"target"` // Target - lookup key
+
+	Count uint `json:"count"` // Count - number of contacts requested (bucket size if 0)
+
+	Sender *node.Node `json:"sender"` // Sender - requesting node, added to the routing table of the queried node
+
+	Key string `json:"key"` // Key - key of requested record ("" for node lookups)
+}
+
+// FindNodeResponse - contacts closest to requested target, closest first, as well as records of requested key held by the queried node
+type FindNodeResponse struct {
+	Nodes []node.Node `json:"nodes"` // Nodes - contacts
+
+	Record *Record `json:"record"` // Record - value record of requested key (nil if not held)
+
+	Providers []node.Node `json:"providers"` // Providers - providers of requested key
+}
+
+// findNodeResult - result of querying single contact during lookup
+type findNodeResult struct {
+	contact node.Node // contact - queried contact
+
+	response *FindNodeResponse // response - response of contact
+
+	err error // err - error querying contact
+}
+
+// lookupResult - contacts that responded to, failed during a lookup, applied to the routing table once the lookup completes
+type lookupResult struct {
+	target NodeKey // target - lookup key
+
+	responded []node.Node // responded - responsive contacts
+
+	failed []NodeKey // failed - keys of unresponsive contacts
+}
+
+// lookupValues - records found during value lookup
+type lookupValues struct {
+	record *Record // record - most recently published value record
+
+	providers []node.Node // providers - providers of key
+}
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// EnableRoutingTable - run database in DHT mode, keeping at most k contacts per XOR-distance bucket of given local node instead of every node in the network (Nodes only holds routing table contacts). Existing nodes are inserted into the table.
+func (db *NodeDatabase) EnableRoutingTable(localNode *node.Node, k int) error {
+	table, err := NewRoutingTable(localNode, k) // Init routing table
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	table.Local = &node.Node{NodeID: localNode.NodeID, PublicKey: localNode.PublicKey, Address: localNode.Address, Addresses: localNode.Addresses} // Set local contact
+
+	if db.Nodes != nil { // Check for existing nodes
+		for x := range *db.Nodes { // Iterate through nodes
+			_, err = table.AddNode(&(*db.Nodes)[x]) // Add node
+
+			if err != nil { // Check for errors
+				return err // Return found error
+			}
+		}
+	}
+
+	db.RoutingTable = table // Set routing table
+
+	db.SetLocalNode(localNode) // Dial contacts on behalf of local node
+
+	db.syncRoutingTable() // Keep only routing table contacts
+
+	return nil // No error occurred, return nil
+}
+
+// ClosestNodes - fetch at most count known nodes closest to given key, closest first
+func (db *NodeDatabase) ClosestNodes(target NodeKey, count int) []node.Node {
+	if db.RoutingTable != nil { // Check for DHT mode
+		return db.RoutingTable.Closest(target, count) // Return closest contacts
+	}
+
+	if db.Nodes == nil { // Check for nil nodes
+		return []node.Node{} // No nodes
+	}
+
+	return closestNodes(*db.Nodes, target, count) // Return closest nodes
+}
+
+// FindNode - iteratively look up nodes closest to given key, querying DefaultLookupConcurrency contacts (listening on given port) at a time until the closest known contacts have all responded. Responding contacts are added to the routing table, unresponsive ones are removed; write database to memory to persist them.
+func (db *NodeDatabase) FindNode(target NodeKey, port uint) ([]node.Node, error) {
+	closest, _, err := db.lookup(target, "", port, false) // Look up target
+
+	return closest, err // Return closest contacts
+}
+
+// RefreshBuckets - look up random key in range of each bucket that hasn't been looked up within given duration (contacts listen on given port)
+func (db *NodeDatabase) RefreshBuckets(port uint, interval time.Duration) error {
+	return db.refreshBuckets(port, interval, ignoreLookup) // Refresh buckets
+}
+
+// RefreshRoutine - refresh stale buckets of the network database stored in given node environment every given duration until context is cancelled, applying lookup results to the stored database (db only identifies the network)
+func (db *NodeDatabase) RefreshRoutine(ctx context.Context, localNode *node.Node, port uint, interval time.Duration) {
+	ticker := time.NewTicker(interval) // Init ticker
+
+	defer ticker.Stop() // Stop ticker
+
+	for {
+		select {
+		case <-ticker.C: // Check tick
+			snapshot, err := SnapshotFromMemory(localNode.Environment, db.NetworkAlias) // Read db
+
+			if err == nil { // Check for errors
+				snapshot.SetLocalNode(localNode) // Dial contacts on behalf of local node
+
+				err = snapshot.refreshBuckets(port, interval, func(result *lookupResult) error {
+					return UpdateInMemory(localNode.Environment, db.NetworkAlias, func(current *NodeDatabase) error {
+						current.applyLookup(result) // Apply lookup result
+
+						return nil // No error occurred, return nil
+					}) // Write lookup result to memory
+				}) // Refresh buckets
+			}
+
+			if err != nil { // Check for errors
+				common.Printf("\n-- DHT -- bucket refresh failed: %s", err.Error()) // Log failure
+			}
+		case <-ctx.Done(): // Check cancelled
+			return // Stop
+		}
+	}
+}
+
+// HandleFindNode - respond to lookup request with closest known nodes, adding sender to routing table
+func (db *NodeDatabase) HandleFindNode(request *FindNodeRequest) (*FindNodeResponse, error) {
+	count := int(request.Count) // Fetch requested count
+
+	if count == 0 || count > DefaultBucketSize { // Check for default, oversized count
+		count = DefaultBucketSize // Set bucket size
+	}
+
+	if db.RoutingTable != nil { // Check for DHT mode
+		if count > db.RoutingTable.K { // Check for oversized count
+			count = db.RoutingTable.K // Set bucket size
+		}
+
+		if request.Sender != nil { // Check for sender
+			_, err := db.RoutingTable.AddNode(request.Sender) // Add sender
+
+			if err != nil { // Check for errors
+				return nil, err // Return found error
+			}
+
+			db.syncRoutingTable() // Keep only routing table contacts
+		}
+	}
+
+	response := &FindNodeResponse{Nodes: []node.Node{}} // Init response
+
+	for _, contact := range db.ClosestNodes(request.Target, count+1) { // Iterate through closest nodes
+		if request.Sender != nil && NewNodeKey(&contact) == NewNodeKey(request.Sender) { // Check for sender
+			continue // Skip sender
+		}
+
+		response.Nodes = append(response.Nodes, node.Node{NodeID: contact.NodeID, PublicKey: contact.PublicKey, Address: contact.Address, LastPingTime: contact.LastPingTime, IsBootstrap: contact.IsBootstrap, Reachability: contact.Reachability, Addresses: contact.Addresses}) // Append contact
+	}
+
+	if len(response.Nodes) > count { // Check for too many contacts
+		response.Nodes = response.Nodes[:count] // Truncate contacts
+	}
+
+	return response, nil // Return response
+}
+
+// Encode - encode request with given codec
+func (request *FindNodeRequest) Encode(codec common.Codec) ([]byte, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		return proto.Marshal(request.ToWire()) // Marshal request
+	case common.CodecJSON:
+		return json.Marshal(request) // Serialize request
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// DecodeFindNodeRequest - decode request encoded with given codec
+func DecodeFindNodeRequest(codec common.Codec, b []byte) (*FindNodeRequest, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		wireRequest := &wire.FindNodeRequest{} // Init wire request buffer
+
+		err := proto.Unmarshal(b, wireRequest) // Unmarshal request
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return FindNodeRequestFromWire(wireRequest) // Return request
+	case common.CodecJSON:
+		request := &FindNodeRequest{} // Init request buffer
+
+		err := json.Unmarshal(b, request) // Decode request
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return request, nil // Return request
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// ToWire - convert request to protobuf wire type
+func (request *FindNodeRequest) ToWire() *wire.FindNodeRequest {
+	return &wire.FindNodeRequest{Network: request.Network, Target: request.Target.String(), Count: uint32(request.Count), Sender: request.Sender.ToWire(), Key: request.Key} // Return wire request
+}
+
+// FindNodeRequestFromWire - convert protobuf wire type to request
+func FindNodeRequestFromWire(wireRequest *wire.FindNodeRequest) (*FindNodeRequest, error) {
+	target, err := ParseNodeKey(wireRequest.Target) // Decode target
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return &FindNodeRequest{Network: wireRequest.Network, Target: target, Count: uint(wireRequest.Count), Sender: node.NodeFromWire(wireRequest.Sender), Key: wireRequest.Key}, nil // Return request
+}
+
+// Encode - encode response with given codec
+func (response *FindNodeResponse) Encode(codec common.Codec) ([]byte, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		return proto.Marshal(response.ToWire()) // Marshal response
+	case common.CodecJSON:
+		return json.Marshal(response) // Serialize response
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// DecodeFindNodeResponse - decode response encoded with given codec
+func DecodeFindNodeResponse(codec common.Codec, b []byte) (*FindNodeResponse, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		wireResponse := &wire.FindNodeResponse{} // Init wire response buffer
+
+		err := proto.Unmarshal(b, wireResponse) // Unmarshal response
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return FindNodeResponseFromWire(wireResponse), nil // Return response
+	case common.CodecJSON:
+		response := &FindNodeResponse{} // Init response buffer
+
+		err := json.Unmarshal(b, response) // Decode response
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return response, nil // Return response
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// ToWire - convert response to protobuf wire type
+func (response *FindNodeResponse) ToWire() *wire.FindNodeResponse {
+	wireResponse := &wire.FindNodeResponse{Record: response.Record.ToWire()} // Init wire response
+
+	for x := range response.Nodes { // Iterate through nodes
+		wireResponse.Nodes = append(wireResponse.Nodes, response.Nodes[x].ToWire()) // Append node
+	}
+
+	for x := range response.Providers { // Iterate through providers
+		wireResponse.Providers = append(wireResponse.Providers, response.Providers[x].ToWire()) // Append provider
+	}
+
+	return wireResponse // Return wire response
+}
+
+// FindNodeResponseFromWire - convert protobuf wire type to response
+func FindNodeResponseFromWire(wireResponse *wire.FindNodeResponse) *FindNodeResponse {
+	response := &FindNodeResponse{Nodes: []node.Node{}, Record: RecordFromWire(wireResponse.Record)} // Init response
+
+	for _, wireNode := range wireResponse.Nodes { // Iterate through nodes
+		if wireNode != nil { // Check for non-nil node
+			response.Nodes = append(response.Nodes, *node.NodeFromWire(wireNode)) // Append node
+		}
+	}
+
+	for _, wireNode := range wireResponse.Providers { // Iterate through providers
+		if wireNode != nil { // Check for non-nil provider
+			response.Providers = append(response.Providers, *node.NodeFromWire(wireNode)) // Append provider
+		}
+	}
+
+	return response // Return response
+}
+
+/*
+	END EXPORTED METHODS
+*/
+
+/*
+	BEGIN INTERNAL METHODS:
+*/
+
+// lookup - iteratively look up nodes closest to given key, requesting records of given key (if set) from each contact, then apply lookup result to routing table. Stops once a value record is found if stopOnValue is set.
+func (db *NodeDatabase) lookup(target NodeKey, key string, port uint, stopOnValue bool) ([]node.Node, *lookupValues, error) {
+	closest, values, result, err := db.search(target, key, port, stopOnValue) // Look up target
+
+	if result != nil { // Check contacts were queried
+		db.applyLookup(result) // Apply lookup result
+	}
+
+	return closest, values, err // Return closest contacts, found records
+}
+
+// search - iteratively look up nodes closest to given key without modifying the routing table, requesting records of given key (if set) from each contact. Stops once a value record is found if stopOnValue is set. Returns closest responsive contacts, found records, and lookup result to apply to routing table (nil if no contacts were queried).
+func (db *NodeDatabase) search(target NodeKey, key string, port uint, stopOnValue bool) ([]node.Node, *lookupValues, *lookupResult, error) {
+	if db.RoutingTable == nil { // Check for flat database
+		return nil, nil, nil, errors.New("database doesn't have a routing table") // Return error
+	}
+
+	k := db.RoutingTable.K // Fetch bucket size
+
+	shortlist := db.ClosestNodes(target, k) // Init shortlist with closest contacts
+
+	if len(shortlist) == 0 { // Check for no contacts
+		return nil, nil, nil, ErrNoContacts // Return error
+	}
+
+	seen := map[NodeKey]bool{db.RoutingTable.Self: true} // Init seen buffer
+
+	for x := range shortlist { // Iterate through shortlist
+		seen[NewNodeKey(&shortlist[x])] = true // Set seen
+	}
+
+	queried := make(map[NodeKey]bool) // Init queried buffer
+
+	lookup := &lookupResult{target: target} // Init lookup result
+
+	values := &lookupValues{} // Init found records buffer
+
+	for {
+		candidates := []node.Node{} // Init candidates buffer
+
+		for _, contact := range shortlist { // Iterate through shortlist
+			if !queried[NewNodeKey(&contact)] { // Check not queried
+				candidates = append(candidates, contact) // Append candidate
+			}
+
+			if len(candidates) == DefaultLookupConcurrency { // Check for enough candidates
+				break // Stop collecting candidates
+			}
+		}
+
+		if len(candidates) == 0 { // Check closest contacts have all been queried
+			break // Lookup complete
+		}
+
+		results := make(chan findNodeResult, len(candidates)) // Init results buffer
+
+		for _, contact := range candidates { // Iterate through candidates
+			queried[NewNodeKey(&contact)] = true // Set queried
+
+			go func(contact node.Node) {
+				response, err := db.sendFindNode(&contact, target, key, port) // Query contact
+
+				results <- findNodeResult{contact: contact, response: response, err: err} // Write result
+			}(contact) // Query contact
+		}
+
+		failed := make(map[NodeKey]bool) // Init failed buffer
+
+		for range candidates { // Wait for results
+			result := <-results // Read result
+
+			key := NewNodeKey(&result.contact) // Fetch contact key
+
+			if result.err != nil { // Check for errors
+				common.Printf("\n-- DHT -- lookup of %s failed at %s: %s", target.String(), result.contact.Address, result.err.Error()) // Log failure
+
+				lookup.failed = append(lookup.failed, key) // Remove unresponsive contact
+
+				failed[key] = true // Set failed
+
+				continue // Continue to next result
+			}
+
+			lookup.responded = append(lookup.responded, result.contact) // Refresh responsive contact
+
+			values.add(result.response) // Collect records
+
+			for x := range result.response.Nodes { // Iterate through returned contacts
+				contact := result.response.Nodes[x] // Fetch contact
+
+				if contact.NodeID != "" && contact.VerifyIdentity() != nil { // Check for invalid identity
+					continue // Skip contact
+				}
+
+				if contactKey := NewNodeKey(&contact); !seen[contactKey] { // Check for new contact
+					seen[contactKey] = true // Set seen
+
+					shortlist = append(shortlist, contact) // Append contact
+				}
+			}
+		}
+
+		remaining := []node.Node{} // Init remaining buffer
+
+		for _, contact := range shortlist { // Iterate through shortlist
+			if !failed[NewNodeKey(&contact)] { // Check contact responded
+				remaining = append(remaining, contact) // Append contact
+			}
+		}
+
+		shortlist = closestNodes(remaining, target, k) // Keep k closest contacts
+
+		if stopOnValue && values.record != nil { // Check value was found
+			break // Lookup complete
+		}
+	}
+
+	if len(lookup.responded) == 0 { // Check no contacts responded
+		return nil, nil, lookup, errors.New("no contacts responded to lookup") // Return error
+	}
+
+	return closestNodes(lookup.responded, target, k), values, lookup, nil // Return closest responsive contacts, found records, lookup result
+}
+
+// applyLookup - remove unresponsive contacts of given lookup result from routing table, refresh responsive contacts and bucket of lookup key
+func (db *NodeDatabase) applyLookup(result *lookupResult) {
+	if db.RoutingTable == nil { // Check for flat database
+		return // Nothing to apply
+	}
+
+	for _, key := range result.failed { // Iterate through unresponsive contacts
+		db.RoutingTable.RemoveNode(key) // Remove contact
+	}
+
+	for x := range result.responded { // Iterate through responsive contacts
+		db.RoutingTable.AddNode(&result.responded[x]) // Refresh contact
+	}
+
+	db.RoutingTable.MarkRefreshed(result.target) // Set bucket refreshed
+
+	db.syncRoutingTable() // Keep only routing table contacts
+}
+
+// ignoreLookup - lookup result callback of lookups whose results are only applied to the queried database
+func ignoreLookup(result *lookupResult) error {
+	return nil // Nothing to do
+}
+
+// refreshBuckets - look up random key in range of each bucket that hasn't been looked up within given duration (contacts listen on given port), applying each lookup result to the routing table before passing it to given callback
+func (db *NodeDatabase) refreshBuckets(port uint, interval time.Duration, applied func(result *lookupResult) error) error {
+	if db.RoutingTable == nil { // Check for flat database
+		return errors.New("database doesn't have a routing table") // Return error
+	}
+
+	for _, cpl := range db.RoutingTable.StaleBuckets(interval) { // Iterate through stale buckets
+		if db.RoutingTable.Len() == 0 { // Check for no contacts
+			return ErrNoContacts // Return error
+		}
+
+		key, err := db.RoutingTable.RandomKey(cpl) // Generate key in bucket range
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		_, _, result, err := db.search(key, "", port, false) // Look up key
+
+		if result != nil { // Check contacts were queried
+			db.applyLookup(result) // Apply lookup result
+
+			if appliedErr := applied(result); appliedErr != nil { // Pass result to callback
+				return appliedErr // Return found error
+			}
+		}
+
+		if err != nil { // Check for errors
+			common.Printf("\n-- DHT -- refresh of bucket %d failed: %s", cpl, err.Error()) // Log failure
+		}
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// add - collect unexpired records of given response
+func (values *lookupValues) add(response *FindNodeResponse) {
+	if record := response.Record; record != nil && !record.Expired() && (values.record == nil || record.Published.After(values.record.Published)) { // Check for newer value
+		values.record = record // Set record
+	}
+
+	for x := range response.Providers { // Iterate through providers
+		provider := response.Providers[x] // Fetch provider
+
+		if provider.NodeID != "" && provider.VerifyIdentity() == nil && indexOfKey(values.providers, NewNodeKey(&provider)) < 0 { // Check for new, valid provider
+			values.providers = append(values.providers, provider) // Append provider
+		}
+	}
+}
+
+// sendFindNode - request contacts closest to target (and records of given key, if set) from given contact over pooled session of local node, verifying contact identity
+func (db *NodeDatabase) sendFindNode(contact *node.Node, target NodeKey, key string, port uint) (*FindNodeResponse, error) {
+	address := contact.DialAddress(int(port)) // Init contact address
+
+	request := &FindNodeRequest{Network: db.NetworkAlias, Target: target, Count: uint(db.RoutingTable.K), Sender: db.RoutingTable.Local, Key: key} // Init request
+
+	pool, err := db.localNode.ConnectionPool() // Fetch pool of local node
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	codec, result, err := pool.RequestEnvelope(address, contact.NodeID, common.EnvelopeKindFindNode, request) // Send request
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return DecodeFindNodeResponse(codec, result) // Decode response
+}
+
+// syncRoutingTable - set database nodes to routing table contacts
+func (db *NodeDatabase) syncRoutingTable() {
+	nodes := db.RoutingTable.Nodes() // Fetch contacts
+
+	db.Nodes = &nodes // Set nodes
+}
+
+/*
+	END INTERNAL METHODS
+*/
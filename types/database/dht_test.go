@@ -0,0 +1,107 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+// TestEncodeFindNodeRequest - test functionality of FindNodeRequest, FindNodeResponse Encode(), Decode() methods
+func TestEncodeFindNodeRequest(t *testing.T) {
+	sender := newTestContact(t, "memory://find-sender") // Init sender
+
+	for _, codec := range []common.Codec{common.CodecJSON, common.CodecProtobuf} { // Iterate through codecs
+		request := &FindNodeRequest{Network: "GoP2P_TestNet", Target: NewNodeKey(sender), Count: 4, Sender: sender} // Init request
+
+		encoded, err := request.Encode(codec) // Encode request
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		decoded, err := DecodeFindNodeRequest(codec, encoded) // Decode request
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if decoded.Target != request.Target || decoded.Count != 4 || decoded.Network != request.Network || decoded.Sender.NodeID != sender.NodeID { // Check for mismatch
+			t.Errorf("invalid decoded %s request %v", codec, decoded) // Log found error
+			t.FailNow()                                               // Panic
+		}
+
+		response := &FindNodeResponse{Nodes: []node.Node{*sender}} // Init response
+
+		encoded, err = response.Encode(codec) // Encode response
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		decodedResponse, err := DecodeFindNodeResponse(codec, encoded) // Decode response
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if len(decodedResponse.Nodes) != 1 || decodedResponse.Nodes[0].VerifyIdentity() != nil { // Check for mismatch
+			t.Errorf("invalid decoded %s response %v", codec, decodedResponse) // Log found error
+			t.FailNow()                                                        // Panic
+		}
+	}
+}
+
+// TestHandleFindNode - test functionality of EnableRoutingTable(), HandleFindNode() methods
+func TestHandleFindNode(t *testing.T) {
+	localNode := newTestContact(t, "memory://find-local") // Init local node
+
+	contacts := []node.Node{} // Init contacts buffer
+
+	for x := 0; x < 8; x++ { // Generate contacts
+		contacts = append(contacts, *newTestContact(t, "memory://find-contact")) // Append contact
+	}
+
+	db := NodeDatabase{NetworkAlias: "GoP2P_TestNet", Nodes: &contacts} // Init flat database
+
+	err := db.EnableRoutingTable(localNode, 1) // Keep single contact per bucket
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if len(*db.Nodes) != db.RoutingTable.Len() || len(*db.Nodes) >= len(contacts) { // Check only routing table contacts are kept
+		t.Errorf("invalid number of contacts %d", len(*db.Nodes)) // Log found error
+		t.FailNow()                                               // Panic
+	}
+
+	sender := newTestContact(t, "memory://find-sender") // Init sender
+
+	response, err := db.HandleFindNode(&FindNodeRequest{Network: db.NetworkAlias, Target: NewNodeKey(sender), Sender: sender}) // Handle request
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	for _, contact := range response.Nodes { // Iterate through returned contacts
+		if contact.NodeID == sender.NodeID { // Check for sender
+			t.Errorf("expected sender not to be returned") // Log found error
+			t.FailNow()                                    // Panic
+		}
+	}
+
+	key := NewNodeKey(sender) // Fetch sender key
+
+	bucket := db.RoutingTable.Buckets[db.RoutingTable.Self.CommonPrefixLength(key)] // Fetch sender bucket
+
+	if bucket == nil || (indexOfKey(bucket.Nodes, key) < 0 && indexOfKey(bucket.Replacements, key) < 0) { // Check sender was added
+		t.Errorf("expected sender to be added to routing table") // Log found error
+		t.FailNow()                                              // Panic
+	}
+}
@@ -0,0 +1,550 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/internal/rpc/proto/wire"
+	"github.com/dowlandaiello/GoP2P/types/environment"
+	"github.com/dowlandaiello/GoP2P/types/node"
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	// RecordVariablePrefix - prefix of types of environment variables holding value records (followed by routing key of record key)
+	RecordVariablePrefix = "DHTRecord:"
+
+	// ProviderVariablePrefix - prefix of types of environment variables holding provider records (followed by routing key of record key, provider NodeID)
+	ProviderVariablePrefix = "DHTProvider:"
+
+	// DefaultRecordTTL - default duration a published record is kept before it expires
+	DefaultRecordTTL = 24 * time.Hour
+
+	// DefaultRepublishInterval - default duration between republishing stored records to the nodes closest to their keys
+	DefaultRepublishInterval = time.Hour
+)
+
+var (
+	// ErrRecordNotFound - error returned when no node holds a record of a key
+	ErrRecordNotFound = errors.New("record not found")
+)
+
+// Record - value (or provider) of a key, stored on the nodes closest to the key
+type Record struct {
+	Key string `json:"key"` // Key - record key
+
+	Value []byte `json:"value"` // Value - record value (nil for provider records)
+
+	Provider *node.Node `json:"provider"` // Provider - node holding larger object referenced by key (provider records only)
+
+	Publisher string `json:"publisher"` // Publisher - NodeID of node that published record (refreshes record when republishing)
+
+	Published time.Time `json:"published"` // Published - time record was last published by its publisher
+
+	TTL time.Duration `json:"ttl"` // TTL - duration after publishing record expires
+}
+
+// StoreRequest - request to store record, sent to nodes closest to record key
+type StoreRequest struct {
+	Record *Record `json:"record"` // Record - record to store
+}
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// NewRecord - initialize value record of given key published by given node, expiring after given duration (DefaultRecordTTL if 0)
+func NewRecord(key string, value []byte, publisher *node.Node, ttl time.Duration) (*Record, error) {
+	if key == "" || publisher == nil { // Check for invalid parameters
+		return nil, errors.New("invalid record") // Return error
+	}
+
+	if ttl <= 0 { // Check for default TTL
+		ttl = DefaultRecordTTL // Set default TTL
+	}
+
+	return &Record{Key: key, Value: value, Publisher: publisher.NodeID, Published: time.Now(), TTL: ttl}, nil // Return initialized record
+}
+
+// NewProviderRecord - initialize record advertising given node as a provider of given key, expiring after given duration (DefaultRecordTTL if 0)
+func NewProviderRecord(key string, provider *node.Node, ttl time.Duration) (*Record, error) {
+	if provider == nil || provider.NodeID == "" { // Check for anonymous provider
+		return nil, errors.New("provider has no identity") // Return error
+	}
+
+	record, err := NewRecord(key, nil, provider, ttl) // Init record
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	record.Provider = &node.Node{NodeID: provider.NodeID, PublicKey: provider.PublicKey, Address: provider.Address, Addresses: provider.Addresses} // Set provider contact
+
+	return record, nil // Return initialized record
+}
+
+// IsProvider - check record is a provider record
+func (record *Record) IsProvider() bool {
+	return record.Provider != nil // Check for provider
+}
+
+// Expired - check record has outlived its TTL
+func (record *Record) Expired() bool {
+	return time.Since(record.Published) > record.TTL // Check expired
+}
+
+// Validate - check record has a key, hasn't expired and (for provider records) names a provider with a valid identity
+func (record *Record) Validate() error {
+	if record.Key == "" || record.TTL <= 0 { // Check for invalid record
+		return errors.New("invalid record") // Return error
+	}
+
+	if record.Expired() { // Check for expired record
+		return errors.New("record has expired") // Return error
+	}
+
+	if record.IsProvider() { // Check for provider record
+		if record.Provider.NodeID == "" { // Check for anonymous provider
+			return errors.New("provider has no identity") // Return error
+		}
+
+		return record.Provider.VerifyIdentity() // Verify provider identity
+	}
+
+	return nil // Valid
+}
+
+// StoreRecord - write record to given environment, unless a more recently published record of the same key (and provider) is already stored
+func StoreRecord(env *environment.Environment, record *Record) error {
+	variableType := recordVariableType(record) // Fetch variable type
+
+	if existing, err := env.QueryType(variableType); err == nil { // Check for stored record
+		storedRecord := &Record{} // Init record buffer
+
+		if json.Unmarshal(existing.VariableData, storedRecord) == nil && storedRecord.Published.After(record.Published) { // Check stored record is newer
+			return nil // Keep stored record
+		}
+
+		env.RemoveType(variableType) // Remove stored record
+	}
+
+	variable, err := environment.NewVariable(variableType, record) // Init variable
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	return env.AddVariable(variable, false) // Add variable
+}
+
+// LoadRecord - fetch unexpired value record of given key from given environment
+func LoadRecord(env *environment.Environment, key string) (*Record, error) {
+	variable, err := env.QueryType(RecordVariablePrefix + KeyFromString(key).String()) // Query record
+
+	if err != nil { // Check for errors
+		return nil, ErrRecordNotFound // Return error
+	}
+
+	record := &Record{} // Init record buffer
+
+	err = json.Unmarshal(variable.VariableData, record) // Decode record
+
+	if err != nil || record.Key != key || record.Expired() { // Check for invalid, expired record
+		return nil, ErrRecordNotFound // Return error
+	}
+
+	return record, nil // Return record
+}
+
+// LoadProviders - fetch providers of given key with unexpired provider records in given environment
+func LoadProviders(env *environment.Environment, key string) []node.Node {
+	providers := []node.Node{} // Init providers buffer
+
+	for _, record := range LoadRecords(env) { // Iterate through records
+		if record.IsProvider() && record.Key == key { // Check for provider of key
+			providers = append(providers, *record.Provider) // Append provider
+		}
+	}
+
+	return providers // Return providers
+}
+
+// LoadRecords - fetch all unexpired value, provider records in given environment
+func LoadRecords(env *environment.Environment) []*Record {
+	records := []*Record{} // Init records buffer
+
+	for _, variable := range env.EnvironmentVariables { // Iterate through variables
+		if !strings.HasPrefix(variable.VariableType, RecordVariablePrefix) && !strings.HasPrefix(variable.VariableType, ProviderVariablePrefix) { // Check for non-record variable
+			continue // Skip variable
+		}
+
+		record := &Record{} // Init record buffer
+
+		if json.Unmarshal(variable.VariableData, record) == nil && !record.Expired() { // Check for valid record
+			records = append(records, record) // Append record
+		}
+	}
+
+	return records // Return records
+}
+
+// ExpireRecords - remove expired records from given environment, returning number of removed records
+func ExpireRecords(env *environment.Environment) int {
+	expired := 0 // Init expired counter
+
+	for _, variable := range append([]*environment.Variable{}, env.EnvironmentVariables...) { // Iterate through variables
+		if !strings.HasPrefix(variable.VariableType, RecordVariablePrefix) && !strings.HasPrefix(variable.VariableType, ProviderVariablePrefix) { // Check for non-record variable
+			continue // Skip variable
+		}
+
+		record := &Record{} // Init record buffer
+
+		if err := json.Unmarshal(variable.VariableData, record); err != nil || record.Expired() { // Check for invalid, expired record
+			if env.RemoveType(variable.VariableType) == nil { // Remove record
+				expired++ // Increment expired
+			}
+		}
+	}
+
+	return expired // Return expired count
+}
+
+// Put - store value of given key on the nodes closest to key (listening on given port), keeping a copy in the local node environment for republishing
+func (db *NodeDatabase) Put(localNode *node.Node, key string, value []byte, port uint) error {
+	record, err := NewRecord(key, value, localNode, DefaultRecordTTL) // Init record
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	return db.publishLocal(localNode, record, port) // Publish record
+}
+
+// Get - fetch value of given key from the local node environment, or the nodes closest to key (listening on given port)
+func (db *NodeDatabase) Get(localNode *node.Node, key string, port uint) ([]byte, error) {
+	if record, err := LoadRecord(localNode.Environment, key); err == nil { // Check for local record
+		return record.Value, nil // Return value
+	}
+
+	db.SetLocalNode(localNode) // Dial contacts on behalf of local node
+
+	_, values, err := db.lookup(KeyFromString(key), key, port, true) // Look up value
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if values.record == nil { // Check no value was found
+		return nil, ErrRecordNotFound // Return error
+	}
+
+	return values.record.Value, nil // Return value
+}
+
+// Provide - advertise local node as a provider of given key on the nodes closest to key (listening on given port)
+func (db *NodeDatabase) Provide(localNode *node.Node, key string, port uint) error {
+	record, err := NewProviderRecord(key, localNode, DefaultRecordTTL) // Init record
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	return db.publishLocal(localNode, record, port) // Publish record
+}
+
+// FindProviders - fetch providers of given key known to the local node, and the nodes closest to key (listening on given port)
+func (db *NodeDatabase) FindProviders(localNode *node.Node, key string, port uint) ([]node.Node, error) {
+	providers := LoadProviders(localNode.Environment, key) // Fetch local providers
+
+	db.SetLocalNode(localNode) // Dial contacts on behalf of local node
+
+	_, values, err := db.lookup(KeyFromString(key), key, port, false) // Look up providers
+
+	if err == nil { // Check for errors
+		for _, provider := range values.providers { // Iterate through found providers
+			if indexOfKey(providers, NewNodeKey(&provider)) < 0 { // Check for new provider
+				providers = append(providers, provider) // Append provider
+			}
+		}
+	}
+
+	if len(providers) == 0 { // Check no providers were found
+		if err != nil { // Check for lookup error
+			return nil, err // Return found error
+		}
+
+		return nil, ErrRecordNotFound // Return error
+	}
+
+	return providers, nil // Return providers
+}
+
+// Republish - remove expired records from the local node environment, storing remaining records on the nodes closest to their keys (listening on given port). Records published by the local node are refreshed.
+func (db *NodeDatabase) Republish(localNode *node.Node, port uint) error {
+	return db.republish(localNode, port, ignoreLookup) // Republish records
+}
+
+// RepublishRoutine - republish records every given duration until context is cancelled, applying lookup results to the network database stored in given node environment (db only identifies the network) and writing node to given path after each republish
+func (db *NodeDatabase) RepublishRoutine(ctx context.Context, localNode *node.Node, path string, port uint, interval time.Duration) {
+	ticker := time.NewTicker(interval) // Init ticker
+
+	defer ticker.Stop() // Stop ticker
+
+	for {
+		select {
+		case <-ticker.C: // Check tick
+			snapshot, err := SnapshotFromMemory(localNode.Environment, db.NetworkAlias) // Read db
+
+			if err == nil { // Check for errors
+				err = snapshot.republish(localNode, port, func(result *lookupResult) error {
+					return UpdateInMemory(localNode.Environment, db.NetworkAlias, func(current *NodeDatabase) error {
+						current.applyLookup(result) // Apply lookup result
+
+						return nil // No error occurred, return nil
+					}) // Write lookup result to memory
+				}) // Republish records
+			}
+
+			if err == nil { // Check for errors
+				MemoryMutex.Lock() // Lock databases, records
+
+				err = localNode.WriteToMemory(path) // Write node to memory
+
+				MemoryMutex.Unlock() // Unlock databases, records
+			}
+
+			if err != nil { // Check for errors
+				common.Printf("\n-- DHT -- republish failed: %s", err.Error()) // Log failure
+			}
+		case <-ctx.Done(): // Check cancelled
+			return // Stop
+		}
+	}
+}
+
+// Encode - encode request with given codec
+func (request *StoreRequest) Encode(codec common.Codec) ([]byte, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		return proto.Marshal(&wire.StoreRequest{Record: request.Record.ToWire()}) // Marshal request
+	case common.CodecJSON:
+		return json.Marshal(request) // Serialize request
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// DecodeStoreRequest - decode request encoded with given codec
+func DecodeStoreRequest(codec common.Codec, b []byte) (*StoreRequest, error) {
+	request := &StoreRequest{} // Init request buffer
+
+	switch codec {
+	case common.CodecProtobuf:
+		wireRequest := &wire.StoreRequest{} // Init wire request buffer
+
+		err := proto.Unmarshal(b, wireRequest) // Unmarshal request
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		request.Record = RecordFromWire(wireRequest.Record) // Set record
+	case common.CodecJSON:
+		err := json.Unmarshal(b, request) // Decode request
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+
+	if request.Record == nil { // Check for nil record
+		return nil, errors.New("invalid store request") // Return error
+	}
+
+	return request, nil // Return request
+}
+
+// ToWire - convert record to protobuf wire type
+func (record *Record) ToWire() *wire.Record {
+	if record == nil { // Check for nil record
+		return nil // Return nil
+	}
+
+	return &wire.Record{Key: record.Key, Value: record.Value, Provider: record.Provider.ToWire(), Publisher: record.Publisher, Published: record.Published.UnixNano(), Ttl: int64(record.TTL)} // Return wire record
+}
+
+// RecordFromWire - convert protobuf wire type to record
+func RecordFromWire(wireRecord *wire.Record) *Record {
+	if wireRecord == nil { // Check for nil record
+		return nil // Return nil
+	}
+
+	return &Record{Key: wireRecord.Key, Value: wireRecord.Value, Provider: node.NodeFromWire(wireRecord.Provider), Publisher: wireRecord.Publisher, Published: time.Unix(0, wireRecord.Published), TTL: time.Duration(wireRecord.Ttl)} // Return record
+}
+
+/*
+	END EXPORTED METHODS
+*/
+
+/*
+	BEGIN INTERNAL METHODS:
+*/
+
+// publishLocal - write record to local node environment, storing it on the nodes closest to its key
+func (db *NodeDatabase) publishLocal(localNode *node.Node, record *Record, port uint) error {
+	err := StoreRecord(localNode.Environment, record) // Keep record for republishing
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	db.SetLocalNode(localNode) // Dial contacts on behalf of local node
+
+	_, err = db.publish(record, port, ignoreLookup) // Publish record
+
+	return err // Return error (might be nil)
+}
+
+// republish - remove expired records from the local node environment, storing remaining records on the nodes closest to their keys (listening on given port), applying each lookup result to the routing table before passing it to given callback. Records published by the local node are refreshed.
+func (db *NodeDatabase) republish(localNode *node.Node, port uint, applied func(result *lookupResult) error) error {
+	if db.RoutingTable == nil { // Check for flat database
+		return errors.New("database doesn't have a routing table") // Return error
+	}
+
+	db.SetLocalNode(localNode) // Dial contacts on behalf of local node
+
+	records, err := refreshRecords(localNode) // Expire, refresh records
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	for _, record := range records { // Iterate through records
+		_, err := db.publish(record, port, applied) // Republish record
+
+		if err != nil { // Check for errors
+			common.Printf("\n-- DHT -- republishing %s failed: %s", record.Key, err.Error()) // Log failure
+		}
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// refreshRecords - remove expired records from the local node environment, refreshing records published by the local node, returning remaining records
+func refreshRecords(localNode *node.Node) ([]*Record, error) {
+	MemoryMutex.Lock() // Lock records
+
+	defer MemoryMutex.Unlock() // Unlock records
+
+	if expired := ExpireRecords(localNode.Environment); expired > 0 { // Remove expired records
+		common.Printf("\n-- DHT -- expired %d records", expired) // Log expired records
+	}
+
+	records := LoadRecords(localNode.Environment) // Load records
+
+	for _, record := range records { // Iterate through records
+		if record.Publisher == localNode.NodeID { // Check record was published by local node
+			record.Published = time.Now() // Refresh record
+
+			err := StoreRecord(localNode.Environment, record) // Write refreshed record
+
+			if err != nil { // Check for errors
+				return nil, err // Return found error
+			}
+		}
+	}
+
+	return records, nil // Return records
+}
+
+// publish - store record on the nodes closest to its key, applying lookup result to the routing table before passing it to given callback. Returns number of nodes storing record.
+func (db *NodeDatabase) publish(record *Record, port uint, applied func(result *lookupResult) error) (int, error) {
+	closest, _, result, err := db.search(KeyFromString(record.Key), "", port, false) // Look up nodes closest to key
+
+	if result != nil { // Check contacts were queried
+		db.applyLookup(result) // Apply lookup result
+
+		if appliedErr := applied(result); appliedErr != nil { // Pass result to callback
+			return 0, appliedErr // Return found error
+		}
+	}
+
+	if err != nil { // Check for errors
+		return 0, err // Return found error
+	}
+
+	stored := 0 // Init stored counter
+
+	var lastErr error // Init last error buffer
+
+	var mutex sync.Mutex // Init counter lock
+
+	var wg sync.WaitGroup // Init store group
+
+	for x := range closest { // Iterate through closest nodes
+		wg.Add(1) // Add store
+
+		go func(contact node.Node) {
+			defer wg.Done() // Finish store
+
+			err := sendStore(db.localNode, &contact, record, port) // Store record
+
+			mutex.Lock() // Lock counter
+
+			defer mutex.Unlock() // Unlock counter
+
+			if err != nil { // Check for errors
+				lastErr = err // Set last error
+
+				return // Stop
+			}
+
+			stored++ // Increment stored
+		}(closest[x]) // Store record on contact
+	}
+
+	wg.Wait() // Wait for stores
+
+	if stored == 0 { // Check record wasn't stored
+		return 0, lastErr // Return last error
+	}
+
+	common.Printf("\n-- DHT -- stored record %s on %d nodes", record.Key, stored) // Log publish
+
+	return stored, nil // Return stored count
+}
+
+// sendStore - send store request for record to given contact over pooled session of given local node, verifying contact identity
+func sendStore(localNode *node.Node, contact *node.Node, record *Record, port uint) error {
+	pool, err := localNode.ConnectionPool() // Fetch pool of local node
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	address := contact.DialAddress(int(port)) // Init contact address
+
+	_, _, err = pool.RequestEnvelope(address, contact.NodeID, common.EnvelopeKindStoreRecord, &StoreRequest{Record: record}) // Send request
+
+	return err // Return error (might be nil)
+}
+
+// recordVariableType - fetch type of environment variable holding given record
+func recordVariableType(record *Record) string {
+	if record.IsProvider() { // Check for provider record
+		return ProviderVariablePrefix + KeyFromString(record.Key).String() + ":" + record.Provider.NodeID // Return provider type
+	}
+
+	return RecordVariablePrefix + KeyFromString(record.Key).String() // Return value type
+}
+
+/*
+	END INTERNAL METHODS
+*/
@@ -0,0 +1,119 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/types/environment"
+)
+
+// TestStoreRecord - test functionality of StoreRecord(), LoadRecord(), LoadProviders(), ExpireRecords() functions
+func TestStoreRecord(t *testing.T) {
+	env, err := environment.NewEnvironment() // Init environment
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	publisher := newTestContact(t, "memory://record-publisher") // Init publisher
+
+	record, err := NewRecord("testKey", []byte("testValue"), publisher, 0) // Init record
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	staleRecord := *record // Copy record
+
+	staleRecord.Value, staleRecord.Published = []byte("staleValue"), record.Published.Add(-time.Minute) // Set stale value
+
+	for _, storedRecord := range []*Record{record, &staleRecord} { // Iterate through records
+		err = StoreRecord(env, storedRecord) // Store record
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+	}
+
+	if loaded, err := LoadRecord(env, "testKey"); err != nil || string(loaded.Value) != "testValue" { // Check newer record was kept
+		t.Errorf("invalid loaded record (%v)", err) // Log found error
+		t.FailNow()                                 // Panic
+	}
+
+	providerRecord, err := NewProviderRecord("testObject", publisher, 0) // Init provider record
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if err = providerRecord.Validate(); err != nil { // Validate record
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	StoreRecord(env, providerRecord) // Store provider record
+
+	if providers := LoadProviders(env, "testObject"); len(providers) != 1 || providers[0].NodeID != publisher.NodeID { // Check for invalid providers
+		t.Errorf("invalid providers %v", providers) // Log found error
+		t.FailNow()                                 // Panic
+	}
+
+	expiredRecord, _ := NewRecord("expiredKey", []byte("testValue"), publisher, time.Millisecond) // Init expiring record
+
+	StoreRecord(env, expiredRecord) // Store expiring record
+
+	time.Sleep(2 * time.Millisecond) // Wait for record to expire
+
+	if _, err = LoadRecord(env, "expiredKey"); err != ErrRecordNotFound { // Check expired record isn't returned
+		t.Errorf("expected expired record not to be found") // Log found error
+		t.FailNow()                                         // Panic
+	}
+
+	if expired := ExpireRecords(env); expired != 1 || len(LoadRecords(env)) != 2 { // Check expired record was removed
+		t.Errorf("invalid number of expired records %d", expired) // Log found error
+		t.FailNow()                                               // Panic
+	}
+
+	if expiredRecord.Validate() == nil { // Validate expired record
+		t.Errorf("expected expired record to be invalid") // Log found error
+		t.FailNow()                                       // Panic
+	}
+}
+
+// TestEncodeStoreRequest - test functionality of StoreRequest Encode(), DecodeStoreRequest() methods
+func TestEncodeStoreRequest(t *testing.T) {
+	provider := newTestContact(t, "memory://record-provider") // Init provider
+
+	record, err := NewProviderRecord("testObject", provider, time.Hour) // Init record
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	for _, codec := range []common.Codec{common.CodecJSON, common.CodecProtobuf} { // Iterate through codecs
+		encoded, err := (&StoreRequest{Record: record}).Encode(codec) // Encode request
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		decoded, err := DecodeStoreRequest(codec, encoded) // Decode request
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if decoded.Record.Key != record.Key || decoded.Record.TTL != record.TTL || !decoded.Record.Published.Equal(record.Published) || decoded.Record.Validate() != nil { // Check for mismatch
+			t.Errorf("invalid decoded %s record %v", codec, decoded.Record) // Log found error
+			t.FailNow()                                                     // Panic
+		}
+	}
+}
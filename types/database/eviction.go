@@ -0,0 +1,152 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+// EvictionPolicy - thresholds after which unresponsive peers are marked suspect, then evicted (zero values disable a threshold)
+type EvictionPolicy struct {
+	SuspectFailures uint // SuspectFailures - number of consecutive failed pings after which a peer is suspect
+
+	SuspectAge time.Duration // SuspectAge - duration since LastPingTime after which a peer is suspect
+
+	EvictFailures uint // EvictFailures - number of consecutive failed pings after which a peer is evicted
+
+	EvictAge time.Duration // EvictAge - duration since LastPingTime after which a peer is evicted
+}
+
+// Eviction - record of a peer evicted from a database
+type Eviction struct {
+	Node node.Node `json:"node"` // Node - evicted peer
+
+	Reason string `json:"reason"` // Reason - threshold exceeded by peer
+
+	Time time.Time `json:"time"` // Time - time of eviction
+}
+
+var (
+	// DefaultEvictionPolicy - policy applied by the liveness scheduler (see LivenessRoutine)
+	DefaultEvictionPolicy = EvictionPolicy{
+		SuspectFailures: 2,                // Set suspect failures
+		SuspectAge:      10 * time.Minute, // Set suspect age
+		EvictFailures:   5,                // Set evicted failures
+		EvictAge:        time.Hour,        // Set evicted age
+	}
+)
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// EvictDeadPeers - mark peers exceeding the suspect thresholds of given policy as suspect, removing peers exceeding its eviction thresholds. Evictions are logged and pushed to remote database instances (unless running in DHT mode).
+func (db *NodeDatabase) EvictDeadPeers(policy EvictionPolicy) []Eviction {
+	evictions := []Eviction{} // Init evictions buffer
+
+	if db.Nodes == nil { // Check for no peers
+		return evictions // Nothing to evict
+	}
+
+	for _, peer := range append([]node.Node{}, *db.Nodes...) { // Iterate through copy of peers
+		liveness := db.PeerLivenessOf(&peer) // Fetch liveness state
+
+		if reason := exceeds(&peer, liveness, policy.EvictFailures, policy.EvictAge); reason != "" { // Check peer is dead
+			db.evict(&peer) // Remove peer
+
+			eviction := Eviction{Node: peer, Reason: reason, Time: time.Now()} // Init eviction
+
+			evictions = append(evictions, eviction) // Append eviction
+
+			common.Printf("\n-- EVICTION -- evicted peer %s (%s): %s", peer.Address, peer.NodeID, reason) // Log eviction
+
+			continue // Continue to next peer
+		}
+
+		suspect := exceeds(&peer, liveness, policy.SuspectFailures, policy.SuspectAge) // Check peer is suspect
+
+		if liveness == nil || liveness.Suspect == (suspect != "") { // Check suspicion unchanged
+			continue // Continue to next peer
+		}
+
+		liveness.Suspect = suspect != "" // Set suspect
+
+		if liveness.Suspect { // Check became suspect
+			common.Printf("\n-- EVICTION -- suspect peer %s (%s): %s", peer.Address, peer.NodeID, suspect) // Log suspicion
+		}
+	}
+
+	if len(evictions) > 0 && db.RoutingTable == nil { // Check for evictions to propagate
+		operations := []Operation{} // Init operations buffer
+
+		for x := range evictions { // Iterate through evictions
+			if operation, err := db.record(Operation{Kind: OperationRemoveNode, Address: livenessKey(&evictions[x].Node)}); err == nil { // Record removal
+				operations = append(operations, operation) // Append operation
+			}
+		}
+
+		db.replicate(operations...) // Push removals to remote database instances
+	}
+
+	return evictions // Return evictions
+}
+
+// IsSuspect - check given peer has been marked suspect (see EvictDeadPeers)
+func (db *NodeDatabase) IsSuspect(peer *node.Node) bool {
+	liveness := db.PeerLivenessOf(peer) // Fetch liveness state
+
+	return liveness != nil && liveness.Suspect // Check suspect
+}
+
+// String - convert eviction to string
+func (eviction *Eviction) String() string {
+	return fmt.Sprintf("evicted %s (%s) at %s: %s", eviction.Node.Address, eviction.Node.NodeID, eviction.Time.Format(time.RFC3339), eviction.Reason) // Return string
+}
+
+/*
+	END EXPORTED METHODS
+*/
+
+/*
+	BEGIN INTERNAL METHODS:
+*/
+
+// evict - remove given peer, its liveness state from database
+func (db *NodeDatabase) evict(peer *node.Node) {
+	delete(db.Liveness, livenessKey(peer)) // Remove liveness state
+
+	if db.RoutingTable != nil { // Check for DHT mode
+		db.RoutingTable.RemoveNode(NewNodeKey(peer)) // Remove contact, promoting replacement
+
+		db.syncRoutingTable() // Keep only routing table contacts
+
+		return // Stop
+	}
+
+	for x := range *db.Nodes { // Iterate through peers
+		if livenessKey(&(*db.Nodes)[x]) == livenessKey(peer) { // Check for match
+			db.remove(x) // Remove peer
+
+			return // Stop
+		}
+	}
+}
+
+// exceeds - describe which of given thresholds (consecutive failed pings, duration since last seen) given peer exceeds ("" if none)
+func exceeds(peer *node.Node, liveness *PeerLiveness, failures uint, age time.Duration) string {
+	if failures > 0 && liveness != nil && liveness.Failures >= failures { // Check failed pings
+		return fmt.Sprintf("%d consecutive failed pings (last error: %s)", liveness.Failures, liveness.LastError) // Return reason
+	}
+
+	if age > 0 && !peer.LastPingTime.IsZero() && time.Since(peer.LastPingTime) > age { // Check time since seen
+		return fmt.Sprintf("not seen since %s", peer.LastPingTime.Format(time.RFC3339)) // Return reason
+	}
+
+	return "" // No threshold exceeded
+}
+
+/*
+	END INTERNAL METHODS
+*/
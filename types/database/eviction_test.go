@@ -0,0 +1,56 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+// TestEvictDeadPeers - test functionality of EvictDeadPeers() method
+func TestEvictDeadPeers(t *testing.T) {
+	alive := newTestContact(t, "memory://eviction-alive") // Init responsive peer
+
+	alive.LastPingTime = time.Now() // Set seen
+
+	failing := newTestContact(t, "memory://eviction-failing") // Init peer failing pings
+
+	failing.LastPingTime = time.Now() // Set seen
+
+	stale := newTestContact(t, "memory://eviction-stale") // Init peer not seen in a long time
+
+	stale.LastPingTime = time.Now().Add(-2 * time.Hour) // Set seen long ago
+
+	db := &NodeDatabase{NetworkAlias: "GoP2P_TestNet", Nodes: &[]node.Node{*alive, *failing, *stale}} // Init database
+
+	policy := EvictionPolicy{SuspectFailures: 2, EvictFailures: 3, SuspectAge: time.Minute, EvictAge: time.Hour} // Init policy
+
+	for x := 0; x < 2; x++ { // Fail twice
+		db.recordPing(failing, nil, errors.New("timed out")) // Record failed ping
+	}
+
+	evictions := db.EvictDeadPeers(policy) // Check peers
+
+	if len(evictions) != 1 || evictions[0].Node.NodeID != stale.NodeID { // Check stale peer was evicted
+		t.Errorf("invalid evictions %v", evictions) // Log found error
+		t.FailNow()                                 // Panic
+	}
+
+	if !db.IsSuspect(failing) || db.IsSuspect(alive) { // Check failing peer is suspect
+		t.Errorf("failing peer wasn't marked suspect") // Log found error
+		t.FailNow()                                    // Panic
+	}
+
+	if sample := db.SamplePeers(10, 0); len(sample) != 1 || sample[0].NodeID != alive.NodeID { // Check suspect peer isn't offered
+		t.Errorf("invalid sample %v", sample) // Log found error
+		t.FailNow()                           // Panic
+	}
+
+	db.recordPing(failing, nil, errors.New("timed out")) // Record failed ping
+
+	if evictions = db.EvictDeadPeers(policy); len(evictions) != 1 || evictions[0].Node.NodeID != failing.NodeID || len(*db.Nodes) != 1 || db.PeerLivenessOf(failing) != nil { // Check failing peer was evicted
+		t.Errorf("invalid evictions %v", evictions) // Log found error
+		t.FailNow()                                 // Panic
+	}
+}
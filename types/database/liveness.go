@@ -0,0 +1,451 @@
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/internal/rpc/proto/wire"
+	"github.com/dowlandaiello/GoP2P/types/node"
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	// DefaultPingInterval - default duration between liveness rounds
+	DefaultPingInterval = 30 * time.Second
+
+	// nonceSize - size of ping nonces in bytes
+	nonceSize = 16
+)
+
+// Ping - liveness probe sent to a peer over its GoP2P port, answered with a Pong
+type Ping struct {
+	Network string `json:"network"` // Network - alias of network whose database the sender is recorded in (if authenticated)
+
+	Sender *node.Node `json:"sender"` // Sender - pinging node
+
+	Nonce string `json:"nonce"` // Nonce - random nonce echoed in pong
+
+	Sent time.Time `json:"sent"` // Sent - time ping was sent
+}
+
+// Pong - answer to a Ping
+type Pong struct {
+	Responder *node.Node `json:"responder"` // Responder - pinged node
+
+	Nonce string `json:"nonce"` // Nonce - nonce of answered ping
+
+	Sent time.Time `json:"sent"` // Sent - time ping was sent (copied from ping)
+}
+
+// PingResult - outcome of a successful ping
+type PingResult struct {
+	Responder *node.Node `json:"responder"` // Responder - pinged node (identity verified against its TLS certificate)
+
+	RTT time.Duration `json:"rtt"` // RTT - measured round-trip time
+
+	Time time.Time `json:"time"` // Time - time pong was received
+}
+
+// pingRound - outcomes of a round of pings, made on a database snapshot (see pingPeers)
+type pingRound struct {
+	peers []node.Node // peers - pinged peers
+
+	results []*PingResult // results - results of successful pings (by peer index)
+
+	errs []error // errs - errors of failed pings (by peer index)
+}
+
+// PeerLiveness - liveness state of a peer, maintained by the liveness scheduler (see LivenessRoutine)
+type PeerLiveness struct {
+	RTT time.Duration `json:"rtt"` // RTT - round-trip time of last successful ping
+
+	LastAttempt time.Time `json:"last attempt"` // LastAttempt - time peer was last pinged
+
+	Failures uint `json:"failures"` // Failures - number of consecutive failed pings
+
+	LastError string `json:"error,omitempty"` // LastError - error of last failed ping
+
+	Suspect bool `json:"suspect,omitempty"` // Suspect - peer exceeded suspect thresholds (see EvictDeadPeers)
+}
+
+var (
+	// ErrInvalidPong - error returned when a pong doesn't answer the sent ping
+	ErrInvalidPong = errors.New("invalid pong")
+)
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// PingNode - ping given contact (listening on given port) over pooled session of given local node, measuring round-trip time and verifying the responder is the peer authenticated on the session (and the contact, if its identity is known)
+func PingNode(localNode *node.Node, contact *node.Node, network string, port uint) (*PingResult, error) {
+	pool, err := localNode.ConnectionPool() // Fetch pool of local node
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	address := contact.DialAddress(int(port)) // Init contact address
+
+	peerID, err := pool.PeerID(address, contact.NodeID) // Fetch authenticated peer ID (connecting before ping is timed)
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	nonce, err := newNonce() // Generate nonce
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	ping := &Ping{Network: network, Sender: contactOf(localNode), Nonce: nonce, Sent: time.Now()} // Init ping
+
+	codec, result, err := pool.RequestEnvelope(address, contact.NodeID, common.EnvelopeKindPing, ping) // Send ping
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	rtt := time.Since(ping.Sent) // Measure round-trip time
+
+	pong, err := DecodePong(codec, result) // Decode pong
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if pong.Nonce != nonce || pong.Responder == nil || pong.Responder.NodeID != peerID || pong.Responder.VerifyIdentity() != nil { // Check pong answers ping, responder is authenticated peer
+		return nil, ErrInvalidPong // Return error
+	}
+
+	pong.Responder.Address = contact.Address // Set responder address
+
+	return &PingResult{Responder: pong.Responder, RTT: rtt, Time: time.Now()}, nil // Return result
+}
+
+// NewPong - initialize pong answering given ping from given local node
+func NewPong(localNode *node.Node, ping *Ping) *Pong {
+	return &Pong{Responder: contactOf(localNode), Nonce: ping.Nonce, Sent: ping.Sent} // Return pong
+}
+
+// HandlePing - answer ping, marking sender as seen unless it is anonymous or banned
+func (db *NodeDatabase) HandlePing(localNode *node.Node, ping *Ping) (*Pong, error) {
+	if ping.Sender != nil && ping.Sender.NodeID != "" { // Check for sender identity
+		err := db.markSeen(ping.Sender) // Mark sender seen
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+	}
+
+	return NewPong(localNode, ping), nil // Return pong
+}
+
+// PingPeer - ping given peer (listening on given port), recording result in peer liveness state and reputation
+func (db *NodeDatabase) PingPeer(localNode *node.Node, peer *node.Node, port uint) (*PingResult, error) {
+	result, err := PingNode(localNode, peer, db.NetworkAlias, port) // Ping peer
+
+	db.recordPing(peer, result, err) // Record result
+
+	return result, err // Return result
+}
+
+// PingPeers - concurrently ping every peer (listening on given port), recording results. Returns results of successful pings.
+func (db *NodeDatabase) PingPeers(localNode *node.Node, port uint) []PingResult {
+	return db.applyPings(db.pingPeers(localNode, port)) // Ping peers, record results
+}
+
+// LivenessRoutine - ping every peer of the network database stored in given node environment every given duration until context is cancelled, recording results and evicting dead peers (see DefaultEvictionPolicy) in the stored database after each round (db only identifies the network)
+func (db *NodeDatabase) LivenessRoutine(ctx context.Context, localNode *node.Node, port uint, interval time.Duration) {
+	ticker := time.NewTicker(interval) // Init ticker
+
+	defer ticker.Stop() // Stop ticker
+
+	for {
+		select {
+		case <-ticker.C: // Check tick
+			snapshot, err := SnapshotFromMemory(localNode.Environment, db.NetworkAlias) // Read db
+
+			if err == ErrNoDatabase { // Check network not joined
+				continue // Wait for next tick
+			}
+
+			if err == nil { // Check for errors
+				round := snapshot.pingPeers(localNode, port) // Ping peers
+
+				err = UpdateInMemory(localNode.Environment, db.NetworkAlias, func(current *NodeDatabase) error {
+					current.SetLocalNode(localNode) // Record evictions on behalf of local node
+
+					results := current.applyPings(round) // Record results
+
+					common.Printf("\n-- PING -- %d of %d peers alive", len(results), len(round.peers)) // Log round
+
+					current.EvictDeadPeers(DefaultEvictionPolicy) // Evict dead peers
+
+					return nil // Write db
+				}) // Write round to memory
+			}
+
+			if err != nil { // Check for errors
+				common.Printf("\n-- PING -- liveness round failed: %s", err.Error()) // Log failure
+			}
+		case <-ctx.Done(): // Check cancelled
+			return // Stop
+		}
+	}
+}
+
+// PeerLivenessOf - fetch liveness state of given peer (nil if it has never been pinged)
+func (db *NodeDatabase) PeerLivenessOf(peer *node.Node) *PeerLiveness {
+	return db.Liveness[livenessKey(peer)] // Return liveness state
+}
+
+// Encode - encode ping with given codec
+func (ping *Ping) Encode(codec common.Codec) ([]byte, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		return proto.Marshal(ping.ToWire()) // Marshal ping
+	case common.CodecJSON:
+		return json.Marshal(ping) // Serialize ping
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// DecodePing - decode ping encoded with given codec
+func DecodePing(codec common.Codec, b []byte) (*Ping, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		wirePing := &wire.Ping{} // Init wire ping buffer
+
+		err := proto.Unmarshal(b, wirePing) // Unmarshal ping
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return PingFromWire(wirePing), nil // Return ping
+	case common.CodecJSON:
+		ping := &Ping{} // Init ping buffer
+
+		err := json.Unmarshal(b, ping) // Decode ping
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return ping, nil // Return ping
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// ToWire - convert ping to protobuf wire type
+func (ping *Ping) ToWire() *wire.Ping {
+	return &wire.Ping{Network: ping.Network, Sender: ping.Sender.ToWire(), Nonce: ping.Nonce, Sent: ping.Sent.UnixNano()} // Return wire ping
+}
+
+// PingFromWire - convert protobuf wire type to ping
+func PingFromWire(wirePing *wire.Ping) *Ping {
+	return &Ping{Network: wirePing.Network, Sender: node.NodeFromWire(wirePing.Sender), Nonce: wirePing.Nonce, Sent: time.Unix(0, wirePing.Sent)} // Return ping
+}
+
+// Encode - encode pong with given codec
+func (pong *Pong) Encode(codec common.Codec) ([]byte, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		return proto.Marshal(pong.ToWire()) // Marshal pong
+	case common.CodecJSON:
+		return json.Marshal(pong) // Serialize pong
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// DecodePong - decode pong encoded with given codec
+func DecodePong(codec common.Codec, b []byte) (*Pong, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		wirePong := &wire.Pong{} // Init wire pong buffer
+
+		err := proto.Unmarshal(b, wirePong) // Unmarshal pong
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return PongFromWire(wirePong), nil // Return pong
+	case common.CodecJSON:
+		pong := &Pong{} // Init pong buffer
+
+		err := json.Unmarshal(b, pong) // Decode pong
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return pong, nil // Return pong
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// ToWire - convert pong to protobuf wire type
+func (pong *Pong) ToWire() *wire.Pong {
+	return &wire.Pong{Responder: pong.Responder.ToWire(), Nonce: pong.Nonce, Sent: pong.Sent.UnixNano()} // Return wire pong
+}
+
+// PongFromWire - convert protobuf wire type to pong
+func PongFromWire(wirePong *wire.Pong) *Pong {
+	return &Pong{Responder: node.NodeFromWire(wirePong.Responder), Nonce: wirePong.Nonce, Sent: time.Unix(0, wirePong.Sent)} // Return pong
+}
+
+// String - convert result to string
+func (result *PingResult) String() string {
+	return fmt.Sprintf("pong from %s at %s in %s", result.Responder.NodeID, result.Responder.Address, result.RTT.String()) // Return string
+}
+
+/*
+	END EXPORTED METHODS
+*/
+
+/*
+	BEGIN INTERNAL METHODS:
+*/
+
+// recordPing - update liveness state, reputation of given peer with given ping outcome
+func (db *NodeDatabase) recordPing(peer *node.Node, result *PingResult, pingErr error) {
+	if db.Liveness == nil { // Check for nil liveness states
+		db.Liveness = make(map[string]*PeerLiveness) // Init liveness states
+	}
+
+	key := livenessKey(peer) // Fetch liveness key
+
+	liveness, found := db.Liveness[key] // Fetch liveness state
+
+	if !found { // Check for first ping
+		liveness = &PeerLiveness{} // Init liveness state
+
+		db.Liveness[key] = liveness // Set liveness state
+	}
+
+	liveness.LastAttempt = time.Now() // Set attempt time
+
+	if pingErr != nil { // Check ping failed
+		liveness.Failures++                  // Increment consecutive failures
+		liveness.LastError = pingErr.Error() // Set error
+
+		if peer.NodeID != "" { // Check peer has identity
+			db.RecordEvent(peer.NodeID, ReputationTimeout) // Penalize unresponsive peer
+		}
+
+		return // Stop
+	}
+
+	liveness.RTT, liveness.Failures, liveness.LastError = result.RTT, 0, "" // Set alive
+
+	if peer.NodeID == "" { // Check for peer without known identity
+		if nodeIndex, err := db.QueryForAddress(peer.Address); err == nil { // Check peer still in database
+			learned := (*db.Nodes)[nodeIndex] // Fetch peer
+
+			learned.NodeID, learned.PublicKey = result.Responder.NodeID, result.Responder.PublicKey // Set verified identity
+
+			db.updateNode(nodeIndex, &learned) // Update peer
+		}
+
+		delete(db.Liveness, key) // Re-key liveness state by NodeID
+
+		db.Liveness[result.Responder.NodeID] = liveness // Set liveness state
+	}
+
+	db.RecordEvent(result.Responder.NodeID, ReputationResponse) // Reward peer, mark seen
+}
+
+// pingPeers - concurrently ping every peer (listening on given port) without recording results (see applyPings)
+func (db *NodeDatabase) pingPeers(localNode *node.Node, port uint) *pingRound {
+	round := &pingRound{peers: []node.Node{}} // Init round
+
+	if db.Nodes == nil { // Check for no peers
+		return round // Nothing to ping
+	}
+
+	for _, peer := range *db.Nodes { // Iterate through peers
+		if isExcluded(&peer, []*node.Node{localNode}) || (peer.NodeID != "" && db.IsBanned(peer.NodeID)) { // Check for local, banned peer
+			continue // Skip peer
+		}
+
+		round.peers = append(round.peers, peer) // Append peer
+	}
+
+	round.results = make([]*PingResult, len(round.peers)) // Init results buffer
+	round.errs = make([]error, len(round.peers))          // Init errors buffer
+
+	var wg sync.WaitGroup // Init wait group
+
+	for x := range round.peers { // Iterate through peers
+		wg.Add(1) // Add ping
+
+		go func(x int) {
+			defer wg.Done() // Finish ping
+
+			round.results[x], round.errs[x] = PingNode(localNode, &round.peers[x], db.NetworkAlias, port) // Ping peer
+		}(x)
+	}
+
+	wg.Wait() // Wait for pings
+
+	return round // Return round
+}
+
+// applyPings - record outcomes of given round of pings for peers still in database. Returns results of successful pings.
+func (db *NodeDatabase) applyPings(round *pingRound) []PingResult {
+	succeeded := []PingResult{} // Init successful results buffer
+
+	for x := range round.peers { // Iterate through peers
+		if _, err := db.QueryForAddress(round.peers[x].Address); err != nil { // Check peer was removed since ping
+			continue // Skip peer
+		}
+
+		db.recordPing(&round.peers[x], round.results[x], round.errs[x]) // Record result
+
+		if round.errs[x] == nil { // Check ping succeeded
+			succeeded = append(succeeded, *round.results[x]) // Append result
+		}
+	}
+
+	return succeeded // Return successful results
+}
+
+// livenessKey - key of given peer's liveness state (NodeID, or address for peers without known identity)
+func livenessKey(peer *node.Node) string {
+	if peer.NodeID != "" { // Check peer has identity
+		return peer.NodeID // Return NodeID
+	}
+
+	return peer.Address // Return address
+}
+
+// newNonce - generate random hex-encoded nonce
+func newNonce() (string, error) {
+	b := make([]byte, nonceSize) // Init nonce buffer
+
+	_, err := rand.Read(b) // Read random bytes
+
+	if err != nil { // Check for errors
+		return "", err // Return found error
+	}
+
+	return hex.EncodeToString(b), nil // Return nonce
+}
+
+/*
+	END INTERNAL METHODS
+*/
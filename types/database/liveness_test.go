@@ -0,0 +1,80 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/common"
+)
+
+// TestEncodePing - test functionality of Ping, Pong Encode(), Decode() methods
+func TestEncodePing(t *testing.T) {
+	sender := newTestContact(t, "memory://ping-sender") // Init sender
+
+	for _, codec := range []common.Codec{common.CodecJSON, common.CodecProtobuf} { // Iterate through codecs
+		ping := &Ping{Network: "GoP2P_TestNet", Sender: sender, Nonce: "nonce", Sent: time.Unix(0, 42)} // Init ping
+
+		encoded, err := ping.Encode(codec) // Encode ping
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		decoded, err := DecodePing(codec, encoded) // Decode ping
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if decoded.Network != ping.Network || decoded.Sender.NodeID != sender.NodeID || decoded.Nonce != ping.Nonce || !decoded.Sent.Equal(ping.Sent) { // Check for mismatch
+			t.Errorf("invalid decoded %s ping %v", codec, decoded) // Log found error
+			t.FailNow()                                            // Panic
+		}
+
+		encoded, err = NewPong(sender, ping).Encode(codec) // Encode pong
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		pong, err := DecodePong(codec, encoded) // Decode pong
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if pong.Responder.NodeID != sender.NodeID || pong.Nonce != ping.Nonce || !pong.Sent.Equal(ping.Sent) { // Check for mismatch
+			t.Errorf("invalid decoded %s pong %v", codec, pong) // Log found error
+			t.FailNow()                                         // Panic
+		}
+	}
+}
+
+// TestRecordPing - test functionality of recordPing() method
+func TestRecordPing(t *testing.T) {
+	peer := newTestContact(t, "memory://ping-peer") // Init peer
+
+	db := &NodeDatabase{NetworkAlias: "GoP2P_TestNet"} // Init database
+
+	db.insertNode(peer) // Add peer
+
+	db.recordPing(peer, nil, errors.New("timed out")) // Record failed ping
+	db.recordPing(peer, nil, errors.New("timed out")) // Record failed ping
+
+	if liveness := db.PeerLivenessOf(peer); liveness.Failures != 2 || liveness.LastError != "timed out" { // Check failures were counted
+		t.Errorf("invalid liveness %v", liveness) // Log found error
+		t.FailNow()                               // Panic
+	}
+
+	db.recordPing(peer, &PingResult{Responder: peer, RTT: time.Millisecond, Time: time.Now()}, nil) // Record successful ping
+
+	if liveness := db.PeerLivenessOf(peer); liveness.Failures != 0 || liveness.RTT != time.Millisecond || (*db.Nodes)[0].LastPingTime.IsZero() { // Check peer was marked alive
+		t.Errorf("invalid liveness %v", liveness) // Log found error
+		t.FailNow()                               // Panic
+	}
+}
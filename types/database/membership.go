@@ -0,0 +1,959 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/internal/rpc/proto/wire"
+	"github.com/dowlandaiello/GoP2P/types/node"
+	"github.com/golang/protobuf/proto"
+)
+
+// MemberState - state of a member in a membership list
+type MemberState string
+
+// MembershipMessageKind - kind of membership protocol message
+type MembershipMessageKind string
+
+// MembershipEventKind - kind of change in a membership list
+type MembershipEventKind string
+
+const (
+	// MemberAlive - member answered a recent probe (or gossip says so)
+	MemberAlive = MemberState("alive")
+
+	// MemberSuspect - member failed a direct and indirect probe, and is declared dead unless it refutes the suspicion in time
+	MemberSuspect = MemberState("suspect")
+
+	// MemberDead - member was suspect for longer than the suspicion timeout
+	MemberDead = MemberState("dead")
+
+	// MemberLeft - member left gracefully
+	MemberLeft = MemberState("left")
+
+	// MembershipPing - direct probe of the receiving member
+	MembershipPing = MembershipMessageKind("ping")
+
+	// MembershipPingReq - request to probe a target member on behalf of the sender
+	MembershipPingReq = MembershipMessageKind("ping-req")
+
+	// MembershipSync - full membership list exchange (used to join through a seed)
+	MembershipSync = MembershipMessageKind("sync")
+
+	// MembershipJoin - member joined (or rejoined) the network
+	MembershipJoin = MembershipEventKind("join")
+
+	// MembershipLeave - member left the network gracefully
+	MembershipLeave = MembershipEventKind("leave")
+
+	// MembershipFail - member was declared dead
+	MembershipFail = MembershipEventKind("fail")
+)
+
+// Member - entry of a membership list
+type Member struct {
+	Node node.Node `json:"node"` // Node - member contact
+
+	State MemberState `json:"state"` // State - member state
+
+	Incarnation uint64 `json:"incarnation"` // Incarnation - incarnation number (only incremented by the member itself, to refute suspicions)
+
+	Changed time.Time `json:"changed"` // Changed - time state last changed (suspicion timeouts are measured from this time)
+}
+
+// MembershipUpdate - change of a member's state, piggybacked on membership protocol messages
+type MembershipUpdate struct {
+	Node node.Node `json:"node"` // Node - member contact
+
+	State MemberState `json:"state"` // State - new member state
+
+	Incarnation uint64 `json:"incarnation"` // Incarnation - incarnation number of state
+}
+
+// MembershipEvent - join, leave or failure of a member
+type MembershipEvent struct {
+	Kind MembershipEventKind `json:"kind"` // Kind - kind of event
+
+	Member Member `json:"member"` // Member - member after change
+}
+
+// MembershipMessage - membership protocol message (ping, ping-req or sync), answered with a MembershipAck
+type MembershipMessage struct {
+	Network string `json:"network"` // Network - alias of network whose members are probed
+
+	Kind MembershipMessageKind `json:"kind"` // Kind - kind of message
+
+	Sender *node.Node `json:"sender"` // Sender - sending member
+
+	Target *node.Node `json:"target,omitempty"` // Target - member probed on behalf of sender (ping-req)
+
+	Nonce string `json:"nonce"` // Nonce - random nonce echoed in ack
+
+	Updates []MembershipUpdate `json:"updates"` // Updates - piggybacked membership updates (every member for sync)
+}
+
+// MembershipAck - answer to a MembershipMessage
+type MembershipAck struct {
+	Responder *node.Node `json:"responder"` // Responder - answering member
+
+	Nonce string `json:"nonce"` // Nonce - nonce of answered message
+
+	Acked bool `json:"acked"` // Acked - probed member answered (false if the target of a ping-req didn't answer in time)
+
+	Updates []MembershipUpdate `json:"updates"` // Updates - piggybacked membership updates (every member for sync)
+}
+
+// MembershipConfig - parameters of the membership protocol
+type MembershipConfig struct {
+	ProbeInterval time.Duration // ProbeInterval - duration of a protocol period (one member is probed per period)
+
+	ProbeTimeout time.Duration // ProbeTimeout - maximum duration to wait on a direct probe
+
+	IndirectProbes int // IndirectProbes - number of members asked to probe a member that didn't answer a direct probe
+
+	SuspicionTimeout time.Duration // SuspicionTimeout - duration after which a suspect member is declared dead
+
+	RetransmitMultiplier int // RetransmitMultiplier - updates are piggybacked RetransmitMultiplier * log10(members + 1) times
+
+	MaxPiggybacked int // MaxPiggybacked - maximum number of updates piggybacked on a single message
+
+	DeadRetention time.Duration // DeadRetention - duration dead and departed members are remembered (keeps stale gossip from resurrecting them)
+}
+
+// Membership - gossip-based (SWIM) membership list of a network, maintained by a local node
+type Membership struct {
+	Node *node.Node // Node - local node
+
+	Network string // Network - alias of network
+
+	Port uint // Port - port members listen on
+
+	Config MembershipConfig // Config - protocol parameters
+
+	Events func(event *MembershipEvent) // Events - called with every join, leave and failure (optional)
+
+	members map[string]*Member // members - membership list (by NodeID, including local node)
+
+	broadcasts []*membershipBroadcast // broadcasts - updates waiting to be piggybacked
+
+	probeOrder []string // probeOrder - NodeIDs of members left to probe in the current round
+
+	mutex sync.Mutex // mutex - guards members, broadcasts, probeOrder
+}
+
+// membershipBroadcast - update waiting to be piggybacked, with the number of times it has been sent
+type membershipBroadcast struct {
+	update MembershipUpdate // update - piggybacked update
+
+	transmits int // transmits - number of messages update was piggybacked on
+}
+
+var (
+	// DefaultMembershipConfig - default membership protocol parameters
+	DefaultMembershipConfig = MembershipConfig{
+		ProbeInterval:        time.Second,            // Set protocol period
+		ProbeTimeout:         500 * time.Millisecond, // Set probe timeout
+		IndirectProbes:       3,                      // Set indirect probes
+		SuspicionTimeout:     5 * time.Second,        // Set suspicion timeout
+		RetransmitMultiplier: 4,                      // Set retransmit multiplier
+		MaxPiggybacked:       16,                     // Set piggybacked updates
+		DeadRetention:        time.Minute,            // Set dead member retention
+	}
+
+	// ErrNotMember - error returned when a node receives membership messages of a network it hasn't joined
+	ErrNotMember = errors.New("node isn't a member of network")
+
+	// ErrInvalidAck - error returned when an ack doesn't answer the sent message
+	ErrInvalidAck = errors.New("invalid membership ack")
+
+	// memberships - running memberships (by local NodeID, network alias)
+	memberships = make(map[string]*Membership)
+
+	// membershipsMutex - guards memberships
+	membershipsMutex sync.Mutex
+)
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// NewMembership - initialize membership list of given network containing only given local node (listening on given port), registering it to answer membership messages
+func NewMembership(localNode *node.Node, network string, port uint) (*Membership, error) {
+	err := localNode.VerifyIdentity() // Check local node has identity
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	membership := &Membership{
+		Node:    localNode,                // Set local node
+		Network: network,                  // Set network
+		Port:    port,                     // Set port
+		Config:  DefaultMembershipConfig,  // Set config
+		members: make(map[string]*Member), // Init members
+	} // Init membership
+
+	membership.members[localNode.NodeID] = &Member{Node: *contactOf(localNode), State: MemberAlive, Changed: time.Now()} // Add local node
+
+	membershipsMutex.Lock() // Lock memberships
+
+	memberships[membershipKey(localNode, network)] = membership // Register membership
+
+	membershipsMutex.Unlock() // Unlock memberships
+
+	return membership, nil // Return membership
+}
+
+// MembershipOf - fetch membership of given local node in given network (nil if it hasn't joined the network)
+func MembershipOf(localNode *node.Node, network string) *Membership {
+	membershipsMutex.Lock() // Lock memberships
+
+	defer membershipsMutex.Unlock() // Unlock memberships
+
+	return memberships[membershipKey(localNode, network)] // Return membership
+}
+
+// Join - exchange membership lists with nodes at given seed addresses, returning number of seeds that answered
+func (membership *Membership) Join(seedAddresses ...string) (int, error) {
+	joined := 0 // Init joined counter
+
+	var lastErr error // Init error buffer
+
+	for _, seedAddress := range seedAddresses { // Iterate through seeds
+		seed, err := node.NodeFromAddress(seedAddress) // Init seed contact (identity unknown unless pinned by address)
+
+		if err != nil { // Check for errors
+			lastErr = err // Set error
+
+			continue // Try next seed
+		}
+
+		ack, err := membership.send(seed, MembershipSync, nil, membership.fullState(), membership.Config.ProbeTimeout) // Exchange membership lists
+
+		if err != nil { // Check for errors
+			lastErr = err // Set error
+
+			continue // Try next seed
+		}
+
+		membership.publish(membership.merge(ack.Updates)) // Merge seed membership list
+
+		joined++ // Increment joined
+	}
+
+	if joined == 0 && lastErr != nil { // Check no seed answered
+		return 0, lastErr // Return found error
+	}
+
+	return joined, nil // Return joined seeds
+}
+
+// Leave - announce local node is leaving to up to Config.IndirectProbes members, unregistering membership
+func (membership *Membership) Leave() error {
+	membership.mutex.Lock() // Lock membership
+
+	local := membership.members[membership.Node.NodeID] // Fetch local member
+
+	local.State, local.Incarnation, local.Changed = MemberLeft, local.Incarnation+1, time.Now() // Set left
+
+	membership.queue(MembershipUpdate{Node: local.Node, State: MemberLeft, Incarnation: local.Incarnation}) // Queue leave
+
+	targets := membership.randomMembers(membership.Config.IndirectProbes) // Select members to notify
+
+	membership.mutex.Unlock() // Unlock membership
+
+	membershipsMutex.Lock() // Lock memberships
+
+	delete(memberships, membershipKey(membership.Node, membership.Network)) // Unregister membership
+
+	membershipsMutex.Unlock() // Unlock memberships
+
+	var lastErr error // Init error buffer
+
+	for x := range targets { // Iterate through members
+		_, err := membership.send(&targets[x], MembershipPing, nil, membership.piggyback(), membership.Config.ProbeTimeout) // Notify member
+
+		if err == nil { // Check member was notified
+			return nil // Leave will be gossiped by notified member
+		}
+
+		lastErr = err // Set error
+	}
+
+	return lastErr // Return found error (nil if there are no members)
+}
+
+// Members - fetch alive and suspect members (including local node), sorted by NodeID
+func (membership *Membership) Members() []Member {
+	membership.mutex.Lock() // Lock membership
+
+	defer membership.mutex.Unlock() // Unlock membership
+
+	members := []Member{} // Init members buffer
+
+	for _, member := range membership.members { // Iterate through members
+		if member.isActive() { // Check member is alive or suspect
+			members = append(members, *member) // Append member
+		}
+	}
+
+	sort.Slice(members, func(i, j int) bool { return members[i].Node.NodeID < members[j].Node.NodeID }) // Sort members
+
+	return members // Return members
+}
+
+// Member - fetch member with given NodeID (in any state)
+func (membership *Membership) Member(nodeID string) (*Member, bool) {
+	membership.mutex.Lock() // Lock membership
+
+	defer membership.mutex.Unlock() // Unlock membership
+
+	member, found := membership.members[nodeID] // Fetch member
+
+	if !found { // Check member unknown
+		return nil, false // Not found
+	}
+
+	copied := *member // Copy member
+
+	return &copied, true // Return member
+}
+
+// Probe - run a single protocol period: probe the next member directly, asking Config.IndirectProbes members to probe it if it doesn't answer (marking it suspect if none of them reach it), then declare members suspect for longer than Config.SuspicionTimeout dead. Returns the error of a failed probe.
+func (membership *Membership) Probe() error {
+	membership.mutex.Lock() // Lock membership
+
+	target := membership.nextTarget() // Select member to probe
+
+	membership.mutex.Unlock() // Unlock membership
+
+	var probeErr error // Init probe error buffer
+
+	if target != nil { // Check for member to probe
+		probeErr = membership.probe(target) // Probe member
+	}
+
+	membership.mutex.Lock() // Lock membership
+
+	if probeErr != nil { // Check probe failed
+		membership.suspect(target) // Suspect member
+	}
+
+	events := membership.expireSuspicions() // Declare suspects dead
+
+	membership.mutex.Unlock() // Unlock membership
+
+	membership.publish(events) // Publish failures
+
+	return probeErr // Return probe error
+}
+
+// Start - probe members every Config.ProbeInterval until context is cancelled
+func (membership *Membership) Start(ctx context.Context) {
+	ticker := time.NewTicker(membership.Config.ProbeInterval) // Init ticker
+
+	defer ticker.Stop() // Stop ticker
+
+	for {
+		select {
+		case <-ticker.C: // Check tick
+			membership.Probe() // Run protocol period
+		case <-ctx.Done(): // Check cancelled
+			return // Stop
+		}
+	}
+}
+
+// HandleMessage - merge updates piggybacked on given message, answering it with an ack (probing the target of a ping-req first)
+func (membership *Membership) HandleMessage(message *MembershipMessage) (*MembershipAck, error) {
+	membership.publish(membership.merge(message.Updates)) // Merge piggybacked updates
+
+	ack := &MembershipAck{Responder: contactOf(membership.Node), Nonce: message.Nonce, Acked: true} // Init ack
+
+	switch message.Kind {
+	case MembershipPing:
+		ack.Updates = membership.piggyback() // Piggyback updates
+	case MembershipPingReq:
+		if message.Target == nil || message.Target.NodeID == "" { // Check for missing target
+			return nil, errors.New("ping-req has no target") // Return error
+		}
+
+		_, err := membership.send(message.Target, MembershipPing, nil, membership.piggyback(), membership.Config.ProbeTimeout) // Probe target
+
+		ack.Acked, ack.Updates = err == nil, membership.piggyback() // Set acked, piggyback updates
+	case MembershipSync:
+		ack.Updates = membership.fullState() // Send every member
+	default:
+		return nil, errors.New("unknown membership message kind") // Return error
+	}
+
+	return ack, nil // Return ack
+}
+
+// ApplyMembershipEvent - add joined member to database (refreshing its shard entries), removing departed and failed members from database and shards. Changes are local to the database (they aren't recorded as replicated operations): events are disseminated by the membership protocol itself, each member applying them to its own view.
+func (db *NodeDatabase) ApplyMembershipEvent(event *MembershipEvent) error {
+	peer := event.Member.Node // Fetch member contact
+
+	if event.Kind != MembershipJoin { // Check member left or failed
+		if db.Nodes != nil { // Check for peers
+			db.evict(&peer) // Remove peer
+		}
+
+		if db.Shards != nil { // Check for shards
+			for x := range *db.Shards { // Iterate through shards
+				(*db.Shards)[x].RemoveNode(peer.NodeID) // Remove from shard
+			}
+		}
+
+		return nil // No error occurred, return nil
+	}
+
+	err := db.markSeen(&peer) // Add peer
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	if nodeIndex, err := db.QueryForNodeID(peer.NodeID); err == nil && db.Shards != nil { // Check peer was added, database has shards
+		for x := range *db.Shards { // Iterate through shards
+			(*db.Shards)[x].UpdateNode(&(*db.Nodes)[nodeIndex]) // Refresh shard entries
+		}
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// Encode - encode membership message with given codec
+func (message *MembershipMessage) Encode(codec common.Codec) ([]byte, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		return proto.Marshal(message.ToWire()) // Marshal message
+	case common.CodecJSON:
+		return json.Marshal(message) // Serialize message
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// DecodeMembershipMessage - decode membership message encoded with given codec
+func DecodeMembershipMessage(codec common.Codec, b []byte) (*MembershipMessage, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		wireMessage := &wire.MembershipMessage{} // Init wire message buffer
+
+		err := proto.Unmarshal(b, wireMessage) // Unmarshal message
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return MembershipMessageFromWire(wireMessage), nil // Return message
+	case common.CodecJSON:
+		message := &MembershipMessage{} // Init message buffer
+
+		err := json.Unmarshal(b, message) // Decode message
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return message, nil // Return message
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// ToWire - convert membership message to protobuf wire type
+func (message *MembershipMessage) ToWire() *wire.MembershipMessage {
+	return &wire.MembershipMessage{Network: message.Network, Kind: string(message.Kind), Sender: message.Sender.ToWire(), Target: message.Target.ToWire(), Nonce: message.Nonce, Updates: updatesToWire(message.Updates)} // Return wire message
+}
+
+// MembershipMessageFromWire - convert protobuf wire type to membership message
+func MembershipMessageFromWire(wireMessage *wire.MembershipMessage) *MembershipMessage {
+	return &MembershipMessage{Network: wireMessage.Network, Kind: MembershipMessageKind(wireMessage.Kind), Sender: node.NodeFromWire(wireMessage.Sender), Target: node.NodeFromWire(wireMessage.Target), Nonce: wireMessage.Nonce, Updates: updatesFromWire(wireMessage.Updates)} // Return message
+}
+
+// Encode - encode membership ack with given codec
+func (ack *MembershipAck) Encode(codec common.Codec) ([]byte, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		return proto.Marshal(ack.ToWire()) // Marshal ack
+	case common.CodecJSON:
+		return json.Marshal(ack) // Serialize ack
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// DecodeMembershipAck - decode membership ack encoded with given codec
+func DecodeMembershipAck(codec common.Codec, b []byte) (*MembershipAck, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		wireAck := &wire.MembershipAck{} // Init wire ack buffer
+
+		err := proto.Unmarshal(b, wireAck) // Unmarshal ack
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return MembershipAckFromWire(wireAck), nil // Return ack
+	case common.CodecJSON:
+		ack := &MembershipAck{} // Init ack buffer
+
+		err := json.Unmarshal(b, ack) // Decode ack
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return ack, nil // Return ack
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// ToWire - convert membership ack to protobuf wire type
+func (ack *MembershipAck) ToWire() *wire.MembershipAck {
+	return &wire.MembershipAck{Responder: ack.Responder.ToWire(), Nonce: ack.Nonce, Acked: ack.Acked, Updates: updatesToWire(ack.Updates)} // Return wire ack
+}
+
+// MembershipAckFromWire - convert protobuf wire type to membership ack
+func MembershipAckFromWire(wireAck *wire.MembershipAck) *MembershipAck {
+	return &MembershipAck{Responder: node.NodeFromWire(wireAck.Responder), Nonce: wireAck.Nonce, Acked: wireAck.Acked, Updates: updatesFromWire(wireAck.Updates)} // Return ack
+}
+
+/*
+	END EXPORTED METHODS
+*/
+
+/*
+	BEGIN INTERNAL METHODS:
+*/
+
+// probe - probe given member directly, then indirectly through Config.IndirectProbes random members
+func (membership *Membership) probe(target *node.Node) error {
+	_, err := membership.send(target, MembershipPing, nil, membership.piggyback(), membership.Config.ProbeTimeout) // Probe member directly
+
+	if err == nil { // Check member answered
+		return nil // Member alive
+	}
+
+	membership.mutex.Lock() // Lock membership
+
+	intermediaries := membership.randomMembers(membership.Config.IndirectProbes, target) // Select intermediaries
+
+	membership.mutex.Unlock() // Unlock membership
+
+	acked := make(chan bool, len(intermediaries)) // Init acked buffer
+
+	for x := range intermediaries { // Iterate through intermediaries
+		go func(intermediary *node.Node) {
+			ack, err := membership.send(intermediary, MembershipPingReq, target, membership.piggyback(), 2*membership.Config.ProbeTimeout) // Ask intermediary to probe member
+
+			acked <- err == nil && ack.Acked // Send result
+		}(&intermediaries[x])
+	}
+
+	for range intermediaries { // Wait on intermediaries
+		if <-acked { // Check member answered intermediary
+			return nil // Member alive
+		}
+	}
+
+	return err // Return direct probe error
+}
+
+// send - send membership message of given kind (probing given target for ping-reqs) to given contact, waiting at most given timeout for its ack and merging piggybacked updates
+func (membership *Membership) send(contact *node.Node, kind MembershipMessageKind, target *node.Node, updates []MembershipUpdate, timeout time.Duration) (*MembershipAck, error) {
+	nonce, err := newNonce() // Generate nonce
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	message := &MembershipMessage{Network: membership.Network, Kind: kind, Sender: contactOf(membership.Node), Target: target, Nonce: nonce, Updates: updates} // Init message
+
+	type result struct {
+		ack *MembershipAck
+		err error
+	}
+
+	results := make(chan result, 1) // Init result buffer
+
+	go func() {
+		ack, err := sendMembershipMessage(membership.Node, contact, message, membership.Port) // Send message
+
+		results <- result{ack, err} // Send result
+	}()
+
+	timer := time.NewTimer(timeout) // Init timeout
+
+	defer timer.Stop() // Stop timer
+
+	select {
+	case sent := <-results: // Check answered
+		if sent.err != nil { // Check for errors
+			return nil, sent.err // Return found error
+		}
+
+		if sent.ack.Nonce != nonce { // Check ack answers message
+			return nil, ErrInvalidAck // Return error
+		}
+
+		if kind != MembershipSync { // Check full state isn't merged by caller
+			membership.publish(membership.merge(sent.ack.Updates)) // Merge piggybacked updates
+		}
+
+		return sent.ack, nil // Return ack
+	case <-timer.C: // Check timed out
+		return nil, errors.New("membership probe of " + contact.Address + " timed out") // Return error
+	}
+}
+
+// merge - apply given updates to membership list, queuing accepted updates for gossip, refuting suspicions of local node. Returns resulting joins, leaves and failures.
+func (membership *Membership) merge(updates []MembershipUpdate) []MembershipEvent {
+	membership.mutex.Lock() // Lock membership
+
+	defer membership.mutex.Unlock() // Unlock membership
+
+	events := []MembershipEvent{} // Init events buffer
+
+	for _, update := range updates { // Iterate through updates
+		if update.Node.VerifyIdentity() != nil { // Check update describes a valid identity
+			continue // Skip update
+		}
+
+		if update.Node.NodeID == membership.Node.NodeID { // Check update describes local node
+			membership.refute(&update) // Refute suspicion
+
+			continue // Continue to next update
+		}
+
+		update.Node = *contactOf(&update.Node) // Strip contact
+
+		member, found := membership.members[update.Node.NodeID] // Fetch member
+
+		if !membership.supersedes(member, &update) { // Check update is stale
+			continue // Skip update
+		}
+
+		if !found { // Check member unknown
+			member = &Member{} // Init member
+
+			membership.members[update.Node.NodeID] = member // Add member
+		}
+
+		wasActive := found && member.isActive() // Check member was alive or suspect
+
+		member.Node, member.State, member.Incarnation, member.Changed = update.Node, update.State, update.Incarnation, time.Now() // Apply update
+
+		membership.queue(update) // Gossip update
+
+		switch {
+		case update.State == MemberAlive && !wasActive:
+			events = append(events, MembershipEvent{Kind: MembershipJoin, Member: *member}) // Append join
+		case update.State == MemberLeft && wasActive:
+			events = append(events, MembershipEvent{Kind: MembershipLeave, Member: *member}) // Append leave
+		case update.State == MemberDead && wasActive:
+			events = append(events, MembershipEvent{Kind: MembershipFail, Member: *member}) // Append failure
+		}
+	}
+
+	return events // Return events
+}
+
+// supersedes - check given update overrides given member state (nil if member unknown): newer incarnations override older ones; at equal incarnations, suspect overrides alive, and dead or left override both
+func (membership *Membership) supersedes(member *Member, update *MembershipUpdate) bool {
+	if member == nil { // Check member unknown
+		return update.State == MemberAlive // Only learn live members
+	}
+
+	switch update.State {
+	case MemberAlive:
+		return update.Incarnation > member.Incarnation // Check newer incarnation
+	case MemberSuspect:
+		if !member.isActive() { // Check member dead or left
+			return false // Only alive messages revive members
+		}
+
+		return update.Incarnation > member.Incarnation || (update.Incarnation == member.Incarnation && member.State == MemberAlive) // Check newer incarnation, or alive member at same incarnation
+	case MemberDead, MemberLeft:
+		return member.isActive() && update.Incarnation >= member.Incarnation // Check member isn't already gone
+	default:
+		return false // Unknown state
+	}
+}
+
+// refute - refute given update declaring the local node suspect, dead or left (unless it is leaving) by gossiping a newer incarnation
+func (membership *Membership) refute(update *MembershipUpdate) {
+	local := membership.members[membership.Node.NodeID] // Fetch local member
+
+	if update.State == MemberAlive || local.State == MemberLeft || update.Incarnation < local.Incarnation { // Check nothing to refute
+		return // Nothing to do
+	}
+
+	local.Incarnation, local.Changed = update.Incarnation+1, time.Now() // Increment incarnation
+
+	membership.queue(MembershipUpdate{Node: local.Node, State: MemberAlive, Incarnation: local.Incarnation}) // Gossip refutation
+
+	common.Printf("\n-- MEMBERSHIP -- refuted %s state of local node (incarnation %d)", update.State, local.Incarnation) // Log refutation
+}
+
+// suspect - mark given member suspect (if it is alive), gossiping suspicion
+func (membership *Membership) suspect(target *node.Node) {
+	member, found := membership.members[target.NodeID] // Fetch member
+
+	if !found || member.State != MemberAlive { // Check member isn't alive
+		return // Nothing to do
+	}
+
+	member.State, member.Changed = MemberSuspect, time.Now() // Set suspect
+
+	membership.queue(MembershipUpdate{Node: member.Node, State: MemberSuspect, Incarnation: member.Incarnation}) // Gossip suspicion
+
+	common.Printf("\n-- MEMBERSHIP -- suspect member %s (%s)", member.Node.Address, member.Node.NodeID) // Log suspicion
+}
+
+// expireSuspicions - declare members suspect for longer than Config.SuspicionTimeout dead, forgetting members gone for longer than Config.DeadRetention. Returns resulting failures.
+func (membership *Membership) expireSuspicions() []MembershipEvent {
+	events := []MembershipEvent{} // Init events buffer
+
+	for nodeID, member := range membership.members { // Iterate through members
+		age := time.Since(member.Changed) // Fetch time since state changed
+
+		switch {
+		case member.State == MemberSuspect && age > membership.Config.SuspicionTimeout:
+			member.State, member.Changed = MemberDead, time.Now() // Declare dead
+
+			membership.queue(MembershipUpdate{Node: member.Node, State: MemberDead, Incarnation: member.Incarnation}) // Gossip failure
+
+			events = append(events, MembershipEvent{Kind: MembershipFail, Member: *member}) // Append failure
+		case !member.isActive() && nodeID != membership.Node.NodeID && age > membership.Config.DeadRetention:
+			delete(membership.members, nodeID) // Forget member
+		}
+	}
+
+	return events // Return events
+}
+
+// publish - log given events, pass them to membership.Events, and apply them to the network database stored in the local node environment
+func (membership *Membership) publish(events []MembershipEvent) {
+	if len(events) == 0 { // Check no events
+		return // Nothing to do
+	}
+
+	for x := range events { // Iterate through events
+		common.Printf("\n-- MEMBERSHIP -- %s: %s (%s)", events[x].Kind, events[x].Member.Node.Address, events[x].Member.Node.NodeID) // Log event
+
+		if membership.Events != nil { // Check for callback
+			membership.Events(&events[x]) // Publish event
+		}
+	}
+
+	if membership.Node.Environment == nil { // Check no database to update
+		return // Nothing to do
+	}
+
+	MemoryMutex.Lock() // Lock databases
+
+	defer MemoryMutex.Unlock() // Unlock databases
+
+	db, err := ReadDatabaseFromMemory(membership.Node.Environment, membership.Network) // Read network database
+
+	if err != nil { // Check for missing database
+		db = &NodeDatabase{NetworkAlias: membership.Network} // Init database
+	}
+
+	for x := range events { // Iterate through events
+		if err = db.ApplyMembershipEvent(&events[x]); err != nil { // Apply event
+			common.Printf("\n-- MEMBERSHIP -- failed to apply %s of %s: %s", events[x].Kind, events[x].Member.Node.NodeID, err.Error()) // Log failure
+		}
+	}
+
+	if err = db.WriteToMemory(membership.Node.Environment); err != nil { // Write db to memory
+		common.Printf("\n-- MEMBERSHIP -- failed to write database: %s", err.Error()) // Log failure
+	}
+}
+
+// queue - queue given update for gossip, replacing queued updates of the same member
+func (membership *Membership) queue(update MembershipUpdate) {
+	for x, broadcast := range membership.broadcasts { // Iterate through broadcasts
+		if broadcast.update.Node.NodeID == update.Node.NodeID { // Check for match
+			membership.broadcasts = append(membership.broadcasts[:x], membership.broadcasts[x+1:]...) // Remove broadcast
+
+			break // Stop searching
+		}
+	}
+
+	membership.broadcasts = append(membership.broadcasts, &membershipBroadcast{update: update}) // Queue update
+}
+
+// piggyback - fetch at most Config.MaxPiggybacked least-sent queued updates, dropping updates sent Config.RetransmitMultiplier * log10(members + 1) times
+func (membership *Membership) piggyback() []MembershipUpdate {
+	membership.mutex.Lock() // Lock membership
+
+	defer membership.mutex.Unlock() // Unlock membership
+
+	limit := membership.Config.RetransmitMultiplier * int(math.Ceil(math.Log10(float64(len(membership.members)+1)))) // Calculate retransmit limit
+
+	sort.SliceStable(membership.broadcasts, func(i, j int) bool { return membership.broadcasts[i].transmits < membership.broadcasts[j].transmits }) // Prefer least-sent updates
+
+	updates := []MembershipUpdate{} // Init updates buffer
+
+	remaining := []*membershipBroadcast{} // Init remaining broadcasts buffer
+
+	for _, broadcast := range membership.broadcasts { // Iterate through broadcasts
+		if len(updates) < membership.Config.MaxPiggybacked { // Check room for update
+			updates = append(updates, broadcast.update) // Append update
+
+			broadcast.transmits++ // Increment transmits
+		}
+
+		if broadcast.transmits < limit { // Check update should be sent again
+			remaining = append(remaining, broadcast) // Keep broadcast
+		}
+	}
+
+	membership.broadcasts = remaining // Set remaining broadcasts
+
+	return updates // Return updates
+}
+
+// fullState - fetch updates describing every member
+func (membership *Membership) fullState() []MembershipUpdate {
+	membership.mutex.Lock() // Lock membership
+
+	defer membership.mutex.Unlock() // Unlock membership
+
+	updates := []MembershipUpdate{} // Init updates buffer
+
+	for _, member := range membership.members { // Iterate through members
+		updates = append(updates, MembershipUpdate{Node: member.Node, State: member.State, Incarnation: member.Incarnation}) // Append update
+	}
+
+	return updates // Return updates
+}
+
+// nextTarget - fetch next member of the current probe round (starting a new, shuffled round once every member has been probed)
+func (membership *Membership) nextTarget() *node.Node {
+	if len(membership.probeOrder) == 0 { // Check round finished
+		for _, peer := range membership.randomMembers(len(membership.members)) { // Iterate through shuffled members
+			membership.probeOrder = append(membership.probeOrder, peer.NodeID) // Append member
+		}
+	}
+
+	for len(membership.probeOrder) > 0 { // Iterate through remaining members
+		member, found := membership.members[membership.probeOrder[0]] // Fetch member
+
+		membership.probeOrder = membership.probeOrder[1:] // Pop member
+
+		if found && member.isActive() { // Check member is still alive or suspect
+			target := member.Node // Copy contact
+
+			return &target // Return member
+		}
+	}
+
+	return nil // No members to probe
+}
+
+// randomMembers - fetch at most count random alive or suspect members, excluding local node and given nodes
+func (membership *Membership) randomMembers(count int, exclude ...*node.Node) []node.Node {
+	peers := []node.Node{} // Init peers buffer
+
+	for _, member := range membership.members { // Iterate through members
+		if member.isActive() && member.Node.NodeID != membership.Node.NodeID && !isExcluded(&member.Node, exclude) { // Check member is eligible
+			peers = append(peers, member.Node) // Append member
+		}
+	}
+
+	shufflePeers(peers) // Shuffle members
+
+	if len(peers) > count { // Check for too many members
+		peers = peers[:count] // Truncate members
+	}
+
+	return peers // Return members
+}
+
+// isActive - check member is alive or suspect
+func (member *Member) isActive() bool {
+	return member.State == MemberAlive || member.State == MemberSuspect // Check alive or suspect
+}
+
+// sendMembershipMessage - send membership message to given contact over pooled session of given local node, verifying the responder is the authenticated peer
+func sendMembershipMessage(localNode *node.Node, contact *node.Node, message *MembershipMessage, port uint) (*MembershipAck, error) {
+	pool, err := localNode.ConnectionPool() // Fetch pool of local node
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	address := contact.DialAddress(int(port)) // Init contact address
+
+	codec, result, err := pool.RequestEnvelope(address, contact.NodeID, common.EnvelopeKindMembership, message) // Send message
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	ack, err := DecodeMembershipAck(codec, result) // Decode ack
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	peerID, err := pool.PeerID(address, contact.NodeID) // Fetch authenticated peer ID
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if ack.Responder == nil || ack.Responder.NodeID != peerID { // Check responder is authenticated peer
+		return nil, ErrInvalidAck // Return error
+	}
+
+	return ack, nil // Return ack
+}
+
+// membershipKey - key of membership of given local node in given network
+func membershipKey(localNode *node.Node, network string) string {
+	return localNode.NodeID + "/" + network // Return key
+}
+
+// updatesToWire - convert membership updates to protobuf wire types
+func updatesToWire(updates []MembershipUpdate) []*wire.MembershipUpdate {
+	wireUpdates := []*wire.MembershipUpdate{} // Init wire updates buffer
+
+	for x := range updates { // Iterate through updates
+		wireUpdates = append(wireUpdates, &wire.MembershipUpdate{Node: updates[x].Node.ToWire(), State: string(updates[x].State), Incarnation: updates[x].Incarnation}) // Append update
+	}
+
+	return wireUpdates // Return wire updates
+}
+
+// updatesFromWire - convert protobuf wire types to membership updates
+func updatesFromWire(wireUpdates []*wire.MembershipUpdate) []MembershipUpdate {
+	updates := []MembershipUpdate{} // Init updates buffer
+
+	for _, wireUpdate := range wireUpdates { // Iterate through wire updates
+		if wireUpdate == nil || wireUpdate.Node == nil { // Check for invalid update
+			continue // Skip update
+		}
+
+		updates = append(updates, MembershipUpdate{Node: *node.NodeFromWire(wireUpdate.Node), State: MemberState(wireUpdate.State), Incarnation: wireUpdate.Incarnation}) // Append update
+	}
+
+	return updates // Return updates
+}
+
+/*
+	END INTERNAL METHODS
+*/
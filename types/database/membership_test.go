@@ -0,0 +1,111 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/dowlandaiello/GoP2P/common"
+)
+
+// TestMergeMembershipUpdates - test functionality of incarnation-ordered membership updates, refutations
+func TestMergeMembershipUpdates(t *testing.T) {
+	localNode := newTestContact(t, "memory://membership-local") // Init local node
+
+	peer := newTestContact(t, "memory://membership-peer") // Init peer
+
+	membership, err := NewMembership(localNode, "GoP2P_TestNet", 3000) // Init membership
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if events := membership.merge([]MembershipUpdate{{Node: *peer, State: MemberAlive}}); len(events) != 1 || events[0].Kind != MembershipJoin { // Learn peer
+		t.Errorf("expected join, found %v", events) // Log found error
+		t.FailNow()                                 // Panic
+	}
+
+	membership.merge([]MembershipUpdate{{Node: *peer, State: MemberSuspect}, {Node: *peer, State: MemberAlive}}) // Suspect peer, replay stale alive message
+
+	if member, _ := membership.Member(peer.NodeID); member.State != MemberSuspect { // Check suspicion overrides alive at same incarnation
+		t.Errorf("expected suspect member, found %s member", member.State) // Log found error
+		t.FailNow()                                                        // Panic
+	}
+
+	membership.merge([]MembershipUpdate{{Node: *peer, State: MemberAlive, Incarnation: 1}}) // Refute suspicion
+
+	if member, _ := membership.Member(peer.NodeID); member.State != MemberAlive || member.Incarnation != 1 { // Check newer incarnation overrides suspicion
+		t.Errorf("expected alive member, found %s member", member.State) // Log found error
+		t.FailNow()                                                      // Panic
+	}
+
+	if events := membership.merge([]MembershipUpdate{{Node: *peer, State: MemberDead, Incarnation: 1}}); len(events) != 1 || events[0].Kind != MembershipFail { // Declare peer dead
+		t.Errorf("expected failure, found %v", events) // Log found error
+		t.FailNow()                                    // Panic
+	}
+
+	if len(membership.Members()) != 1 { // Check dead peer isn't listed
+		t.Errorf("dead peer listed as member") // Log found error
+		t.FailNow()                            // Panic
+	}
+
+	membership.merge([]MembershipUpdate{{Node: *localNode, State: MemberSuspect, Incarnation: 3}}) // Suspect local node
+
+	if member, _ := membership.Member(localNode.NodeID); member.State != MemberAlive || member.Incarnation != 4 { // Check suspicion was refuted
+		t.Errorf("expected refutation, found incarnation %d", member.Incarnation) // Log found error
+		t.FailNow()                                                               // Panic
+	}
+
+	if updates := membership.piggyback(); len(updates) != 2 || updates[1].Node.NodeID != localNode.NodeID || updates[1].Incarnation != 4 { // Check refutation was queued
+		t.Errorf("invalid piggybacked updates %v", updates) // Log found error
+		t.FailNow()                                         // Panic
+	}
+}
+
+// TestEncodeMembershipMessage - test functionality of membership message, ack encoding
+func TestEncodeMembershipMessage(t *testing.T) {
+	sender := newTestContact(t, "memory://membership-sender") // Init sender
+
+	target := newTestContact(t, "memory://membership-target") // Init target
+
+	for _, codec := range []common.Codec{common.CodecJSON, common.CodecProtobuf} { // Iterate through codecs
+		message := &MembershipMessage{Network: "GoP2P_TestNet", Kind: MembershipPingReq, Sender: sender, Target: target, Nonce: "nonce", Updates: []MembershipUpdate{{Node: *target, State: MemberSuspect, Incarnation: 2}}} // Init message
+
+		encoded, err := message.Encode(codec) // Encode message
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		decoded, err := DecodeMembershipMessage(codec, encoded) // Decode message
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if decoded.Kind != message.Kind || decoded.Target.NodeID != target.NodeID || len(decoded.Updates) != 1 || decoded.Updates[0].State != MemberSuspect || decoded.Updates[0].Incarnation != 2 { // Check for mismatch
+			t.Errorf("invalid decoded %s message %v", codec, decoded) // Log found error
+			t.FailNow()                                               // Panic
+		}
+
+		encoded, err = (&MembershipAck{Responder: sender, Nonce: "nonce", Acked: true}).Encode(codec) // Encode ack
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		ack, err := DecodeMembershipAck(codec, encoded) // Decode ack
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if !ack.Acked || ack.Nonce != "nonce" || ack.Responder.NodeID != sender.NodeID { // Check for mismatch
+			t.Errorf("invalid decoded %s ack %v", codec, ack) // Log found error
+			t.FailNow()                                       // Panic
+		}
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"reflect"
 
 	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/internal/rpc/proto/wire"
+	"github.com/golang/protobuf/proto"
 )
 
 var (
@@ -59,3 +61,45 @@ func MessageFromBytes(b []byte) (*Message, error) {
 
 	return &object, nil // No error occurred, return read value
 }
+
+// Encode - encode message with given codec
+func (message *Message) Encode(codec common.Codec) ([]byte, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		return proto.Marshal(message.ToWire()) // Marshal message
+	case common.CodecJSON:
+		return message.ToBytes() // Serialize message
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// DecodeMessage - decode message encoded with given codec
+func DecodeMessage(codec common.Codec, b []byte) (*Message, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		wireMessage := &wire.Message{} // Init wire message buffer
+
+		err := proto.Unmarshal(b, wireMessage) // Unmarshal message
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return MessageFromWire(wireMessage), nil // Return message
+	case common.CodecJSON:
+		return MessageFromBytes(b) // Decode message
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// ToWire - convert message to protobuf wire type
+func (message *Message) ToWire() *wire.Message {
+	return &wire.Message{Message: message.Message, Priority: uint32(message.Priority), Type: message.Type, Network: message.Network} // Return wire message
+}
+
+// MessageFromWire - convert protobuf wire type to message
+func MessageFromWire(wireMessage *wire.Message) *Message {
+	return &Message{Message: wireMessage.Message, Priority: uint(wireMessage.Priority), Type: wireMessage.Type, Network: wireMessage.Network} // Return message
+}
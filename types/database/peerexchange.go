@@ -0,0 +1,586 @@
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/internal/rpc/proto/wire"
+	"github.com/dowlandaiello/GoP2P/types/node"
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	// DefaultPeerExchangeInterval - default duration between peer exchange rounds
+	DefaultPeerExchangeInterval = time.Minute
+
+	// DefaultPeerExchangeFanout - default number of peers contacted per peer exchange round
+	DefaultPeerExchangeFanout = 2
+
+	// DefaultBootstrapRounds - default number of peer exchange rounds run after exchanging peers with a bootstrap node
+	DefaultBootstrapRounds = 3
+)
+
+// PeerExchangeLimits - bounds on the peers a node offers and accepts during peer exchanges
+type PeerExchangeLimits struct {
+	SampleSize int // SampleSize - maximum number of peers offered per exchange
+
+	MaxAccepted int // MaxAccepted - maximum number of new peers accepted per exchange
+
+	MaxPeerAge time.Duration // MaxPeerAge - peers not seen within this duration aren't offered (every seen peer is offered if 0)
+
+	MinSenderInterval time.Duration // MinSenderInterval - minimum duration between exchanges whose peers are accepted from a single sender
+}
+
+var (
+	// DefaultPeerExchangeLimits - limits applied to peer exchanges
+	DefaultPeerExchangeLimits = PeerExchangeLimits{
+		SampleSize:        16,               // Set sample size
+		MaxAccepted:       8,                // Set accepted peers
+		MaxPeerAge:        30 * time.Minute, // Set peer age
+		MinSenderInterval: 10 * time.Second, // Set sender interval
+	}
+
+	// exchangeSenders - time peers of each sender (by NodeID) were last accepted
+	exchangeSenders = make(map[string]time.Time)
+
+	// exchangeSendersMutex - guards exchangeSenders
+	exchangeSendersMutex sync.Mutex
+)
+
+// PeerExchangeRequest - random sample of peers seen by sender, sent to a peer in exchange for a sample of its peers
+type PeerExchangeRequest struct {
+	Network string `json:"network"` // Network - alias of network whose peers are exchanged
+
+	Sender *node.Node `json:"sender"` // Sender - requesting node (peers of anonymous or unauthenticated senders are ignored)
+
+	Peers []node.Node `json:"peers"` // Peers - sample of peers seen by sender
+}
+
+// PeerExchangeResponse - random sample of peers seen by the queried node
+type PeerExchangeResponse struct {
+	Peers []node.Node `json:"peers"` // Peers - sample of peers seen by responder
+
+	Responder *node.Node `json:"responder"` // Responder - queried node
+}
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// SamplePeers - fetch random sample of at most count peers with identities seen within maxAge (every seen peer if 0) that aren't banned or suspect, excluding given nodes
+func (db *NodeDatabase) SamplePeers(count int, maxAge time.Duration, exclude ...*node.Node) []node.Node {
+	if db.Nodes == nil || count <= 0 { // Check for no nodes
+		return []node.Node{} // No peers
+	}
+
+	candidates := []node.Node{} // Init candidates buffer
+
+	for _, peer := range *db.Nodes { // Iterate through nodes
+		if peer.NodeID == "" || peer.LastPingTime.IsZero() || (maxAge > 0 && time.Since(peer.LastPingTime) > maxAge) || db.IsBanned(peer.NodeID) || db.IsSuspect(&peer) { // Check peer hasn't been seen, is banned or suspect
+			continue // Skip peer
+		}
+
+		if isExcluded(&peer, exclude) { // Check peer is excluded
+			continue // Skip peer
+		}
+
+		candidates = append(candidates, node.Node{NodeID: peer.NodeID, PublicKey: peer.PublicKey, Address: peer.Address, LastPingTime: peer.LastPingTime, IsBootstrap: peer.IsBootstrap, Reachability: peer.Reachability, Addresses: peer.Addresses}) // Append contact
+	}
+
+	shufflePeers(candidates) // Randomize sample
+
+	if len(candidates) > count { // Check for too many candidates
+		candidates = candidates[:count] // Truncate sample
+	}
+
+	return candidates // Return sample
+}
+
+// MergePeers - add at most max peers with valid identities that aren't yet known, returning number of added peers. Added peers aren't offered to other nodes until they've been seen by the local node (see SamplePeers). Additions are local to the database (they aren't recorded as replicated operations, peers are learned by exchanging them).
+func (db *NodeDatabase) MergePeers(localNode *node.Node, peers []node.Node, max int) int {
+	added := 0 // Init added counter
+
+	for _, peer := range peers { // Iterate through peers
+		if added >= max { // Check accepted enough peers
+			break // Stop merging
+		}
+
+		if peer.NodeID == "" || peer.Address == "" || peer.VerifyIdentity() != nil || isExcluded(&peer, []*node.Node{localNode}) || db.IsBanned(peer.NodeID) { // Check for invalid, local, banned peer
+			continue // Skip peer
+		}
+
+		if _, err := db.QueryForNodeID(peer.NodeID); err == nil { // Check peer already known
+			continue // Skip peer
+		}
+
+		if db.insertNode(&node.Node{NodeID: peer.NodeID, PublicKey: peer.PublicKey, Address: peer.Address, IsBootstrap: peer.IsBootstrap, Reachability: peer.Reachability, Addresses: peer.Addresses}) == nil { // Add unseen peer
+			added++ // Increment added
+		}
+	}
+
+	return added // Return added count
+}
+
+// HandlePeerExchange - respond to exchange with a sample of seen peers, accepting the sender and (at most limits.MaxAccepted of) its peers unless the sender is anonymous or exchanged peers within limits.MinSenderInterval
+func (db *NodeDatabase) HandlePeerExchange(localNode *node.Node, request *PeerExchangeRequest, limits PeerExchangeLimits) (*PeerExchangeResponse, error) {
+	response := &PeerExchangeResponse{Peers: db.SamplePeers(limits.SampleSize, limits.MaxPeerAge, localNode, request.Sender), Responder: contactOf(localNode)} // Init response
+
+	if request.Sender == nil || request.Sender.NodeID == "" { // Check for anonymous sender
+		return response, nil // Don't learn from anonymous sender
+	}
+
+	err := db.markSeen(request.Sender) // Add sender
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if acceptSender(request.Sender.NodeID, limits.MinSenderInterval) { // Check sender may offer peers
+		if added := db.MergePeers(localNode, request.Peers, limits.MaxAccepted); added > 0 { // Merge offered peers
+			common.Printf("\n-- PEX -- learned %d peers from %s", added, request.Sender.Address) // Log merge
+		}
+	}
+
+	return response, nil // Return response
+}
+
+// ExchangePeers - swap samples of seen peers with given contact (listening on given port), merging at most DefaultPeerExchangeLimits.MaxAccepted new peers. The contact is marked as seen if it responds. Returns number of added peers.
+func (db *NodeDatabase) ExchangePeers(localNode *node.Node, contact *node.Node, port uint) (int, error) {
+	response, err := db.requestExchange(localNode, contact, port) // Send request
+
+	if err == ErrPeerBanned { // Check contact is banned
+		return 0, err // Return found error
+	}
+
+	return db.applyExchange(localNode, contact, response, err) // Merge peers
+}
+
+// GossipPeers - exchange peers with at most fanout random known peers (listening on given port), returning number of added peers
+func (db *NodeDatabase) GossipPeers(localNode *node.Node, port uint, fanout int) (int, error) {
+	peers := db.gossipPeers(localNode, fanout) // Pick peers
+
+	if len(peers) == 0 { // Check for no peers
+		return 0, ErrNoContacts // Return error
+	}
+
+	added := 0 // Init added counter
+
+	for x := range peers { // Iterate through peers
+		newPeers, err := db.ExchangePeers(localNode, &peers[x], port) // Exchange peers
+
+		if err != nil { // Check for errors
+			common.Printf("\n-- PEX -- exchange with %s failed: %s", peers[x].Address, err.Error()) // Log failure
+
+			continue // Continue to next peer
+		}
+
+		added += newPeers // Add new peers
+	}
+
+	return added, nil // Return added count
+}
+
+// Bootstrap - exchange peers with node at given bootstrap address, then gossip with random learned peers for given number of rounds (contacts listen on given port)
+func (db *NodeDatabase) Bootstrap(localNode *node.Node, bootstrapAddress string, port uint, rounds int) error {
+	added, err := db.ExchangePeers(localNode, &node.Node{Address: bootstrapAddress}, port) // Exchange peers with bootstrap node
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	for x := 0; x < rounds; x++ { // Gossip
+		newPeers, err := db.GossipPeers(localNode, port, DefaultPeerExchangeFanout) // Exchange peers
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		added += newPeers // Add new peers
+	}
+
+	common.Printf("\n-- PEX -- learned %d peers from bootstrap node %s", added, bootstrapAddress) // Log bootstrap
+
+	return nil // No error occurred, return nil
+}
+
+// GossipRoutine - exchange peers with random known peers of the network database stored in given node environment every given duration until context is cancelled, merging each exchange into the stored database (db only identifies the network)
+func (db *NodeDatabase) GossipRoutine(ctx context.Context, localNode *node.Node, port uint, interval time.Duration) {
+	ticker := time.NewTicker(interval) // Init ticker
+
+	defer ticker.Stop() // Stop ticker
+
+	for {
+		select {
+		case <-ticker.C: // Check tick
+			snapshot, err := SnapshotFromMemory(localNode.Environment, db.NetworkAlias) // Read db
+
+			if err == ErrNoDatabase { // Check network not joined
+				continue // Wait for next tick
+			}
+
+			peers := []node.Node{} // Init peers buffer
+
+			if err == nil { // Check for errors
+				if peers = snapshot.gossipPeers(localNode, DefaultPeerExchangeFanout); len(peers) == 0 { // Pick peers
+					err = ErrNoContacts // Set error
+				}
+			}
+
+			for x := range peers { // Iterate through peers
+				response, exchangeErr := snapshot.requestExchange(localNode, &peers[x], port) // Exchange peers
+
+				if exchangeErr != ErrPeerBanned { // Check contact was contacted
+					writeErr := UpdateInMemory(localNode.Environment, db.NetworkAlias, func(current *NodeDatabase) error {
+						_, exchangeErr = current.applyExchange(localNode, &peers[x], response, exchangeErr) // Merge peers
+
+						return nil // Write db (penalizing contact if exchange failed)
+					}) // Write exchange to memory
+
+					if writeErr != nil { // Check for errors
+						exchangeErr = writeErr // Set error
+					}
+				}
+
+				if exchangeErr != nil { // Check for errors
+					common.Printf("\n-- PEX -- exchange with %s failed: %s", peers[x].Address, exchangeErr.Error()) // Log failure
+				}
+			}
+
+			if err != nil { // Check for errors
+				common.Printf("\n-- PEX -- peer exchange failed: %s", err.Error()) // Log failure
+			}
+		case <-ctx.Done(): // Check cancelled
+			return // Stop
+		}
+	}
+}
+
+// Encode - encode request with given codec
+func (request *PeerExchangeRequest) Encode(codec common.Codec) ([]byte, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		return proto.Marshal(request.ToWire()) // Marshal request
+	case common.CodecJSON:
+		return json.Marshal(request) // Serialize request
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// DecodePeerExchangeRequest - decode request encoded with given codec
+func DecodePeerExchangeRequest(codec common.Codec, b []byte) (*PeerExchangeRequest, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		wireRequest := &wire.PeerExchangeRequest{} // Init wire request buffer
+
+		err := proto.Unmarshal(b, wireRequest) // Unmarshal request
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return PeerExchangeRequestFromWire(wireRequest), nil // Return request
+	case common.CodecJSON:
+		request := &PeerExchangeRequest{} // Init request buffer
+
+		err := json.Unmarshal(b, request) // Decode request
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return request, nil // Return request
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// ToWire - convert request to protobuf wire type
+func (request *PeerExchangeRequest) ToWire() *wire.PeerExchangeRequest {
+	return &wire.PeerExchangeRequest{Network: request.Network, Sender: request.Sender.ToWire(), Peers: peersToWire(request.Peers)} // Return wire request
+}
+
+// PeerExchangeRequestFromWire - convert protobuf wire type to request
+func PeerExchangeRequestFromWire(wireRequest *wire.PeerExchangeRequest) *PeerExchangeRequest {
+	return &PeerExchangeRequest{Network: wireRequest.Network, Sender: node.NodeFromWire(wireRequest.Sender), Peers: peersFromWire(wireRequest.Peers)} // Return request
+}
+
+// Encode - encode response with given codec
+func (response *PeerExchangeResponse) Encode(codec common.Codec) ([]byte, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		return proto.Marshal(response.ToWire()) // Marshal response
+	case common.CodecJSON:
+		return json.Marshal(response) // Serialize response
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// DecodePeerExchangeResponse - decode response encoded with given codec
+func DecodePeerExchangeResponse(codec common.Codec, b []byte) (*PeerExchangeResponse, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		wireResponse := &wire.PeerExchangeResponse{} // Init wire response buffer
+
+		err := proto.Unmarshal(b, wireResponse) // Unmarshal response
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return PeerExchangeResponseFromWire(wireResponse), nil // Return response
+	case common.CodecJSON:
+		response := &PeerExchangeResponse{} // Init response buffer
+
+		err := json.Unmarshal(b, response) // Decode response
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return response, nil // Return response
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// ToWire - convert response to protobuf wire type
+func (response *PeerExchangeResponse) ToWire() *wire.PeerExchangeResponse {
+	return &wire.PeerExchangeResponse{Peers: peersToWire(response.Peers), Responder: response.Responder.ToWire()} // Return wire response
+}
+
+// PeerExchangeResponseFromWire - convert protobuf wire type to response
+func PeerExchangeResponseFromWire(wireResponse *wire.PeerExchangeResponse) *PeerExchangeResponse {
+	return &PeerExchangeResponse{Peers: peersFromWire(wireResponse.Peers), Responder: node.NodeFromWire(wireResponse.Responder)} // Return response
+}
+
+/*
+	END EXPORTED METHODS
+*/
+
+/*
+	BEGIN INTERNAL METHODS:
+*/
+
+// gossipPeers - pick at most fanout random known peers with identities that aren't banned to exchange peers with
+func (db *NodeDatabase) gossipPeers(localNode *node.Node, fanout int) []node.Node {
+	peers := []node.Node{} // Init peers buffer
+
+	if db.Nodes == nil { // Check for no peers
+		return peers // No peers
+	}
+
+	for _, peer := range *db.Nodes { // Iterate through nodes
+		if peer.NodeID != "" && !isExcluded(&peer, []*node.Node{localNode}) && !db.IsBanned(peer.NodeID) { // Check peer has identity, isn't banned
+			peers = append(peers, peer) // Append peer
+		}
+	}
+
+	shufflePeers(peers) // Pick random peers
+
+	if len(peers) > fanout { // Check for too many peers
+		peers = peers[:fanout] // Truncate peers
+	}
+
+	return peers // Return peers
+}
+
+// requestExchange - send sample of seen peers to given contact (listening on given port), returning its response. Returns ErrPeerBanned without contacting banned contacts.
+func (db *NodeDatabase) requestExchange(localNode *node.Node, contact *node.Node, port uint) (*PeerExchangeResponse, error) {
+	if contact.NodeID != "" && db.IsBanned(contact.NodeID) { // Check contact is banned
+		return nil, ErrPeerBanned // Return error
+	}
+
+	limits := DefaultPeerExchangeLimits // Fetch limits
+
+	request := &PeerExchangeRequest{Network: db.NetworkAlias, Sender: contactOf(localNode), Peers: db.SamplePeers(limits.SampleSize, limits.MaxPeerAge, localNode, contact)} // Init request
+
+	return sendPeerExchange(localNode, contact, request, port) // Send request
+}
+
+// applyExchange - merge at most DefaultPeerExchangeLimits.MaxAccepted new peers of given exchange response (marking contact as seen), or penalize contact if exchange failed with given error. Returns number of added peers.
+func (db *NodeDatabase) applyExchange(localNode *node.Node, contact *node.Node, response *PeerExchangeResponse, exchangeErr error) (int, error) {
+	limits := DefaultPeerExchangeLimits // Fetch limits
+
+	if exchangeErr != nil { // Check for errors
+		if contact.NodeID != "" { // Check contact has identity
+			db.RecordEvent(contact.NodeID, ReputationTimeout) // Penalize unresponsive contact
+		}
+
+		return 0, exchangeErr // Return found error
+	}
+
+	if contact.NodeID == "" && response.Responder != nil { // Check for contact without known identity (e.g. bootstrap address)
+		added := db.MergePeers(localNode, []node.Node{*response.Responder}, 1) // Learn responder (seen once exchanged with by identity)
+
+		return added + db.MergePeers(localNode, response.Peers, limits.MaxAccepted), nil // Merge peers
+	}
+
+	err := db.markSeen(contact) // Mark contact seen
+
+	if err != nil { // Check for errors
+		return 0, err // Return found error
+	}
+
+	db.RecordEvent(contact.NodeID, ReputationResponse) // Reward contact
+
+	return db.MergePeers(localNode, response.Peers, limits.MaxAccepted), nil // Merge peers
+}
+
+// insertNode - add node to database (or update node with same NodeID) without checking its address or pushing the database to remote nodes
+func (db *NodeDatabase) insertNode(destNode *node.Node) error {
+	if db.RoutingTable != nil { // Check for DHT mode
+		_, err := db.RoutingTable.AddNode(destNode) // Add contact (kept as replacement if bucket is full)
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		db.syncRoutingTable() // Keep only routing table contacts
+
+		return nil // No error occurred, return nil
+	}
+
+	if db.Nodes == nil { // Check if node array is nil
+		db.Nodes = &[]node.Node{*destNode} // Initialize array with destNode
+	} else if nodeIndex, err := db.QueryForNodeID(destNode.NodeID); err == nil { // Check node already in database
+		(*db.Nodes)[nodeIndex] = *destNode // Update node
+	} else { // Node not in database
+		*db.Nodes = append(*db.Nodes, *destNode) // Append node
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// markSeen - set last ping time of given peer (adding it if it isn't known) to the current time. Local to the database: the addition isn't recorded as a replicated operation (see ApplyMembershipEvent, MergePeers).
+func (db *NodeDatabase) markSeen(peer *node.Node) error {
+	if peer.NodeID == "" { // Check for anonymous peer
+		return errors.New("peer has no identity") // Return error
+	}
+
+	if db.IsBanned(peer.NodeID) { // Check peer is banned
+		return ErrPeerBanned // Return error
+	}
+
+	seen := node.Node{NodeID: peer.NodeID, PublicKey: peer.PublicKey, Address: peer.Address, IsBootstrap: peer.IsBootstrap, Reachability: peer.Reachability, Addresses: peer.Addresses, LastPingTime: time.Now(), Reputation: DefaultReputationPolicy.NeutralReputation} // Init seen peer
+
+	if nodeIndex, err := db.QueryForNodeID(peer.NodeID); err == nil && !(*db.Nodes)[nodeIndex].LastPingTime.IsZero() { // Check peer already seen
+		seen.Reputation = (*db.Nodes)[nodeIndex].Reputation // Keep reputation
+	}
+
+	return db.insertNode(&seen) // Add peer
+}
+
+// sendPeerExchange - send exchange request to given contact over pooled session of given local node, verifying contact identity (if known)
+func sendPeerExchange(localNode *node.Node, contact *node.Node, request *PeerExchangeRequest, port uint) (*PeerExchangeResponse, error) {
+	pool, err := localNode.ConnectionPool() // Fetch pool of local node
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	address := contact.DialAddress(int(port)) // Init contact address
+
+	codec, result, err := pool.RequestEnvelope(address, contact.NodeID, common.EnvelopeKindPeerExchange, request) // Send request
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return DecodePeerExchangeResponse(codec, result) // Decode response
+}
+
+// acceptSender - check peers may be accepted from sender with given NodeID, recording exchange
+func acceptSender(nodeID string, interval time.Duration) bool {
+	exchangeSendersMutex.Lock() // Lock senders
+
+	defer exchangeSendersMutex.Unlock() // Unlock senders
+
+	now := time.Now() // Fetch current time
+
+	for sender, last := range exchangeSenders { // Iterate through senders
+		if now.Sub(last) >= interval { // Check sender may exchange again
+			delete(exchangeSenders, sender) // Forget sender
+		}
+	}
+
+	if _, found := exchangeSenders[nodeID]; found { // Check sender recently exchanged peers
+		return false // Too frequent
+	}
+
+	exchangeSenders[nodeID] = now // Record exchange
+
+	return true // Accept
+}
+
+// contactOf - strip given node to its contact information
+func contactOf(localNode *node.Node) *node.Node {
+	if localNode == nil { // Check for nil node
+		return nil // Return nil
+	}
+
+	return &node.Node{NodeID: localNode.NodeID, PublicKey: localNode.PublicKey, Address: localNode.Address, IsBootstrap: localNode.IsBootstrap, Reachability: localNode.Reachability, Addresses: localNode.Addresses} // Return contact
+}
+
+// isExcluded - check given peer shares a NodeID (or, for anonymous nodes, an address) with one of given nodes
+func isExcluded(peer *node.Node, exclude []*node.Node) bool {
+	for _, excluded := range exclude { // Iterate through excluded nodes
+		if excluded == nil { // Check for nil node
+			continue // Skip node
+		}
+
+		if (excluded.NodeID != "" && excluded.NodeID == peer.NodeID) || (excluded.NodeID == "" && excluded.Address == peer.Address) { // Check for match
+			return true // Excluded
+		}
+	}
+
+	return false // Not excluded
+}
+
+// shufflePeers - randomly permute given peers in place
+func shufflePeers(peers []node.Node) {
+	for x := len(peers) - 1; x > 0; x-- { // Iterate through peers
+		y, err := rand.Int(rand.Reader, big.NewInt(int64(x+1))) // Pick random index
+
+		if err != nil { // Check for errors
+			return // Keep remaining order
+		}
+
+		peers[x], peers[y.Int64()] = peers[y.Int64()], peers[x] // Swap peers
+	}
+}
+
+// peersToWire - convert peers to protobuf wire types
+func peersToWire(peers []node.Node) []*wire.Node {
+	wirePeers := []*wire.Node{} // Init wire peers buffer
+
+	for x := range peers { // Iterate through peers
+		wirePeers = append(wirePeers, peers[x].ToWire()) // Append peer
+	}
+
+	return wirePeers // Return wire peers
+}
+
+// peersFromWire - convert protobuf wire types to peers
+func peersFromWire(wirePeers []*wire.Node) []node.Node {
+	peers := []node.Node{} // Init peers buffer
+
+	for _, wirePeer := range wirePeers { // Iterate through wire peers
+		if wirePeer != nil { // Check for non-nil peer
+			peers = append(peers, *node.NodeFromWire(wirePeer)) // Append peer
+		}
+	}
+
+	return peers // Return peers
+}
+
+/*
+	END INTERNAL METHODS
+*/
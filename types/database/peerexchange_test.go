@@ -0,0 +1,143 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+// TestEncodePeerExchangeRequest - test functionality of PeerExchangeRequest, PeerExchangeResponse Encode(), Decode() methods
+func TestEncodePeerExchangeRequest(t *testing.T) {
+	sender := newTestContact(t, "memory://pex-sender") // Init sender
+
+	peer := newTestContact(t, "memory://pex-peer") // Init peer
+
+	for _, codec := range []common.Codec{common.CodecJSON, common.CodecProtobuf} { // Iterate through codecs
+		request := &PeerExchangeRequest{Network: "GoP2P_TestNet", Sender: sender, Peers: []node.Node{*peer}} // Init request
+
+		encoded, err := request.Encode(codec) // Encode request
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		decoded, err := DecodePeerExchangeRequest(codec, encoded) // Decode request
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if decoded.Network != request.Network || decoded.Sender.NodeID != sender.NodeID || len(decoded.Peers) != 1 || decoded.Peers[0].VerifyIdentity() != nil { // Check for mismatch
+			t.Errorf("invalid decoded %s request %v", codec, decoded) // Log found error
+			t.FailNow()                                               // Panic
+		}
+
+		response := &PeerExchangeResponse{Peers: []node.Node{*peer}, Responder: sender} // Init response
+
+		encoded, err = response.Encode(codec) // Encode response
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		decodedResponse, err := DecodePeerExchangeResponse(codec, encoded) // Decode response
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if len(decodedResponse.Peers) != 1 || decodedResponse.Peers[0].NodeID != peer.NodeID || decodedResponse.Responder.NodeID != sender.NodeID { // Check for mismatch
+			t.Errorf("invalid decoded %s response %v", codec, decodedResponse) // Log found error
+			t.FailNow()                                                        // Panic
+		}
+	}
+}
+
+// TestHandlePeerExchange - test functionality of SamplePeers(), MergePeers(), HandlePeerExchange() methods
+func TestHandlePeerExchange(t *testing.T) {
+	localNode := newTestContact(t, "memory://pex-local") // Init local node
+
+	seen := newTestContact(t, "memory://pex-seen") // Init seen peer
+
+	seen.LastPingTime = time.Now() // Set seen
+
+	stale := newTestContact(t, "memory://pex-stale") // Init stale peer
+
+	stale.LastPingTime = time.Now().Add(-time.Hour) // Set seen long ago
+
+	db := &NodeDatabase{NetworkAlias: "GoP2P_TestNet", Nodes: &[]node.Node{*seen, *stale, *newTestContact(t, "memory://pex-unseen")}} // Init database
+
+	if sample := db.SamplePeers(10, time.Minute); len(sample) != 1 || sample[0].NodeID != seen.NodeID { // Check only recently seen peers are sampled
+		t.Errorf("invalid sample %v", sample) // Log found error
+		t.FailNow()                           // Panic
+	}
+
+	offered := []node.Node{*localNode, *seen, {Address: "memory://pex-anonymous"}} // Init offered peers
+
+	for x := 0; x < 4; x++ { // Generate new peers
+		offered = append(offered, *newTestContact(t, "memory://pex-offered")) // Append peer
+	}
+
+	limits := PeerExchangeLimits{SampleSize: 10, MaxAccepted: 2, MaxPeerAge: time.Minute, MinSenderInterval: time.Minute} // Init limits
+
+	response, err := db.HandlePeerExchange(localNode, &PeerExchangeRequest{Network: db.NetworkAlias, Peers: offered}, limits) // Exchange with anonymous sender
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if len(*db.Nodes) != 3 || len(response.Peers) != 1 || response.Responder.NodeID != localNode.NodeID { // Check peers of anonymous sender were ignored
+		t.Errorf("expected peers of anonymous sender to be ignored") // Log found error
+		t.FailNow()                                                  // Panic
+	}
+
+	sender := newTestContact(t, "memory://pex-sender") // Init sender
+
+	response, err = db.HandlePeerExchange(localNode, &PeerExchangeRequest{Network: db.NetworkAlias, Sender: sender, Peers: offered}, limits) // Exchange with sender
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if len(*db.Nodes) != 6 { // Check sender, at most MaxAccepted new peers were added
+		t.Errorf("invalid number of nodes %d", len(*db.Nodes)) // Log found error
+		t.FailNow()                                            // Panic
+	}
+
+	if _, err = db.QueryForNodeID(localNode.NodeID); err == nil { // Check local node wasn't added
+		t.Errorf("expected local node not to be added") // Log found error
+		t.FailNow()                                     // Panic
+	}
+
+	for _, peer := range response.Peers { // Iterate through sample
+		if peer.NodeID == sender.NodeID { // Check for sender
+			t.Errorf("expected sender not to be sampled") // Log found error
+			t.FailNow()                                   // Panic
+		}
+	}
+
+	_, err = db.HandlePeerExchange(localNode, &PeerExchangeRequest{Network: db.NetworkAlias, Sender: sender, Peers: offered}, limits) // Exchange with sender again
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if len(*db.Nodes) != 6 { // Check peers of too frequent exchange were ignored
+		t.Errorf("expected peers of too frequent exchange to be ignored") // Log found error
+		t.FailNow()                                                       // Panic
+	}
+
+	if sample := db.SamplePeers(10, time.Minute); len(sample) != 2 { // Check sender was marked seen, merged peers weren't
+		t.Errorf("invalid sample %v", sample) // Log found error
+		t.FailNow()                           // Panic
+	}
+}
@@ -0,0 +1,997 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/internal/rpc/proto/wire"
+	"github.com/dowlandaiello/GoP2P/types/node"
+	"github.com/dowlandaiello/GoP2P/types/shard"
+	"github.com/golang/protobuf/proto"
+)
+
+// OperationKind - kind of database change replicated by an operation
+type OperationKind string
+
+const (
+	// OperationAddNode - operation adds (or updates) a node
+	OperationAddNode = OperationKind("add node")
+
+	// OperationRemoveNode - operation removes node with NodeID (or, for anonymous nodes, address)
+	OperationRemoveNode = OperationKind("remove node")
+
+	// OperationAddShard - operation adds (or replaces) a shard
+	OperationAddShard = OperationKind("add shard")
+
+	// OperationRemoveShard - operation removes shard with address
+	OperationRemoveShard = OperationKind("remove shard")
+
+	// DefaultAntiEntropyInterval - default duration between anti-entropy rounds
+	DefaultAntiEntropyInterval = time.Minute
+
+	// DefaultAntiEntropyFanout - default number of peers synchronized with per anti-entropy round
+	DefaultAntiEntropyFanout = 2
+
+	// DefaultOperationLogSize - number of most recent operations kept for peers catching up (peers missing older operations are sent a snapshot)
+	DefaultOperationLogSize = 1024
+)
+
+var (
+	// ErrNotReplicated - error returned when synchronizing a database running in DHT mode (routing tables are local to each node)
+	ErrNotReplicated = errors.New("databases running in DHT mode aren't replicated")
+
+	// ErrNoLocalNode - error returned when recording an operation on a database without a local node (see SetLocalNode)
+	ErrNoLocalNode = errors.New("database has no local node to originate operations")
+
+	// ErrInvalidOperationSignature - error returned when verifying an operation that wasn't signed by its origin
+	ErrInvalidOperationSignature = errors.New("operation isn't signed by its origin")
+
+	// DefaultReplicationPort - port operations are sent to on peers that don't advertise self-describing addresses
+	DefaultReplicationPort = uint(3000)
+)
+
+// Operation - single change to a database, identified by the node it originated from and its sequence number among that node's operations
+type Operation struct {
+	Origin string `json:"origin"` // Origin - NodeID of node operation originated from
+
+	Sequence uint64 `json:"sequence"` // Sequence - sequence number of operation among operations of origin (starting at 1)
+
+	Kind OperationKind `json:"kind"` // Kind - kind of change
+
+	Node *node.Node `json:"node,omitempty"` // Node - added node (OperationAddNode)
+
+	Shard *shard.Shard `json:"shard,omitempty"` // Shard - added shard (OperationAddShard)
+
+	Address string `json:"address,omitempty"` // Address - NodeID (or address) of removed node, address of removed shard
+
+	Time time.Time `json:"time"` // Time - time operation was recorded
+
+	PublicKey []byte `json:"publicKey,omitempty"` // PublicKey - Ed25519 public key of origin (Origin is derived from this key)
+
+	Signature []byte `json:"signature,omitempty"` // Signature - signature of operation by origin (see Sign)
+}
+
+// ReplicationRequest - operations pushed by sender, along with the latest operations it has applied from each origin
+type ReplicationRequest struct {
+	Network string `json:"network"` // Network - alias of network whose database is replicated
+
+	Operations []Operation `json:"operations,omitempty"` // Operations - operations pushed by sender
+
+	Versions map[string]uint64 `json:"versions,omitempty"` // Versions - sequence number of latest operation applied by sender per origin
+
+	CatchUp bool `json:"catchUp,omitempty"` // CatchUp - sender requests operations it is missing
+}
+
+// ReplicationResponse - latest operations applied by the queried node from each origin, along with operations the sender is missing (if requested)
+type ReplicationResponse struct {
+	Operations []Operation `json:"operations,omitempty"` // Operations - operations missed by sender
+
+	Versions map[string]uint64 `json:"versions,omitempty"` // Versions - sequence number of latest operation applied by responder per origin
+
+	Snapshot bool `json:"snapshot,omitempty"` // Snapshot - operations missed by sender were truncated from the operation log, Changes are sent instead
+
+	Changes []Operation `json:"changes,omitempty"` // Changes - latest operation changing each node, shard of responder, removals included (snapshot only, see NodeDatabase.Changes)
+}
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// ApplyOperations - apply given operations in sequence order of each origin, skipping operations that have already been applied or weren't signed by their origin. Operations of different origins changing the same node or shard are decided by last writer wins (latest time, then greatest origin), so databases applying them in any order converge. Returns number of applied operations, and whether operations were held back since preceding operations of their origin are missing (see Synchronize).
+func (db *NodeDatabase) ApplyOperations(operations []Operation) (int, bool) {
+	pending := append([]Operation{}, operations...) // Copy operations
+
+	sort.SliceStable(pending, func(i, j int) bool {
+		if pending[i].Origin != pending[j].Origin { // Check for different origins
+			return pending[i].Origin < pending[j].Origin // Group by origin
+		}
+
+		return pending[i].Sequence < pending[j].Sequence // Sort by sequence
+	}) // Sort operations
+
+	applied, missing := 0, false // Init counters
+
+	for x := range pending { // Iterate through operations
+		operation := &pending[x] // Fetch operation
+
+		if operation.Origin == "" || operation.Sequence == 0 { // Check for invalid operation
+			continue // Skip operation
+		}
+
+		latest := db.Versions[operation.Origin] // Fetch latest applied operation of origin
+
+		if operation.Sequence <= latest { // Check already applied
+			continue // Skip operation
+		}
+
+		if operation.Sequence > latest+1 { // Check for missing operations
+			missing = true // Operations missing
+
+			continue // Hold back operation
+		}
+
+		if err := operation.Verify(); err != nil { // Verify operation was signed by origin
+			common.Printf("\n-- REPLICATION -- rejected operation %d of %s: %s", operation.Sequence, operation.Origin, err.Error()) // Log rejected operation (not logged, so later operations of its origin are held back)
+
+			continue // Skip operation
+		}
+
+		if err := db.apply(operation); err != nil { // Apply operation
+			common.Printf("\n-- REPLICATION -- skipped operation %d of %s: %s", operation.Sequence, operation.Origin, err.Error()) // Log invalid operation (still logged, so later operations of its origin aren't held back)
+		}
+
+		db.logOperation(*operation) // Log operation
+
+		applied++ // Increment applied
+	}
+
+	return applied, missing // Return counters
+}
+
+// OperationsSince - fetch logged operations a database that has applied operations up to given versions (see NodeDatabase.Versions) is missing. Returns false if some of them have been truncated from the operation log (a snapshot is needed instead).
+func (db *NodeDatabase) OperationsSince(versions map[string]uint64) ([]Operation, bool) {
+	operations := []Operation{} // Init operations buffer
+
+	found := make(map[string]uint64) // Init found counters
+
+	for _, operation := range db.Operations { // Iterate through log
+		if operation.Sequence > versions[operation.Origin] { // Check operation missing
+			operations = append(operations, operation) // Append operation
+
+			found[operation.Origin]++ // Increment found
+		}
+	}
+
+	for origin, latest := range db.Versions { // Iterate through origins
+		if latest > versions[origin] && found[origin] != latest-versions[origin] { // Check operations truncated from log
+			return operations, false // Incomplete
+		}
+	}
+
+	return operations, true // Return operations
+}
+
+// HandleReplication - apply operations pushed by sender (ignored in DHT mode), responding with the latest applied operations of each origin and, if requested, the operations sender is missing
+func (db *NodeDatabase) HandleReplication(request *ReplicationRequest) *ReplicationResponse {
+	if db.RoutingTable == nil && len(request.Operations) > 0 { // Check for pushed operations
+		applied, missing := db.ApplyOperations(request.Operations) // Apply operations
+
+		common.Printf("\n-- REPLICATION -- applied %d of %d operations (missing preceding operations: %t)", applied, len(request.Operations), missing) // Log replication
+	}
+
+	response := &ReplicationResponse{Versions: db.versionsCopy()} // Init response
+
+	if !request.CatchUp { // Check sender isn't catching up
+		return response // Return response
+	}
+
+	operations, complete := db.OperationsSince(request.Versions) // Fetch missed operations
+
+	if complete { // Check log contains every missed operation
+		response.Operations = operations // Set operations
+
+		return response // Return response
+	}
+
+	response.Snapshot = true // Set snapshot
+
+	for _, change := range db.Changes { // Iterate through changes
+		response.Changes = append(response.Changes, change) // Append change
+	}
+
+	return response // Return response
+}
+
+// Synchronize - exchange latest applied operations with given contact (listening on given port), applying operations the local database is missing, then sending the contact operations it is missing (anti-entropy catch-up). Returns number of applied operations.
+func (db *NodeDatabase) Synchronize(contact *node.Node, port uint) (int, error) {
+	response, err := db.requestSync(contact, port) // Request missed operations
+
+	if err != nil { // Check for errors
+		return 0, err // Return found error
+	}
+
+	applied, followUp := db.applySync(response) // Apply missed operations
+
+	if followUp != nil { // Check contact missed operations
+		_, err = sendReplication(db.localNode, contact, followUp, port) // Send missed operations
+	}
+
+	return applied, err // Return applied count
+}
+
+// AntiEntropy - synchronize with at most fanout random known peers (listening on given port), returning number of applied operations
+func (db *NodeDatabase) AntiEntropy(port uint, fanout int) (int, error) {
+	peers, err := db.antiEntropyPeers(fanout) // Pick peers
+
+	if err != nil { // Check for errors
+		return 0, err // Return found error
+	}
+
+	applied := 0 // Init applied counter
+
+	for x := range peers { // Iterate through peers
+		newOperations, err := db.Synchronize(&peers[x], port) // Synchronize with peer
+
+		if err != nil { // Check for errors
+			common.Printf("\n-- REPLICATION -- synchronization with %s failed: %s", peers[x].Address, err.Error()) // Log failure
+
+			continue // Continue to next peer
+		}
+
+		applied += newOperations // Add applied operations
+	}
+
+	return applied, nil // Return applied count
+}
+
+// AntiEntropyInMemory - synchronize the network database stored in given node environment with at most fanout random known peers (listening on given port), applying each exchange to the stored database (db only identifies the network). Returns ErrNotReplicated for databases in DHT mode.
+func (db *NodeDatabase) AntiEntropyInMemory(localNode *node.Node, port uint, fanout int) error {
+	snapshot, err := SnapshotFromMemory(localNode.Environment, db.NetworkAlias) // Read db
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	snapshot.SetLocalNode(localNode) // Synchronize on behalf of local node
+
+	peers, err := snapshot.antiEntropyPeers(fanout) // Pick peers
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	for x := range peers { // Iterate through peers
+		if syncErr := snapshot.synchronizeInMemory(localNode, &peers[x], port); syncErr != nil { // Synchronize with peer
+			common.Printf("\n-- REPLICATION -- synchronization with %s failed: %s", peers[x].Address, syncErr.Error()) // Log failure
+		}
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// AntiEntropyRoutine - synchronize the network database stored in given node environment with random known peers every given duration until context is cancelled, applying each exchange to the stored database (db only identifies the network; databases in DHT mode are skipped)
+func (db *NodeDatabase) AntiEntropyRoutine(ctx context.Context, localNode *node.Node, port uint, interval time.Duration) {
+	ticker := time.NewTicker(interval) // Init ticker
+
+	defer ticker.Stop() // Stop ticker
+
+	for {
+		select {
+		case <-ticker.C: // Check tick
+			err := db.AntiEntropyInMemory(localNode, port, DefaultAntiEntropyFanout) // Synchronize with peers
+
+			if err != nil && err != ErrNoDatabase && err != ErrNotReplicated { // Check for errors (skipping networks not joined, DHT mode)
+				common.Printf("\n-- REPLICATION -- anti-entropy failed: %s", err.Error()) // Log failure
+			}
+		case <-ctx.Done(): // Check cancelled
+			return // Stop
+		}
+	}
+}
+
+// Encode - encode request with given codec
+func (request *ReplicationRequest) Encode(codec common.Codec) ([]byte, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		wireRequest, err := request.ToWire() // Convert request
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return proto.Marshal(wireRequest) // Marshal request
+	case common.CodecJSON:
+		return json.Marshal(request) // Serialize request
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// DecodeReplicationRequest - decode request encoded with given codec
+func DecodeReplicationRequest(codec common.Codec, b []byte) (*ReplicationRequest, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		wireRequest := &wire.ReplicationRequest{} // Init wire request buffer
+
+		err := proto.Unmarshal(b, wireRequest) // Unmarshal request
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return ReplicationRequestFromWire(wireRequest) // Return request
+	case common.CodecJSON:
+		request := &ReplicationRequest{} // Init request buffer
+
+		err := json.Unmarshal(b, request) // Decode request
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return request, nil // Return request
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// ToWire - convert request to protobuf wire type
+func (request *ReplicationRequest) ToWire() (*wire.ReplicationRequest, error) {
+	operations, err := operationsToWire(request.Operations) // Convert operations
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return &wire.ReplicationRequest{Network: request.Network, Operations: operations, Versions: versionsToWire(request.Versions), CatchUp: request.CatchUp}, nil // Return wire request
+}
+
+// ReplicationRequestFromWire - convert protobuf wire type to request
+func ReplicationRequestFromWire(wireRequest *wire.ReplicationRequest) (*ReplicationRequest, error) {
+	operations, err := operationsFromWire(wireRequest.Operations) // Convert operations
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return &ReplicationRequest{Network: wireRequest.Network, Operations: operations, Versions: versionsFromWire(wireRequest.Versions), CatchUp: wireRequest.CatchUp}, nil // Return request
+}
+
+// Encode - encode response with given codec
+func (response *ReplicationResponse) Encode(codec common.Codec) ([]byte, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		wireResponse, err := response.ToWire() // Convert response
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return proto.Marshal(wireResponse) // Marshal response
+	case common.CodecJSON:
+		return json.Marshal(response) // Serialize response
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// DecodeReplicationResponse - decode response encoded with given codec
+func DecodeReplicationResponse(codec common.Codec, b []byte) (*ReplicationResponse, error) {
+	switch codec {
+	case common.CodecProtobuf:
+		wireResponse := &wire.ReplicationResponse{} // Init wire response buffer
+
+		err := proto.Unmarshal(b, wireResponse) // Unmarshal response
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return ReplicationResponseFromWire(wireResponse) // Return response
+	case common.CodecJSON:
+		response := &ReplicationResponse{} // Init response buffer
+
+		err := json.Unmarshal(b, response) // Decode response
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return response, nil // Return response
+	default:
+		return nil, common.ErrUnsupportedCodec // Return error
+	}
+}
+
+// ToWire - convert response to protobuf wire type
+func (response *ReplicationResponse) ToWire() (*wire.ReplicationResponse, error) {
+	operations, err := operationsToWire(response.Operations) // Convert operations
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	changes, err := operationsToWire(response.Changes) // Convert changes
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return &wire.ReplicationResponse{Operations: operations, Versions: versionsToWire(response.Versions), Snapshot: response.Snapshot, Changes: changes}, nil // Return wire response
+}
+
+// ReplicationResponseFromWire - convert protobuf wire type to response
+func ReplicationResponseFromWire(wireResponse *wire.ReplicationResponse) (*ReplicationResponse, error) {
+	operations, err := operationsFromWire(wireResponse.Operations) // Convert operations
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	changes, err := operationsFromWire(wireResponse.Changes) // Convert changes
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return &ReplicationResponse{Operations: operations, Versions: versionsFromWire(wireResponse.Versions), Snapshot: wireResponse.Snapshot, Changes: changes}, nil // Return response
+}
+
+// Sign - sign operation with given identity of its origin
+func (operation *Operation) Sign(identity *node.Identity) error {
+	operation.PublicKey, operation.Signature = identity.PublicKey, nil // Set public key, reset signature
+
+	message, err := operation.signedBytes() // Fetch signed contents
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	operation.Signature = identity.Sign(message) // Sign operation
+
+	return nil // No error occurred, return nil
+}
+
+// Verify - verify operation was signed by its origin
+func (operation *Operation) Verify() error {
+	if operation.Origin != node.NodeIDFromPublicKey(operation.PublicKey) { // Check public key belongs to origin
+		return ErrInvalidOperationSignature // Return error
+	}
+
+	message, err := operation.signedBytes() // Fetch signed contents
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	if !node.VerifySignature(operation.PublicKey, message, operation.Signature) { // Verify signature
+		return ErrInvalidOperationSignature // Return error
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// ToWire - convert operation to protobuf wire type
+func (operation *Operation) ToWire() (*wire.Operation, error) {
+	wireOperation := &wire.Operation{Origin: operation.Origin, Sequence: operation.Sequence, Kind: string(operation.Kind), Node: operation.Node.ToWire(), Address: operation.Address, Time: operation.Time.UnixNano(), PublicKey: operation.PublicKey, Signature: operation.Signature} // Init wire operation
+
+	if operation.Shard != nil { // Check for shard
+		encodedShard, err := json.Marshal(operation.Shard) // Encode shard
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		wireOperation.Shard = encodedShard // Set shard
+	}
+
+	return wireOperation, nil // Return wire operation
+}
+
+// OperationFromWire - convert protobuf wire type to operation
+func OperationFromWire(wireOperation *wire.Operation) (*Operation, error) {
+	operation := &Operation{Origin: wireOperation.Origin, Sequence: wireOperation.Sequence, Kind: OperationKind(wireOperation.Kind), Node: node.NodeFromWire(wireOperation.Node), Address: wireOperation.Address, Time: time.Unix(0, wireOperation.Time).UTC(), PublicKey: wireOperation.PublicKey, Signature: wireOperation.Signature} // Init operation
+
+	if len(wireOperation.Shard) != 0 { // Check for shard
+		operation.Shard = &shard.Shard{} // Init shard buffer
+
+		err := json.Unmarshal(wireOperation.Shard, operation.Shard) // Decode shard
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+	}
+
+	return operation, nil // Return operation
+}
+
+/*
+	END EXPORTED METHODS
+*/
+
+/*
+	BEGIN INTERNAL METHODS:
+*/
+
+// record - sign given operation (already applied to database) with the local node identity, logging it as the next operation originating from the local node
+func (db *NodeDatabase) record(operation Operation) (Operation, error) {
+	if db.localNode == nil || db.localNode.Identity() == nil { // Check for no local node
+		return Operation{}, ErrNoLocalNode // Return error
+	}
+
+	identity := db.localNode.Identity() // Fetch identity
+
+	origin := identity.NodeID() // Fetch origin
+
+	operation.Origin, operation.Sequence, operation.Time = origin, db.Versions[origin]+1, time.Now().UTC() // Set origin, sequence, time
+
+	if current, found := db.Changes[operation.key()]; found && !operation.Time.After(current.Time) { // Check latest change of node, shard was recorded later (clock skew)
+		operation.Time = current.Time.Add(time.Nanosecond) // Order operation after latest change
+	}
+
+	err := operation.Sign(identity) // Sign operation
+
+	if err != nil { // Check for errors
+		return Operation{}, err // Return found error
+	}
+
+	db.logOperation(operation) // Log operation
+
+	db.setChange(operation) // Set latest change of node, shard
+
+	return operation, nil // Return operation
+}
+
+// logOperation - append given applied operation to operation log (truncating the oldest operations beyond DefaultOperationLogSize), advancing version of its origin (operations are only logged in sequence order)
+func (db *NodeDatabase) logOperation(operation Operation) {
+	if db.Versions == nil { // Check for nil versions
+		db.Versions = make(map[string]uint64) // Init versions
+	}
+
+	db.Versions[operation.Origin] = operation.Sequence // Set version
+
+	db.Operations = append(db.Operations, operation) // Append operation
+
+	if len(db.Operations) > DefaultOperationLogSize { // Check log too long
+		db.Operations = append([]Operation{}, db.Operations[len(db.Operations)-DefaultOperationLogSize:]...) // Truncate oldest operations
+	}
+}
+
+// apply - apply given operation replicated from a remote database, unless the node, shard it changes was changed by a later operation
+func (db *NodeDatabase) apply(operation *Operation) error {
+	if current, found := db.Changes[operation.key()]; found && !operation.supersedes(&current) { // Check node, shard was changed later
+		return nil // Superseded
+	}
+
+	err := db.applyChange(operation) // Apply change
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	db.setChange(*operation) // Set latest change of node, shard
+
+	return nil // No error occurred, return nil
+}
+
+// applyChange - apply change of given operation to nodes, shards of database
+func (db *NodeDatabase) applyChange(operation *Operation) error {
+	switch operation.Kind {
+	case OperationAddNode:
+		if operation.Node == nil { // Check for nil node
+			return errors.New("operation has no node") // Return error
+		}
+
+		return db.mergeNode(operation.Node) // Add node
+	case OperationRemoveNode:
+		if nodeIndex, err := db.QueryForAddress(operation.Address); err == nil { // Check node in database (already removed otherwise)
+			db.remove(int(nodeIndex)) // Remove node
+		}
+
+		return nil // No error occurred, return nil
+	case OperationAddShard:
+		if operation.Shard == nil || operation.Shard.Address == "" { // Check for invalid shard
+			return errors.New("operation has no shard") // Return error
+		}
+
+		db.putShard(operation.Shard) // Add shard
+
+		return nil // No error occurred, return nil
+	case OperationRemoveShard:
+		if shardIndex, err := db.QueryForShardAddress(operation.Address); err == nil { // Check shard in database (already removed otherwise)
+			db.removeShard(int(shardIndex)) // Remove shard
+		}
+
+		return nil // No error occurred, return nil
+	default:
+		return fmt.Errorf("unsupported operation kind %s", operation.Kind) // Return error
+	}
+}
+
+// key - fetch key of node (by NodeID, or address for anonymous nodes), shard (by address) changed by operation
+func (operation *Operation) key() string {
+	switch operation.Kind {
+	case OperationAddNode:
+		if operation.Node == nil { // Check for nil node
+			return "node:" // Return key
+		}
+
+		return "node:" + livenessKey(operation.Node) // Return key
+	case OperationRemoveNode:
+		return "node:" + operation.Address // Return key
+	default:
+		if operation.Shard != nil { // Check for shard
+			return "shard:" + operation.Shard.Address // Return key
+		}
+
+		return "shard:" + operation.Address // Return key
+	}
+}
+
+// supersedes - check operation was recorded after given operation changing the same node, shard (ties are broken by origin, then sequence)
+func (operation *Operation) supersedes(current *Operation) bool {
+	if !operation.Time.Equal(current.Time) { // Check for different times
+		return operation.Time.After(current.Time) // Latest time wins
+	}
+
+	if operation.Origin != current.Origin { // Check for different origins
+		return operation.Origin > current.Origin // Greatest origin wins
+	}
+
+	return operation.Sequence > current.Sequence // Latest operation of origin wins
+}
+
+// setChange - set given operation as the latest change of the node, shard it changes
+func (db *NodeDatabase) setChange(operation Operation) {
+	if db.Changes == nil { // Check for nil changes
+		db.Changes = make(map[string]Operation) // Init changes
+	}
+
+	db.Changes[operation.key()] = operation // Set change
+}
+
+// mergeNode - add replicated node after checking its identity, keeping reputation and last ping time of known nodes
+func (db *NodeDatabase) mergeNode(replicatedNode *node.Node) error {
+	if replicatedNode.Address == "" { // Check for nil address
+		return errors.New("nil address") // Return error
+	}
+
+	if replicatedNode.NodeID != "" && (len(replicatedNode.PublicKey) != 0 || !replicatedNode.IsPinned()) { // Check node has identity (pinned NodeIDs are verified once dialed)
+		err := replicatedNode.VerifyIdentity() // Verify NodeID matches public key
+
+		if err != nil { // Check for invalid identity
+			return err // Return found error
+		}
+	}
+
+	err := replicatedNode.CheckAddresses() // Check advertised addresses
+
+	if err != nil { // Check for invalid addresses
+		return err // Return found error
+	}
+
+	if replicatedNode.NodeID != "" && db.IsBanned(replicatedNode.NodeID) { // Check node is banned
+		return ErrPeerBanned // Return error
+	}
+
+	merged := contactOf(replicatedNode) // Strip node to contact information
+
+	if nodeIndex, err := db.QueryForNodeID(merged.NodeID); err == nil { // Check node already known
+		merged.Reputation, merged.LastPingTime = (*db.Nodes)[nodeIndex].Reputation, (*db.Nodes)[nodeIndex].LastPingTime // Keep local state
+	} else if merged.NodeID == "" { // Check for anonymous node
+		if _, err = db.QueryForAddress(merged.Address); err == nil { // Check node already known
+			return nil // Nothing to add
+		}
+	}
+
+	return db.insertNode(merged) // Add node
+}
+
+// mergeSnapshot - apply latest changes of given snapshot response (removals included, so nodes, shards removed by the responder are removed as well), advancing versions to those of the responder for origins whose changes were all signed by them (the latest changes cover every operation of the responder up to its versions). Returns number of operations covered by the snapshot.
+func (db *NodeDatabase) mergeSnapshot(response *ReplicationResponse) int {
+	rejected := make(map[string]bool) // Init origins of rejected changes buffer
+
+	for x := range response.Changes { // Iterate through changes
+		change := &response.Changes[x] // Fetch change
+
+		if err := change.Verify(); err != nil { // Verify change was signed by origin
+			rejected[change.Origin] = true // Don't advance version of origin
+
+			continue // Skip change
+		}
+
+		if err := db.apply(change); err != nil { // Apply change
+			common.Printf("\n-- REPLICATION -- skipped change %d of %s: %s", change.Sequence, change.Origin, err.Error()) // Log invalid change
+		}
+	}
+
+	covered := 0 // Init covered counter
+
+	if db.Versions == nil { // Check for nil versions
+		db.Versions = make(map[string]uint64) // Init versions
+	}
+
+	for origin, latest := range response.Versions { // Iterate through versions
+		if !rejected[origin] && latest > db.Versions[origin] { // Check responder is ahead
+			covered += int(latest - db.Versions[origin]) // Add covered operations
+
+			db.Versions[origin] = latest // Set version
+		}
+	}
+
+	return covered // Return covered count
+}
+
+// signedBytes - fetch contents of operation covered by its signature (its protobuf wire encoding without signature, so operations relayed with either codec verify alike)
+func (operation *Operation) signedBytes() ([]byte, error) {
+	wireOperation, err := operation.ToWire() // Convert operation
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	wireOperation.Signature = nil // Reset signature
+
+	return proto.Marshal(wireOperation) // Marshal operation
+}
+
+// putShard - add given shard to database, replacing shard with same address
+func (db *NodeDatabase) putShard(destinationShard *shard.Shard) {
+	if db.Shards == nil { // Check for nil shards
+		db.Shards = &[]shard.Shard{*destinationShard} // Initialize w/shard
+
+		return // Stop
+	}
+
+	if shardIndex, err := db.QueryForShardAddress(destinationShard.Address); err == nil { // Check shard already in database
+		(*db.Shards)[shardIndex] = *destinationShard // Replace shard
+
+		return // Stop
+	}
+
+	*db.Shards = append(*db.Shards, *destinationShard) // Append shard
+}
+
+// replicate - push given operations to every known peer in the background, working on a copy of the database (so the caller may keep modifying it)
+func (db *NodeDatabase) replicate(operations ...Operation) {
+	if db.RoutingTable != nil || len(operations) == 0 { // Check for DHT mode, no operations
+		return // Nothing to replicate
+	}
+
+	peers := db.replicationPeers() // Fetch peers
+
+	if len(peers) == 0 { // Check for no peers
+		return // Nothing to replicate
+	}
+
+	replica := &NodeDatabase{NetworkAlias: db.NetworkAlias, Versions: db.versionsCopy(), Operations: append([]Operation{}, db.Operations...), localNode: db.localNode} // Copy operation log
+
+	for x := range peers { // Iterate through peers
+		go replica.pushOperations(&peers[x], operations, DefaultReplicationPort) // Push operations
+	}
+}
+
+// pushOperations - send given operations to given peer (listening on given port), following up with preceding operations the peer reports missing
+func (db *NodeDatabase) pushOperations(peer *node.Node, operations []Operation, port uint) {
+	response, err := sendReplication(db.localNode, peer, &ReplicationRequest{Network: db.NetworkAlias, Operations: operations, Versions: db.Versions}, port) // Push operations
+
+	if err != nil { // Check for errors
+		common.Printf("\n-- REPLICATION -- push to %s failed: %s", peer.Address, err.Error()) // Log failure
+
+		return // Stop
+	}
+
+	if missing, complete := db.OperationsSince(response.Versions); len(missing) > 0 && complete { // Check peer missed operations (peers missing truncated operations fetch a snapshot once they synchronize)
+		sendReplication(db.localNode, peer, &ReplicationRequest{Network: db.NetworkAlias, Operations: missing, Versions: db.Versions}, port) // Send missed operations
+	}
+}
+
+// requestSync - request operations the database is missing from given contact (listening on given port), sending the latest applied operations of each origin
+func (db *NodeDatabase) requestSync(contact *node.Node, port uint) (*ReplicationResponse, error) {
+	if db.RoutingTable != nil { // Check for DHT mode
+		return nil, ErrNotReplicated // Return error
+	}
+
+	if contact.NodeID != "" && db.IsBanned(contact.NodeID) { // Check contact is banned
+		return nil, ErrPeerBanned // Return error
+	}
+
+	return sendReplication(db.localNode, contact, &ReplicationRequest{Network: db.NetworkAlias, Versions: db.versionsCopy(), CatchUp: true}, port) // Request missed operations
+}
+
+// applySync - merge snapshot, operations of given synchronization response, returning number of applied operations and the request sending the contact operations it is missing (nil if it isn't missing any, or needs a snapshot since they've been truncated)
+func (db *NodeDatabase) applySync(response *ReplicationResponse) (int, *ReplicationRequest) {
+	applied := 0 // Init applied counter
+
+	if response.Snapshot { // Check for snapshot
+		applied = db.mergeSnapshot(response) // Merge snapshot
+	}
+
+	newOperations, _ := db.ApplyOperations(response.Operations) // Apply missed operations
+
+	applied += newOperations // Add applied operations
+
+	if missing, complete := db.OperationsSince(response.Versions); len(missing) > 0 && complete { // Check contact missed operations (contacts missing truncated operations fetch a snapshot once they synchronize)
+		return applied, &ReplicationRequest{Network: db.NetworkAlias, Operations: missing, Versions: db.versionsCopy()} // Return follow-up
+	}
+
+	return applied, nil // Return applied count
+}
+
+// synchronizeInMemory - synchronize network database stored in given node environment with given contact (listening on given port), requesting operations with the database (a snapshot) and applying the response to the stored database
+func (db *NodeDatabase) synchronizeInMemory(localNode *node.Node, contact *node.Node, port uint) error {
+	db.SetLocalNode(localNode) // Dial contact on behalf of local node
+
+	response, err := db.requestSync(contact, port) // Request missed operations
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	var followUp *ReplicationRequest // Init follow-up buffer
+
+	err = UpdateInMemory(localNode.Environment, db.NetworkAlias, func(current *NodeDatabase) error {
+		if current.RoutingTable != nil { // Check switched to DHT mode
+			return ErrNotReplicated // Return error
+		}
+
+		_, followUp = current.applySync(response) // Apply missed operations
+
+		return nil // Write db
+	}) // Write synchronization to memory
+
+	if err != nil || followUp == nil { // Check for errors, contact missing nothing
+		return err // Return error (might be nil)
+	}
+
+	_, err = sendReplication(localNode, contact, followUp, port) // Send missed operations
+
+	return err // Return error (might be nil)
+}
+
+// antiEntropyPeers - pick at most fanout random known peers to synchronize with
+func (db *NodeDatabase) antiEntropyPeers(fanout int) ([]node.Node, error) {
+	if db.RoutingTable != nil { // Check for DHT mode
+		return nil, ErrNotReplicated // Return error
+	}
+
+	peers := db.replicationPeers() // Fetch peers
+
+	if len(peers) == 0 { // Check for no peers
+		return nil, ErrNoContacts // Return error
+	}
+
+	shufflePeers(peers) // Pick random peers
+
+	if len(peers) > fanout { // Check for too many peers
+		peers = peers[:fanout] // Truncate peers
+	}
+
+	return peers, nil // Return peers
+}
+
+// replicationPeers - fetch copies of known peers operations are replicated to (excluding the local node and banned peers)
+func (db *NodeDatabase) replicationPeers() []node.Node {
+	peers := []node.Node{} // Init peers buffer
+
+	if db.Nodes == nil { // Check for no nodes
+		return peers // No peers
+	}
+
+	origin := "" // Init NodeID of local node buffer
+
+	if db.localNode != nil { // Check for local node
+		origin = db.localNode.NodeID // Set NodeID of local node
+	}
+
+	for _, peer := range *db.Nodes { // Iterate through nodes
+		if peer.NodeID != "" && (peer.NodeID == origin || db.IsBanned(peer.NodeID)) { // Check peer is local node, banned
+			continue // Skip peer
+		}
+
+		peers = append(peers, *contactOf(&peer)) // Append peer
+	}
+
+	return peers // Return peers
+}
+
+// versionsCopy - copy latest applied operation of each origin
+func (db *NodeDatabase) versionsCopy() map[string]uint64 {
+	versions := make(map[string]uint64) // Init versions buffer
+
+	for origin, latest := range db.Versions { // Iterate through versions
+		versions[origin] = latest // Copy version
+	}
+
+	return versions // Return versions
+}
+
+// sendReplication - send replication request to given contact over pooled session of given local node, verifying contact identity (if known)
+func sendReplication(localNode *node.Node, contact *node.Node, request *ReplicationRequest, port uint) (*ReplicationResponse, error) {
+	pool, err := localNode.ConnectionPool() // Fetch pool of local node
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	address := contact.DialAddress(int(port)) // Init contact address
+
+	codec, result, err := pool.RequestEnvelope(address, contact.NodeID, common.EnvelopeKindReplication, request) // Send request
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return DecodeReplicationResponse(codec, result) // Decode response
+}
+
+// operationsToWire - convert operations to protobuf wire types
+func operationsToWire(operations []Operation) ([]*wire.Operation, error) {
+	wireOperations := []*wire.Operation{} // Init wire operations buffer
+
+	for x := range operations { // Iterate through operations
+		wireOperation, err := operations[x].ToWire() // Convert operation
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		wireOperations = append(wireOperations, wireOperation) // Append operation
+	}
+
+	return wireOperations, nil // Return wire operations
+}
+
+// operationsFromWire - convert protobuf wire types to operations
+func operationsFromWire(wireOperations []*wire.Operation) ([]Operation, error) {
+	operations := []Operation{} // Init operations buffer
+
+	for _, wireOperation := range wireOperations { // Iterate through wire operations
+		if wireOperation == nil { // Check for nil operation
+			continue // Skip operation
+		}
+
+		operation, err := OperationFromWire(wireOperation) // Convert operation
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		operations = append(operations, *operation) // Append operation
+	}
+
+	return operations, nil // Return operations
+}
+
+// versionsToWire - convert versions to protobuf wire types
+func versionsToWire(versions map[string]uint64) []*wire.Version {
+	wireVersions := []*wire.Version{} // Init wire versions buffer
+
+	for origin, latest := range versions { // Iterate through versions
+		wireVersions = append(wireVersions, &wire.Version{Origin: origin, Sequence: latest}) // Append version
+	}
+
+	return wireVersions // Return wire versions
+}
+
+// versionsFromWire - convert protobuf wire types to versions
+func versionsFromWire(wireVersions []*wire.Version) map[string]uint64 {
+	versions := make(map[string]uint64) // Init versions buffer
+
+	for _, wireVersion := range wireVersions { // Iterate through wire versions
+		if wireVersion != nil { // Check for non-nil version
+			versions[wireVersion.Origin] = wireVersion.Sequence // Set version
+		}
+	}
+
+	return versions // Return versions
+}
+
+/*
+	END INTERNAL METHODS
+*/
@@ -0,0 +1,304 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/types/node"
+	"github.com/dowlandaiello/GoP2P/types/shard"
+)
+
+// TestApplyOperations - test functionality of ApplyOperations() method
+func TestApplyOperations(t *testing.T) {
+	first := newTestContact(t, "memory://replication-first") // Init first peer
+
+	second := newTestContact(t, "memory://replication-second") // Init second peer
+
+	third := newTestContact(t, "memory://replication-third") // Init third peer
+
+	a, b := newTestContact(t, "memory://replication-a"), newTestContact(t, "memory://replication-b") // Init origins
+
+	operations := signOperations(t, a,
+		Operation{Sequence: 1, Kind: OperationAddNode, Node: first},              // Add first peer
+		Operation{Sequence: 2, Kind: OperationAddNode, Node: second},             // Add second peer
+		Operation{Sequence: 3, Kind: OperationRemoveNode, Address: first.NodeID}, // Remove first peer
+	) // Init operations of origin a
+
+	db := &NodeDatabase{NetworkAlias: "GoP2P_TestNet"} // Init database
+
+	if applied, missing := db.ApplyOperations([]Operation{operations[2], operations[0]}); applied != 1 || !missing { // Check operation following missing operation is held back
+		t.Errorf("expected 1 applied operation and missing operations, found %d, %t", applied, missing) // Log found error
+		t.FailNow()                                                                                     // Panic
+	}
+
+	if applied, missing := db.ApplyOperations(operations); applied != 2 || missing { // Check remaining operations are applied
+		t.Errorf("expected 2 applied operations, found %d (missing: %t)", applied, missing) // Log found error
+		t.FailNow()                                                                         // Panic
+	}
+
+	if applied, _ := db.ApplyOperations(operations); applied != 0 || len(*db.Nodes) != 1 || (*db.Nodes)[0].NodeID != second.NodeID || db.Versions[a.NodeID] != 3 { // Check operations are applied once
+		t.Errorf("invalid database after replaying operations: %v", db.Nodes) // Log found error
+		t.FailNow()                                                           // Panic
+	}
+
+	forged := signOperations(t, a, Operation{Sequence: 1, Kind: OperationAddNode, Node: third})[0] // Init operation signed by origin a
+
+	forged.Origin = b.NodeID // Claim operation originated from origin b
+
+	tampered := signOperations(t, b, Operation{Sequence: 1, Kind: OperationAddNode, Node: third})[0] // Init operation of origin b
+
+	tampered.Node = first // Replace added node
+
+	if applied, _ := db.ApplyOperations([]Operation{forged, tampered, {Origin: b.NodeID, Sequence: 1, Kind: OperationAddNode, Node: third}}); applied != 0 || db.Versions[b.NodeID] != 0 { // Check operations not signed by their origin are rejected
+		t.Errorf("expected operations not signed by their origin to be rejected, found %d applied", applied) // Log found error
+		t.FailNow()                                                                                          // Panic
+	}
+
+	concurrent := signOperations(t, b, Operation{Sequence: 1, Kind: OperationAddNode, Node: third})[0] // Init operation recorded concurrently by origin b
+
+	other := &NodeDatabase{NetworkAlias: "GoP2P_TestNet"} // Init database receiving operations in another order
+
+	other.ApplyOperations(append([]Operation{concurrent}, operations...)) // Apply operations
+
+	db.ApplyOperations([]Operation{concurrent}) // Apply operation
+
+	for _, peer := range []*node.Node{second, third} { // Iterate through expected peers
+		if _, err := other.QueryForNodeID(peer.NodeID); err != nil || len(*other.Nodes) != len(*db.Nodes) { // Check databases converged
+			t.Errorf("databases diverged: %v, %v", db.Nodes, other.Nodes) // Log found error
+			t.FailNow()                                                   // Panic
+		}
+	}
+}
+
+// TestConcurrentOperations - test concurrent operations of different origins changing the same shard converge to the latest change
+func TestConcurrentOperations(t *testing.T) {
+	a, b := newTestContact(t, "memory://replication-a"), newTestContact(t, "memory://replication-b") // Init origins
+
+	now := time.Now().UTC() // Fetch time
+
+	added := signOperations(t, a, Operation{Sequence: 1, Kind: OperationAddShard, Shard: &shard.Shard{Address: "shard"}, Time: now})[0] // Init addition of origin a
+
+	removed := signOperations(t, b, Operation{Sequence: 1, Kind: OperationRemoveShard, Address: "shard", Time: now.Add(time.Second)})[0] // Init later removal of origin b
+
+	readded := signOperations(t, a, Operation{Sequence: 2, Kind: OperationAddShard, Shard: &shard.Shard{Address: "shard"}, Time: now.Add(time.Minute)})[0] // Init later addition of origin a
+
+	for _, order := range [][]Operation{{added, removed}, {removed, added}} { // Iterate through delivery orders
+		db := &NodeDatabase{NetworkAlias: "GoP2P_TestNet"} // Init database
+
+		for _, operation := range order { // Iterate through operations
+			db.ApplyOperations([]Operation{operation}) // Apply operation
+		}
+
+		if _, err := db.QueryForShardAddress("shard"); err == nil { // Check removal won
+			t.Errorf("expected later removal to win over addition, found shards %v", db.Shards) // Log found error
+			t.FailNow()                                                                         // Panic
+		}
+
+		db.ApplyOperations([]Operation{readded}) // Apply later addition
+
+		if _, err := db.QueryForShardAddress("shard"); err != nil { // Check later addition won
+			t.Errorf("expected later addition to win over removal") // Log found error
+			t.FailNow()                                             // Panic
+		}
+	}
+}
+
+// TestOperationsSince - test functionality of OperationsSince() method
+func TestOperationsSince(t *testing.T) {
+	a, b := newTestContact(t, "memory://replication-a"), newTestContact(t, "memory://replication-b") // Init origins
+
+	db := &NodeDatabase{NetworkAlias: "GoP2P_TestNet"} // Init database
+
+	for x := uint64(1); x <= 3; x++ { // Add shards
+		db.ApplyOperations(signOperations(t, a, Operation{Sequence: x, Kind: OperationAddShard, Shard: &shard.Shard{Address: string('a' + rune(x))}})) // Apply operation
+	}
+
+	db.ApplyOperations(signOperations(t, b, Operation{Sequence: 1, Kind: OperationRemoveShard, Address: "b", Time: time.Now()})) // Remove shard
+
+	if operations, complete := db.OperationsSince(map[string]uint64{a.NodeID: 1}); len(operations) != 3 || !complete { // Check missing operations are found
+		t.Errorf("expected 3 missing operations, found %v (complete: %t)", operations, complete) // Log found error
+		t.FailNow()                                                                              // Panic
+	}
+
+	if operations, complete := db.OperationsSince(db.versionsCopy()); len(operations) != 0 || !complete { // Check up-to-date database is missing nothing
+		t.Errorf("expected no missing operations, found %v", operations) // Log found error
+		t.FailNow()                                                      // Panic
+	}
+
+	db.Operations = db.Operations[1:] // Truncate log
+
+	if _, complete := db.OperationsSince(map[string]uint64{}); complete { // Check truncated operations require snapshot
+		t.Errorf("expected truncated log to be incomplete") // Log found error
+		t.FailNow()                                         // Panic
+	}
+
+	if len(*db.Shards) != 2 { // Check shards
+		t.Errorf("invalid shards %v", db.Shards) // Log found error
+		t.FailNow()                              // Panic
+	}
+}
+
+// TestMergeSnapshot - test databases missing truncated operations converge to the latest changes of a snapshot, removals included
+func TestMergeSnapshot(t *testing.T) {
+	a, b := newTestContact(t, "memory://replication-a"), newTestContact(t, "memory://replication-b") // Init origins
+
+	operations := signOperations(t, a,
+		Operation{Sequence: 1, Kind: OperationAddShard, Shard: &shard.Shard{Address: "removed"}}, // Add removed shard
+		Operation{Sequence: 2, Kind: OperationAddShard, Shard: &shard.Shard{Address: "added"}},   // Add shard
+		Operation{Sequence: 3, Kind: OperationRemoveShard, Address: "removed"},                   // Remove removed shard
+	) // Init operations of origin a
+
+	responder := &NodeDatabase{NetworkAlias: "GoP2P_TestNet"} // Init responder database
+
+	responder.ApplyOperations(operations) // Apply operations
+
+	responder.Operations = nil // Truncate log
+
+	db := &NodeDatabase{NetworkAlias: "GoP2P_TestNet"} // Init database
+
+	db.ApplyOperations(operations[:1]) // Apply first operation
+
+	response := responder.HandleReplication(&ReplicationRequest{Network: "GoP2P_TestNet", Versions: db.versionsCopy(), CatchUp: true}) // Request missed operations
+
+	forged := signOperations(t, a, Operation{Sequence: 1, Kind: OperationAddShard, Shard: &shard.Shard{Address: "forged"}})[0] // Init change signed by origin a
+
+	forged.Origin = b.NodeID // Claim change originated from origin b
+
+	response.Changes, response.Versions[b.NodeID] = append(response.Changes, forged), 1 // Append forged change
+
+	db.applySync(response) // Merge snapshot
+
+	if _, err := db.QueryForShardAddress("removed"); err == nil || !response.Snapshot { // Check removal was merged
+		t.Errorf("expected snapshot to remove shard, found shards %v", db.Shards) // Log found error
+		t.FailNow()                                                               // Panic
+	}
+
+	if _, err := db.QueryForShardAddress("added"); err != nil || db.Versions[a.NodeID] != 3 { // Check addition was merged
+		t.Errorf("expected snapshot to add shard, found shards %v (versions: %v)", db.Shards, db.Versions) // Log found error
+		t.FailNow()                                                                                        // Panic
+	}
+
+	if _, err := db.QueryForShardAddress("forged"); err == nil || db.Versions[b.NodeID] != 0 { // Check forged change was rejected
+		t.Errorf("expected forged change to be rejected, found shards %v (versions: %v)", db.Shards, db.Versions) // Log found error
+		t.FailNow()                                                                                               // Panic
+	}
+}
+
+// TestRecordOperations - test AddNode(), RemoveNode() record operations replaying to the same database
+func TestRecordOperations(t *testing.T) {
+	added := newTestContact(t, "memory://replication-added") // Init added peer
+
+	removed := newTestContact(t, "memory://replication-removed") // Init removed peer
+
+	localNode := newTestContact(t, "memory://replication-local") // Init local node
+
+	db := &NodeDatabase{NetworkAlias: "GoP2P_TestNet"} // Init database
+
+	if err := db.AddNode(added); err != ErrNoLocalNode { // Check operations aren't recorded without a local node
+		t.Errorf("expected ErrNoLocalNode, found %v", err) // Log found error
+		t.FailNow()                                        // Panic
+	}
+
+	db = &NodeDatabase{NetworkAlias: "GoP2P_TestNet"} // Reset database
+
+	db.SetLocalNode(localNode) // Record operations on behalf of local node
+
+	for _, peer := range []*node.Node{added, removed} { // Iterate through peers
+		err := db.AddNode(peer) // Add peer
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+	}
+
+	err := db.RemoveNode(removed.NodeID) // Remove peer
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if len(db.Operations) != 3 || db.Versions[localNode.NodeID] != 3 || db.Operations[2].Kind != OperationRemoveNode { // Check operations were recorded
+		t.Errorf("invalid operations %v", db.Operations) // Log found error
+		t.FailNow()                                      // Panic
+	}
+
+	replica := &NodeDatabase{NetworkAlias: "GoP2P_TestNet"} // Init replica
+
+	if applied, _ := replica.ApplyOperations(db.Operations); applied != 3 || len(*replica.Nodes) != 1 || (*replica.Nodes)[0].NodeID != added.NodeID { // Check replica matches database
+		t.Errorf("invalid replica %v", replica.Nodes) // Log found error
+		t.FailNow()                                   // Panic
+	}
+}
+
+// TestEncodeReplicationRequest - test functionality of ReplicationRequest, ReplicationResponse Encode(), Decode() methods
+func TestEncodeReplicationRequest(t *testing.T) {
+	peer := newTestContact(t, "memory://replication-peer") // Init peer
+
+	operations := signOperations(t, peer,
+		Operation{Sequence: 1, Kind: OperationAddNode, Node: peer, Time: time.Now().UTC()},                       // Add peer
+		Operation{Sequence: 2, Kind: OperationAddShard, Shard: &shard.Shard{Address: "shard"}, Time: time.Now()}, // Add shard
+	) // Init operations
+
+	for _, codec := range []common.Codec{common.CodecJSON, common.CodecProtobuf} { // Iterate through codecs
+		request := &ReplicationRequest{Network: "GoP2P_TestNet", Operations: operations, Versions: map[string]uint64{peer.NodeID: 2}, CatchUp: true} // Init request
+
+		encoded, err := request.Encode(codec) // Encode request
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		decoded, err := DecodeReplicationRequest(codec, encoded) // Decode request
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if decoded.Network != request.Network || !decoded.CatchUp || decoded.Versions[peer.NodeID] != 2 || len(decoded.Operations) != 2 || decoded.Operations[0].Node.VerifyIdentity() != nil || decoded.Operations[1].Shard.Address != "shard" || decoded.Operations[0].Verify() != nil || decoded.Operations[1].Verify() != nil { // Check for mismatch
+			t.Errorf("invalid decoded %s request %v", codec, decoded) // Log found error
+			t.FailNow()                                               // Panic
+		}
+
+		response := &ReplicationResponse{Versions: map[string]uint64{peer.NodeID: 2}, Snapshot: true, Changes: operations} // Init response
+
+		encoded, err = response.Encode(codec) // Encode response
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		decodedResponse, err := DecodeReplicationResponse(codec, encoded) // Decode response
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if !decodedResponse.Snapshot || decodedResponse.Versions[peer.NodeID] != 2 || len(decodedResponse.Changes) != 2 || decodedResponse.Changes[1].Shard.Address != "shard" || decodedResponse.Changes[0].Verify() != nil || decodedResponse.Changes[1].Verify() != nil { // Check for mismatch
+			t.Errorf("invalid decoded %s response %v", codec, decodedResponse) // Log found error
+			t.FailNow()                                                        // Panic
+		}
+	}
+}
+
+// signOperations - sign given operations as operations originating from given node
+func signOperations(t *testing.T, origin *node.Node, operations ...Operation) []Operation {
+	for x := range operations { // Iterate through operations
+		operations[x].Origin = origin.NodeID // Set origin
+
+		err := operations[x].Sign(origin.Identity()) // Sign operation
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+	}
+
+	return operations // Return signed operations
+}
@@ -0,0 +1,308 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+// ReputationEvent - kind of peer behavior affecting its reputation
+type ReputationEvent string
+
+const (
+	// ReputationResponse - peer answered a request successfully
+	ReputationResponse = ReputationEvent("response")
+
+	// ReputationTimeout - peer couldn't be reached or didn't answer in time
+	ReputationTimeout = ReputationEvent("timeout")
+
+	// ReputationMalformed - peer sent a message that couldn't be decoded
+	ReputationMalformed = ReputationEvent("malformed")
+
+	// ReputationViolation - peer broke the protocol (e.g. impersonation, flooding)
+	ReputationViolation = ReputationEvent("violation")
+
+	// DefaultReputationInterval - default duration between reputation decay rounds
+	DefaultReputationInterval = time.Minute
+)
+
+// ReputationPolicy - adjustments applied to peer reputations, decay and ban parameters
+type ReputationPolicy struct {
+	Adjustments map[ReputationEvent]int // Adjustments - reputation change caused by each event
+
+	MaxReputation uint // MaxReputation - reputations are capped at this value
+
+	NeutralReputation uint // NeutralReputation - reputation of newly seen peers (reputations decay towards this value)
+
+	HalfLife time.Duration // HalfLife - duration after which the difference between a reputation and NeutralReputation is halved
+
+	BanThreshold uint // BanThreshold - peers penalized below this reputation are banned
+
+	BanDuration time.Duration // BanDuration - duration of automatic bans
+}
+
+var (
+	// DefaultReputationPolicy - policy applied to reputation events
+	DefaultReputationPolicy = ReputationPolicy{
+		Adjustments: map[ReputationEvent]int{
+			ReputationResponse:  1,   // Reward responses
+			ReputationTimeout:   -2,  // Penalize timeouts
+			ReputationMalformed: -5,  // Penalize malformed messages
+			ReputationViolation: -10, // Penalize protocol violations
+		}, // Set adjustments
+		MaxReputation:     100,                     // Set maximum reputation
+		NeutralReputation: common.NodeAvailableRep, // Set neutral reputation
+		HalfLife:          time.Hour,               // Set half-life
+		BanThreshold:      3,                       // Set ban threshold
+		BanDuration:       30 * time.Minute,        // Set ban duration
+	}
+
+	// ErrPeerBanned - error returned when interacting with a banned peer
+	ErrPeerBanned = errors.New("peer is temporarily banned")
+)
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// RecordEvent - adjust reputation of peer with given NodeID according to DefaultReputationPolicy, banning the peer if a penalty drops its reputation below the ban threshold. Returns true if the peer was banned.
+func (db *NodeDatabase) RecordEvent(nodeID string, event ReputationEvent) (bool, error) {
+	policy := DefaultReputationPolicy // Fetch policy
+
+	adjustment, found := policy.Adjustments[event] // Fetch adjustment
+
+	if !found { // Check for unknown event
+		return false, errors.New("unknown reputation event") // Return error
+	}
+
+	nodeIndex, err := db.QueryForNodeID(nodeID) // Fetch peer index
+
+	if err != nil { // Check for errors
+		return false, err // Return found error
+	}
+
+	peer := (*db.Nodes)[nodeIndex] // Fetch peer
+
+	if peer.LastPingTime.IsZero() && peer.Reputation < policy.NeutralReputation { // Check peer has never been seen (e.g. learned from another peer)
+		peer.Reputation = policy.NeutralReputation // Start from neutral reputation
+	}
+
+	if event == ReputationResponse { // Check peer answered
+		peer.LastPingTime = time.Now() // Set seen
+	}
+
+	peer.Reputation = adjustReputation(peer.Reputation, adjustment, policy.MaxReputation) // Adjust reputation
+
+	db.updateNode(nodeIndex, &peer) // Update peer
+
+	if adjustment >= 0 || peer.Reputation >= policy.BanThreshold { // Check ban not necessary
+		return false, nil // Not banned
+	}
+
+	db.Ban(nodeID, policy.BanDuration) // Ban peer
+
+	common.Printf("\n-- REPUTATION -- banned peer %s for %s (%s)", nodeID, policy.BanDuration.String(), string(event)) // Log ban
+
+	return true, nil // Banned
+}
+
+// Ban - ban peer with given NodeID for given duration (extending existing bans)
+func (db *NodeDatabase) Ban(nodeID string, duration time.Duration) {
+	if db.Bans == nil { // Check for nil bans
+		db.Bans = make(map[string]time.Time) // Init bans
+	}
+
+	expiry := time.Now().Add(duration) // Calculate ban expiry
+
+	if expiry.After(db.Bans[nodeID]) { // Check extends ban
+		db.Bans[nodeID] = expiry // Set ban
+	}
+}
+
+// Unban - lift ban of peer with given NodeID
+func (db *NodeDatabase) Unban(nodeID string) {
+	delete(db.Bans, nodeID) // Remove ban
+}
+
+// IsBanned - check peer with given NodeID is currently banned
+func (db *NodeDatabase) IsBanned(nodeID string) bool {
+	expiry, found := db.Bans[nodeID] // Fetch ban
+
+	return found && time.Now().Before(expiry) // Check ban hasn't expired
+}
+
+// ExpireBans - remove expired bans, returning number of lifted bans
+func (db *NodeDatabase) ExpireBans() int {
+	lifted := 0 // Init lifted counter
+
+	now := time.Now() // Fetch current time
+
+	for nodeID, expiry := range db.Bans { // Iterate through bans
+		if !now.Before(expiry) { // Check ban expired
+			delete(db.Bans, nodeID) // Lift ban
+
+			lifted++ // Increment lifted
+		}
+	}
+
+	return lifted // Return lifted count
+}
+
+// DecayReputation - move every reputation towards the neutral reputation as if given duration had elapsed
+func (db *NodeDatabase) DecayReputation(elapsed time.Duration) {
+	if db.Nodes == nil || DefaultReputationPolicy.HalfLife <= 0 { // Check nothing to decay
+		return // Nothing to do
+	}
+
+	neutral := float64(DefaultReputationPolicy.NeutralReputation) // Fetch neutral reputation
+
+	factor := math.Pow(0.5, elapsed.Seconds()/DefaultReputationPolicy.HalfLife.Seconds()) // Calculate decay factor
+
+	for x, peer := range *db.Nodes { // Iterate through peers
+		if peer.Reputation == 0 && peer.LastPingTime.IsZero() { // Check peer has never been seen
+			continue // Unseen peers don't earn reputation
+		}
+
+		decayed := neutral + (float64(peer.Reputation)-neutral)*factor // Decay reputation
+
+		if peer.Reputation > DefaultReputationPolicy.NeutralReputation { // Check decaying downwards
+			decayed = math.Floor(decayed) // Round towards neutral
+		} else {
+			decayed = math.Ceil(decayed) // Round towards neutral
+		}
+
+		if uint(decayed) == peer.Reputation { // Check unchanged
+			continue // Skip peer
+		}
+
+		peer.Reputation = uint(decayed) // Set reputation
+
+		db.updateNode(uint(x), &peer) // Update peer
+	}
+}
+
+// PeerScore - score of given peer used to rank peers (reputation weighted by how recently the peer was seen)
+func PeerScore(peer *node.Node) float64 {
+	if peer.LastPingTime.IsZero() { // Check peer has never been seen
+		return 0 // Unseen peers rank last
+	}
+
+	age := time.Since(peer.LastPingTime) // Fetch time since peer was seen
+
+	if age <= 0 || DefaultReputationPolicy.HalfLife <= 0 { // Check for clock skew, disabled decay
+		return float64(peer.Reputation) // Return unweighted reputation
+	}
+
+	return float64(peer.Reputation) * math.Pow(0.5, age.Seconds()/DefaultReputationPolicy.HalfLife.Seconds()) // Weight reputation by age
+}
+
+// SelectPeers - fetch at most count peers that aren't banned, highest-scoring (see PeerScore) first, excluding given nodes
+func (db *NodeDatabase) SelectPeers(count int, exclude ...*node.Node) []node.Node {
+	candidates := []node.Node{} // Init candidates buffer
+
+	if db.Nodes == nil { // Check for nil nodes
+		return candidates // No peers
+	}
+
+	for _, peer := range *db.Nodes { // Iterate through peers
+		if (peer.NodeID != "" && db.IsBanned(peer.NodeID)) || isExcluded(&peer, exclude) { // Check peer banned, excluded
+			continue // Skip peer
+		}
+
+		candidates = append(candidates, peer) // Append candidate
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		scoreI, scoreJ := PeerScore(&candidates[i]), PeerScore(&candidates[j]) // Fetch scores
+
+		if scoreI != scoreJ { // Check scores differ
+			return scoreI > scoreJ // Prefer higher score
+		}
+
+		return candidates[i].LastPingTime.After(candidates[j].LastPingTime) // Prefer recently seen peer
+	}) // Sort candidates
+
+	if len(candidates) > count { // Check for too many candidates
+		candidates = candidates[:count] // Truncate candidates
+	}
+
+	return candidates // Return selected peers
+}
+
+// BestPeer - fetch highest-scoring peer that isn't banned, excluding given nodes
+func (db *NodeDatabase) BestPeer(exclude ...*node.Node) (*node.Node, error) {
+	peers := db.SelectPeers(1, exclude...) // Select peer
+
+	if len(peers) == 0 { // Check no peers available
+		return nil, ErrNoContacts // Return error
+	}
+
+	return &peers[0], nil // Return peer
+}
+
+// ReputationRoutine - decay reputations, lift expired bans of the network database stored in given node environment every given duration until context is cancelled (db only identifies the network)
+func (db *NodeDatabase) ReputationRoutine(ctx context.Context, localNode *node.Node, interval time.Duration) {
+	ticker := time.NewTicker(interval) // Init ticker
+
+	defer ticker.Stop() // Stop ticker
+
+	for {
+		select {
+		case <-ticker.C: // Check tick
+			err := UpdateInMemory(localNode.Environment, db.NetworkAlias, func(current *NodeDatabase) error {
+				current.DecayReputation(interval) // Decay reputations
+
+				current.ExpireBans() // Lift expired bans
+
+				return nil // Write db
+			}) // Write round to memory
+
+			if err != nil && err != ErrNoDatabase { // Check for errors (other than network not joined)
+				common.Printf("\n-- REPUTATION -- failed to write database: %s", err.Error()) // Log failure
+			}
+		case <-ctx.Done(): // Check cancelled
+			return // Stop
+		}
+	}
+}
+
+/*
+	END EXPORTED METHODS
+*/
+
+/*
+	BEGIN INTERNAL METHODS:
+*/
+
+// adjustReputation - apply given adjustment to given reputation, keeping it within [0, max]
+func adjustReputation(reputation uint, adjustment int, max uint) uint {
+	adjusted := int64(reputation) + int64(adjustment) // Adjust reputation
+
+	if adjusted < 0 { // Check for negative reputation
+		return 0 // Floor reputation
+	}
+
+	if uint64(adjusted) > uint64(max) { // Check reputation exceeds maximum
+		return max // Cap reputation
+	}
+
+	return uint(adjusted) // Return adjusted reputation
+}
+
+// updateNode - replace peer at given index with given peer, keeping its routing table position (DHT mode)
+func (db *NodeDatabase) updateNode(nodeIndex uint, peer *node.Node) {
+	(*db.Nodes)[nodeIndex] = *peer // Update peer
+
+	if db.RoutingTable != nil { // Check for DHT mode
+		db.RoutingTable.UpdateNode(peer) // Update contact
+	}
+}
+
+/*
+	END INTERNAL METHODS
+*/
@@ -0,0 +1,125 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+// TestRecordEvent - test functionality of RecordEvent(), IsBanned(), ExpireBans() methods
+func TestRecordEvent(t *testing.T) {
+	peer := newTestContact(t, "memory://reputation-peer") // Init peer
+
+	db := &NodeDatabase{NetworkAlias: "GoP2P_TestNet", Nodes: &[]node.Node{*peer}} // Init database
+
+	banned, err := db.RecordEvent(peer.NodeID, ReputationResponse) // Reward first response
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if banned || (*db.Nodes)[0].Reputation != common.NodeAvailableRep+1 || (*db.Nodes)[0].LastPingTime.IsZero() { // Check peer was rewarded, seen
+		t.Errorf("invalid reputation %d after response", (*db.Nodes)[0].Reputation) // Log found error
+		t.FailNow()                                                                 // Panic
+	}
+
+	if banned, _ = db.RecordEvent(peer.NodeID, ReputationMalformed); banned { // Penalize malformed message
+		t.Errorf("peer banned above ban threshold") // Log found error
+		t.FailNow()                                 // Panic
+	}
+
+	if banned, _ = db.RecordEvent(peer.NodeID, ReputationViolation); !banned || !db.IsBanned(peer.NodeID) || (*db.Nodes)[0].Reputation != 0 { // Penalize violation
+		t.Errorf("peer wasn't banned below ban threshold") // Log found error
+		t.FailNow()                                        // Panic
+	}
+
+	if sample := db.SamplePeers(10, 0); len(sample) != 0 { // Check banned peer isn't offered
+		t.Errorf("banned peer was sampled") // Log found error
+		t.FailNow()                         // Panic
+	}
+
+	if db.MergePeers(nil, []node.Node{*peer}, 1) != 0 || db.markSeen(peer) != ErrPeerBanned { // Check banned peer isn't accepted
+		t.Errorf("banned peer was accepted") // Log found error
+		t.FailNow()                          // Panic
+	}
+
+	if db.ExpireBans() != 0 { // Check active ban was lifted
+		t.Errorf("active ban was lifted") // Log found error
+		t.FailNow()                       // Panic
+	}
+
+	db.Bans[peer.NodeID] = time.Now().Add(-time.Second) // Expire ban
+
+	if db.IsBanned(peer.NodeID) || db.ExpireBans() != 1 || len(db.Bans) != 0 { // Check expired ban was lifted
+		t.Errorf("expired ban wasn't lifted") // Log found error
+		t.FailNow()                           // Panic
+	}
+
+	if _, err = db.RecordEvent("unknown", ReputationResponse); err == nil { // Check unknown peer
+		t.Errorf("recorded event of unknown peer") // Log found error
+		t.FailNow()                                // Panic
+	}
+}
+
+// TestDecayReputation - test functionality of DecayReputation() method
+func TestDecayReputation(t *testing.T) {
+	trusted := newTestContact(t, "memory://reputation-trusted") // Init trusted peer
+
+	trusted.Reputation, trusted.LastPingTime = common.NodeAvailableRep+40, time.Now() // Set reputation
+
+	penalized := newTestContact(t, "memory://reputation-penalized") // Init penalized peer
+
+	penalized.Reputation, penalized.LastPingTime = 2, time.Now() // Set reputation
+
+	unseen := newTestContact(t, "memory://reputation-unseen") // Init unseen peer
+
+	db := &NodeDatabase{NetworkAlias: "GoP2P_TestNet", Nodes: &[]node.Node{*trusted, *penalized, *unseen}} // Init database
+
+	db.DecayReputation(DefaultReputationPolicy.HalfLife) // Decay by one half-life
+
+	if (*db.Nodes)[0].Reputation != common.NodeAvailableRep+20 || (*db.Nodes)[1].Reputation != 6 || (*db.Nodes)[2].Reputation != 0 { // Check reputations decayed towards neutral
+		t.Errorf("invalid decayed reputations %d, %d, %d", (*db.Nodes)[0].Reputation, (*db.Nodes)[1].Reputation, (*db.Nodes)[2].Reputation) // Log found error
+		t.FailNow()                                                                                                                         // Panic
+	}
+}
+
+// TestSelectPeers - test functionality of SelectPeers(), BestPeer() methods
+func TestSelectPeers(t *testing.T) {
+	best := newTestContact(t, "memory://reputation-best") // Init best peer
+
+	best.Reputation, best.LastPingTime = 50, time.Now() // Set reputation
+
+	stale := newTestContact(t, "memory://reputation-stale") // Init stale peer
+
+	stale.Reputation, stale.LastPingTime = 50, time.Now().Add(-3*DefaultReputationPolicy.HalfLife) // Set seen long ago
+
+	average := newTestContact(t, "memory://reputation-average") // Init average peer
+
+	average.Reputation, average.LastPingTime = 20, time.Now() // Set reputation
+
+	db := &NodeDatabase{NetworkAlias: "GoP2P_TestNet", Nodes: &[]node.Node{*stale, *average, *best}} // Init database
+
+	selected := db.SelectPeers(2) // Select peers
+
+	if len(selected) != 2 || selected[0].NodeID != best.NodeID || selected[1].NodeID != average.NodeID { // Check peers ranked by reputation, recency
+		t.Errorf("invalid selection %v", selected) // Log found error
+		t.FailNow()                                // Panic
+	}
+
+	db.Ban(best.NodeID, time.Minute) // Ban best peer
+
+	peer, err := db.BestPeer(average) // Select best peer
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if peer.NodeID != stale.NodeID { // Check banned, excluded peers were skipped
+		t.Errorf("invalid best peer %s", peer.NodeID) // Log found error
+		t.FailNow()                                   // Panic
+	}
+}
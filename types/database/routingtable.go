@@ -0,0 +1,445 @@
+package database
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+const (
+	// KeySize - size of routing keys in bytes
+	KeySize = 32
+
+	// DefaultBucketSize - default number of contacts kept per k-bucket (k)
+	DefaultBucketSize = 20
+)
+
+var (
+	// ErrInvalidNodeKey - error returned when decoding a routing key of invalid length
+	ErrInvalidNodeKey = errors.New("invalid node key")
+)
+
+// NodeKey - fixed-size routing key of a node (Sha3 hash of its NodeID, or of its address for nodes without an identity)
+type NodeKey [KeySize]byte
+
+// KBucket - contacts sharing a common key prefix of given length with the local node, least recently seen first
+type KBucket struct {
+	Nodes []node.Node `json:"nodes"` // Nodes - contacts, least recently seen first
+
+	Replacements []node.Node `json:"replacements"` // Replacements - contacts seen while bucket was full, promoted when a contact is removed
+
+	LastRefreshed time.Time `json:"refreshed"` // LastRefreshed - time of last lookup in bucket's key range
+}
+
+// RoutingTable - Kademlia-style XOR-metric routing table, keeping at most K contacts per common prefix length with the local node
+type RoutingTable struct {
+	Self NodeKey `json:"self"` // Self - routing key of local node
+
+	Local *node.Node `json:"local"` // Local - local node contact, sent to peers during lookups
+
+	K int `json:"k"` // K - maximum number of contacts per bucket
+
+	Buckets map[int]*KBucket `json:"buckets"` // Buckets - non-empty buckets by common prefix length with Self
+
+	mutex sync.Mutex // mutex - guards Buckets
+}
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// NewNodeKey - derive routing key of given node
+func NewNodeKey(destNode *node.Node) NodeKey {
+	if destNode.NodeID != "" { // Check node has identity
+		return KeyFromString(destNode.NodeID) // Return NodeID key
+	}
+
+	return KeyFromString(destNode.Address) // Return address key
+}
+
+// KeyFromString - derive routing key from given string
+func KeyFromString(s string) NodeKey {
+	key := NodeKey{} // Init key buffer
+
+	hash, _ := hex.DecodeString(common.Sha3([]byte(s))) // Hash string
+
+	copy(key[:], hash) // Set key
+
+	return key // Return key
+}
+
+// ParseNodeKey - decode hex-encoded routing key
+func ParseNodeKey(s string) (NodeKey, error) {
+	key := NodeKey{} // Init key buffer
+
+	decoded, err := hex.DecodeString(s) // Decode key
+
+	if err != nil || len(decoded) != KeySize { // Check for invalid key
+		return key, ErrInvalidNodeKey // Return error
+	}
+
+	copy(key[:], decoded) // Set key
+
+	return key, nil // Return key
+}
+
+// String - hex-encode key
+func (key NodeKey) String() string {
+	return hex.EncodeToString(key[:]) // Return encoded key
+}
+
+// MarshalText - implement encoding.TextMarshaler
+func (key NodeKey) MarshalText() ([]byte, error) {
+	return []byte(key.String()), nil // Return encoded key
+}
+
+// UnmarshalText - implement encoding.TextUnmarshaler
+func (key *NodeKey) UnmarshalText(b []byte) error {
+	decoded, err := ParseNodeKey(string(b)) // Decode key
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	*key = decoded // Set key
+
+	return nil // No error occurred, return nil
+}
+
+// Distance - fetch XOR distance between keys
+func (key NodeKey) Distance(other NodeKey) NodeKey {
+	distance := NodeKey{} // Init distance buffer
+
+	for x := range key { // Iterate through bytes
+		distance[x] = key[x] ^ other[x] // XOR bytes
+	}
+
+	return distance // Return distance
+}
+
+// CommonPrefixLength - fetch number of leading bits shared by keys
+func (key NodeKey) CommonPrefixLength(other NodeKey) int {
+	for x := range key { // Iterate through bytes
+		if diff := key[x] ^ other[x]; diff != 0 { // Check for differing byte
+			length := x * 8 // Count shared bytes
+
+			for diff&0x80 == 0 { // Count shared bits
+				length++ // Increment length
+
+				diff <<= 1 // Shift to next bit
+			}
+
+			return length // Return length
+		}
+	}
+
+	return KeySize * 8 // Keys are equal
+}
+
+// Closer - check key a is closer to key than key b
+func (key NodeKey) Closer(a NodeKey, b NodeKey) bool {
+	distanceA, distanceB := key.Distance(a), key.Distance(b) // Fetch distances
+
+	return bytes.Compare(distanceA[:], distanceB[:]) < 0 // Compare distances
+}
+
+// NewRoutingTable - initialize empty routing table for given local node, keeping k contacts per bucket (DefaultBucketSize if k is 0)
+func NewRoutingTable(localNode *node.Node, k int) (*RoutingTable, error) {
+	if localNode == nil || (localNode.NodeID == "" && localNode.Address == "") { // Check for invalid local node
+		return nil, errors.New("invalid local node") // Return error
+	}
+
+	if k <= 0 { // Check for default bucket size
+		k = DefaultBucketSize // Set default bucket size
+	}
+
+	return &RoutingTable{Self: NewNodeKey(localNode), K: k, Buckets: make(map[int]*KBucket)}, nil // Return initialized table
+}
+
+// AddNode - insert or refresh contact, returning true if it is in the table. Contacts seen while their bucket is full are kept as replacements.
+func (table *RoutingTable) AddNode(destNode *node.Node) (bool, error) {
+	if destNode.NodeID != "" { // Check node has identity
+		err := destNode.VerifyIdentity() // Verify NodeID matches public key
+
+		if err != nil { // Check for invalid identity
+			return false, err // Return found error
+		}
+	} else if destNode.Address == "" { // Check for nil address
+		return false, errors.New("invalid node") // Return error
+	}
+
+	key := NewNodeKey(destNode) // Fetch key
+
+	if key == table.Self { // Check for local node
+		return false, nil // Local node isn't a contact
+	}
+
+	contact := node.Node{NodeID: destNode.NodeID, PublicKey: destNode.PublicKey, Address: destNode.Address, Reputation: destNode.Reputation, LastPingTime: destNode.LastPingTime, IsBootstrap: destNode.IsBootstrap, Reachability: destNode.Reachability, Addresses: destNode.Addresses} // Strip environment
+
+	table.mutex.Lock() // Lock table
+
+	defer table.mutex.Unlock() // Unlock table
+
+	bucket := table.bucket(table.Self.CommonPrefixLength(key), true) // Fetch bucket
+
+	if index := indexOfKey(bucket.Nodes, key); index >= 0 { // Check contact already in bucket
+		bucket.Nodes = append(append(bucket.Nodes[:index], bucket.Nodes[index+1:]...), contact) // Move to most recently seen
+
+		return true, nil // Contact refreshed
+	}
+
+	if len(bucket.Nodes) < table.K { // Check bucket has room
+		bucket.Nodes = append(bucket.Nodes, contact) // Append contact
+
+		return true, nil // Contact added
+	}
+
+	if index := indexOfKey(bucket.Replacements, key); index >= 0 { // Check contact already a replacement
+		bucket.Replacements = append(bucket.Replacements[:index], bucket.Replacements[index+1:]...) // Remove stale replacement
+	} else if len(bucket.Replacements) >= table.K { // Check replacements are full
+		bucket.Replacements = bucket.Replacements[1:] // Drop least recently seen replacement
+	}
+
+	bucket.Replacements = append(bucket.Replacements, contact) // Append replacement
+
+	return false, nil // Bucket full, prefer long-lived contacts
+}
+
+// RemoveNode - remove contact with given key, promoting most recently seen replacement. Returns false if contact wasn't found.
+func (table *RoutingTable) RemoveNode(key NodeKey) bool {
+	table.mutex.Lock() // Lock table
+
+	defer table.mutex.Unlock() // Unlock table
+
+	cpl := table.Self.CommonPrefixLength(key) // Fetch bucket index
+
+	bucket := table.bucket(cpl, false) // Fetch bucket
+
+	if bucket == nil { // Check for empty bucket
+		return false // Not found
+	}
+
+	if index := indexOfKey(bucket.Replacements, key); index >= 0 { // Check for replacement
+		bucket.Replacements = append(bucket.Replacements[:index], bucket.Replacements[index+1:]...) // Remove replacement
+	}
+
+	index := indexOfKey(bucket.Nodes, key) // Fetch contact index
+
+	if index < 0 { // Check contact not found
+		return false // Not found
+	}
+
+	bucket.Nodes = append(bucket.Nodes[:index], bucket.Nodes[index+1:]...) // Remove contact
+
+	if len(bucket.Replacements) > 0 { // Check for replacements
+		bucket.Nodes = append(bucket.Nodes, bucket.Replacements[len(bucket.Replacements)-1]) // Promote replacement
+
+		bucket.Replacements = bucket.Replacements[:len(bucket.Replacements)-1] // Remove promoted replacement
+	}
+
+	if len(bucket.Nodes) == 0 && bucket.LastRefreshed.IsZero() { // Check bucket is empty
+		delete(table.Buckets, cpl) // Remove bucket
+	}
+
+	return true // Removed
+}
+
+// UpdateNode - replace contact sharing given node's key with given node (e.g. to update its reputation) without changing its position. Returns false if contact wasn't found.
+func (table *RoutingTable) UpdateNode(destNode *node.Node) bool {
+	key := NewNodeKey(destNode) // Fetch key
+
+	contact := node.Node{NodeID: destNode.NodeID, PublicKey: destNode.PublicKey, Address: destNode.Address, Reputation: destNode.Reputation, LastPingTime: destNode.LastPingTime, IsBootstrap: destNode.IsBootstrap, Reachability: destNode.Reachability, Addresses: destNode.Addresses} // Strip environment
+
+	table.mutex.Lock() // Lock table
+
+	defer table.mutex.Unlock() // Unlock table
+
+	bucket := table.bucket(table.Self.CommonPrefixLength(key), false) // Fetch bucket
+
+	if bucket == nil { // Check for empty bucket
+		return false // Not found
+	}
+
+	if index := indexOfKey(bucket.Nodes, key); index >= 0 { // Check for contact
+		bucket.Nodes[index] = contact // Update contact
+
+		return true // Updated
+	}
+
+	if index := indexOfKey(bucket.Replacements, key); index >= 0 { // Check for replacement
+		bucket.Replacements[index] = contact // Update replacement
+
+		return true // Updated
+	}
+
+	return false // Not found
+}
+
+// Closest - fetch at most count contacts closest to given key, closest first
+func (table *RoutingTable) Closest(target NodeKey, count int) []node.Node {
+	return closestNodes(table.Nodes(), target, count) // Return closest contacts
+}
+
+// Nodes - fetch all contacts, nearest bucket first
+func (table *RoutingTable) Nodes() []node.Node {
+	table.mutex.Lock() // Lock table
+
+	defer table.mutex.Unlock() // Unlock table
+
+	nodes := []node.Node{} // Init contacts buffer
+
+	for x := KeySize * 8; x >= 0; x-- { // Iterate through buckets
+		if bucket, found := table.Buckets[x]; found { // Check bucket exists
+			nodes = append(nodes, bucket.Nodes...) // Append contacts
+		}
+	}
+
+	return nodes // Return contacts
+}
+
+// Len - fetch number of contacts in table
+func (table *RoutingTable) Len() int {
+	table.mutex.Lock() // Lock table
+
+	defer table.mutex.Unlock() // Unlock table
+
+	length := 0 // Init length buffer
+
+	for _, bucket := range table.Buckets { // Iterate through buckets
+		length += len(bucket.Nodes) // Add contacts
+	}
+
+	return length // Return length
+}
+
+// MarkRefreshed - set refresh time of bucket containing given key
+func (table *RoutingTable) MarkRefreshed(key NodeKey) {
+	table.mutex.Lock() // Lock table
+
+	defer table.mutex.Unlock() // Unlock table
+
+	if key == table.Self { // Check for local key
+		return // Local key isn't in any bucket
+	}
+
+	table.bucket(table.Self.CommonPrefixLength(key), true).LastRefreshed = time.Now() // Set refreshed
+}
+
+// StaleBuckets - fetch indexes of buckets that haven't been looked up within given duration, up to one past the deepest non-empty bucket
+func (table *RoutingTable) StaleBuckets(interval time.Duration) []int {
+	table.mutex.Lock() // Lock table
+
+	defer table.mutex.Unlock() // Unlock table
+
+	deepest := 0 // Init deepest bucket buffer
+
+	for x, bucket := range table.Buckets { // Iterate through buckets
+		if len(bucket.Nodes) > 0 && x+1 > deepest { // Check for deeper bucket
+			deepest = x + 1 // Set deepest
+		}
+	}
+
+	if deepest >= KeySize*8 { // Check for out of range bucket
+		deepest = KeySize*8 - 1 // Set last bucket
+	}
+
+	stale := []int{} // Init stale buffer
+
+	for x := 0; x <= deepest; x++ { // Iterate through buckets
+		if bucket, found := table.Buckets[x]; !found || time.Since(bucket.LastRefreshed) > interval { // Check stale
+			stale = append(stale, x) // Append stale bucket
+		}
+	}
+
+	return stale // Return stale buckets
+}
+
+// RandomKey - generate random key sharing a common prefix of exactly given length with the local node
+func (table *RoutingTable) RandomKey(cpl int) (NodeKey, error) {
+	if cpl < 0 || cpl >= KeySize*8 { // Check for invalid prefix length
+		return NodeKey{}, fmt.Errorf("invalid bucket %d", cpl) // Return error
+	}
+
+	key := NodeKey{} // Init key buffer
+
+	_, err := rand.Read(key[:]) // Generate random key
+
+	if err != nil { // Check for errors
+		return NodeKey{}, err // Return found error
+	}
+
+	for x := 0; x <= cpl; x++ { // Iterate through prefix bits
+		mask := byte(0x80 >> uint(x%8)) // Init bit mask
+
+		if x == cpl { // Check for first differing bit
+			key[x/8] = key[x/8]&^mask | ^table.Self[x/8]&mask // Flip local bit
+		} else {
+			key[x/8] = key[x/8]&^mask | table.Self[x/8]&mask // Copy local bit
+		}
+	}
+
+	return key, nil // Return key
+}
+
+/*
+	END EXPORTED METHODS
+*/
+
+/*
+	BEGIN INTERNAL METHODS:
+*/
+
+// bucket - fetch bucket with given index, initializing it if create is set (table.mutex must be held)
+func (table *RoutingTable) bucket(cpl int, create bool) *KBucket {
+	if table.Buckets == nil { // Check for nil buckets
+		table.Buckets = make(map[int]*KBucket) // Init buckets
+	}
+
+	bucket, found := table.Buckets[cpl] // Fetch bucket
+
+	if !found && create { // Check for new bucket
+		bucket = &KBucket{} // Init bucket
+
+		table.Buckets[cpl] = bucket // Set bucket
+	}
+
+	return bucket // Return bucket
+}
+
+// indexOfKey - fetch index of node with given key in given nodes (-1 if not found)
+func indexOfKey(nodes []node.Node, key NodeKey) int {
+	for x := range nodes { // Iterate through nodes
+		if NewNodeKey(&nodes[x]) == key { // Check for match
+			return x // Return index
+		}
+	}
+
+	return -1 // Not found
+}
+
+// closestNodes - sort copy of given nodes by distance to target, returning at most count nodes
+func closestNodes(nodes []node.Node, target NodeKey, count int) []node.Node {
+	sorted := append([]node.Node{}, nodes...) // Copy nodes
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return target.Closer(NewNodeKey(&sorted[i]), NewNodeKey(&sorted[j])) // Compare distances
+	}) // Sort nodes
+
+	if count >= 0 && len(sorted) > count { // Check for too many nodes
+		sorted = sorted[:count] // Truncate nodes
+	}
+
+	return sorted // Return sorted nodes
+}
+
+/*
+	END INTERNAL METHODS
+*/
@@ -0,0 +1,150 @@
+package database
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+// TestNodeKey - test functionality of NodeKey Distance(), CommonPrefixLength(), text encoding methods
+func TestNodeKey(t *testing.T) {
+	a, b := NodeKey{}, NodeKey{} // Init keys
+
+	b[1] = 0x10 // Set 12th bit
+
+	if a.CommonPrefixLength(b) != 11 || a.CommonPrefixLength(a) != KeySize*8 { // Check for invalid prefix length
+		t.Errorf("invalid common prefix length %d", a.CommonPrefixLength(b)) // Log found error
+		t.FailNow()                                                          // Panic
+	}
+
+	if a.Distance(b) != b || !a.Closer(a, b) || a.Closer(b, a) { // Check for invalid distance
+		t.Errorf("invalid distance %s", a.Distance(b).String()) // Log found error
+		t.FailNow()                                             // Panic
+	}
+
+	encoded, err := b.MarshalText() // Encode key
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	decoded := NodeKey{} // Init key buffer
+
+	if err = decoded.UnmarshalText(encoded); err != nil || decoded != b { // Decode key
+		t.Errorf("invalid decoded key %s (%v)", decoded.String(), err) // Log found error
+		t.FailNow()                                                    // Panic
+	}
+
+	if _, err = ParseNodeKey("test"); err == nil { // Decode invalid key
+		t.Errorf("expected invalid key to be rejected") // Log found error
+		t.FailNow()                                     // Panic
+	}
+}
+
+// TestRoutingTable - test functionality of RoutingTable AddNode(), RemoveNode(), Closest(), RandomKey() methods
+func TestRoutingTable(t *testing.T) {
+	localNode := newTestContact(t, "memory://routing-local") // Init local node
+
+	table, err := NewRoutingTable(localNode, 2) // Init table with buckets of 2 contacts
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if added, _ := table.AddNode(localNode); added { // Add local node
+		t.Errorf("expected local node not to be added") // Log found error
+		t.FailNow()                                     // Panic
+	}
+
+	farthest := []*node.Node{} // Init farthest bucket contacts buffer
+
+	for len(farthest) < 4 { // Generate contacts in farthest bucket
+		contact := newTestContact(t, "memory://routing-"+strconv.Itoa(len(farthest))) // Init contact
+
+		if table.Self.CommonPrefixLength(NewNodeKey(contact)) == 0 { // Check contact is in farthest bucket
+			farthest = append(farthest, contact) // Append contact
+		}
+	}
+
+	for x, contact := range farthest { // Iterate through contacts
+		added, err := table.AddNode(contact) // Add contact
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if added != (x < 2) { // Check contacts beyond bucket size weren't added
+			t.Errorf("invalid added state of contact %d", x) // Log found error
+			t.FailNow()                                      // Panic
+		}
+	}
+
+	if table.Len() != 2 || len(table.Buckets[0].Replacements) != 2 { // Check bucket is full
+		t.Errorf("invalid table size %d", table.Len()) // Log found error
+		t.FailNow()                                    // Panic
+	}
+
+	if !table.RemoveNode(NewNodeKey(farthest[0])) { // Remove contact
+		t.Errorf("expected contact to be removed") // Log found error
+		t.FailNow()                                // Panic
+	}
+
+	if table.Len() != 2 || indexOfKey(table.Buckets[0].Nodes, NewNodeKey(farthest[3])) < 0 { // Check replacement was promoted
+		t.Errorf("expected most recent replacement to be promoted") // Log found error
+		t.FailNow()                                                 // Panic
+	}
+
+	closest := table.Closest(NewNodeKey(farthest[1]), 1) // Fetch closest contact
+
+	if len(closest) != 1 || closest[0].NodeID != farthest[1].NodeID { // Check for invalid closest contact
+		t.Errorf("invalid closest contact") // Log found error
+		t.FailNow()                         // Panic
+	}
+
+	for _, cpl := range []int{0, 7, 100, KeySize*8 - 1} { // Iterate through buckets
+		key, err := table.RandomKey(cpl) // Generate key in bucket
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if table.Self.CommonPrefixLength(key) != cpl { // Check key is in bucket
+			t.Errorf("key %s not in bucket %d", key.String(), cpl) // Log found error
+			t.FailNow()                                            // Panic
+		}
+	}
+
+	if stale := table.StaleBuckets(time.Hour); len(stale) != 2 { // Check buckets 0, 1 are stale
+		t.Errorf("invalid stale buckets %v", stale) // Log found error
+		t.FailNow()                                 // Panic
+	}
+
+	table.MarkRefreshed(NewNodeKey(farthest[1])) // Refresh farthest bucket
+
+	if stale := table.StaleBuckets(time.Hour); len(stale) != 1 || stale[0] != 1 { // Check bucket 0 was refreshed
+		t.Errorf("invalid stale buckets %v", stale) // Log found error
+		t.FailNow()                                 // Panic
+	}
+}
+
+// newTestContact - initialize node with new identity at given address
+func newTestContact(t *testing.T, address string) *node.Node {
+	identity, err := node.NewIdentity() // Init identity
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	contact := &node.Node{Address: address} // Init contact
+
+	contact.SetIdentity(identity) // Set identity
+
+	return contact // Return contact
+}
@@ -109,6 +109,25 @@ func (environment *Environment) AddVariable(variable *Variable, replaceExisting
 	return environment.addVariable(variable)
 }
 
+// RemoveType - remove all variables with matching type
+func (environment *Environment) RemoveType(variableType string) error {
+	remaining := []*Variable{} // Init remaining variables buffer
+
+	for _, variable := range environment.EnvironmentVariables { // Iterate through variables
+		if variable.VariableType != variableType { // Check for non-matching type
+			remaining = append(remaining, variable) // Keep variable
+		}
+	}
+
+	if len(remaining) == len(environment.EnvironmentVariables) { // Check nothing was removed
+		return errors.New("no matching variable found") // Return error
+	}
+
+	environment.EnvironmentVariables = remaining // Set variables
+
+	return nil // No error occurred, return nil
+}
+
 // LogEnvironment - serialize and print contents of entire environment
 func (environment *Environment) LogEnvironment() error {
 	marshaledVal, err := json.MarshalIndent(*environment, "", "  ") // Marshal environment
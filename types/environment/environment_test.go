@@ -60,6 +60,44 @@ func TestQueryType(t *testing.T) {
 	t.Logf("found variable %s", foundVariable.VariableIdentifier) // Log success
 }
 
+// TestRemoveType - test functionality of RemoveType() function
+func TestRemoveType(t *testing.T) {
+	env, err := NewEnvironment() // Initialize new environment
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	for x := 0; x < 2; x++ { // Add variables
+		variable, err := NewVariable("test", x) // Create new variable
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		env.AddVariable(variable, false) // Add variable to environment
+	}
+
+	err = env.RemoveType("test") // Remove variables
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if _, err = env.QueryType("test"); err == nil || len(env.EnvironmentVariables) != 1 { // Check variables were removed
+		t.Errorf("expected variables to be removed") // Log found error
+		t.FailNow()                                  // Panic
+	}
+
+	if err = env.RemoveType("test"); err == nil { // Remove missing variables
+		t.Errorf("expected removing missing type to fail") // Log found error
+		t.FailNow()                                        // Panic
+	}
+}
+
 // TestQueryValue - test functionality of QueryValue() function
 func TestQueryValue(t *testing.T) {
 	env, err := NewEnvironment() // Initialize new environment
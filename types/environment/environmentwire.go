@@ -0,0 +1,69 @@
+package environment
+
+import "github.com/dowlandaiello/GoP2P/internal/rpc/proto/wire"
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// ToWire - convert environment to protobuf wire type
+func (environment *Environment) ToWire() *wire.Environment {
+	if environment == nil { // Check for nil environment
+		return nil // Return nil
+	}
+
+	wireEnvironment := &wire.Environment{} // Init wire environment
+
+	for _, variable := range environment.EnvironmentVariables { // Iterate through variables
+		wireEnvironment.Variables = append(wireEnvironment.Variables, variable.ToWire()) // Append variable
+	}
+
+	return wireEnvironment // Return wire environment
+}
+
+// EnvironmentFromWire - convert protobuf wire type to environment
+func EnvironmentFromWire(wireEnvironment *wire.Environment) *Environment {
+	if wireEnvironment == nil { // Check for nil environment
+		return nil // Return nil
+	}
+
+	environment := &Environment{EnvironmentVariables: []*Variable{}} // Init environment
+
+	for _, wireVariable := range wireEnvironment.Variables { // Iterate through variables
+		environment.EnvironmentVariables = append(environment.EnvironmentVariables, VariableFromWire(wireVariable)) // Append variable
+	}
+
+	return environment // Return environment
+}
+
+// ToWire - convert variable to protobuf wire type
+func (variable *Variable) ToWire() *wire.Variable {
+	if variable == nil { // Check for nil variable
+		return nil // Return nil
+	}
+
+	return &wire.Variable{
+		Type:       variable.VariableType,           // Set type
+		Identifier: variable.VariableIdentifier,     // Set identifier
+		Data:       variable.VariableData,           // Set data
+		Serialized: variable.VariableSerializedData, // Set serialized data
+	} // Return wire variable
+}
+
+// VariableFromWire - convert protobuf wire type to variable
+func VariableFromWire(wireVariable *wire.Variable) *Variable {
+	if wireVariable == nil { // Check for nil variable
+		return nil // Return nil
+	}
+
+	return &Variable{
+		VariableType:           wireVariable.Type,       // Set type
+		VariableIdentifier:     wireVariable.Identifier, // Set identifier
+		VariableData:           wireVariable.Data,       // Set data
+		VariableSerializedData: wireVariable.Serialized, // Set serialized data
+	} // Return variable
+}
+
+/*
+	END EXPORTED METHODS
+*/
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/types/database"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+var (
+	databaseMutex = &database.MemoryMutex // databaseMutex - serializes updates of databases, records stored in node environments
+)
+
+/* BEGIN INTERNAL METHODS */
+
+// handleFindNode - respond to lookup with contacts of requested network database closest to target (and records of requested key), adding sender to its routing table if it is the authenticated peer. Peers claiming another peer's identity are penalized (lookups are still answered).
+func handleFindNode(node *node.Node, conn net.Conn, codec common.Codec, payload []byte) ([]byte, error) {
+	request, err := database.DecodeFindNodeRequest(codec, payload) // Decode request
+
+	if err != nil { // Check for errors
+		return nil, &malformedError{err: err} // Return found error
+	}
+
+	impersonator := "" // Init impersonating peer buffer
+
+	if !verifySender(conn, request.Sender) { // Check sender isn't authenticated peer
+		if request.Sender != nil && request.Sender.NodeID != "" { // Check sender claimed an identity
+			impersonator = connectionPeerID(conn) // Peer claimed another peer's identity
+		}
+
+		request.Sender = nil // Don't learn unverified sender
+	}
+
+	databaseMutex.Lock() // Lock databases
+
+	defer databaseMutex.Unlock() // Unlock databases
+
+	db, err := database.ReadDatabaseFromMemory(node.Environment, request.Network) // Read network database
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if impersonator != "" { // Check for impersonation
+		if _, err = db.RecordEvent(impersonator, database.ReputationViolation); err == nil { // Penalize known peer
+			db.WriteToMemory(node.Environment) // Write db to memory
+		}
+	}
+
+	response, err := db.HandleFindNode(request) // Fetch closest contacts
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if request.Key != "" { // Check for value lookup
+		response.Record, _ = database.LoadRecord(node.Environment, request.Key) // Fetch value record
+
+		response.Providers = database.LoadProviders(node.Environment, request.Key) // Fetch providers
+	}
+
+	if db.RoutingTable != nil && request.Sender != nil { // Check sender was added to routing table
+		err = db.WriteToMemory(node.Environment) // Write db to memory
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+	}
+
+	common.Printf("\n-- DHT -- returned %d contacts closest to %s to %s", len(response.Nodes), request.Target.String(), conn.RemoteAddr().String()) // Log lookup
+
+	return response.Encode(codec) // Return encoded response
+}
+
+// handleStoreRecord - validate received record, writing it to node environment. Peers sending invalid records are penalized.
+func handleStoreRecord(node *node.Node, conn net.Conn, codec common.Codec, payload []byte) ([]byte, error) {
+	request, err := database.DecodeStoreRequest(codec, payload) // Decode request
+
+	if err != nil { // Check for errors
+		return nil, &malformedError{err: err} // Return found error
+	}
+
+	if request.Record == nil { // Check for missing record
+		return nil, &malformedError{err: errors.New("request contains no record")} // Return error
+	}
+
+	err = request.Record.Validate() // Validate record
+
+	if err != nil { // Check for errors
+		return nil, &malformedError{err: err} // Return found error
+	}
+
+	if node.Environment == nil { // Check for nil environment
+		return nil, errors.New("node has no environment to store record in") // Return error
+	}
+
+	databaseMutex.Lock() // Lock records
+
+	defer databaseMutex.Unlock() // Unlock records
+
+	err = database.StoreRecord(node.Environment, request.Record) // Store record
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	common.Printf("\n-- DHT -- stored record %s from %s", request.Record.Key, conn.RemoteAddr().String()) // Log store
+
+	return nil, nil // No error occurred, return nil
+}
+
+// verifySender - check sender of request is the peer authenticated on given connection
+func verifySender(conn net.Conn, sender *node.Node) bool {
+	if sender == nil || sender.NodeID == "" { // Check for anonymous sender
+		return false // Can't verify sender
+	}
+
+	peerID := connectionPeerID(conn) // Fetch verified peer ID
+
+	return peerID != "" && peerID == sender.NodeID // Check sender is peer
+}
+
+// connectionPeerID - fetch NodeID of peer authenticated on given connection ("" if the connection isn't authenticated)
+func connectionPeerID(conn net.Conn) string {
+	tlsConn, ok := conn.(*tls.Conn) // Fetch TLS connection
+
+	if !ok { // Check for unauthenticated connection
+		return "" // No verified peer
+	}
+
+	peerID, err := common.ConnectionPeerID(tlsConn) // Fetch verified peer ID
+
+	if err != nil { // Check for errors
+		return "" // No verified peer
+	}
+
+	return peerID // Return verified peer ID
+}
+
+/* END INTERNAL METHODS */
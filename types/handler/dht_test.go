@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/dowlandaiello/GoP2P/types/database"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+// TestHandleFindNode - test functionality of iterative FindNode() lookups over a chain of handlers that each only know the next node
+func TestHandleFindNode(t *testing.T) {
+	nodes := []*node.Node{} // Init nodes buffer
+
+	for x := 0; x < 6; x++ { // Start handlers
+		nodes = append(nodes, startMemoryHandler(t, "memory://dht-test-"+strconv.Itoa(x))) // Append node
+	}
+
+	for x, testNode := range nodes { // Iterate through nodes
+		db := &database.NodeDatabase{NetworkAlias: "GoP2P_TestNet"} // Init database
+
+		err := db.EnableRoutingTable(testNode, 2) // Enable DHT mode
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if x < len(nodes)-1 { // Check node has next node
+			_, err = db.RoutingTable.AddNode(nodes[x+1]) // Add next node
+
+			if err != nil { // Check for errors
+				t.Errorf(err.Error()) // Log found error
+				t.FailNow()           // Panic
+			}
+		}
+
+		err = db.WriteToMemory(testNode.Environment) // Write db to memory
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+	}
+
+	db, err := database.ReadDatabaseFromMemory(nodes[0].Environment, "GoP2P_TestNet") // Read first node database
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	db.RoutingTable.K = database.DefaultBucketSize // Keep every contact found during lookup
+
+	unresponsive := &node.Node{Address: "memory://dht-test-missing"} // Init unresponsive contact
+
+	_, err = db.RoutingTable.AddNode(unresponsive) // Add unresponsive contact
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	target := nodes[len(nodes)-1] // Fetch last node
+
+	found, err := db.FindNode(database.NewNodeKey(target), 3000) // Look up last node
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if len(found) == 0 || found[0].NodeID != target.NodeID { // Check last node was found
+		t.Errorf("expected lookup to find last node") // Log found error
+		t.FailNow()                                   // Panic
+	}
+
+	if _, err = db.QueryForAddress(unresponsive.Address); err == nil { // Check unresponsive contact was removed
+		t.Errorf("expected unresponsive contact to be removed") // Log found error
+		t.FailNow()                                             // Panic
+	}
+
+	if db.RoutingTable.Len() != len(nodes)-1 { // Check every node was added to routing table
+		t.Errorf("invalid routing table size %d", db.RoutingTable.Len()) // Log found error
+		t.FailNow()                                                      // Panic
+	}
+}
+
+// TestDHTStore - test functionality of Put(), Get(), Provide(), FindProviders() methods over handlers that each know every other node
+func TestDHTStore(t *testing.T) {
+	nodes := []*node.Node{} // Init nodes buffer
+
+	for x := 0; x < 5; x++ { // Start handlers
+		nodes = append(nodes, startMemoryHandler(t, "memory://dht-store-test-"+strconv.Itoa(x))) // Append node
+	}
+
+	dbs := []*database.NodeDatabase{} // Init databases buffer
+
+	for _, testNode := range nodes { // Iterate through nodes
+		db := &database.NodeDatabase{NetworkAlias: "GoP2P_TestNet"} // Init database
+
+		err := db.EnableRoutingTable(testNode, 2) // Enable DHT mode
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		for _, contact := range nodes { // Iterate through contacts
+			db.RoutingTable.AddNode(contact) // Add contact
+		}
+
+		err = db.WriteToMemory(testNode.Environment) // Write db to memory
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		dbs = append(dbs, db) // Append database
+	}
+
+	err := dbs[0].Put(nodes[0], "testKey", []byte("testValue"), 3000) // Store value
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	stored := 0 // Init stored counter
+
+	for _, testNode := range nodes[1:] { // Iterate through remote nodes
+		if _, err := database.LoadRecord(testNode.Environment, "testKey"); err == nil { // Check node stored record
+			stored++ // Increment stored
+		}
+	}
+
+	if stored != 2 { // Check record was stored on k closest nodes
+		t.Errorf("expected record to be stored on 2 nodes, found %d", stored) // Log found error
+		t.FailNow()                                                           // Panic
+	}
+
+	value, err := dbs[4].Get(nodes[4], "testKey", 3000) // Fetch value
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if string(value) != "testValue" { // Check for invalid value
+		t.Errorf("invalid value %s", string(value)) // Log found error
+		t.FailNow()                                 // Panic
+	}
+
+	if _, err = dbs[4].Get(nodes[4], "missingKey", 3000); err != database.ErrRecordNotFound { // Fetch missing value
+		t.Errorf("expected missing key not to be found (%v)", err) // Log found error
+		t.FailNow()                                                // Panic
+	}
+
+	err = dbs[1].Provide(nodes[1], "testObject", 3000) // Advertise provider
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	providers, err := dbs[3].FindProviders(nodes[3], "testObject", 3000) // Fetch providers
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if len(providers) != 1 || providers[0].NodeID != nodes[1].NodeID { // Check for invalid providers
+		t.Errorf("invalid providers %v", providers) // Log found error
+		t.FailNow()                                 // Panic
+	}
+
+	err = dbs[0].Republish(nodes[0], 3000) // Republish records
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+}
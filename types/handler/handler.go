@@ -1,12 +1,17 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/dowlandaiello/GoP2P/common"
 	"github.com/dowlandaiello/GoP2P/internal/proto"
@@ -17,35 +22,302 @@ import (
 	"github.com/fatih/color"
 )
 
+var (
+	// DefaultShutdownTimeout - default duration a shutting down handler waits on in-flight connections
+	DefaultShutdownTimeout = 30 * time.Second
+
+	// ErrHandlerClosed - error returned by Handler.Start once handler has been shut down
+	ErrHandlerClosed = errors.New("handler closed")
+
+	// ErrHandlerRunning - error returned when starting a handler that is already running
+	ErrHandlerRunning = errors.New("handler already running")
+)
+
+// Service - background routine run alongside a handler, returning once given context is cancelled
+type Service func(ctx context.Context)
+
+// Handler - node request handler accepting connections on one or more transport addresses until shut down
+type Handler struct {
+	Node *node.Node // Node - node requests are handled for
+
+	Addresses []string // Addresses - transport addresses to listen on (e.g. ":3000", "unix:///tmp/node.sock:3000")
+
+	StatePath string // StatePath - directory node, environment state is flushed to on shutdown (not flushed if empty)
+
+	Limits Limits // Limits - resource limits applied to peers (read on start)
+
+	listeners []*net.Listener // listeners - open listeners
+
+	services []Service // services - background routines run while handler is running
+
+	run *handlerRun // run - state of current run
+
+	running bool // running - handler is accepting connections
+
+	shuttingDown bool // shuttingDown - handler is draining connections
+
+	mutex sync.Mutex // mutex - guards listeners, services, run, running, shuttingDown
+}
+
+// handlerRun - state of a single run (Start) of a handler
+type handlerRun struct {
+	limits Limits // limits - resource limits
+
+	conns map[net.Conn]string // conns - accepted connections, with their remote IPs
+
+	peerConns map[string]int // peerConns - number of accepted connections by remote IP
+
+	active sync.WaitGroup // active - connections being handled
+
+	workers *workerPool // workers - request workers
+
+	limiter *rateLimiter // limiter - request rate limiter
+
+	services sync.WaitGroup // services - running background services
+
+	stopServices context.CancelFunc // stopServices - cancels context of background services
+}
+
 /* BEGIN EXPORTED METHODS */
 
-// StartHandler - attempt to accept and handle requests on given listener
+// NewHandler - initialize handler for given node, listening on given transport addresses and flushing state to given path (if set) on shutdown
+func NewHandler(node *node.Node, statePath string, addresses ...string) (*Handler, error) {
+	if reflect.ValueOf(node).IsNil() || node.Address == "" || len(addresses) == 0 { // Check for nil parameters
+		return &Handler{}, errors.New("invalid parameters") // Return error
+	}
+
+	return &Handler{Node: node, Addresses: addresses, StatePath: statePath, Limits: DefaultLimits}, nil // Return initialized handler
+}
+
+// Listen - listen on handler addresses (if not already listening), without accepting connections
+func (handler *Handler) Listen() error {
+	handler.mutex.Lock() // Lock handler
+
+	defer handler.mutex.Unlock() // Unlock handler
+
+	if len(handler.listeners) != 0 { // Check already listening
+		return nil // Nothing to do
+	}
+
+	for _, address := range handler.Addresses { // Iterate through addresses
+		ln, err := handler.Node.Listen(address) // Listen on address
+
+		if err != nil { // Check for errors
+			handler.closeListeners() // Close opened listeners
+
+			return err // Return found error
+		}
+
+		handler.listeners = append(handler.listeners, ln) // Append listener
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// AddService - run given background routine (e.g. a database maintenance routine) while handler is running. Services are started by Start, and cancelled before state is flushed on shutdown.
+func (handler *Handler) AddService(service Service) {
+	handler.mutex.Lock() // Lock handler
+
+	defer handler.mutex.Unlock() // Unlock handler
+
+	handler.services = append(handler.services, service) // Append service
+}
+
+// Start - listen on handler addresses (unless already listening), accepting and handling connections until ctx is cancelled (closing all connections), a listener fails or the handler is shut down (returning ErrHandlerClosed)
+func (handler *Handler) Start(ctx context.Context) error {
+	err := handler.Listen() // Listen on addresses
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	handler.mutex.Lock() // Lock handler
+
+	if handler.running { // Check already running
+		handler.mutex.Unlock() // Unlock handler
+
+		return ErrHandlerRunning // Return error
+	} else if len(handler.listeners) == 0 { // Check for nil listeners
+		handler.mutex.Unlock() // Unlock handler
+
+		return errors.New("handler has no addresses to listen on") // Return error
+	}
+
+	run := &handlerRun{
+		limits:    handler.Limits,                                                             // Set limits
+		conns:     make(map[net.Conn]string),                                                  // Init connections
+		peerConns: make(map[string]int),                                                       // Init peer connections
+		workers:   newWorkerPool(handler.Limits.MaxWorkers, handler.Limits.MaxQueuedRequests), // Start workers
+		limiter:   newRateLimiter(handler.Limits),                                             // Init rate limiter
+	} // Init run
+
+	handler.running, handler.shuttingDown, handler.run = true, false, run // Set running
+
+	listeners := handler.listeners // Store listeners
+
+	serviceCtx, stopServices := context.WithCancel(ctx) // Init service context
+
+	run.stopServices = stopServices // Set service cancel func
+
+	for _, service := range handler.services { // Iterate through services
+		run.services.Add(1) // Add service
+
+		go func(service Service) {
+			defer run.services.Done() // Finish service
+
+			service(serviceCtx) // Run service
+		}(service)
+	}
+
+	handler.mutex.Unlock() // Unlock handler
+
+	defer run.stopWorkers() // Stop workers once connections are handled
+
+	errs := make(chan error, len(listeners)) // Init error buffer
+
+	for _, ln := range listeners { // Iterate through listeners
+		go func(ln *net.Listener) {
+			errs <- handler.serve(ln, run) // Accept connections
+		}(ln)
+	}
+
+	select {
+	case err = <-errs: // Check for failed listener
+	case <-ctx.Done(): // Check cancelled
+		err = ctx.Err() // Set error
+	}
+
+	if err == ErrHandlerClosed { // Check shut down
+		return err // Return closed error
+	}
+
+	handler.mutex.Lock() // Lock handler
+
+	handler.closeListeners() // Stop accepting
+
+	run.closeConnections() // Close connections
+
+	handler.running = false // Set stopped
+
+	handler.mutex.Unlock() // Unlock handler
+
+	run.stopServices() // Cancel services
+
+	run.services.Wait() // Wait on services
+
+	return err // Return found error
+}
+
+// Shutdown - stop accepting connections, cancel services, wait on in-flight connections, services to finish (forcibly closing connections once ctx is done), flushing node and environment state to handler.StatePath
+func (handler *Handler) Shutdown(ctx context.Context) error {
+	handler.mutex.Lock() // Lock handler
+
+	if !handler.running || handler.shuttingDown { // Check not running
+		handler.mutex.Unlock() // Unlock handler
+
+		return nil // Nothing to do
+	}
+
+	handler.shuttingDown = true // Set shutting down
+
+	handler.closeListeners() // Stop accepting
+
+	run := handler.run // Fetch run
+
+	for conn := range run.conns { // Iterate through connections
+		conn.SetReadDeadline(time.Now()) // Stop reading new frames (in-flight frames are still handled)
+	}
+
+	handler.mutex.Unlock() // Unlock handler
+
+	run.stopServices() // Cancel services
+
+	drained := make(chan struct{}) // Init drained channel
+
+	go func() {
+		run.active.Wait() // Wait on connections
+
+		run.services.Wait() // Wait on services
+
+		close(drained) // Notify drained
+	}()
+
+	var err error // Init error buffer
+
+	select {
+	case <-drained: // Check drained
+	case <-ctx.Done(): // Check deadline exceeded
+		err = ctx.Err() // Set error
+
+		handler.mutex.Lock() // Lock handler
+
+		run.closeConnections() // Close remaining connections
+
+		handler.mutex.Unlock() // Unlock handler
+	}
+
+	if flushErr := handler.flush(); flushErr != nil { // Flush state
+		err = flushErr // Set error
+	}
+
+	handler.mutex.Lock() // Lock handler
+
+	handler.running = false // Set stopped
+
+	handler.mutex.Unlock() // Unlock handler
+
+	common.Println("\n-- HANDLER -- shut down handler") // Log shutdown
+
+	return err // Return error (might be nil)
+}
+
+// IsRunning - check handler is accepting connections
+func (handler *Handler) IsRunning() bool {
+	handler.mutex.Lock() // Lock handler
+
+	defer handler.mutex.Unlock() // Unlock handler
+
+	return handler.running && !handler.shuttingDown // Check running
+}
+
+// StartHandler - attempt to accept and handle requests on given listener until it is closed
 func StartHandler(node *node.Node, ln *net.Listener) error {
-	if reflect.ValueOf(node).IsNil() || node.Address == "" || reflect.ValueOf(ln).IsNil() { // Check for nil parameters
+	return StartHandlers(node, ln) // Start handler
+}
+
+// StartHandlers - attempt to accept and handle requests on several listeners (e.g. one per transport) at once, returning once any listener fails
+func StartHandlers(node *node.Node, listeners ...*net.Listener) error {
+	if reflect.ValueOf(node).IsNil() || node.Address == "" || len(listeners) == 0 { // Check for nil parameters
 		return errors.New("invalid parameters") // Return error
 	}
 
-	for {
-		conn, err := (*ln).Accept() // Accept connection
-
-		if err == nil { // Check for errors
-			go handleConnection(node, conn) // Handle connection
+	for _, ln := range listeners { // Iterate through listeners
+		if reflect.ValueOf(ln).IsNil() { // Check for nil listener
+			return errors.New("invalid parameters") // Return error
 		}
 	}
+
+	return (&Handler{Node: node, listeners: listeners, Limits: DefaultLimits}).Start(context.Background()) // Start handler
 }
 
-// StartProtobufHandler - attempt to accept and handle protobuf message requests
-func StartProtobufHandler(handler func(message []byte) error, protoID string, ln *net.Listener) error {
-	if protoID == "" || reflect.ValueOf(ln).IsNil() || reflect.ValueOf(handler).IsNil() { // Check for nil parameters
+// StartProtobufHandler - attempt to accept and handle protobuf message requests on listener of given node (see node.Listen)
+func StartProtobufHandler(node *node.Node, handler func(message []byte) error, protoID string, ln *net.Listener) error {
+	if node == nil || protoID == "" || reflect.ValueOf(ln).IsNil() || reflect.ValueOf(handler).IsNil() { // Check for nil parameters
 		return errors.New("invalid parameters") // Return error
 	}
 
 	for {
 		conn, err := (*ln).Accept() // Accept connection
 
-		if err == nil { // Check for errors
-			go handleProtobufConnection(conn, handler, protoID)
+		if err != nil { // Check for errors
+			if netErr, isNetErr := err.(net.Error); isNetErr && netErr.Temporary() { // Check for temporary error
+				continue // Accept next connection
+			}
+
+			return err // Listener closed, return found error
 		}
+
+		go handleProtobufConnection(node, conn, handler, protoID) // Handle connection
 	}
 }
 
@@ -53,128 +325,426 @@ func StartProtobufHandler(handler func(message []byte) error, protoID string, ln
 
 /* BEGIN INTERNAL METHODS */
 
-// handleConnection - attempt to fetch connection metadata, handle it respectively (stack or singular)
-func handleConnection(node *node.Node, conn net.Conn) error {
-	data, err := common.ReadConnectionWaitAsyncNoTLS(conn) // Read entire connection
+// serve - accept connections on given listener until it is closed, rejecting connections exceeding limits of given run
+func (handler *Handler) serve(ln *net.Listener, run *handlerRun) error {
+	for {
+		conn, err := (*ln).Accept() // Accept connection
+
+		if err != nil { // Check for errors
+			if netErr, isNetErr := err.(net.Error); isNetErr && netErr.Temporary() { // Check for temporary error
+				continue // Accept next connection
+			}
+
+			if handler.isShuttingDown() { // Check listener closed by shutdown
+				return ErrHandlerClosed // Return closed error
+			}
+
+			return err // Listener closed, return found error
+		}
+
+		if busy := handler.track(conn, run); busy != nil { // Track connection
+			go reject(conn, busy) // Reject connection
+
+			continue // Accept next connection
+		}
+
+		go func() {
+			defer handler.untrack(conn, run) // Untrack connection once handled
+
+			handler.handleConnection(conn, run) // Handle connection
+		}()
+	}
+}
+
+// track - register accepted connection, returning rejection if handler is shutting down or connection exceeds limits
+func (handler *Handler) track(conn net.Conn, run *handlerRun) *common.BusyError {
+	ip := remoteIP(conn) // Fetch remote IP
+
+	handler.mutex.Lock() // Lock handler
+
+	defer handler.mutex.Unlock() // Unlock handler
+
+	if handler.shuttingDown || handler.run != run { // Check shutting down
+		return &common.BusyError{Reason: "shutting down", RetryAfter: BusyRetryAfter} // Refuse connection
+	} else if run.limits.MaxConnections > 0 && len(run.conns) >= run.limits.MaxConnections { // Check connection limit
+		return &common.BusyError{Reason: "too many connections", RetryAfter: BusyRetryAfter} // Refuse connection
+	} else if run.limits.MaxConnectionsPerPeer > 0 && run.peerConns[ip] >= run.limits.MaxConnectionsPerPeer { // Check peer connection limit
+		return &common.BusyError{Reason: "too many connections from peer", RetryAfter: BusyRetryAfter} // Refuse connection
+	} else if busy := run.limiter.allow(ip); busy != nil { // Check rate
+		return busy // Refuse connection
+	}
+
+	run.conns[conn] = ip // Add connection
+
+	run.peerConns[ip]++ // Increment peer connections
+
+	run.active.Add(1) // Add active connection
+
+	return nil // Tracked
+}
+
+// untrack - remove handled connection
+func (handler *Handler) untrack(conn net.Conn, run *handlerRun) {
+	handler.mutex.Lock() // Lock handler
+
+	ip := run.conns[conn] // Fetch remote IP
+
+	delete(run.conns, conn) // Remove connection
+
+	if run.peerConns[ip]--; run.peerConns[ip] <= 0 { // Decrement peer connections
+		delete(run.peerConns, ip) // Remove peer
+	}
+
+	handler.mutex.Unlock() // Unlock handler
+
+	run.active.Done() // Finish connection
+}
+
+// isShuttingDown - check handler is shutting down
+func (handler *Handler) isShuttingDown() bool {
+	handler.mutex.Lock() // Lock handler
+
+	defer handler.mutex.Unlock() // Unlock handler
+
+	return handler.shuttingDown // Return shutting down
+}
+
+// setIdleDeadline - set idle read deadline of connection, returning false if handler is shutting down (connection should stop reading)
+func (handler *Handler) setIdleDeadline(conn net.Conn) bool {
+	handler.mutex.Lock() // Lock handler
+
+	defer handler.mutex.Unlock() // Unlock handler
+
+	if handler.shuttingDown { // Check shutting down
+		return false // Stop reading
+	}
+
+	conn.SetReadDeadline(time.Now().Add(common.ConnectionIdleTimeout)) // Set idle deadline
+
+	return true // Keep reading
+}
+
+// reject - notify peer of rejected connection, closing it
+func reject(conn net.Conn, busy *common.BusyError) {
+	defer conn.Close() // Close connection
+
+	common.Printf("\n-- CONNECTION -- rejected peer %s: %s", conn.RemoteAddr().String(), busy.Reason) // Log rejection
+
+	if _, err := common.ReadFrameWait(conn); err != nil { // Read hello (peers write before reading)
+		return // Peer is gone
+	}
+
+	conn.SetWriteDeadline(time.Now().Add(common.ConnectionReadTimeout)) // Set write deadline
+
+	if common.WriteBusyFrame(conn, 0, busy) != nil { // Notify peer
+		return // Peer is gone
+	}
+
+	conn.SetReadDeadline(time.Now().Add(BusyRetryAfter)) // Set linger deadline
+
+	io.Copy(ioutil.Discard, conn) // Wait on peer to close connection, ensuring rejection is read
+}
+
+// closeListeners - close open listeners (handler.mutex must be held)
+func (handler *Handler) closeListeners() {
+	for _, ln := range handler.listeners { // Iterate through listeners
+		(*ln).Close() // Close listener
+	}
+
+	handler.listeners = nil // Reset listeners
+}
+
+// closeConnections - close tracked connections (handler.mutex must be held)
+func (run *handlerRun) closeConnections() {
+	for conn := range run.conns { // Iterate through connections
+		conn.Close() // Close connection
+	}
+}
+
+// stopWorkers - stop workers once all connections of run have been handled
+func (run *handlerRun) stopWorkers() {
+	go func() {
+		run.active.Wait() // Wait on connections
+
+		run.workers.stop() // Stop workers
+	}()
+}
+
+// flush - write node and environment to handler.StatePath (if set)
+func (handler *Handler) flush() error {
+	if handler.StatePath == "" { // Check for nil state path
+		return nil // Nothing to do
+	}
+
+	err := handler.Node.WriteToMemory(handler.StatePath) // Write node
 
 	if err != nil { // Check for errors
 		return err // Return found error
 	}
 
-	if strings.Contains(string(data), "messagetype") { // Check for network message
-		return handleLogNetworkMessage(data) // Handle network message
-	} else if strings.Contains(string(data), common.ProtobufPrefix) { // Check for protobuf message
-		return nil // Handled in protobuf server
+	if handler.Node.Environment != nil { // Check for environment
+		return handler.Node.Environment.WriteToMemory(handler.StatePath) // Write environment
 	}
 
-	if len(data) < 100 { // Check for safe length
-		common.Printf("\n-- CONNECTION -- incoming connection from address: %s with data %s", conn.RemoteAddr().String(), fmt.Sprintf("%s", string(data))) // Log connection
-	} else {
-		common.Printf("\n-- CONNECTION -- incoming connection from address: %s with data %s", conn.RemoteAddr().String(), fmt.Sprintf("%s... }", string(data)[0:100])) // Log connection
-	}
+	return nil // No error occurred, return nil
+}
+
+// handleConnection - attempt to read request frames from persistent connection until closed, idle or handler is shutting down, queueing each for a worker (stack or singular). Frames exceeding limits of given run are rejected.
+func (handler *Handler) handleConnection(conn net.Conn, run *handlerRun) error {
+	defer conn.Close() // Close connection once handled
+
+	node := handler.Node // Fetch node
+
+	ip := remoteIP(conn) // Fetch remote IP
 
-	readConnection, err := connection.FromBytes(data) // Attempt to decode data
+	writeMutex := &sync.Mutex{} // Init write mutex (streams share connection)
+
+	pending := &sync.WaitGroup{} // Init pending stream group
+
+	defer pending.Wait() // Wait for pending streams before closing
+
+	handshake, err := common.ServerHandshake(conn, func(remote *common.Hello) (*common.Hello, error) {
+		return nodeHello(node, remote) // Accept peers on networks known to node
+	}) // Perform handshake
 
 	if err != nil { // Check for errors
+		if err == common.ErrUnframedPeer { // Check for legacy peer
+			common.Printf("\n-- CONNECTION -- rejected unframed peer %s", conn.RemoteAddr().String()) // Log rejection
+
+			common.WriteFrame(conn, common.FrameTypeError, []byte(err.Error())) // Notify peer
+		} else {
+			common.Printf("\n-- CONNECTION -- refused peer %s: %s", conn.RemoteAddr().String(), err.Error()) // Log refusal
+		}
+
 		return err // Return found error
 	}
 
-	common.Println("\n\n-- CONNECTION " + conn.RemoteAddr().String() + " -- attempted to read " + strconv.Itoa(len(data)) + " bytes of data.") // Log read connection
+	peerID := connectionPeerID(conn) // Fetch verified peer ID
 
-	if len(readConnection.ConnectionStack) == 0 { // Check if event stack exists
-		val, isMessage, err := handleSingular(node, readConnection) // Handle singular event
+	if peerID == "" { // Check for unauthenticated peer
+		peerID = handshake.Remote.NodeID // Check claimed identity
+	}
+
+	if peerBanned(node, handshake.Remote.NetworkAlias, peerID) { // Check peer is banned
+		common.Printf("\n-- CONNECTION -- rejected banned peer %s with NodeID %s", conn.RemoteAddr().String(), peerID) // Log rejection
+
+		common.WriteFrame(conn, common.FrameTypeError, []byte(database.ErrPeerBanned.Error())) // Notify peer
+
+		return database.ErrPeerBanned // Return error
+	}
+
+	common.Printf("\n-- CONNECTION -- accepted peer %s with NodeID %s (capabilities: %s)", conn.RemoteAddr().String(), handshake.Remote.NodeID, strings.Join(handshake.Capabilities, ", ")) // Log handshake
+
+	maxMessageSize := run.limits.MaxMessageSize // Fetch maximum message size
+
+	if maxMessageSize <= 0 { // Check for unlimited message size
+		maxMessageSize = common.MaxFrameSize // Limit to frame size
+	}
+
+	for handler.setIdleDeadline(conn) { // Read until shutting down
+		frame, err := common.ReadFrameLimit(conn, maxMessageSize) // Read request frame
+
+		if tooLarge, isTooLarge := err.(*common.TooLargeError); isTooLarge { // Check for oversized frame (discarded)
+			writeRejection(conn, writeMutex, func() error {
+				return common.WriteTooLargeFrame(conn, tooLarge) // Notify peer
+			}) // Reject frame
+
+			continue // Read next frame
+		}
 
 		if err != nil { // Check for errors
+			if netErr, isNetErr := err.(net.Error); err == io.EOF || (isNetErr && netErr.Timeout()) { // Check peer closed, idle or shutting down
+				return nil // Connection finished
+			}
+
 			return err // Return found error
 		}
 
-		serializedResponse, err := common.SerializeToBytes(connection.Response{Val: [][]byte{val}}) // Attempt to serialize response
+		busy := run.limiter.allow(ip) // Check rate
 
-		if err != nil { // Check for errors
-			return err // Return found error
+		pending.Add(1) // Add pending stream
+
+		if busy == nil && !run.workers.submit(func() {
+			defer pending.Done() // Finish stream
+
+			handleFrame(node, conn, handshake.Codec, handshake.Remote.NetworkAlias, frame, writeMutex) // Handle frame
+		}) { // Check workers are busy
+			busy = &common.BusyError{Reason: "request queue full", RetryAfter: BusyRetryAfter} // Set rejection
 		}
 
-		if isMessage == true {
-			handleLogNetworkMessage(val) // Handle network message
-		} else {
-			common.Println("\n-- CONNECTION " + conn.RemoteAddr().String() + " -- responding with data " + common.SafeSlice(serializedResponse) + "...") // Log response
+		if busy != nil { // Check for rejection
+			pending.Done() // Finish stream
+
+			if frame.Type == common.FrameTypeRequest { // Check response expected
+				writeRejection(conn, writeMutex, func() error {
+					return common.WriteBusyFrame(conn, frame.StreamID, busy) // Notify peer
+				}) // Reject frame
+			}
 		}
+	}
+
+	return nil // Shutting down
+}
 
-		conn.Write(serializedResponse) // Write success
+// writeRejection - write rejection frame with given write method
+func writeRejection(conn net.Conn, writeMutex *sync.Mutex, write func() error) {
+	writeMutex.Lock() // Lock writes
 
-		return nil // No error occurred, return nil
+	defer writeMutex.Unlock() // Unlock writes
+
+	write() // Write rejection
+}
+
+// handleFrame - attempt to handle single frame (encoded with given codec) sent by peer connected to given network, writing response on frame stream if requested
+func handleFrame(node *node.Node, conn net.Conn, codec common.Codec, network string, frame *common.Frame, writeMutex *sync.Mutex) error {
+	response, err := handleData(node, conn, codec, network, frame.Payload) // Handle frame contents
+
+	if frame.Type != common.FrameTypeRequest { // Check peer is not waiting on a response
+		return err // Return error (might be nil)
 	}
 
-	val, err := handleStack(node, readConnection) // Attempt to handle stack
+	writeMutex.Lock() // Lock writes
+
+	defer writeMutex.Unlock() // Unlock writes
 
 	if err != nil { // Check for errors
+		common.WriteStreamFrame(conn, common.FrameTypeError, frame.StreamID, []byte(err.Error())) // Write error
+
 		return err // Return found error
 	}
 
-	instancedResponse := connection.Response{Val: val} // Create response instance for byte serialization
+	return common.WriteStreamFrame(conn, common.FrameTypeResponse, frame.StreamID, response) // Write response
+}
 
-	serializedResponse, err := common.SerializeToBytes(instancedResponse) // Serialize
+// envelopeHandler - handler of a single kind of envelope payload (encoded with given codec), returning response encoded with given codec
+type envelopeHandler func(node *node.Node, conn net.Conn, codec common.Codec, payload []byte) ([]byte, error)
+
+// envelopeHandlers - dispatch table of handlers by envelope kind
+var envelopeHandlers = map[common.EnvelopeKind]envelopeHandler{
+	common.EnvelopeKindConnection:     handleConnectionEnvelope, // Handle connections
+	common.EnvelopeKindEvent:          handleEventEnvelope,      // Handle single events
+	common.EnvelopeKindNetworkMessage: handleNetworkMessage,     // Handle network messages
+	common.EnvelopeKindProtobuf:       handleProtobufEnvelope,   // Handle protobuf messages
+	common.EnvelopeKindStreamChunk:    handleStreamChunk,        // Handle chunked transfers
+	common.EnvelopeKindFindNode:       handleFindNode,           // Handle DHT lookups
+	common.EnvelopeKindStoreRecord:    handleStoreRecord,        // Handle DHT records
+	common.EnvelopeKindPeerExchange:   handlePeerExchange,       // Handle peer exchanges
+	common.EnvelopeKindPing:           handlePing,               // Handle liveness pings
+	common.EnvelopeKindMembership:     handleMembership,         // Handle membership probes
+	common.EnvelopeKindReplication:    handleReplication,        // Handle database replication
+}
+
+// handleData - attempt to decode envelope from given request data (encoded with given codec) sent by peer connected to given network, dispatching payload to handler of envelope kind. Peers sending malformed data are penalized in the network database.
+func handleData(node *node.Node, conn net.Conn, codec common.Codec, network string, data []byte) ([]byte, error) {
+	envelope, err := common.DecodeEnvelope(codec, data) // Decode envelope
 
 	if err != nil { // Check for errors
-		return err // Return found error
+		recordPeerEvent(node, conn, network, database.ReputationMalformed) // Penalize peer
+
+		return nil, err // Return found error
+	}
+
+	handler, found := envelopeHandlers[envelope.Kind] // Fetch handler
+
+	if !found { // Check for unsupported kind
+		return nil, fmt.Errorf("unsupported envelope kind %s", envelope.Kind) // Return error
 	}
 
-	common.Println("\n-- CONNECTION " + readConnection.InitializationNode.Address + " -- responding with data " + common.SafeSlice(serializedResponse) + "...") // Log response
+	response, err := handler(node, conn, codec, envelope.Payload) // Handle payload
 
-	_, err = conn.Write(serializedResponse) // Write success
+	if _, isMalformed := err.(*malformedError); isMalformed { // Check for malformed payload
+		recordPeerEvent(node, conn, network, database.ReputationMalformed) // Penalize peer
+	}
 
-	return err // Attempt to handle stack
+	return response, err // Return response
 }
 
-// handleSingular - no stack present in found connection, write variable with connection data
-func handleSingular(node *node.Node, connection *connection.Connection) ([]byte, bool, error) {
-	db, err := database.FromBytes(connection.Data) // Attempt to read db
+// handleConnectionEnvelope - handle received connection (stack or singular)
+func handleConnectionEnvelope(node *node.Node, conn net.Conn, codec common.Codec, payload []byte) ([]byte, error) {
+	common.Printf("\n-- CONNECTION -- incoming connection from address: %s with data %s", conn.RemoteAddr().String(), common.SafeSlice(payload)) // Log connection
+
+	readConnection, err := connection.DecodeConnection(codec, payload) // Attempt to decode connection
 
-	if err == nil { // Check for success
-		err = db.WriteToMemory(node.Environment) // Write db to memory
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	common.Println("\n\n-- CONNECTION " + conn.RemoteAddr().String() + " -- attempted to read " + strconv.Itoa(len(payload)) + " bytes of data.") // Log read connection
+
+	val := [][]byte{} // Init response buffer
+
+	if len(readConnection.ConnectionStack) == 0 { // Check if event stack exists
+		singularVal, err := handleSingular(node, readConnection) // Handle singular connection
 
 		if err != nil { // Check for errors
-			return nil, false, err // Return found error
+			return nil, err // Return found error
 		}
 
-		result, err := common.SerializeToBytes(*db) // Attempt to serialize
+		val = append(val, singularVal) // Append response
+	} else {
+		val, err = handleStack(node, readConnection) // Attempt to handle stack
 
 		if err != nil { // Check for errors
-			return nil, false, err // Return found error
+			return nil, err // Return found error
 		}
-
-		return result, false, nil // Attempt to serialize
 	}
 
-	result, err := handleNetworkMessage(node, connection) // Attempt to decode message
+	serializedResponse, err := (&connection.Response{Val: val}).Encode(codec) // Encode response
 
-	if err == nil { // Check for success
-		return result, true, nil // Return result
+	if err != nil { // Check for errors
+		return nil, err // Return found error
 	}
 
+	common.Println("\n-- CONNECTION " + conn.RemoteAddr().String() + " -- responding with data " + common.SafeSlice(serializedResponse) + "...") // Log response
+
+	return serializedResponse, nil // No error occurred, return response
+}
+
+// handleSingular - no stack present in found connection, write variable with connection data
+func handleSingular(node *node.Node, connection *connection.Connection) ([]byte, error) {
 	variable, err := environment.NewVariable("Connection", connection) // Init variable to hold connection data
 
 	if err != nil { // Check for errors
-		return nil, false, err // Return found error
+		return nil, err // Return found error
 	}
 
 	varByteVal, err := common.SerializeToBytes(variable) // Serialize
 
 	if err != nil { // Check for errors
-		return nil, false, err // Return found error
+		return nil, err // Return found error
 	}
 
-	return varByteVal, false, node.Environment.AddVariable(variable, false) // Attempt to add variable to environment, return variable value as byte
+	return varByteVal, node.Environment.AddVariable(variable, false) // Attempt to add variable to environment, return variable value as byte
 }
 
-// handleLogNetworkMessage - handle logging of a network message
-func handleLogNetworkMessage(b []byte) error {
-	message, err := database.MessageFromBytes(b) // Fetch message from connection data
+// handleEventEnvelope - handle single received event
+func handleEventEnvelope(node *node.Node, conn net.Conn, codec common.Codec, payload []byte) ([]byte, error) {
+	event, err := connection.DecodeEvent(codec, payload) // Decode event
 
 	if err != nil { // Check for errors
-		return err // Return found error
+		return nil, err // Return found error
+	}
+
+	if event.Command == nil { // Check for nil command
+		return nil, errors.New("invalid command") // Return error
+	}
+
+	val, err := handleCommand(node, event) // Handle event command
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
 	}
 
+	return (&connection.Response{Val: [][]byte{val}}).Encode(codec) // Return encoded response
+}
+
+// handleProtobufEnvelope - protobuf messages are handled by StartProtobufHandler listeners
+func handleProtobufEnvelope(node *node.Node, conn net.Conn, codec common.Codec, payload []byte) ([]byte, error) {
+	return nil, nil // Handled in protobuf server
+}
+
+// handleLogNetworkMessage - handle logging of a network message
+func handleLogNetworkMessage(message *database.Message) {
 	red := color.New(color.FgRed)       // Init red writer
 	yellow := color.New(color.FgYellow) // Init yellow writer
 	cyan := color.New(color.FgCyan)     // Init cyan writer
@@ -191,52 +761,88 @@ func handleLogNetworkMessage(b []byte) error {
 	default: // Check for any other priority
 		common.Printf("\n== Network Message (%s) From Network %s == %s", message.Type, message.Network, message.Message) // Log response
 	}
-
-	return nil // No error occurred, return nil
 }
 
 // handleProtobufConnection - handle received protobuf message
-func handleProtobufConnection(conn net.Conn, handler func(message []byte) error, protoID string) error {
-	data, err := common.ReadConnectionWaitAsyncNoTLS(conn) // Read entire connection
+func handleProtobufConnection(node *node.Node, conn net.Conn, handler func(message []byte) error, protoID string) error {
+	defer conn.Close() // Close connection once handled
+
+	handshake, err := common.ServerHandshake(conn, func(remote *common.Hello) (*common.Hello, error) {
+		cert, err := node.Certificate() // Fetch certificate presented by listener
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		return common.NodeHello(cert) // Accept any compatible peer
+	}) // Perform handshake
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	frame, err := common.ReadFrameWait(conn) // Read message frame
 
 	if err != nil { // Check for errors
 		return err // Return found error
 	}
 
-	protoMessage, err := proto.FromBytes(data) // Decode message from bytes
+	envelope, err := common.DecodeEnvelope(handshake.Codec, frame.Payload) // Decode envelope
 
 	if err != nil { // Check for errors
 		return err // Return found error
 	}
 
-	if protoMessage.Guide.ProtoID == protoID { // Check for match
-		return handler(data) // Run handler
+	if envelope.Kind != common.EnvelopeKindProtobuf { // Check for non-protobuf payload
+		return fmt.Errorf("unsupported envelope kind %s", envelope.Kind) // Return error
+	}
+
+	protoMessage, err := proto.FromBytes(envelope.Payload) // Decode message from bytes
+
+	if err != nil { // Check for errors
+		return err // Return found error
 	}
 
-	return errors.New("couldn't find matching protoID") // Couldn't find matching protoID
+	if protoMessage.Guide.ProtoID != protoID { // Check for mismatch
+		return errors.New("couldn't find matching protoID") // Couldn't find matching protoID
+	}
+
+	err = handler(envelope.Payload) // Run handler
+
+	if frame.Type == common.FrameTypeRequest { // Check peer is waiting on a response
+		if err != nil { // Check for errors
+			common.WriteStreamFrame(conn, common.FrameTypeError, frame.StreamID, []byte(err.Error())) // Write error
+		} else {
+			common.WriteStreamFrame(conn, common.FrameTypeResponse, frame.StreamID, []byte{}) // Acknowledge message
+		}
+	}
+
+	return err // Return error (might be nil)
 }
 
-// handleNetworkMessage - handle received network message
-func handleNetworkMessage(node *node.Node, connection *connection.Connection) ([]byte, error) {
-	message, err := database.MessageFromBytes(connection.Data) // Fetch message from connection data
+// handleNetworkMessage - handle received network message, storing and logging message
+func handleNetworkMessage(node *node.Node, conn net.Conn, codec common.Codec, payload []byte) ([]byte, error) {
+	message, err := database.DecodeMessage(codec, payload) // Fetch message from payload
 
 	if err != nil { // Check for errors
-		return []byte{}, err // Return found error
+		return nil, err // Return found error
 	}
 
 	variable, err := environment.NewVariable(fmt.Sprintf("%sNetworkMessage", message.Network), *message) // Init variable
 
 	if err != nil { // Check for errors
-		return []byte{}, err // Return found error
+		return nil, err // Return found error
 	}
 
 	err = node.Environment.AddVariable(variable, false) // Attempt to add variable to environment
 
 	if err != nil { // Check for errors
-		return []byte{}, err // Return found error
+		return nil, err // Return found error
 	}
 
-	return message.ToBytes() // Return message value
+	handleLogNetworkMessage(message) // Log message
+
+	return message.Encode(codec) // Return message value
 }
 
 // handleStack - found connection with stack, iterate through and handle each command
@@ -351,6 +957,31 @@ func handleAddVariable(node *node.Node, event *connection.Event) ([]byte, error)
 	return serializedValue, nil // Return serialized value
 }
 
+// nodeHello - init hello advertising node, refusing peers on networks node hasn't joined
+func nodeHello(node *node.Node, remote *common.Hello) (*common.Hello, error) {
+	cert, err := node.Certificate() // Fetch certificate presented by listener
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	hello, err := common.NodeHello(cert) // Fetch hello of node
+
+	if err != nil || remote.NetworkAlias == "" || node.Environment == nil { // Check for errors, unspecified network
+		return hello, err // Return hello
+	}
+
+	db, err := database.ReadDatabaseFromMemory(node.Environment, remote.NetworkAlias) // Read network database
+
+	if err != nil { // Check for errors
+		return nil, &common.HandshakeError{Reason: "unknown network " + remote.NetworkAlias} // Refuse peer
+	}
+
+	hello.NetworkID, hello.NetworkAlias = db.NetworkID, db.NetworkAlias // Set network
+
+	return hello, nil // Return hello
+}
+
 func refreshNode() (*node.Node, error) {
 	currentDir, err := common.GetCurrentDir() // Fetch working directory
 
@@ -1,10 +1,18 @@
 package handler
 
 import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/types/connection"
+	"github.com/dowlandaiello/GoP2P/types/database"
 	"github.com/dowlandaiello/GoP2P/types/environment"
 	"github.com/dowlandaiello/GoP2P/types/node"
 )
@@ -58,7 +66,7 @@ func TestStartProtobufHandler(t *testing.T) {
 	}
 
 	go func() {
-		err = StartProtobufHandler(testHandler, "test", ln) // Attempt to start handler
+		err = StartProtobufHandler(node, testHandler, "test", ln) // Attempt to start handler
 
 		if err != nil { // Check for error
 			t.Errorf(err.Error()) // Log found error
@@ -67,6 +75,249 @@ func TestStartProtobufHandler(t *testing.T) {
 	}()
 }
 
+// TestStartHandlers - test handling of connections over several transports at once
+func TestStartHandlers(t *testing.T) {
+	env, err := environment.NewEnvironment() // Init environment
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	identity, err := node.NewIdentity() // Init identity
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	dir, err := ioutil.TempDir("", "gop2p") // Init socket directory
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	defer os.RemoveAll(dir) // Remove socket directory
+
+	listeners := []*net.Listener{} // Init listener buffer
+
+	addresses := []string{"memory://handler-test", "unix://" + filepath.Join(dir, "node")} // Init node addresses
+
+	testNode := &node.Node{Address: addresses[0], Environment: env} // Init node
+
+	testNode.SetIdentity(identity) // Set identity
+
+	for _, address := range addresses { // Iterate through addresses
+		ln, err := testNode.Listen(address + ":3000") // Listen on address
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		defer (*ln).Close() // Close listener
+
+		listeners = append(listeners, ln) // Append listener
+	}
+
+	go StartHandlers(testNode, listeners...) // Start handlers
+
+	for _, address := range addresses { // Iterate through addresses
+		destination := &node.Node{Address: address, NodeID: testNode.NodeID} // Init destination
+
+		conn, err := connection.NewConnection(destination, destination, 3000, []byte("test"), "relay", []connection.Event{}) // Init connection
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		response, err := conn.AttemptResponse() // Attempt connection
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if len(response.Val) != 1 { // Check for invalid response
+			t.Errorf("invalid response from %s", address) // Log found error
+			t.FailNow()                                   // Panic
+		}
+
+		t.Logf("handled connection over %s", address) // Log success
+	}
+}
+
+// TestHandlerShutdown - test functionality of Handler Start(), Shutdown() methods
+func TestHandlerShutdown(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gop2p") // Init state directory
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	defer os.RemoveAll(dir) // Remove state directory
+
+	env, err := environment.NewEnvironment() // Init environment
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	identity, err := node.NewIdentity() // Init identity
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	testNode := &node.Node{Address: "memory://shutdown-test", Environment: env} // Init node
+
+	testNode.SetIdentity(identity) // Set identity
+
+	handler, err := NewHandler(testNode, dir, "memory://shutdown-test:3000") // Init handler
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	serviceStopped := make(chan struct{}, 1) // Init service stopped channel
+
+	handler.AddService(func(ctx context.Context) {
+		<-ctx.Done() // Wait on shutdown
+
+		serviceStopped <- struct{}{} // Mark service stopped
+	}) // Add service
+
+	for x := 0; x < 2; x++ { // Start, restart handler
+		if err = handler.Listen(); err != nil { // Listen on addresses
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		stopped := make(chan error, 1) // Init stopped channel
+
+		go func() {
+			stopped <- handler.Start(context.Background()) // Start handler
+		}()
+
+		conn, err := connection.NewConnection(testNode, testNode, 3000, []byte("test"), "relay", []connection.Event{}) // Init connection
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if _, err = conn.AttemptResponse(); err != nil { // Attempt connection (session is kept open)
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second) // Init shutdown deadline
+
+		err = handler.Shutdown(ctx) // Shut down handler, draining open session
+
+		cancel() // Release deadline
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		select {
+		case <-serviceStopped: // Check service was stopped before shutdown returned
+		default:
+			t.Errorf("expected service to be stopped on shutdown") // Log found error
+			t.FailNow()                                            // Panic
+		}
+
+		if err = <-stopped; err != ErrHandlerClosed { // Check handler stopped
+			t.Errorf("expected handler to be closed, got %v", err) // Log found error
+			t.FailNow()                                            // Panic
+		}
+	}
+
+	readNode, err := node.ReadNodeFromMemory(dir) // Read flushed node
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if _, err = readNode.Environment.QueryType("Connection"); err != nil { // Check handled connection was flushed
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+}
+
+// TestHandleData - test dispatch of enveloped payloads by kind
+func TestHandleData(t *testing.T) {
+	env, err := environment.NewEnvironment() // Init environment
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	testNode := &node.Node{Address: "127.0.0.1", Environment: env} // Init node
+
+	conn, peer := net.Pipe() // Init connection
+
+	defer conn.Close() // Close connection
+	defer peer.Close() // Close peer
+
+	message, err := database.NewMessage("test", 0, "notice", "test") // Init message
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	for _, codec := range common.SupportedCodecs { // Iterate through codecs
+		payload, err := message.Encode(codec) // Encode message
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		data, err := common.Seal(codec, common.EnvelopeKindNetworkMessage, payload) // Seal message
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		response, err := handleData(testNode, conn, codec, "", data) // Handle message
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if decodedMessage, err := database.DecodeMessage(codec, response); err != nil || *decodedMessage != *message { // Check response
+			t.Errorf("invalid %s response %v (%v)", codec, decodedMessage, err) // Log found error
+			t.FailNow()                                                         // Panic
+		}
+
+		if _, err = env.QueryType("testNetworkMessage"); err != nil { // Check message was stored
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		for _, data := range [][]byte{payload, []byte(`{"kind":"unknown","payload":null}`)} { // Iterate through unenveloped, unsupported payloads
+			if _, err = handleData(testNode, conn, codec, "", data); err == nil { // Handle data
+				t.Errorf("expected %s payload %s to be rejected", codec, string(data)) // Log found error
+				t.FailNow()                                                            // Panic
+			}
+		}
+	}
+}
+
 // testHandler - test handler
 func testHandler(b []byte) error {
 	common.Println(string(b)) // Log input
@@ -87,7 +338,15 @@ func newNodeSafe() (*node.Node, error) {
 		return &node.Node{}, err // Return found error
 	}
 
+	identity, err := node.NewIdentity() // Generate identity
+
+	if err != nil { // Check for errors
+		return &node.Node{}, err // Return found error
+	}
+
 	node := node.Node{Address: ip, Reputation: 0, IsBootstrap: false, Environment: environment} // Creates new node instance with specified address
 
+	node.SetIdentity(identity) // Set identity
+
 	return &node, nil // Return initialized node
 }
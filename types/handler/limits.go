@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/common"
+)
+
+// Limits - bounds on the resources a handler commits to peers (zero values are unlimited)
+type Limits struct {
+	MaxWorkers int // MaxWorkers - number of workers handling request frames
+
+	MaxQueuedRequests int // MaxQueuedRequests - number of request frames waiting on a worker before requests are rejected as busy
+
+	MaxConnections int // MaxConnections - maximum number of concurrent connections
+
+	MaxConnectionsPerPeer int // MaxConnectionsPerPeer - maximum number of concurrent connections from a single remote IP
+
+	MaxMessageSize int // MaxMessageSize - maximum size of a request frame payload, enforced before it is read
+
+	RequestsPerSecondPerPeer float64 // RequestsPerSecondPerPeer - rate of connections, requests accepted from a single remote IP
+
+	RequestBurstPerPeer int // RequestBurstPerPeer - number of connections, requests a single remote IP may make at once
+
+	RequestsPerSecond float64 // RequestsPerSecond - rate of connections, requests accepted from all peers
+
+	RequestBurst int // RequestBurst - number of connections, requests all peers may make at once
+}
+
+var (
+	// DefaultLimits - limits of handlers initialized via NewHandler, StartHandler
+	DefaultLimits = Limits{
+		MaxWorkers:               64,               // Set workers
+		MaxQueuedRequests:        1024,             // Set queue size
+		MaxConnections:           1024,             // Set connections
+		MaxConnectionsPerPeer:    8,                // Set per-peer connections
+		MaxMessageSize:           16 * 1024 * 1024, // Set message size
+		RequestsPerSecondPerPeer: 100,              // Set per-peer rate
+		RequestBurstPerPeer:      200,              // Set per-peer burst
+		RequestsPerSecond:        2000,             // Set rate
+		RequestBurst:             4000,             // Set burst
+	}
+
+	// BusyRetryAfter - duration peers rejected for exceeding connection limits are asked to wait before retrying
+	BusyRetryAfter = time.Second
+)
+
+// workerPool - fixed set of workers running queued jobs
+type workerPool struct {
+	jobs chan func() // jobs - queued jobs
+
+	unbounded bool // unbounded - pool runs each job in a new goroutine
+
+	stopOnce sync.Once // stopOnce - closes jobs once
+}
+
+// tokenBucket - rate limiter allowing given rate of events, with bursts of given size
+type tokenBucket struct {
+	rate float64 // rate - tokens added per second
+
+	burst float64 // burst - maximum number of tokens
+
+	tokens float64 // tokens - available tokens
+
+	last time.Time // last - time tokens were last added
+}
+
+// rateLimiter - per-remote-IP, global token bucket rate limiter
+type rateLimiter struct {
+	limits Limits // limits - rates
+
+	global *tokenBucket // global - bucket shared by all peers
+
+	peers map[string]*tokenBucket // peers - buckets by remote IP
+
+	mutex sync.Mutex // mutex - guards global, peers
+}
+
+/* BEGIN INTERNAL METHODS */
+
+// newWorkerPool - initialize pool of given number of workers, queueing given number of jobs (runs each job in a new goroutine if workers is 0)
+func newWorkerPool(workers int, queueSize int) *workerPool {
+	if workers <= 0 { // Check for unbounded pool
+		return &workerPool{unbounded: true} // Return unbounded pool
+	}
+
+	pool := &workerPool{jobs: make(chan func(), queueSize)} // Init pool
+
+	for x := 0; x < workers; x++ { // Start workers
+		go pool.work() // Start worker
+	}
+
+	return pool // Return initialized pool
+}
+
+// submit - queue given job, returning false if queue is full
+func (pool *workerPool) submit(job func()) bool {
+	if pool.unbounded { // Check for unbounded pool
+		go job() // Run job
+
+		return true // Queued
+	}
+
+	select {
+	case pool.jobs <- job: // Queue job
+		return true // Queued
+	default:
+		return false // Queue full
+	}
+}
+
+// stop - stop workers once queued jobs have run
+func (pool *workerPool) stop() {
+	if pool.unbounded { // Check for unbounded pool
+		return // Nothing to do
+	}
+
+	pool.stopOnce.Do(func() {
+		close(pool.jobs) // Stop workers
+	})
+}
+
+// work - run queued jobs until pool is stopped
+func (pool *workerPool) work() {
+	for job := range pool.jobs { // Iterate through jobs
+		job() // Run job
+	}
+}
+
+// newTokenBucket - initialize full token bucket with given rate, burst
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 { // Check for invalid burst
+		burst = 1 // Allow single event
+	}
+
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()} // Return initialized bucket
+}
+
+// take - take token from bucket, returning false and duration until a token is available if bucket is empty
+func (bucket *tokenBucket) take(now time.Time) (bool, time.Duration) {
+	bucket.tokens = math.Min(bucket.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*bucket.rate) // Add tokens since last take
+
+	bucket.last = now // Set last
+
+	if bucket.tokens < 1 { // Check for empty bucket
+		return false, time.Duration((1 - bucket.tokens) / bucket.rate * float64(time.Second)) // Return wait
+	}
+
+	bucket.tokens-- // Take token
+
+	return true, 0 // Allowed
+}
+
+// full - check bucket would be full at given time
+func (bucket *tokenBucket) full(now time.Time) bool {
+	return bucket.tokens+now.Sub(bucket.last).Seconds()*bucket.rate >= bucket.burst // Check full
+}
+
+// newRateLimiter - initialize rate limiter with rates of given limits
+func newRateLimiter(limits Limits) *rateLimiter {
+	limiter := &rateLimiter{limits: limits, peers: make(map[string]*tokenBucket)} // Init limiter
+
+	if limits.RequestsPerSecond > 0 { // Check for global rate
+		limiter.global = newTokenBucket(limits.RequestsPerSecond, limits.RequestBurst) // Init global bucket
+	}
+
+	return limiter // Return initialized limiter
+}
+
+// allow - check connection or request from given remote IP is allowed, returning rejection otherwise
+func (limiter *rateLimiter) allow(ip string) *common.BusyError {
+	limiter.mutex.Lock() // Lock limiter
+
+	defer limiter.mutex.Unlock() // Unlock limiter
+
+	now := time.Now() // Fetch time
+
+	if limiter.limits.RequestsPerSecondPerPeer > 0 { // Check for per-peer rate
+		bucket, found := limiter.peers[ip] // Fetch peer bucket
+
+		if !found { // Check for new peer
+			if len(limiter.peers) >= 4096 { // Check for too many tracked peers
+				limiter.prune(now) // Remove idle peers
+			}
+
+			bucket = newTokenBucket(limiter.limits.RequestsPerSecondPerPeer, limiter.limits.RequestBurstPerPeer) // Init bucket
+
+			limiter.peers[ip] = bucket // Set bucket
+		}
+
+		if allowed, wait := bucket.take(now); !allowed { // Check peer rate
+			return &common.BusyError{Reason: "peer rate limited", RetryAfter: wait} // Return rejection
+		}
+	}
+
+	if limiter.global != nil { // Check for global rate
+		if allowed, wait := limiter.global.take(now); !allowed { // Check global rate
+			return &common.BusyError{Reason: "rate limited", RetryAfter: wait} // Return rejection
+		}
+	}
+
+	return nil // Allowed
+}
+
+// prune - remove buckets of peers that have been idle long enough to refill (limiter.mutex must be held)
+func (limiter *rateLimiter) prune(now time.Time) {
+	for ip, bucket := range limiter.peers { // Iterate through peers
+		if bucket.full(now) { // Check idle
+			delete(limiter.peers, ip) // Remove bucket
+		}
+	}
+}
+
+// remoteIP - fetch IP of remote address of given connection (or whole address for non-IP transports)
+func remoteIP(conn net.Conn) string {
+	address := conn.RemoteAddr().String() // Fetch address
+
+	if host, _, err := net.SplitHostPort(address); err == nil && !strings.Contains(address, "://") { // Check for host:port
+		return host // Return host
+	}
+
+	return address // Return address
+}
+
+/* END INTERNAL METHODS */
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/types/environment"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+// TestTokenBucket - test token bucket rate limiting
+func TestTokenBucket(t *testing.T) {
+	bucket := newTokenBucket(10, 2) // Init bucket
+
+	now := time.Now() // Fetch time
+
+	for x := 0; x < 2; x++ { // Take burst
+		if allowed, _ := bucket.take(now); !allowed { // Take token
+			t.Errorf("expected burst to be allowed") // Log found error
+			t.FailNow()                              // Panic
+		}
+	}
+
+	if allowed, wait := bucket.take(now); allowed || wait <= 0 { // Take token from empty bucket
+		t.Errorf("expected empty bucket to be limited") // Log found error
+		t.FailNow()                                     // Panic
+	}
+
+	if allowed, _ := bucket.take(now.Add(100 * time.Millisecond)); !allowed { // Take refilled token
+		t.Errorf("expected bucket to refill") // Log found error
+		t.FailNow()                           // Panic
+	}
+}
+
+// TestWorkerPool - test worker pool queueing
+func TestWorkerPool(t *testing.T) {
+	pool := newWorkerPool(1, 1) // Init pool
+
+	defer pool.stop() // Stop pool
+
+	block := make(chan struct{}) // Init block channel
+
+	wg := &sync.WaitGroup{} // Init job group
+
+	wg.Add(2) // Add jobs
+
+	if !pool.submit(func() { <-block; wg.Done() }) { // Submit blocking job
+		t.Errorf("expected job to be queued") // Log found error
+		t.FailNow()                           // Panic
+	}
+
+	queued := false // Init queued buffer
+
+	for x := 0; x < 100 && !queued; x++ { // Wait on worker to take blocking job
+		queued = pool.submit(func() { wg.Done() }) // Submit job
+
+		time.Sleep(time.Millisecond) // Wait
+	}
+
+	if !queued || pool.submit(func() {}) { // Check queue is full
+		t.Errorf("expected full queue to reject job") // Log found error
+		t.FailNow()                                   // Panic
+	}
+
+	close(block) // Unblock worker
+
+	wg.Wait() // Wait on jobs
+}
+
+// TestHandlerLimits - test rejection of connections, requests exceeding handler limits
+func TestHandlerLimits(t *testing.T) {
+	env, err := environment.NewEnvironment() // Init environment
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	identity, err := node.NewIdentity() // Init identity
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	testNode := &node.Node{Address: "memory://limits-test", Environment: env} // Init node
+
+	testNode.SetIdentity(identity) // Set identity
+
+	handler, err := NewHandler(testNode, "", "memory://limits-test:3000") // Init handler
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	handler.Limits = Limits{MaxConnectionsPerPeer: 1, MaxMessageSize: 1024, RequestsPerSecondPerPeer: 0.001, RequestBurstPerPeer: 2} // Set limits
+
+	go handler.Start(context.Background()) // Start handler
+
+	defer handler.Shutdown(context.Background()) // Shut down handler
+
+	pool := common.NewConnectionPool(common.DefaultPoolIdleTimeout) // Init pool
+
+	defer pool.Close() // Close pool
+
+	var response []byte // Init response buffer
+
+	for x := 0; x < 100; x++ { // Wait on handler
+		if response, err = pool.Request("memory://limits-test:3000", testNode.NodeID, make([]byte, 2048)); err == nil || !strings.Contains(err.Error(), "refused") { // Send oversized request
+			break // Handler started
+		}
+
+		time.Sleep(time.Millisecond) // Wait
+	}
+
+	if _, isTooLarge := err.(*common.TooLargeError); !isTooLarge { // Check for unexpected response
+		t.Errorf("expected too large error, got %s (%v)", string(response), err) // Log found error
+		t.FailNow()                                                              // Panic
+	}
+
+	if _, err = pool.Request("memory://limits-test:3000", testNode.NodeID, []byte("test")); err == nil { // Send invalid request on same session
+		t.Errorf("expected invalid request to be rejected") // Log found error
+		t.FailNow()                                         // Panic
+	} else if _, isRemote := err.(*common.RemoteError); !isRemote { // Check session survived oversized request
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	secondPool := common.NewConnectionPool(common.DefaultPoolIdleTimeout) // Init second pool
+
+	defer secondPool.Close() // Close pool
+
+	if _, err = secondPool.Request("memory://limits-test:3000", testNode.NodeID, []byte("test")); err == nil { // Open second connection
+		t.Errorf("expected second connection to be rejected") // Log found error
+		t.FailNow()                                           // Panic
+	} else if busy, isBusy := err.(*common.BusyError); !isBusy || busy.RetryAfter <= 0 { // Check for unexpected error
+		t.Errorf("expected busy error, got %v", err) // Log found error
+		t.FailNow()                                  // Panic
+	}
+
+	if _, err = pool.Request("memory://limits-test:3000", testNode.NodeID, []byte("test")); err == nil { // Exceed rate limit (connection, 2 requests allowed)
+		t.Errorf("expected rate limited request to be rejected") // Log found error
+		t.FailNow()                                              // Panic
+	} else if _, isBusy := err.(*common.BusyError); !isBusy { // Check for unexpected error
+		t.Errorf("expected busy error, got %v", err) // Log found error
+		t.FailNow()                                  // Panic
+	}
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/types/database"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+/* BEGIN INTERNAL METHODS */
+
+// handlePing - answer ping with a pong, marking sender as seen in requested network database if it is the authenticated peer
+func handlePing(node *node.Node, conn net.Conn, codec common.Codec, payload []byte) ([]byte, error) {
+	ping, err := database.DecodePing(codec, payload) // Decode ping
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if !verifySender(conn, ping.Sender) || node.Environment == nil { // Check sender isn't authenticated peer, no database to record sender in
+		return database.NewPong(node, ping).Encode(codec) // Return encoded pong
+	}
+
+	databaseMutex.Lock() // Lock databases
+
+	defer databaseMutex.Unlock() // Unlock databases
+
+	db, err := database.ReadDatabaseFromMemory(node.Environment, ping.Network) // Read network database
+
+	if err != nil { // Check for unknown network
+		return database.NewPong(node, ping).Encode(codec) // Return encoded pong
+	}
+
+	pong, err := db.HandlePing(node, ping) // Answer ping
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	err = db.WriteToMemory(node.Environment) // Write db to memory
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return pong.Encode(codec) // Return encoded pong
+}
+
+/* END INTERNAL METHODS */
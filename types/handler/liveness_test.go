@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/dowlandaiello/GoP2P/types/database"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+// TestPingPeers - test functionality of PingPeers() over handlers
+func TestPingPeers(t *testing.T) {
+	peer := startMemoryHandler(t, "memory://ping-test-peer") // Start peer
+
+	localNode := startMemoryHandler(t, "memory://ping-test-local") // Start local node (presents its certificate on outgoing connections)
+
+	err := (&database.NodeDatabase{NetworkAlias: "GoP2P_TestNet"}).WriteToMemory(peer.Environment) // Init peer database
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	identity, err := node.NewIdentity() // Init identity of dead peer
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	deadPeer := node.Node{Address: "memory://ping-test-dead"} // Init dead peer (not listening)
+
+	deadPeer.SetIdentity(identity) // Set identity
+
+	db := &database.NodeDatabase{NetworkAlias: "GoP2P_TestNet", Nodes: &[]node.Node{{Address: peer.Address}, deadPeer}} // Init database (peer identity unknown)
+
+	results := db.PingPeers(localNode, 3000) // Ping peers
+
+	if len(results) != 1 || results[0].Responder.NodeID != peer.NodeID || results[0].RTT <= 0 { // Check only live peer answered
+		t.Errorf("invalid ping results %v", results) // Log found error
+		t.FailNow()                                  // Panic
+	}
+
+	nodeIndex, err := db.QueryForNodeID(peer.NodeID) // Query for verified peer identity
+
+	if err != nil || (*db.Nodes)[nodeIndex].LastPingTime.IsZero() { // Check peer identity was learned, peer was marked seen
+		t.Errorf("peer wasn't marked alive") // Log found error
+		t.FailNow()                          // Panic
+	}
+
+	if liveness := db.PeerLivenessOf(&(*db.Nodes)[nodeIndex]); liveness == nil || liveness.Failures != 0 || liveness.RTT <= 0 { // Check peer liveness was recorded
+		t.Errorf("invalid peer liveness %v", liveness) // Log found error
+		t.FailNow()                                    // Panic
+	}
+
+	if liveness := db.PeerLivenessOf(&deadPeer); liveness == nil || liveness.Failures != 1 || liveness.LastError == "" { // Check failure was recorded
+		t.Errorf("invalid dead peer liveness %v", liveness) // Log found error
+		t.FailNow()                                         // Panic
+	}
+
+	peerDb, err := database.ReadDatabaseFromMemory(peer.Environment, "GoP2P_TestNet") // Read peer database
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if nodeIndex, err = peerDb.QueryForNodeID(localNode.NodeID); err != nil || (*peerDb.Nodes)[nodeIndex].LastPingTime.IsZero() { // Check peer recorded sender
+		t.Errorf("peer didn't record authenticated sender") // Log found error
+		t.FailNow()                                         // Panic
+	}
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"net"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/types/database"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+/* BEGIN INTERNAL METHODS */
+
+// handleMembership - answer membership message with an ack from the local node's membership of requested network
+func handleMembership(node *node.Node, conn net.Conn, codec common.Codec, payload []byte) ([]byte, error) {
+	message, err := database.DecodeMembershipMessage(codec, payload) // Decode message
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	membership := database.MembershipOf(node, message.Network) // Fetch membership
+
+	if membership == nil { // Check node hasn't joined network
+		return nil, database.ErrNotMember // Return error
+	}
+
+	ack, err := membership.HandleMessage(message) // Answer message
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return ack.Encode(codec) // Return encoded ack
+}
+
+/* END INTERNAL METHODS */
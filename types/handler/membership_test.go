@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/types/database"
+	"github.com/dowlandaiello/GoP2P/types/environment"
+	"github.com/dowlandaiello/GoP2P/types/node"
+	"github.com/dowlandaiello/GoP2P/types/shard"
+)
+
+// TestMembership - test convergence, graceful leaves and failure detection of memberships of in-process nodes
+func TestMembership(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background()) // Init context
+
+	defer cancel() // Stop handlers, memberships
+
+	nodes := []*node.Node{}                 // Init nodes buffer
+	memberships := []*database.Membership{} // Init memberships buffer
+	stops := []context.CancelFunc{}         // Init stop buffer
+
+	for x := 0; x < 5; x++ { // Start nodes
+		testNode, membership, stop := startMembershipNode(ctx, t, "memory://membership-test-"+strconv.Itoa(x)) // Start node
+
+		nodes, memberships, stops = append(nodes, testNode), append(memberships, membership), append(stops, stop) // Append node
+	}
+
+	failedShard, err := shard.NewShard(nodes[4]) // Init shard of node that will fail
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	err = (&database.NodeDatabase{NetworkAlias: "GoP2P_TestNet", Shards: &[]shard.Shard{*failedShard}}).WriteToMemory(nodes[0].Environment) // Init seed database
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	for x := 1; x < len(nodes); x++ { // Iterate through joining nodes
+		if joined, err := memberships[x].Join(nodes[0].Address); err != nil || joined != 1 { // Join through first node
+			t.Errorf("node %d couldn't join (%v)", x, err) // Log found error
+			t.FailNow()                                    // Panic
+		}
+	}
+
+	waitForMembers(t, memberships, 5) // Wait on every node to learn every member
+
+	for x := 1; x < len(nodes); x++ { // Iterate through joined nodes
+		if !knowsPeer(nodes[0], nodes[x]) { // Check join was fed into database
+			t.Errorf("seed database doesn't contain node %d", x) // Log found error
+			t.FailNow()                                          // Panic
+		}
+	}
+
+	err = memberships[3].Leave() // Leave gracefully
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	stops[3]() // Stop leaving node
+
+	waitForMembers(t, []*database.Membership{memberships[0], memberships[1], memberships[2], memberships[4]}, 4) // Wait on leave to spread
+
+	if member, _ := memberships[1].Member(nodes[3].NodeID); member.State != database.MemberLeft { // Check leave wasn't mistaken for failure
+		t.Errorf("expected left member, found %s member", member.State) // Log found error
+		t.FailNow()                                                     // Panic
+	}
+
+	stops[4]() // Crash node
+
+	waitForMembers(t, memberships[:3], 3) // Wait on failure to be detected
+
+	for x := 0; x < 3; x++ { // Iterate through remaining nodes
+		if member, _ := memberships[x].Member(nodes[4].NodeID); member.State != database.MemberDead { // Check failure was detected
+			t.Errorf("expected dead member, found %s member", member.State) // Log found error
+			t.FailNow()                                                     // Panic
+		}
+
+		if knowsPeer(nodes[x], nodes[3]) || knowsPeer(nodes[x], nodes[4]) { // Check leave, failure were fed into database
+			t.Errorf("node %d database contains departed nodes", x) // Log found error
+			t.FailNow()                                             // Panic
+		}
+	}
+
+	db, err := database.ReadDatabaseFromMemory(nodes[0].Environment, "GoP2P_TestNet") // Read seed database
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if len(*(*db.Shards)[0].Nodes) != 0 { // Check failed node was removed from shard
+		t.Errorf("shard contains failed node") // Log found error
+		t.FailNow()                            // Panic
+	}
+}
+
+/*
+	BEGIN HELPER METHODS:
+*/
+
+// startMembershipNode - start handler, membership of the test network of new node listening on given memory address (both are stopped by the returned function)
+func startMembershipNode(ctx context.Context, t *testing.T, address string) (*node.Node, *database.Membership, context.CancelFunc) {
+	env, err := environment.NewEnvironment() // Init environment
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	identity, err := node.NewIdentity() // Init identity
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	testNode := &node.Node{Address: address, Environment: env} // Init node
+
+	testNode.SetIdentity(identity) // Set identity
+
+	handler, err := NewHandler(testNode, "", address+":3000") // Init handler
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	err = handler.Listen() // Listen on address
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	nodeCtx, stop := context.WithCancel(ctx) // Init node context
+
+	go handler.Start(nodeCtx) // Start handler
+
+	membership, err := database.NewMembership(testNode, "GoP2P_TestNet", 3000) // Init membership
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	membership.Config.ProbeInterval, membership.Config.ProbeTimeout, membership.Config.SuspicionTimeout = 50*time.Millisecond, 250*time.Millisecond, 500*time.Millisecond // Speed up protocol
+
+	go membership.Start(nodeCtx) // Start probing
+
+	return testNode, membership, stop // Return node
+}
+
+// waitForMembers - wait at most 10 seconds on each of given memberships to count given number of alive members
+func waitForMembers(t *testing.T, memberships []*database.Membership, count int) {
+	deadline := time.Now().Add(10 * time.Second) // Init deadline
+
+	for time.Now().Before(deadline) { // Wait on convergence
+		converged := true // Init converged buffer
+
+		for _, membership := range memberships { // Iterate through memberships
+			alive := 0 // Init alive counter
+
+			for _, member := range membership.Members() { // Iterate through members
+				if member.State == database.MemberAlive { // Check member alive
+					alive++ // Increment alive
+				}
+			}
+
+			if alive != count || len(membership.Members()) != count { // Check membership hasn't converged
+				converged = false // Set not converged
+			}
+		}
+
+		if converged { // Check converged
+			return // Done
+		}
+
+		time.Sleep(50 * time.Millisecond) // Wait
+	}
+
+	for x, membership := range memberships { // Iterate through memberships
+		t.Logf("membership %d: %v", x, membership.Members()) // Log membership
+	}
+
+	t.Errorf("memberships didn't converge to %d members", count) // Log found error
+	t.FailNow()                                                  // Panic
+}
+
+// knowsPeer - check database of test network stored in given node environment contains given peer
+func knowsPeer(testNode *node.Node, peer *node.Node) bool {
+	databaseMutex.Lock() // Lock databases
+
+	defer databaseMutex.Unlock() // Unlock databases
+
+	db, err := database.ReadDatabaseFromMemory(testNode.Environment, "GoP2P_TestNet") // Read network database
+
+	if err != nil { // Check for missing database
+		return false // Peer not known
+	}
+
+	_, err = db.QueryForNodeID(peer.NodeID) // Query for peer
+
+	return err == nil // Check peer found
+}
+
+/*
+	END HELPER METHODS
+*/
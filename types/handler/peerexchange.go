@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"errors"
+	"net"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/types/database"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+/* BEGIN INTERNAL METHODS */
+
+// handlePeerExchange - respond to peer exchange with a sample of peers of requested network database, learning sender and its peers if it is the authenticated peer
+func handlePeerExchange(node *node.Node, conn net.Conn, codec common.Codec, payload []byte) ([]byte, error) {
+	request, err := database.DecodePeerExchangeRequest(codec, payload) // Decode request
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	impersonator := "" // Init impersonating peer buffer
+
+	if !verifySender(conn, request.Sender) { // Check sender isn't authenticated peer
+		if request.Sender != nil && request.Sender.NodeID != "" { // Check sender claimed an identity
+			impersonator = connectionPeerID(conn) // Peer claimed another peer's identity
+		}
+
+		request.Sender = nil // Don't learn from unverified sender
+	}
+
+	if node.Environment == nil { // Check for nil environment
+		return nil, errors.New("node has no environment to read database from") // Return error
+	}
+
+	databaseMutex.Lock() // Lock databases
+
+	defer databaseMutex.Unlock() // Unlock databases
+
+	db, err := database.ReadDatabaseFromMemory(node.Environment, request.Network) // Read network database
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if impersonator != "" { // Check for impersonation
+		if _, err = db.RecordEvent(impersonator, database.ReputationViolation); err == nil { // Penalize known peer
+			db.WriteToMemory(node.Environment) // Write db to memory
+		}
+
+		return nil, errors.New("sender isn't the authenticated peer") // Return error
+	}
+
+	response, err := db.HandlePeerExchange(node, request, database.DefaultPeerExchangeLimits) // Exchange peers
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if request.Sender != nil { // Check sender was learned
+		err = db.WriteToMemory(node.Environment) // Write db to memory
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+	}
+
+	common.Printf("\n-- PEX -- sent %d peers to %s", len(response.Peers), conn.RemoteAddr().String()) // Log exchange
+
+	return response.Encode(codec) // Return encoded response
+}
+
+/* END INTERNAL METHODS */
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/types/database"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+// TestPeerExchange - test functionality of Bootstrap() over handlers that each only know the next two nodes
+func TestPeerExchange(t *testing.T) {
+	nodes := []*node.Node{} // Init nodes buffer
+
+	for x := 0; x < 6; x++ { // Start handlers
+		nodes = append(nodes, startMemoryHandler(t, "memory://pex-test-"+strconv.Itoa(x))) // Append node
+	}
+
+	for x, testNode := range nodes { // Iterate through nodes
+		db := &database.NodeDatabase{NetworkAlias: "GoP2P_TestNet"} // Init database
+
+		db.MergePeers(testNode, []node.Node{*nodes[(x+1)%len(nodes)], *nodes[(x+2)%len(nodes)]}, 2) // Add next two nodes
+
+		for y := range *db.Nodes { // Iterate through neighbours
+			(*db.Nodes)[y].LastPingTime = time.Now() // Set neighbour seen
+		}
+
+		err := db.WriteToMemory(testNode.Environment) // Write db to memory
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+	}
+
+	newNode := startMemoryHandler(t, "memory://pex-test-new") // Start new node
+
+	db := &database.NodeDatabase{NetworkAlias: "GoP2P_TestNet"} // Init empty database
+
+	err := db.Bootstrap(newNode, nodes[0].Address, 3000, 10) // Bootstrap from first node
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if len(*db.Nodes) != len(nodes) { // Check every node was learned
+		t.Errorf("expected new node to learn %d peers, found %d", len(nodes), len(*db.Nodes)) // Log found error
+		t.FailNow()                                                                           // Panic
+	}
+
+	bootstrapDb, err := database.ReadDatabaseFromMemory(nodes[0].Environment, "GoP2P_TestNet") // Read bootstrap node database
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if _, err = bootstrapDb.QueryForNodeID(newNode.NodeID); err != nil { // Check bootstrap node learned new node
+		t.Errorf("expected bootstrap node to learn new node") // Log found error
+		t.FailNow()                                           // Panic
+	}
+}
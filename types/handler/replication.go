@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"errors"
+	"net"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/types/database"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+/* BEGIN INTERNAL METHODS */
+
+// handleReplication - apply operations pushed to requested network database by an authenticated peer, responding with the operations the sender is missing (if requested)
+func handleReplication(node *node.Node, conn net.Conn, codec common.Codec, payload []byte) ([]byte, error) {
+	request, err := database.DecodeReplicationRequest(codec, payload) // Decode request
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if node.Environment == nil { // Check for nil environment
+		return nil, errors.New("node has no environment to read database from") // Return error
+	}
+
+	databaseMutex.Lock() // Lock databases
+
+	defer databaseMutex.Unlock() // Unlock databases
+
+	db, err := database.ReadDatabaseFromMemory(node.Environment, request.Network) // Read network database
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if peerID := connectionPeerID(conn); peerID == "" || db.IsBanned(peerID) { // Check sender isn't authenticated, is banned
+		request.Operations = nil // Don't apply operations of unauthenticated sender
+	}
+
+	response := db.HandleReplication(request) // Apply operations
+
+	if len(request.Operations) > 0 { // Check operations were pushed
+		err = db.WriteToMemory(node.Environment) // Write db to memory
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+	}
+
+	return response.Encode(codec) // Return encoded response
+}
+
+/* END INTERNAL METHODS */
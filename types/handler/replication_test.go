@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/types/database"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+// TestReplication - test functionality of Synchronize(), AddNode() replicating operations between handlers
+func TestReplication(t *testing.T) {
+	source := startMemoryHandler(t, "memory://replication-test-source") // Start node holding operations
+
+	replica := startMemoryHandler(t, "memory://replication-test-replica") // Start node catching up (local node of test process)
+
+	sourceDb := &database.NodeDatabase{NetworkAlias: "GoP2P_TestNet"} // Init source database
+
+	operations := []database.Operation{
+		{Origin: source.NodeID, Sequence: 1, Kind: database.OperationAddNode, Node: &node.Node{NodeID: source.NodeID, PublicKey: source.PublicKey, Address: source.Address}},    // Add source
+		{Origin: source.NodeID, Sequence: 2, Kind: database.OperationAddNode, Node: &node.Node{NodeID: replica.NodeID, PublicKey: replica.PublicKey, Address: replica.Address}}, // Add replica
+	} // Init operations recorded by source
+
+	for x := range operations { // Iterate through operations
+		if err := operations[x].Sign(source.Identity()); err != nil { // Sign operation
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+	}
+
+	sourceDb.ApplyOperations(operations) // Apply operations recorded by source
+
+	err := sourceDb.WriteToMemory(source.Environment) // Write db to memory
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	db := &database.NodeDatabase{NetworkAlias: "GoP2P_TestNet"} // Init empty replica database
+
+	db.SetLocalNode(replica) // Replicate on behalf of replica
+
+	applied, err := db.Synchronize(source, 3000) // Catch up with source
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if _, err = db.QueryForNodeID(replica.NodeID); applied != 2 || err != nil || db.Versions[source.NodeID] != 2 { // Check operations were applied
+		t.Errorf("expected 2 applied operations, found %d", applied) // Log found error
+		t.FailNow()                                                  // Panic
+	}
+
+	added := &node.Node{Address: "memory://replication-test-added"} // Init added node
+
+	err = db.AddNode(added) // Add node, pushing addition to source
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	for x := 0; x < 50; x++ { // Wait for push
+		databaseMutex.Lock() // Lock databases
+
+		sourceDb, err = database.ReadDatabaseFromMemory(source.Environment, "GoP2P_TestNet") // Read source database
+
+		databaseMutex.Unlock() // Unlock databases
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if _, err = sourceDb.QueryForAddress(added.Address); err == nil { // Check source learned added node
+			return // Success
+		}
+
+		time.Sleep(100 * time.Millisecond) // Wait
+	}
+
+	t.Errorf("expected source to learn node added by replica") // Log found error
+	t.FailNow()                                                // Panic
+}
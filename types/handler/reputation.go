@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"net"
+	"strings"
+
+	"github.com/dowlandaiello/GoP2P/types/database"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+// malformedError - error returned by envelope handlers when a peer sends a payload that can't be decoded or is invalid (penalized with database.ReputationMalformed)
+type malformedError struct {
+	err error // err - decoding, validation error
+}
+
+// Error - fetch message of decoding, validation error
+func (err *malformedError) Error() string {
+	return err.err.Error() // Return message
+}
+
+/* BEGIN INTERNAL METHODS */
+
+// peerBanned - check peer with given NodeID is banned in network databases of given network (every network database of given node if "")
+func peerBanned(node *node.Node, network string, peerID string) bool {
+	if peerID == "" || node.Environment == nil { // Check for anonymous peer, nil environment
+		return false // Can't be banned
+	}
+
+	databaseMutex.Lock() // Lock databases
+
+	defer databaseMutex.Unlock() // Unlock databases
+
+	for _, alias := range peerNetworks(node, network) { // Iterate through networks
+		if db, err := database.ReadDatabaseFromMemory(node.Environment, alias); err == nil && db.IsBanned(peerID) { // Check peer is banned
+			return true // Banned
+		}
+	}
+
+	return false // Not banned
+}
+
+// recordPeerEvent - adjust reputation of peer authenticated on given connection in network databases of given network (every network database of given node if ""), writing adjusted databases to node environment
+func recordPeerEvent(node *node.Node, conn net.Conn, network string, event database.ReputationEvent) {
+	peerID := connectionPeerID(conn) // Fetch verified peer ID
+
+	if peerID == "" || node.Environment == nil { // Check for unauthenticated peer, nil environment
+		return // Can't score peer
+	}
+
+	databaseMutex.Lock() // Lock databases
+
+	defer databaseMutex.Unlock() // Unlock databases
+
+	for _, alias := range peerNetworks(node, network) { // Iterate through networks
+		db, err := database.ReadDatabaseFromMemory(node.Environment, alias) // Read network database
+
+		if err != nil { // Check for errors
+			continue // Skip network
+		}
+
+		if _, err = db.RecordEvent(peerID, event); err == nil { // Score known peer
+			db.WriteToMemory(node.Environment) // Write db to memory
+		}
+	}
+}
+
+// peerNetworks - fetch aliases of network databases a peer connected to given network is scored in (every network database of given node if ""). Must be called while holding databaseMutex.
+func peerNetworks(node *node.Node, network string) []string {
+	if network != "" { // Check peer specified network
+		return []string{network} // Return network
+	}
+
+	networks := []string{} // Init networks buffer
+
+	for _, variable := range node.Environment.EnvironmentVariables { // Iterate through variables
+		if alias := strings.TrimSuffix(variable.VariableType, "NodeDatabase"); alias != "" && alias != variable.VariableType { // Check for network database
+			networks = append(networks, alias) // Append network
+		}
+	}
+
+	return networks // Return networks
+}
+
+/* END INTERNAL METHODS */
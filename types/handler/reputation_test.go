@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/types/database"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+// TestPeerReputation - test handlers penalize peers sending malformed data, refuse banned peers
+func TestPeerReputation(t *testing.T) {
+	peer := startMemoryHandler(t, "memory://reputation-test-peer") // Start peer
+
+	localNode := startMemoryHandler(t, "memory://reputation-test-local") // Start local node (presents its certificate on outgoing connections)
+
+	db := &database.NodeDatabase{NetworkAlias: "GoP2P_TestNet", Nodes: &[]node.Node{{Address: localNode.Address, NodeID: localNode.NodeID, Reputation: database.DefaultReputationPolicy.NeutralReputation, LastPingTime: time.Now()}}} // Init peer database
+
+	err := db.WriteToMemory(peer.Environment) // Write db to memory
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	address := peer.DialAddress(3000) // Init peer address
+
+	pool, err := localNode.ConnectionPool() // Fetch pool presenting local node certificate
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if _, err = pool.Request(address, peer.NodeID, []byte("malformed")); err == nil { // Send malformed request
+		t.Errorf("expected malformed request to be rejected") // Log found error
+		t.FailNow()                                           // Panic
+	}
+
+	db, err = database.ReadDatabaseFromMemory(peer.Environment, "GoP2P_TestNet") // Read peer database
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if (*db.Nodes)[0].Reputation >= database.DefaultReputationPolicy.NeutralReputation { // Check sender was penalized
+		t.Errorf("expected sender of malformed request to be penalized, found reputation %d", (*db.Nodes)[0].Reputation) // Log found error
+		t.FailNow()                                                                                                      // Panic
+	}
+
+	db.Ban(localNode.NodeID, time.Minute) // Ban sender
+
+	err = db.WriteToMemory(peer.Environment) // Write db to memory
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	pool.Evict(address) // Close session
+
+	if _, err = database.PingNode(localNode, peer, "GoP2P_TestNet", 3000); err == nil { // Ping peer
+		t.Errorf("expected banned peer to be refused") // Log found error
+		t.FailNow()                                    // Panic
+	}
+}
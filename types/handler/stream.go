@@ -0,0 +1,315 @@
+package handler
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"github.com/dowlandaiello/GoP2P/types/connection"
+	"github.com/dowlandaiello/GoP2P/types/environment"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+var (
+	// StreamDirectory - directory chunked transfers are written to (partial transfers are kept as <sender NodeID>_<transfer ID>.part, allowing senders to resume them)
+	StreamDirectory = "streams"
+
+	// MaxStreamSize - maximum number of bytes accepted in a single transfer
+	MaxStreamSize int64 = 1024 * 1024 * 1024
+
+	// MaxTransfersPerPeer - maximum number of partial transfers kept for a single sender
+	MaxTransfersPerPeer = 8
+
+	// StreamTransferTimeout - duration after which partial transfers that haven't received a chunk are removed
+	StreamTransferTimeout = 30 * time.Minute
+
+	transfers = make(map[string]*transfer) // transfers - transfers with chunks being handled, by transfer key (see transferKey)
+
+	transfersMutex sync.Mutex // transfersMutex - guards transfers
+)
+
+// transfer - write lock of a transfer, kept while chunks of the transfer are being handled
+type transfer struct {
+	mutex sync.Mutex // mutex - write lock
+
+	refs int // refs - number of chunks holding, waiting on write lock (guarded by transfersMutex)
+}
+
+/* BEGIN INTERNAL METHODS */
+
+// handleStreamChunk - verify received chunk, appending it to its partial transfer on disk (keyed by sender, transfer ID) and writing completed transfers to their sink
+func handleStreamChunk(node *node.Node, conn net.Conn, codec common.Codec, payload []byte) ([]byte, error) {
+	chunk, err := connection.DecodeChunk(codec, payload) // Decode chunk
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	err = validateChunk(node, chunk) // Validate chunk
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	peerID := connectionPeerID(conn) // Fetch verified sender ID
+
+	if peerID == "" { // Check for unauthenticated sender
+		return nil, errors.New("chunked transfers require an authenticated sender") // Return error
+	}
+
+	key := transferKey(peerID, chunk.TransferID) // Init transfer key
+
+	current := acquireTransfer(key) // Fetch transfer lock
+
+	defer releaseTransfer(key, current) // Release transfer lock once handled
+
+	current.mutex.Lock() // Lock transfer
+
+	defer current.mutex.Unlock() // Unlock transfer
+
+	err = os.MkdirAll(StreamDirectory, 0700) // Ensure stream directory exists
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	partPath := filepath.Join(StreamDirectory, key+".part") // Init partial transfer path
+
+	offset, err := streamOffset(partPath) // Fetch received offset
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if offset == 0 { // Check for new transfer
+		expireTransfers() // Remove stale partial transfers
+
+		if count := peerTransfers(peerID); count >= MaxTransfersPerPeer { // Check sender has too many partial transfers
+			return nil, fmt.Errorf("sender already has %d partial transfers", count) // Return error
+		}
+	}
+
+	ack := &connection.ChunkAck{TransferID: chunk.TransferID, Offset: offset} // Init acknowledgement
+
+	if chunk.Offset != offset || (len(chunk.Data) == 0 && !chunk.Final) { // Check for status query, misaligned chunk
+		return ack.Encode(codec) // Respond with received offset
+	}
+
+	err = chunk.Verify() // Verify checksum
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if offset+int64(len(chunk.Data)) > MaxStreamSize { // Check transfer exceeds maximum size
+		os.Remove(partPath) // Remove partial transfer
+
+		return nil, fmt.Errorf("transfer %s exceeds maximum size %d", chunk.TransferID, MaxStreamSize) // Return error
+	}
+
+	err = appendChunk(partPath, chunk.Data) // Write chunk
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	ack.Offset += int64(len(chunk.Data)) // Increment received offset
+
+	common.Printf("\n-- STREAM -- received %d bytes of transfer %s from %s", ack.Offset, chunk.TransferID, conn.RemoteAddr().String()) // Log chunk
+
+	if chunk.Final { // Check for last chunk
+		if chunk.Header.Size >= 0 && chunk.Header.Size != ack.Offset { // Check for size mismatch
+			os.Remove(partPath) // Remove partial transfer
+
+			return nil, fmt.Errorf("transfer %s size %d doesn't match declared size %d", chunk.TransferID, ack.Offset, chunk.Header.Size) // Return error
+		}
+
+		err = completeTransfer(node, chunk, key, partPath, ack.Offset) // Write to sink
+
+		if err != nil { // Check for errors
+			os.Remove(partPath) // Remove partial transfer
+
+			return nil, err // Return found error
+		}
+
+		ack.Complete = true // Set complete
+	}
+
+	return ack.Encode(codec) // Acknowledge chunk
+}
+
+// validateChunk - check chunk has a valid transfer ID and header (declaring at most MaxStreamSize bytes) that node can write to without replacing existing files
+func validateChunk(node *node.Node, chunk *connection.Chunk) error {
+	if _, err := hex.DecodeString(chunk.TransferID); err != nil || chunk.TransferID == "" { // Check for invalid transfer ID
+		return errors.New("invalid transfer ID") // Return error
+	} else if chunk.Header == nil || chunk.Header.Name == "" { // Check for nil header
+		return errors.New("invalid stream header") // Return error
+	} else if chunk.Header.Size > MaxStreamSize { // Check for oversized transfer
+		return fmt.Errorf("declared size %d exceeds maximum size %d", chunk.Header.Size, MaxStreamSize) // Return error
+	}
+
+	switch chunk.Header.Sink {
+	case connection.StreamSinkFile:
+		if name := filepath.Base(chunk.Header.Name); name != chunk.Header.Name || name == "." || name == ".." || strings.HasSuffix(name, ".part") { // Check for path outside stream directory, partial transfer name
+			return fmt.Errorf("invalid stream file name %s", chunk.Header.Name) // Return error
+		}
+
+		if _, err := os.Lstat(filepath.Join(StreamDirectory, chunk.Header.Name)); err == nil { // Check for existing file
+			return fmt.Errorf("stream file %s already exists", chunk.Header.Name) // Return error
+		}
+	case connection.StreamSinkVariable:
+		if node.Environment == nil { // Check for nil environment
+			return errors.New("node has no environment to stream variable to") // Return error
+		}
+	default:
+		return fmt.Errorf("invalid stream sink %s", chunk.Header.Sink) // Return error
+	}
+
+	return nil // Valid
+}
+
+// completeTransfer - move completed partial transfer with given key to its sink
+func completeTransfer(node *node.Node, chunk *connection.Chunk, key string, partPath string, size int64) error {
+	if chunk.Header.Sink == connection.StreamSinkFile { // Check for file sink
+		return moveTransfer(partPath, filepath.Join(StreamDirectory, chunk.Header.Name)) // Move to file
+	}
+
+	path, err := filepath.Abs(filepath.Join(StreamDirectory, key)) // Init data path
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	err = moveTransfer(partPath, path) // Move to data path
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	variable, err := environment.NewVariable(chunk.Header.Name, connection.StreamReference{TransferID: chunk.TransferID, Path: path, Size: size}) // Init variable referencing data
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	return node.Environment.AddVariable(variable, false) // Add variable
+}
+
+// streamOffset - fetch number of bytes received in partial transfer at given path
+func streamOffset(partPath string) (int64, error) {
+	info, err := os.Stat(partPath) // Stat partial transfer
+
+	if os.IsNotExist(err) { // Check for new transfer
+		return 0, nil // Nothing received
+	} else if err != nil { // Check for errors
+		return 0, err // Return found error
+	}
+
+	return info.Size(), nil // Return size
+}
+
+// appendChunk - append given data to partial transfer at given path
+func appendChunk(partPath string, data []byte) error {
+	file, err := os.OpenFile(partPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600) // Open partial transfer
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	_, err = file.Write(data) // Write data
+
+	if err != nil { // Check for errors
+		file.Close() // Close file
+
+		return err // Return found error
+	}
+
+	return file.Close() // Close file
+}
+
+// moveTransfer - move completed partial transfer at given path to given path, refusing to replace an existing file
+func moveTransfer(partPath string, path string) error {
+	err := os.Link(partPath, path) // Link to path (fails if path exists)
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	return os.Remove(partPath) // Remove partial transfer
+}
+
+// transferKey - fetch key of transfer with given ID sent by peer with given NodeID (transfer IDs are chosen by senders, so they are only unique per sender)
+func transferKey(peerID string, transferID string) string {
+	return peerID + "_" + transferID // Return key
+}
+
+// acquireTransfer - fetch write lock of transfer with given key, keeping it until released via releaseTransfer
+func acquireTransfer(key string) *transfer {
+	transfersMutex.Lock() // Lock transfers
+
+	defer transfersMutex.Unlock() // Unlock transfers
+
+	current, found := transfers[key] // Fetch transfer
+
+	if !found { // Check for new transfer
+		current = &transfer{} // Init transfer
+
+		transfers[key] = current // Set transfer
+	}
+
+	current.refs++ // Hold transfer
+
+	return current // Return transfer
+}
+
+// releaseTransfer - release write lock of transfer with given key, removing it once no chunks hold it
+func releaseTransfer(key string, current *transfer) {
+	transfersMutex.Lock() // Lock transfers
+
+	defer transfersMutex.Unlock() // Unlock transfers
+
+	if current.refs--; current.refs == 0 { // Check no chunks hold transfer
+		delete(transfers, key) // Remove transfer
+	}
+}
+
+// expireTransfers - remove partial transfers that haven't received a chunk within StreamTransferTimeout (skipping transfers with chunks being handled)
+func expireTransfers() {
+	paths, err := filepath.Glob(filepath.Join(StreamDirectory, "*.part")) // Fetch partial transfers
+
+	if err != nil { // Check for errors
+		return // Nothing to expire
+	}
+
+	transfersMutex.Lock() // Lock transfers
+
+	defer transfersMutex.Unlock() // Unlock transfers
+
+	for _, path := range paths { // Iterate through partial transfers
+		if _, active := transfers[strings.TrimSuffix(filepath.Base(path), ".part")]; active { // Check transfer is being written
+			continue // Skip transfer
+		}
+
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > StreamTransferTimeout { // Check transfer is stale
+			os.Remove(path) // Remove partial transfer
+
+			common.Printf("\n-- STREAM -- expired partial transfer %s", filepath.Base(path)) // Log expiry
+		}
+	}
+}
+
+// peerTransfers - fetch number of partial transfers sent by peer with given NodeID
+func peerTransfers(peerID string) int {
+	paths, _ := filepath.Glob(filepath.Join(StreamDirectory, transferKey(peerID, "*.part"))) // Fetch partial transfers of peer
+
+	return len(paths) // Return count
+}
+
+/* END INTERNAL METHODS */
@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/types/connection"
+	"github.com/dowlandaiello/GoP2P/types/environment"
+	"github.com/dowlandaiello/GoP2P/types/node"
+)
+
+// TestHandleStreamChunk - test chunked transfers to file, variable sinks, resuming interrupted transfers
+func TestHandleStreamChunk(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gop2p") // Init stream directory
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	defer os.RemoveAll(dir) // Remove stream directory
+
+	StreamDirectory = dir // Set stream directory
+
+	testNode := startMemoryHandler(t, "memory://stream-test") // Start handler
+
+	data := make([]byte, 1024*1024+17) // Init data buffer
+
+	rand.Read(data) // Generate data
+
+	stream, err := connection.NewStream(testNode, testNode, 3000, connection.StreamSinkFile, "test.bin", int64(len(data))) // Init stream
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	stream.ChunkSize = 64 * 1024 // Set chunk size
+
+	if err = stream.Send(bytes.NewReader(data[:3*stream.ChunkSize])); err == nil { // Send truncated data
+		t.Errorf("expected truncated transfer to be rejected") // Log found error
+		t.FailNow()                                            // Panic
+	}
+
+	stream.Offset = 0 // Reset offset (simulate restarted sender)
+
+	err = stream.Resume(bytes.NewReader(data)) // Resume transfer
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if received, err := ioutil.ReadFile(filepath.Join(dir, "test.bin")); err != nil || !bytes.Equal(received, data) { // Check received data
+		t.Errorf("invalid received data (%v)", err) // Log found error
+		t.FailNow()                                 // Panic
+	}
+
+	stream, err = connection.NewStream(testNode, testNode, 3000, connection.StreamSinkVariable, "testStream", -1) // Init variable stream
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	err = stream.Send(bytes.NewReader(data)) // Send data
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	variable, err := testNode.Environment.QueryType("testStream") // Query variable
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	reader, err := connection.OpenStreamVariable(variable) // Open streamed data
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	defer reader.Close() // Close reader
+
+	if received, err := ioutil.ReadAll(reader); err != nil || !bytes.Equal(received, data) { // Check received data
+		t.Errorf("invalid received variable data (%v)", err) // Log found error
+		t.FailNow()                                          // Panic
+	}
+
+	stream, err = connection.NewStream(testNode, testNode, 3000, connection.StreamSinkFile, "../test.bin", -1) // Init stream outside stream directory
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if err = stream.Send(bytes.NewReader(data)); err == nil { // Send data
+		t.Errorf("expected file name outside stream directory to be rejected") // Log found error
+		t.FailNow()                                                            // Panic
+	}
+
+	for _, name := range []string{"test.bin", "test.part"} { // Iterate through existing, partial transfer names
+		stream, err = connection.NewStream(testNode, testNode, 3000, connection.StreamSinkFile, name, -1) // Init stream
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		if err = stream.Send(bytes.NewReader(data)); err == nil { // Send data
+			t.Errorf("expected file name %s to be rejected", name) // Log found error
+			t.FailNow()                                            // Panic
+		}
+	}
+
+	MaxStreamSize = int64(len(data) - 1) // Limit transfer size
+
+	defer func() { MaxStreamSize = 1024 * 1024 * 1024 }() // Reset transfer size
+
+	stream, err = connection.NewStream(testNode, testNode, 3000, connection.StreamSinkFile, "oversized.bin", -1) // Init stream without declared size
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if err = stream.Send(bytes.NewReader(data)); err == nil { // Send data
+		t.Errorf("expected oversized transfer to be rejected") // Log found error
+		t.FailNow()                                            // Panic
+	}
+
+	if parts, _ := filepath.Glob(filepath.Join(dir, "*.part")); len(parts) != 0 { // Check rejected transfers were removed
+		t.Errorf("expected rejected partial transfers to be removed, found %v", parts) // Log found error
+		t.FailNow()                                                                    // Panic
+	}
+}
+
+// TestExpireTransfers - test partial transfers that haven't received a chunk within StreamTransferTimeout are removed
+func TestExpireTransfers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gop2p") // Init stream directory
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	defer os.RemoveAll(dir) // Remove stream directory
+
+	StreamDirectory = dir // Set stream directory
+
+	stale, active := filepath.Join(dir, transferKey("a", "01.part")), filepath.Join(dir, transferKey("b", "01.part")) // Init partial transfer paths
+
+	for _, path := range []string{stale, active} { // Iterate through paths
+		err = ioutil.WriteFile(path, []byte("partial"), 0600) // Write partial transfer
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+
+		err = os.Chtimes(path, time.Now().Add(-2*StreamTransferTimeout), time.Now().Add(-2*StreamTransferTimeout)) // Make partial transfer stale
+
+		if err != nil { // Check for errors
+			t.Errorf(err.Error()) // Log found error
+			t.FailNow()           // Panic
+		}
+	}
+
+	current := acquireTransfer(transferKey("b", "01")) // Hold active transfer
+
+	expireTransfers() // Expire transfers
+
+	releaseTransfer(transferKey("b", "01"), current) // Release active transfer
+
+	if _, err = os.Stat(stale); !os.IsNotExist(err) { // Check stale transfer was removed
+		t.Errorf("expected stale partial transfer to be removed") // Log found error
+		t.FailNow()                                               // Panic
+	}
+
+	if _, err = os.Stat(active); err != nil { // Check active transfer was kept
+		t.Errorf("expected active partial transfer to be kept (%v)", err) // Log found error
+		t.FailNow()                                                       // Panic
+	}
+
+	if len(transfers) != 0 { // Check released transfer was removed
+		t.Errorf("expected released transfer to be removed, found %d transfers", len(transfers)) // Log found error
+		t.FailNow()                                                                              // Panic
+	}
+}
+
+// startMemoryHandler - start handler for new node with environment on given in-process address, port 3000
+func startMemoryHandler(t *testing.T, address string) *node.Node {
+	env, err := environment.NewEnvironment() // Init environment
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	identity, err := node.NewIdentity() // Init identity
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	testNode := &node.Node{Address: address, Environment: env} // Init node
+
+	testNode.SetIdentity(identity) // Set identity
+
+	ln, err := testNode.Listen(address + ":3000") // Listen on address
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	go StartHandler(testNode, ln) // Start handler
+
+	return testNode // Return node
+}
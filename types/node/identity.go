@@ -0,0 +1,92 @@
+package node
+
+import (
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/dowlandaiello/GoP2P/common"
+	"golang.org/x/crypto/ed25519"
+)
+
+const (
+	// identityPEMType - PEM block type of a persisted identity private key
+	identityPEMType = "ED25519 PRIVATE KEY"
+)
+
+// Identity - Ed25519 keypair uniquely identifying a node
+type Identity struct {
+	PublicKey  ed25519.PublicKey  // PublicKey - public key (NodeID is derived from this key)
+	PrivateKey ed25519.PrivateKey // PrivateKey - private key (never leaves the local node)
+}
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// NewIdentity - generate new Ed25519 identity
+func NewIdentity() (*Identity, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader) // Generate keypair
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return &Identity{PublicKey: publicKey, PrivateKey: privateKey}, nil // Return identity
+}
+
+// NodeIDFromPublicKey - derive NodeID from given public key
+func NodeIDFromPublicKey(publicKey []byte) string {
+	return common.Sha3(publicKey) // Return hashed public key
+}
+
+// VerifySignature - verify signature of given message was produced by given public key
+func VerifySignature(publicKey []byte, message []byte, signature []byte) bool {
+	if len(publicKey) != ed25519.PublicKeySize { // Check for invalid key
+		return false // Invalid key
+	}
+
+	return ed25519.Verify(ed25519.PublicKey(publicKey), message, signature) // Verify signature
+}
+
+// NodeID - fetch NodeID derived from identity public key
+func (identity *Identity) NodeID() string {
+	return NodeIDFromPublicKey(identity.PublicKey) // Return NodeID
+}
+
+// Sign - sign given message with identity private key
+func (identity *Identity) Sign(message []byte) []byte {
+	return ed25519.Sign(identity.PrivateKey, message) // Return signature
+}
+
+// WriteToMemory - persist identity private key in specified path (string)
+func (identity *Identity) WriteToMemory(path string) error {
+	block := &pem.Block{Type: identityPEMType, Bytes: identity.PrivateKey} // Init PEM block
+
+	return ioutil.WriteFile(path+filepath.FromSlash("/node.key"), pem.EncodeToMemory(block), 0600) // Write key
+}
+
+// ReadIdentityFromMemory - read identity private key persisted in specified path
+func ReadIdentityFromMemory(path string) (*Identity, error) {
+	data, err := ioutil.ReadFile(path + filepath.FromSlash("/node.key")) // Read key
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	block, _ := pem.Decode(data) // Decode PEM block
+
+	if block == nil || block.Type != identityPEMType || len(block.Bytes) != ed25519.PrivateKeySize { // Check for invalid key
+		return nil, errors.New("invalid identity key") // Return found error
+	}
+
+	privateKey := ed25519.PrivateKey(block.Bytes) // Init private key
+
+	return &Identity{PublicKey: privateKey.Public().(ed25519.PublicKey), PrivateKey: privateKey}, nil // Return identity
+}
+
+/*
+	END EXPORTED METHODS:
+*/
@@ -0,0 +1,64 @@
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// TestNewIdentity - test functionality of NewIdentity() method
+func TestNewIdentity(t *testing.T) {
+	identity, err := NewIdentity() // Generate identity
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	signature := identity.Sign([]byte("test")) // Sign message
+
+	if !VerifySignature(identity.PublicKey, []byte("test"), signature) { // Verify signature
+		t.Errorf("invalid signature") // Log found error
+		t.FailNow()                   // Panic
+	}
+
+	t.Logf("generated identity %s", identity.NodeID()) // Log success
+}
+
+// TestReadIdentityFromMemory - test functionality of ReadIdentityFromMemory() method
+func TestReadIdentityFromMemory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gop2p") // Init temp dir
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	defer os.RemoveAll(dir) // Remove temp dir
+
+	identity, err := NewIdentity() // Generate identity
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	err = identity.WriteToMemory(dir) // Write identity
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	readIdentity, err := ReadIdentityFromMemory(dir) // Read identity
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if readIdentity.NodeID() != identity.NodeID() { // Check for mismatch
+		t.Errorf("read identity %s does not match %s", readIdentity.NodeID(), identity.NodeID()) // Log found error
+		t.FailNow()                                                                              // Panic
+	}
+}
@@ -4,23 +4,45 @@ import (
 	"crypto/tls"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net"
 	"os"
 	"path/filepath"
-	"strconv"
 	"time"
 
 	"github.com/dowlandaiello/GoP2P/common"
 	"github.com/dowlandaiello/GoP2P/types/environment"
 )
 
+const (
+	// ReachabilityUnknown - reachability of node hasn't been detected
+	ReachabilityUnknown = Reachability("")
+
+	// ReachabilityPublic - node accepts connections dialed directly by peers
+	ReachabilityPublic = Reachability("public")
+
+	// ReachabilityPrivate - node is behind a NAT or firewall, and can only be dialed through a relay (or after hole punching)
+	ReachabilityPrivate = Reachability("private")
+)
+
+// Reachability - whether peers can dial a node directly
+type Reachability string
+
 // Node - abstract struct containing metadata for a node
 type Node struct {
-	Address      string                   `json:"IP address"`   // Node's IP address
-	Reputation   uint                     `json:"reputation"`   // Node's reputation (used for node finding algorithm)
-	LastPingTime time.Time                `json:"ping"`         // Last time that the node was pinged successfully (also used for node finding algorithm)
-	IsBootstrap  bool                     `json:"is bootstrap"` // Value used for checking whether or not a specific node is a bootstrap node (again, used for node finding algorithm)
-	Environment  *environment.Environment `json:"environment"`  // Used for variable storage and referencing
+	NodeID       string                   `json:"id"`                     // Node's unique identifier (hash of node's public key)
+	PublicKey    []byte                   `json:"public key"`             // Node's Ed25519 public key
+	Address      string                   `json:"IP address"`             // Node's current IP address (mutable attribute of identity)
+	Reputation   uint                     `json:"reputation"`             // Node's reputation (used for node finding algorithm)
+	LastPingTime time.Time                `json:"ping"`                   // Last time that the node was pinged successfully (also used for node finding algorithm)
+	IsBootstrap  bool                     `json:"is bootstrap"`           // Value used for checking whether or not a specific node is a bootstrap node (again, used for node finding algorithm)
+	Reachability Reachability             `json:"reachability,omitempty"` // Whether node can be dialed directly (relayed nodes advertise a relay:// address)
+	Addresses    []string                 `json:"addresses,omitempty"`    // Self-describing addresses node can be dialed on, in order of preference (e.g. /ip4/1.2.3.4/tcp/3000/tls/p2p/<NodeID>)
+	Environment  *environment.Environment `json:"environment"`            // Used for variable storage and referencing
+
+	identity *Identity // Node's private identity (only set for the local node)
+
+	certificate *tls.Certificate // Node's TLS certificate, bound to identity (only set for the local node)
 }
 
 /*
@@ -39,7 +61,25 @@ func NewNode(address string, isBootstrap bool) (Node, error) {
 		return Node{}, errors.New("invalid init values") // Return error
 	}
 
-	node := Node{Address: address, Reputation: 0, IsBootstrap: isBootstrap, Environment: environment} // Creates new node instance with specified address
+	identity, err := NewIdentity() // Generate identity
+
+	if err != nil { // Check for errors
+		return Node{}, err // Return error
+	}
+
+	parsedAddress, err := common.ParsePeerAddress(address) // Parse address (stripping IPv6 brackets)
+
+	if err != nil { // Check for errors
+		return Node{}, err // Return error
+	}
+
+	if parsedAddress.Port != 0 { // Check for port (peers append the port they dial)
+		return Node{}, fmt.Errorf("node address %s must not include a port", address) // Return error
+	}
+
+	node := Node{Address: parsedAddress.String(), Reputation: 0, IsBootstrap: isBootstrap, Environment: environment} // Creates new node instance with specified address
+
+	node.SetIdentity(identity) // Set node identity
 
 	err = common.CheckAddress(node.Address) // Verify address
 
@@ -53,9 +93,37 @@ func NewNode(address string, isBootstrap bool) (Node, error) {
 	return node, nil // No error occurred, return nil
 }
 
-// StartListener - attempt to listen on specified port, return new listener
+// StartListener - attempt to listen on specified TCP port over both IPv4 and IPv6, requiring peers to authenticate with node identity certificates, return new listener
 func (node *Node) StartListener(port int) (*net.Listener, error) {
-	ln, err := tls.Listen("tcp", ":"+strconv.Itoa(port), common.GeneralTLSConfig) // Listen on port
+	return node.Listen(common.PeerAddress{Port: port}.String()) // Listen on port of all addresses
+}
+
+// PeerAddress - fetch address peers dial node on at given port (e.g. [::1]:3000)
+func (node *Node) PeerAddress(port int) common.PeerAddress {
+	address, err := common.ParsePeerAddress(node.Address) // Parse address
+
+	if err != nil { // Check for errors
+		address = common.PeerAddress{Host: node.Address} // Use address as is
+	}
+
+	return address.WithPort(port) // Set port
+}
+
+// Listen - attempt to listen on given transport address (e.g. :3000, unix:///tmp/node.sock, memory://node:3000), requiring peers to authenticate with node identity certificates. Call once per address to listen on several transports at once.
+func (node *Node) Listen(address string) (*net.Listener, error) {
+	cert, err := node.Certificate() // Fetch node certificate
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	err = common.RegisterNodeCertificate(cert) // Let transports accepting connections on behalf of node present its certificate
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	ln, err := common.ListenAddress(address, common.ServerTLSConfig(cert)) // Listen on address
 
 	if err != nil { // Check for errors
 		return nil, err // Return found error
@@ -84,7 +152,70 @@ func (node *Node) String() string {
 	return string(marshaledVal) // No error occurred, return nil
 }
 
-// WriteToMemory - create serialized instance of specified environment in specified path (string)
+// Identity - fetch node's private identity (nil for remote nodes)
+func (node *Node) Identity() *Identity {
+	return node.identity // Return identity
+}
+
+// SetIdentity - set node's private identity, deriving NodeID and public key
+func (node *Node) SetIdentity(identity *Identity) {
+	node.identity = identity // Set identity
+	node.certificate = nil   // Reset certificate
+
+	node.PublicKey = []byte(identity.PublicKey) // Set public key
+	node.NodeID = identity.NodeID()             // Set NodeID
+}
+
+// Certificate - fetch node's TLS certificate, generating one bound to node identity if none exists
+func (node *Node) Certificate() (*tls.Certificate, error) {
+	if node.certificate != nil { // Check for existing certificate
+		return node.certificate, nil // Return certificate
+	}
+
+	if node.identity == nil { // Check for missing identity
+		return nil, errors.New("node has no identity") // Return error
+	}
+
+	cert, err := common.GenerateNodeCertificate(node.identity.PrivateKey) // Generate certificate
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	node.certificate = cert // Set certificate
+
+	return cert, nil // Return certificate
+}
+
+// ConnectionPool - fetch connection pool dialing peers on behalf of node, presenting node certificate (common.DefaultConnectionPool for nodes without a private identity)
+func (node *Node) ConnectionPool() (*common.ConnectionPool, error) {
+	if node == nil || node.identity == nil { // Check for remote node
+		return common.DefaultConnectionPool, nil // Return default pool
+	}
+
+	cert, err := node.Certificate() // Fetch certificate
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return common.NodeConnectionPool(cert) // Return pool
+}
+
+// VerifyIdentity - check node's NodeID was derived from node's public key
+func (node *Node) VerifyIdentity() error {
+	if node.NodeID == "" || len(node.PublicKey) == 0 { // Check for missing identity
+		return errors.New("node has no identity") // Return error
+	}
+
+	if NodeIDFromPublicKey(node.PublicKey) != node.NodeID { // Check for mismatch
+		return fmt.Errorf("NodeID %s does not match public key", node.NodeID) // Return error
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// WriteToMemory - create serialized instance of specified node in specified path (string), persisting node identity key alongside
 func (node *Node) WriteToMemory(path string) error {
 	os.Remove(path + filepath.FromSlash("/node.gob")) // Overwrite
 
@@ -94,10 +225,26 @@ func (node *Node) WriteToMemory(path string) error {
 		return err // Return error
 	}
 
+	if node.identity != nil { // Check for identity
+		err = node.identity.WriteToMemory(path) // Write identity key
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		cert, err := node.Certificate() // Fetch certificate
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		return common.WriteNodeCertificate(path, cert) // Write certificate
+	}
+
 	return nil // No error occurred, return nil.
 }
 
-// ReadNodeFromMemory - read serialized object of specified node from specified path
+// ReadNodeFromMemory - read serialized object of specified node from specified path, generating an identity for nodes persisted without one
 func ReadNodeFromMemory(path string) (*Node, error) {
 	tempNode := new(Node)
 
@@ -106,6 +253,45 @@ func ReadNodeFromMemory(path string) (*Node, error) {
 	if err != nil { // Check for errors
 		return nil, err // Return error
 	}
+
+	identity, err := ReadIdentityFromMemory(path) // Read identity key
+
+	if os.IsNotExist(err) && tempNode.NodeID == "" { // Check for node persisted before identities
+		identity, err = NewIdentity() // Generate identity
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		tempNode.SetIdentity(identity) // Set identity
+
+		return tempNode, tempNode.WriteToMemory(path) // Persist migrated node
+	} else if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	if identity.NodeID() != tempNode.NodeID && tempNode.NodeID != "" { // Check key belongs to node
+		return nil, fmt.Errorf("identity key in %s does not match node %s", path, tempNode.NodeID) // Return error
+	}
+
+	tempNode.SetIdentity(identity) // Set identity
+
+	cert, err := common.ReadNodeCertificate(path) // Read certificate
+
+	if err == nil { // Check for errors
+		certNodeID, certErr := common.TLSCertificateNodeID(cert) // Fetch NodeID certificate is bound to
+
+		if certErr != nil || certNodeID != tempNode.NodeID { // Check certificate not bound to node
+			err = fmt.Errorf("certificate in %s is not bound to node %s", path, tempNode.NodeID) // Set error
+		}
+	}
+
+	if err != nil { // Check certificate missing or not bound to node
+		return tempNode, tempNode.WriteToMemory(path) // Persist newly generated certificate
+	}
+
+	tempNode.certificate = cert // Set certificate
+
 	return tempNode, nil // No error occurred, return nil error, env
 }
 
@@ -1,6 +1,8 @@
 package node
 
 import (
+	"io/ioutil"
+	"os"
 	"strings"
 	"testing"
 
@@ -40,6 +42,70 @@ func TestStartListener(t *testing.T) {
 	t.Logf("started listener with address %s", (*ln).Addr()) // Log success
 }
 
+// TestPeerAddress - test IPv6 node addresses are bracketed when a port is appended
+func TestPeerAddress(t *testing.T) {
+	node := Node{Address: "2001:db8::1"} // Init node
+
+	if address := node.PeerAddress(3000).String(); address != "[2001:db8::1]:3000" { // Check address
+		t.Errorf("invalid peer address %s", address) // Log found error
+		t.FailNow()                                  // Panic
+	}
+
+	node.Address = "1.1.1.1" // Set IPv4 address
+
+	if address := node.PeerAddress(3000).String(); address != "1.1.1.1:3000" { // Check address
+		t.Errorf("invalid peer address %s", address) // Log found error
+		t.FailNow()                                  // Panic
+	}
+}
+
+// TestReadNodeFromMemory - test functionality of ReadNodeFromMemory() method
+func TestReadNodeFromMemory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gop2p") // Init temp dir
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	defer os.RemoveAll(dir) // Remove temp dir
+
+	legacyNode := &Node{Address: "127.0.0.1"} // Init node without identity
+
+	err = legacyNode.WriteToMemory(dir) // Write node
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	readNode, err := ReadNodeFromMemory(dir) // Read node, generating identity
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if readNode.Identity() == nil || readNode.VerifyIdentity() != nil { // Check identity was generated
+		t.Errorf("expected node identity to be generated") // Log found error
+		t.FailNow()                                        // Panic
+	}
+
+	rereadNode, err := ReadNodeFromMemory(dir) // Read node again
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if rereadNode.NodeID != readNode.NodeID { // Check identity was persisted
+		t.Errorf("expected NodeID %s, got %s", readNode.NodeID, rereadNode.NodeID) // Log found error
+		t.FailNow()                                                                // Panic
+	}
+
+	t.Logf("read node %s", rereadNode.NodeID) // Log success
+}
+
 func newNodeSafe() (*Node, error) {
 	ip, err := common.GetExtIPAddrWithoutUPnP() // Fetch IP address
 
@@ -53,7 +119,15 @@ func newNodeSafe() (*Node, error) {
 		return &Node{}, err // Return found error
 	}
 
+	identity, err := NewIdentity() // Generate identity
+
+	if err != nil { // Check for errors
+		return &Node{}, err // Return found error
+	}
+
 	node := Node{Address: ip, Reputation: 0, IsBootstrap: false, Environment: environment} // Creates new node instance with specified address
 
+	node.SetIdentity(identity) // Set identity
+
 	return &node, nil // Return initialized node
 }
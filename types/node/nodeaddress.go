@@ -0,0 +1,101 @@
+package node
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dowlandaiello/GoP2P/addressing"
+)
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// NodeFromAddress - initialize remote node reachable at given address. Self-describing addresses (e.g. /ip4/1.2.3.4/tcp/3000/tls/p2p/<NodeID>) are advertised by the node, and pin its NodeID (verified against the node's certificate once dialed).
+func NodeFromAddress(address string) (*Node, error) {
+	if !addressing.IsAddress(address) { // Check for plain address
+		if address == "" { // Check for nil address
+			return nil, errors.New("nil address") // Return error
+		}
+
+		return &Node{Address: address}, nil // Return node
+	}
+
+	parsed, err := addressing.Parse(address) // Parse address
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return &Node{NodeID: parsed.NodeID, Address: parsed.PeerAddress().WithPort(0).String(), Addresses: []string{parsed.String()}}, nil // Return node
+}
+
+// SetAddresses - set self-describing addresses advertised by node (e.g. /ip4/1.2.3.4/tcp/3000/tls), pinning each to the node's NodeID
+func (node *Node) SetAddresses(addresses []string) error {
+	advertised := []string{} // Init address buffer
+
+	for _, address := range addresses { // Iterate through addresses
+		parsed, err := addressing.Parse(address) // Parse address
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		if parsed.NodeID == "" { // Check for unpinned address
+			parsed.NodeID = node.NodeID // Pin address
+		}
+
+		if parsed.NodeID != node.NodeID { // Check address belongs to node
+			return fmt.Errorf("address %s is pinned to another node", address) // Return error
+		}
+
+		advertised = append(advertised, parsed.String()) // Append address
+	}
+
+	node.Addresses = advertised // Set addresses
+
+	return nil // No error occurred, return nil
+}
+
+// CheckAddresses - check self-describing addresses advertised by node are valid, and pinned to the node's NodeID
+func (node *Node) CheckAddresses() error {
+	for _, address := range node.Addresses { // Iterate through addresses
+		parsed, err := addressing.Parse(address) // Parse address
+
+		if err != nil { // Check for errors
+			return err // Return found error
+		}
+
+		if parsed.NodeID != "" && parsed.NodeID != node.NodeID { // Check address is pinned to another node
+			return fmt.Errorf("address %s is pinned to another node than %s", address, node.NodeID) // Return error
+		}
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// IsPinned - check node's NodeID was learned from an advertised address, rather than from its public key
+func (node *Node) IsPinned() bool {
+	for _, address := range node.Addresses { // Iterate through addresses
+		if parsed, err := addressing.Parse(address); err == nil && parsed.NodeID != "" && parsed.NodeID == node.NodeID { // Check address pins NodeID
+			return true // Pinned
+		}
+	}
+
+	return false // Not pinned
+}
+
+// DialAddress - fetch transport address node should be dialed on: the first advertised address the local node supports, or the node's address at given port
+func (node *Node) DialAddress(port int) string {
+	if address, err := addressing.Select(node.Addresses); err == nil { // Select advertised address
+		transportAddress, _ := address.TransportAddress() // Convert to transport address (supported addresses always convert)
+
+		return transportAddress // Return address
+	}
+
+	return node.PeerAddress(port).String() // Return address at port
+}
+
+/*
+	END EXPORTED METHODS
+*/
@@ -0,0 +1,69 @@
+package node
+
+import "testing"
+
+// TestNodeFromAddress - test functionality of NodeFromAddress() method
+func TestNodeFromAddress(t *testing.T) {
+	node, err := NodeFromAddress("/ip6/::1/tcp/3001/tls/p2p/test") // Init node
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if node.NodeID != "test" || node.Address != "::1" || len(node.Addresses) != 1 || !node.IsPinned() { // Check node
+		t.Errorf("invalid node %v", node) // Log found error
+		t.FailNow()                       // Panic
+	}
+
+	if address := node.DialAddress(3000); address != "[::1]:3001" { // Check advertised port is dialed
+		t.Errorf("invalid dial address %s", address) // Log found error
+		t.FailNow()                                  // Panic
+	}
+
+	if node, err = NodeFromAddress("1.1.1.1"); err != nil || node.Address != "1.1.1.1" || node.DialAddress(3000) != "1.1.1.1:3000" { // Check plain addresses
+		t.Errorf("invalid node %v (%v)", node, err) // Log found error
+		t.FailNow()                                 // Panic
+	}
+
+	if _, err = NodeFromAddress("/ip4/1.1.1.1/udp/3000"); err == nil { // Check invalid addresses are rejected
+		t.Errorf("expected address to be rejected") // Log found error
+		t.FailNow()                                 // Panic
+	}
+}
+
+// TestSetAddresses - test advertised addresses are pinned to node
+func TestSetAddresses(t *testing.T) {
+	identity, err := NewIdentity() // Generate identity
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	node := &Node{Address: "1.1.1.1"} // Init node
+
+	node.SetIdentity(identity) // Set identity
+
+	if err = node.SetAddresses([]string{"/ip4/1.1.1.1/tcp/3001/tls", "/unix/tmp/gop2p.sock"}); err != nil { // Set addresses
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if node.Addresses[0] != "/ip4/1.1.1.1/tcp/3001/tls/p2p/"+node.NodeID || node.CheckAddresses() != nil || !node.IsPinned() { // Check addresses were pinned
+		t.Errorf("invalid addresses %v", node.Addresses) // Log found error
+		t.FailNow()                                      // Panic
+	}
+
+	if err = node.SetAddresses([]string{"/ip4/1.1.1.1/tcp/3001/tls/p2p/other"}); err == nil { // Check addresses of other nodes are rejected
+		t.Errorf("expected address to be rejected") // Log found error
+		t.FailNow()                                 // Panic
+	}
+
+	node.Addresses = []string{"/ip4/1.1.1.1/tcp/3001/tls/p2p/other"} // Set address of other node
+
+	if node.CheckAddresses() == nil { // Check address of other node is rejected
+		t.Errorf("expected address to be rejected") // Log found error
+		t.FailNow()                                 // Panic
+	}
+}
@@ -0,0 +1,64 @@
+package node
+
+import (
+	"time"
+
+	"github.com/dowlandaiello/GoP2P/internal/rpc/proto/wire"
+	"github.com/dowlandaiello/GoP2P/types/environment"
+)
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// ToWire - convert node to protobuf wire type (private identity is never included)
+func (node *Node) ToWire() *wire.Node {
+	if node == nil { // Check for nil node
+		return nil // Return nil
+	}
+
+	wireNode := &wire.Node{
+		Id:           node.NodeID,               // Set NodeID
+		PublicKey:    node.PublicKey,            // Set public key
+		Address:      node.Address,              // Set address
+		Reputation:   uint32(node.Reputation),   // Set reputation
+		IsBootstrap:  node.IsBootstrap,          // Set is bootstrap
+		Reachability: string(node.Reachability), // Set reachability
+		Addresses:    node.Addresses,            // Set addresses
+		Environment:  node.Environment.ToWire(), // Set environment
+	} // Init wire node
+
+	if !node.LastPingTime.IsZero() { // Check node has been pinged
+		wireNode.LastPingTime = node.LastPingTime.UnixNano() // Set last ping time
+	}
+
+	return wireNode // Return wire node
+}
+
+// NodeFromWire - convert protobuf wire type to node
+func NodeFromWire(wireNode *wire.Node) *Node {
+	if wireNode == nil { // Check for nil node
+		return nil // Return nil
+	}
+
+	node := &Node{
+		NodeID:       wireNode.Id,                                           // Set NodeID
+		PublicKey:    wireNode.PublicKey,                                    // Set public key
+		Address:      wireNode.Address,                                      // Set address
+		Reputation:   uint(wireNode.Reputation),                             // Set reputation
+		IsBootstrap:  wireNode.IsBootstrap,                                  // Set is bootstrap
+		Reachability: Reachability(wireNode.Reachability),                   // Set reachability
+		Addresses:    wireNode.Addresses,                                    // Set addresses
+		Environment:  environment.EnvironmentFromWire(wireNode.Environment), // Set environment
+	} // Init node
+
+	if wireNode.LastPingTime != 0 { // Check node has been pinged
+		node.LastPingTime = time.Unix(0, wireNode.LastPingTime) // Set last ping time
+	}
+
+	return node // Return node
+}
+
+/*
+	END EXPORTED METHODS:
+*/
@@ -0,0 +1,25 @@
+package node
+
+import (
+	"testing"
+	"time"
+)
+
+// TestNodeFromWire - test functionality of node ToWire(), NodeFromWire() methods
+func TestNodeFromWire(t *testing.T) {
+	for _, pingTime := range []time.Time{{}, time.Unix(0, 42)} { // Iterate through ping times
+		node := &Node{NodeID: "test", PublicKey: []byte{0, 1}, Address: "127.0.0.1", Reputation: 3, LastPingTime: pingTime, IsBootstrap: true, Reachability: ReachabilityPrivate, Addresses: []string{"/ip4/127.0.0.1/tcp/3000/tls/p2p/test"}} // Init node
+
+		decoded := NodeFromWire(node.ToWire()) // Convert node
+
+		if decoded.NodeID != node.NodeID || string(decoded.PublicKey) != string(node.PublicKey) || decoded.Address != node.Address || decoded.Reputation != node.Reputation || !decoded.LastPingTime.Equal(node.LastPingTime) || !decoded.IsBootstrap || decoded.Reachability != node.Reachability || len(decoded.Addresses) != 1 || decoded.Addresses[0] != node.Addresses[0] || decoded.Environment != nil { // Check for mismatch
+			t.Errorf("invalid node %v, expected %v", decoded, node) // Log found error
+			t.FailNow()                                             // Panic
+		}
+	}
+
+	if (*Node)(nil).ToWire() != nil || NodeFromWire(nil) != nil { // Check nil nodes are preserved
+		t.Errorf("expected nil node to be preserved") // Log found error
+		t.FailNow()                                   // Panic
+	}
+}
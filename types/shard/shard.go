@@ -30,8 +30,8 @@ type Shard struct {
 
 // NewShard - initialize new shard
 func NewShard(initializingNode *node.Node) (*Shard, error) {
-	initializingNode = &node.Node{Address: initializingNode.Address, Reputation: initializingNode.Reputation, LastPingTime: initializingNode.LastPingTime, IsBootstrap: initializingNode.IsBootstrap} // Remove environment (plz, no recursion :pepeHands:)
-	shard := Shard{Nodes: &[]node.Node{*initializingNode}, ChildNodes: &[]node.Node{*initializingNode}, ChildShards: []*Shard{}, Origin: time.Now().UTC(), Address: (*initializingNode).Address}      // Initialize shard
+	initializingNode = &node.Node{NodeID: initializingNode.NodeID, PublicKey: initializingNode.PublicKey, Address: initializingNode.Address, Reputation: initializingNode.Reputation, LastPingTime: initializingNode.LastPingTime, IsBootstrap: initializingNode.IsBootstrap, Reachability: initializingNode.Reachability, Addresses: initializingNode.Addresses} // Remove environment (plz, no recursion :pepeHands:)
+	shard := Shard{Nodes: &[]node.Node{*initializingNode}, ChildNodes: &[]node.Node{*initializingNode}, ChildShards: []*Shard{}, Origin: time.Now().UTC(), Address: (*initializingNode).Address}                                                                                                                                                                  // Initialize shard
 
 	serialized, err := common.SerializeToBytes(shard) // Serialize shard
 
@@ -55,7 +55,7 @@ func NewShardWithNodes(initializingNodes *[]node.Node) (*Shard, error) {
 	for _, initializingNode := range *initializingNodes {
 		addresses = append(addresses, initializingNode.Address) // Append address
 
-		initializingNode = node.Node{Address: initializingNode.Address, Reputation: initializingNode.Reputation, LastPingTime: initializingNode.LastPingTime, IsBootstrap: initializingNode.IsBootstrap} // Remove environment (plz, no recursion :pepeHands:)
+		initializingNode = node.Node{NodeID: initializingNode.NodeID, PublicKey: initializingNode.PublicKey, Address: initializingNode.Address, Reputation: initializingNode.Reputation, LastPingTime: initializingNode.LastPingTime, IsBootstrap: initializingNode.IsBootstrap, Reachability: initializingNode.Reachability, Addresses: initializingNode.Addresses} // Remove environment (plz, no recursion :pepeHands:)
 	}
 
 	shard := Shard{Nodes: initializingNodes, ChildNodes: initializingNodes, ChildShards: []*Shard{}, Origin: time.Now().UTC(), Address: ""} // Initialize shard
@@ -124,6 +124,68 @@ func (shard *Shard) QueryForAddress(address string) (uint, error) {
 	return 0, errors.New("no value found") // Could not find index of address, return new error
 }
 
+// RemoveNode - remove node with given NodeID from shard and its child shards, returning true if the node was found
+func (shard *Shard) RemoveNode(nodeID string) bool {
+	removed := false // Init removed buffer
+
+	for _, nodes := range []*[]node.Node{shard.Nodes, shard.ChildNodes} { // Iterate through node lists
+		if nodes == nil { // Check for nil list
+			continue // Skip list
+		}
+
+		remaining := []node.Node{} // Init remaining nodes buffer
+
+		for _, shardNode := range *nodes { // Iterate through nodes
+			if shardNode.NodeID == nodeID { // Check for match
+				removed = true // Set removed
+
+				continue // Skip node
+			}
+
+			remaining = append(remaining, shardNode) // Keep node
+		}
+
+		*nodes = remaining // Set remaining nodes
+	}
+
+	for _, childShard := range shard.ChildShards { // Iterate through child shards
+		if childShard != nil && childShard.RemoveNode(nodeID) { // Remove from child shard
+			removed = true // Set removed
+		}
+	}
+
+	return removed // Return removed
+}
+
+// UpdateNode - replace every entry of node with NodeID of given node in shard and its child shards (e.g. after a change of address), returning true if the node was found
+func (shard *Shard) UpdateNode(updatedNode *node.Node) bool {
+	updated := false // Init updated buffer
+
+	contact := node.Node{NodeID: updatedNode.NodeID, PublicKey: updatedNode.PublicKey, Address: updatedNode.Address, Reputation: updatedNode.Reputation, LastPingTime: updatedNode.LastPingTime, IsBootstrap: updatedNode.IsBootstrap, Reachability: updatedNode.Reachability, Addresses: updatedNode.Addresses} // Remove environment
+
+	for _, nodes := range []*[]node.Node{shard.Nodes, shard.ChildNodes} { // Iterate through node lists
+		if nodes == nil { // Check for nil list
+			continue // Skip list
+		}
+
+		for x := range *nodes { // Iterate through nodes
+			if (*nodes)[x].NodeID == updatedNode.NodeID { // Check for match
+				(*nodes)[x] = contact // Update node
+
+				updated = true // Set updated
+			}
+		}
+	}
+
+	for _, childShard := range shard.ChildShards { // Iterate through child shards
+		if childShard != nil && childShard.UpdateNode(updatedNode) { // Update in child shard
+			updated = true // Set updated
+		}
+	}
+
+	return updated // Return updated
+}
+
 // LogShard - serialize and print contents of entire shard
 func (shard *Shard) LogShard() error {
 	marshaledVal, err := json.MarshalIndent(*shard, "", "  ") // Marshal shard
@@ -153,6 +153,42 @@ func TestQueryForAddress(t *testing.T) {
 	t.Logf("found node index: %s", strconv.Itoa(int(index))) // Log success
 }
 
+// TestRemoveNode - test functionality of shard membership updates
+func TestRemoveNode(t *testing.T) {
+	nodeList := &[]node.Node{{NodeID: "a", Address: "1.1.1.1"}, {NodeID: "b", Address: "1.0.0.1"}} // Init node list
+
+	shard, err := NewShardWithNodes(nodeList) // Init shard
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	childShard, err := NewShardWithNodes(&[]node.Node{{NodeID: "b", Address: "1.0.0.1"}}) // Init child shard
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	shard.ChildShards = []*Shard{childShard} // Set child shard
+
+	if !shard.UpdateNode(&node.Node{NodeID: "b", Address: "8.8.8.8"}) || (*childShard.Nodes)[0].Address != "8.8.8.8" { // Update node
+		t.Errorf("node wasn't updated") // Log found error
+		t.FailNow()                     // Panic
+	}
+
+	if !shard.RemoveNode("b") || len(*shard.Nodes) != 1 || len(*shard.ChildNodes) != 1 || len(*childShard.Nodes) != 0 { // Remove node
+		t.Errorf("node wasn't removed") // Log found error
+		t.FailNow()                     // Panic
+	}
+
+	if shard.RemoveNode("b") { // Remove missing node
+		t.Errorf("removed missing node") // Log found error
+		t.FailNow()                      // Panic
+	}
+}
+
 // TestLogShard - test functionality of shard logging
 func TestLogShard(t *testing.T) {
 	nodeList, err := newNodeListSafe(2) // Initialize shard node
@@ -3,48 +3,59 @@ package shard
 import (
 	"errors"
 	"fmt"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/dowlandaiello/GoP2P/common"
 )
 
-// SendBytesShardResult - attempt to send specified bytes to given shard address, returning result
-func SendBytesShardResult(b []byte, address string, port int) ([]byte, error) {
-	if len(address) == 0 || len(address) < 0 || !strings.Contains(address, "::") { // Check for invalid input
+// SendBytesShardResult - attempt to send specified payload to given shard address over pooled sessions, wrapped in an envelope of given kind, returning result
+func SendBytesShardResult(kind common.EnvelopeKind, b []byte, address string, port int) ([]byte, error) {
+	addresses, err := common.ParseShardAddress(address) // Fetch seed addresses
+
+	if err != nil { // Check for invalid input
 		return []byte{}, fmt.Errorf("invalid address %s", address) // Return found error
 	}
 
-	addresses := strings.Split(strings.Split(address, "::")[1], ":") // Split into string slice
-
-	buffer := &[][]byte{} // Init buffer
+	results := make(chan []byte, len(addresses)) // Init result buffer
 
-	startTime := time.Now() // Get current time
+	errs := make(chan error, len(addresses)) // Init error buffer
 
 	for _, address := range addresses { // Iterate through addresses
-		if float64(len(*buffer)) > (0.51 * float64(len(addresses))) { // Check 51% of nodes finished
-			filteredResult, err := common.GetCommonByteDifference(*buffer) // Fetch final result
+		go func(address string) {
+			sealed, err := seal(address, kind, b) // Wrap payload in envelope
+
+			if err == nil { // Check for errors
+				sealed, err = common.DefaultConnectionPool.Request(address, "", sealed) // Send to address
+			}
 
 			if err != nil { // Check for errors
-				return []byte{}, err // Return found error
+				errs <- err // Append error
+			} else {
+				results <- sealed // Append result
 			}
+		}(withPort(address, port)) // Append port
+	}
 
-			return filteredResult, nil // Return read data
-		}
+	buffer := [][]byte{} // Init buffer
 
-		address = address + ":" + strconv.Itoa(port) // Append port
+	failed := 0 // Init failed counter
 
-		go common.SendBytesResultBufferAsync(b, buffer, address) // Send to address, append to buffer
-	}
+	timeout := time.After(3 * time.Second) // Init timeout
 
-	for float64(len(*buffer)) < (0.51 * float64(len(addresses))) { // Check 51% of nodes finished
-		if time.Now().Sub(startTime) > 3*time.Second { // Check for time out
+	for float64(len(buffer)) < (0.51 * float64(len(addresses))) { // Check 51% of nodes finished
+		select {
+		case result := <-results: // Check for result
+			buffer = append(buffer, result) // Append result
+		case err := <-errs: // Check for error
+			if failed++; float64(len(addresses)-failed) < (0.51 * float64(len(addresses))) { // Check 51% of nodes can no longer finish
+				return []byte{}, err // Return found error
+			}
+		case <-timeout: // Check for time out
 			return []byte{}, errors.New("timed out") // Return timed out
 		}
 	}
 
-	filteredResult, err := common.GetCommonByteDifference(*buffer) // Fetch final result
+	filteredResult, err := common.GetCommonByteDifference(buffer) // Fetch final result
 
 	if err != nil { // Check for errors
 		return []byte{}, err // Return found error
@@ -53,33 +64,70 @@ func SendBytesShardResult(b []byte, address string, port int) ([]byte, error) {
 	return filteredResult, nil // Return read data
 }
 
-// SendBytesShard - attempt to send specified bytes to given shard address
-func SendBytesShard(b []byte, address string, port int) error {
-	if len(address) == 0 || len(address) < 0 || !strings.Contains(address, "::") { // Check for invalid input
+// SendBytesShard - attempt to send specified payload to given shard address over pooled sessions, wrapped in an envelope of given kind
+func SendBytesShard(kind common.EnvelopeKind, b []byte, address string, port int) error {
+	addresses, err := common.ParseShardAddress(address) // Fetch seed addresses
+
+	if err != nil { // Check for invalid input
 		return fmt.Errorf("invalid address %s", address) // Return found error
 	}
 
-	addresses := strings.Split(strings.Split(address, "::")[1], ":") // Split into string slice
-
-	finished := &[]bool{} // Init finished buffer
-
-	startTime := time.Now() // Fetch current time
+	finished := make(chan error, len(addresses)) // Init finished buffer
 
 	for _, address := range addresses { // Iterate through addresses
-		address = address + ":" + strconv.Itoa(port) // Append port
+		go func(address string) {
+			sealed, err := seal(address, kind, b) // Wrap payload in envelope
 
-		go common.SendBytesAsync(b, address, finished) // Send to address
+			if err == nil { // Check for errors
+				err = common.DefaultConnectionPool.Send(address, "", sealed) // Send to address
+			}
 
-		if len(*finished) >= len(addresses) { // Check finished
-			return nil // No error occurred, return nil
-		}
+			finished <- err // Finish write
+		}(withPort(address, port)) // Append port
 	}
 
-	for float64(len(*finished)) < 0.75*float64(len(addresses)) { // Check wrote to all nodes
-		if time.Now().Sub(startTime) > 10*time.Second { // Check for timeout
+	failed := 0 // Init failed counter
+
+	timeout := time.After(10 * time.Second) // Init timeout
+
+	for wrote := 0; float64(wrote) < 0.75*float64(len(addresses)); { // Check wrote to 75% of nodes
+		select {
+		case err := <-finished: // Check for finished write
+			if err == nil { // Check for errors
+				wrote++ // Increment wrote
+
+				continue // Wait for remaining writes
+			}
+
+			if failed++; float64(len(addresses)-failed) < 0.75*float64(len(addresses)) { // Check 75% of nodes can no longer be written to
+				return err // Return found error
+			}
+		case <-timeout: // Check for timeout
 			return errors.New("timed out") // Return timed out
 		}
 	}
 
-	return nil // No error occurred, return  nil
+	return nil // No error occurred, return nil
+}
+
+// seal - wrap given payload in an envelope of given kind, encoded with the codec negotiated with peer at given address
+func seal(address string, kind common.EnvelopeKind, b []byte) ([]byte, error) {
+	codec, err := common.DefaultConnectionPool.Codec(address, "") // Fetch codec negotiated with peer
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return common.Seal(codec, kind, b) // Wrap payload in envelope
+}
+
+// withPort - append given port to given seed address (bracketing IPv6 addresses)
+func withPort(address string, port int) string {
+	parsed, err := common.ParsePeerAddress(address) // Parse address
+
+	if err != nil { // Check for errors
+		parsed = common.PeerAddress{Host: address} // Use address as is
+	}
+
+	return parsed.WithPort(port).String() // Return address
 }
@@ -0,0 +1,352 @@
+package upnp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// MappingPending - mapping hasn't been attempted yet
+	MappingPending = MappingStatus("pending")
+
+	// MappingActive - gateway accepted latest mapping attempt
+	MappingActive = MappingStatus("active")
+
+	// MappingFailed - latest mapping attempt failed (retried every RetryInterval)
+	MappingFailed = MappingStatus("failed")
+)
+
+var (
+	// DefaultRetryInterval - default interval at which failed mappings are retried
+	DefaultRetryInterval = time.Minute
+
+	// DefaultRefreshInterval - default interval at which mappings that don't expire (e.g. UPnP mappings) are re-added, recovering from gateway restarts
+	DefaultRefreshInterval = 20 * time.Minute
+
+	// DefaultGatewayCheckInterval - default interval at which the default gateway is checked for changes (mappings are re-added on a new gateway)
+	DefaultGatewayCheckInterval = time.Minute
+
+	// DefaultManager - manager of port mappings requested by the local node
+	DefaultManager = NewManager(DefaultPortMappers)
+)
+
+// MappingStatus - status of managed port mapping
+type MappingStatus string
+
+// ManagedMapping - status of port mapping maintained by a manager
+type ManagedMapping struct {
+	Port uint `json:"port"` // Port - forwarded local port
+
+	Status MappingStatus `json:"status"` // Status - status of mapping
+
+	Mapping *Mapping `json:"mapping,omitempty"` // Mapping - latest mapping accepted by gateway
+
+	Error string `json:"error,omitempty"` // Error - error of latest mapping attempt (if failed)
+
+	Gateway string `json:"gateway,omitempty"` // Gateway - default gateway at time of latest mapping attempt
+
+	Updated time.Time `json:"updated"` // Updated - time of latest mapping attempt
+
+	Expires time.Time `json:"expires,omitempty"` // Expires - time lease of latest mapping expires (zero if mapping doesn't expire)
+}
+
+// Manager - maintainer of port mappings, renewing leases before expiry, re-adding mappings when the default gateway changes, retrying failed mappings
+type Manager struct {
+	Mappers []PortMapper // Mappers - port mappers tried in order
+
+	Lifetime time.Duration // Lifetime - lease lifetime requested for mappings (DefaultLifetime if 0)
+
+	RetryInterval time.Duration // RetryInterval - interval at which failed mappings are retried (DefaultRetryInterval if 0)
+
+	RefreshInterval time.Duration // RefreshInterval - interval at which mappings that don't expire are re-added (DefaultRefreshInterval if 0)
+
+	GatewayCheckInterval time.Duration // GatewayCheckInterval - interval at which the default gateway is checked for changes (DefaultGatewayCheckInterval if 0)
+
+	ports map[uint]*managedPort // ports - managed ports
+
+	gateway func() (net.IP, error) // gateway - default gateway lookup
+
+	mutex sync.Mutex // mutex - guards ports
+}
+
+// managedPort - state of port maintained by a manager
+type managedPort struct {
+	status ManagedMapping // status - status of mapping
+
+	attempted chan struct{} // attempted - closed after first mapping attempt
+
+	stop chan struct{} // stop - closed to stop maintaining mapping
+
+	done chan struct{} // done - closed once mapping is no longer maintained
+}
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// NewManager - initialize manager of port mappings added via given port mappers (tried in order)
+func NewManager(mappers []PortMapper) *Manager {
+	return &Manager{
+		Mappers: mappers,                     // Set mappers
+		ports:   make(map[uint]*managedPort), // Init ports
+		gateway: DefaultGateway,              // Set gateway lookup
+	} // Return manager
+}
+
+// Forward - forward given local port, maintaining mapping until removed (failed mappings are retried). Returns mapping (or error) of first attempt.
+func (manager *Manager) Forward(port uint) (*Mapping, error) {
+	manager.mutex.Lock() // Lock manager
+
+	if manager.ports == nil { // Check for nil ports
+		manager.ports = make(map[uint]*managedPort) // Init ports
+	}
+
+	managed, found := manager.ports[port] // Check for managed port
+
+	if !found { // Check port isn't managed yet
+		managed = &managedPort{
+			status:    ManagedMapping{Port: port, Status: MappingPending}, // Set status
+			attempted: make(chan struct{}),                                // Init attempted
+			stop:      make(chan struct{}),                                // Init stop
+			done:      make(chan struct{}),                                // Init done
+		} // Init managed port
+
+		manager.ports[port] = managed // Add port
+
+		go manager.maintain(managed) // Maintain mapping
+	}
+
+	manager.mutex.Unlock() // Unlock manager
+
+	select {
+	case <-managed.attempted: // Wait for first attempt
+	case <-managed.done: // Port removed before first attempt
+	}
+
+	status := manager.status(managed) // Fetch status
+
+	if status.Status == MappingPending { // Check port removed before first attempt
+		return nil, fmt.Errorf("mapping of port %d was removed", port) // Return error
+	}
+
+	if status.Status == MappingFailed { // Check mapping failed
+		return nil, errors.New(status.Error) // Return error
+	}
+
+	return status.Mapping, nil // Return mapping
+}
+
+// Remove - stop maintaining mapping of given local port, removing it from the gateway
+func (manager *Manager) Remove(port uint) error {
+	manager.mutex.Lock() // Lock manager
+
+	managed, found := manager.ports[port] // Fetch managed port
+
+	delete(manager.ports, port) // Remove port
+
+	manager.mutex.Unlock() // Unlock manager
+
+	if found { // Check port was managed
+		close(managed.stop) // Stop maintaining mapping
+
+		<-managed.done // Wait for maintainer to stop
+	}
+
+	return UnmapPortWith(manager.Mappers, port) // Remove mapping
+}
+
+// Close - remove all managed mappings (e.g. on shutdown)
+func (manager *Manager) Close() error {
+	errs := []string{} // Init error buffer
+
+	for _, status := range manager.Status() { // Iterate through mappings
+		if err := manager.Remove(status.Port); err != nil && status.Status == MappingActive { // Remove mapping
+			errs = append(errs, err.Error()) // Append error
+		}
+	}
+
+	if len(errs) != 0 { // Check for errors
+		return errors.New(strings.Join(errs, "; ")) // Return errors
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// Status - fetch status of managed mappings, sorted by port
+func (manager *Manager) Status() []ManagedMapping {
+	manager.mutex.Lock() // Lock manager
+
+	defer manager.mutex.Unlock() // Unlock manager
+
+	statuses := []ManagedMapping{} // Init status buffer
+
+	for _, managed := range manager.ports { // Iterate through ports
+		statuses = append(statuses, managed.status) // Append status
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Port < statuses[j].Port }) // Sort by port
+
+	return statuses // Return statuses
+}
+
+// String - convert managed mapping to human-readable string
+func (status ManagedMapping) String() string {
+	switch status.Status {
+	case MappingActive:
+		return fmt.Sprintf("port %d: active (%s)", status.Port, status.Mapping.String()) // Return string
+	case MappingFailed:
+		return fmt.Sprintf("port %d: failed (%s)", status.Port, status.Error) // Return string
+	default:
+		return fmt.Sprintf("port %d: %s", status.Port, status.Status) // Return string
+	}
+}
+
+/*
+	END EXPORTED METHODS
+*/
+
+/*
+	BEGIN INTERNAL METHODS:
+*/
+
+// maintain - map given port, renewing, re-adding and retrying the mapping until stopped
+func (manager *Manager) maintain(managed *managedPort) {
+	defer close(managed.done) // Mark stopped
+
+	gateway := manager.refresh(managed) // Map port
+
+	close(managed.attempted) // Mark attempted
+
+	for {
+		due := manager.due(managed) // Fetch time of next attempt
+
+		wait := time.Until(due) // Init wait duration
+
+		if check := manager.gatewayCheckInterval(); check < wait { // Check gateway should be checked first
+			wait = check // Wait until gateway check
+		}
+
+		timer := time.NewTimer(wait) // Init timer
+
+		select {
+		case <-managed.stop: // Check stopped
+			timer.Stop() // Stop timer
+
+			return // Stop
+		case <-timer.C: // Wait for timer
+		}
+
+		if current := manager.currentGateway(); current != gateway || !time.Now().Before(due) { // Check gateway changed, mapping is due
+			gateway = manager.refresh(managed) // Map port
+		}
+	}
+}
+
+// refresh - attempt to map given port, updating its status. Returns default gateway at time of attempt.
+func (manager *Manager) refresh(managed *managedPort) string {
+	gateway := manager.currentGateway() // Fetch gateway
+
+	lifetime := manager.Lifetime // Fetch lifetime
+
+	if lifetime == 0 { // Check for nil lifetime
+		lifetime = DefaultLifetime // Set default lifetime
+	}
+
+	mapping, err := MapPortWith(manager.Mappers, managed.status.Port, lifetime) // Map port
+
+	manager.mutex.Lock() // Lock manager
+
+	defer manager.mutex.Unlock() // Unlock manager
+
+	managed.status.Gateway = gateway    // Set gateway
+	managed.status.Updated = time.Now() // Set updated
+
+	if err != nil { // Check for errors
+		managed.status.Status = MappingFailed // Set failed
+		managed.status.Error = err.Error()    // Set error
+
+		return gateway // Return gateway
+	}
+
+	managed.status.Status = MappingActive // Set active
+	managed.status.Mapping = mapping      // Set mapping
+	managed.status.Error = ""             // Reset error
+	managed.status.Expires = time.Time{}  // Reset expiry
+
+	if mapping.Lifetime != 0 { // Check for expiring lease
+		managed.status.Expires = managed.status.Updated.Add(mapping.Lifetime) // Set expiry
+	}
+
+	return gateway // Return gateway
+}
+
+// due - fetch time given port should next be mapped (halfway through leases, every RefreshInterval for mappings that don't expire, every RetryInterval for failed mappings)
+func (manager *Manager) due(managed *managedPort) time.Time {
+	status := manager.status(managed) // Fetch status
+
+	if status.Status != MappingActive { // Check mapping failed
+		interval := manager.RetryInterval // Fetch retry interval
+
+		if interval == 0 { // Check for nil interval
+			interval = DefaultRetryInterval // Set default interval
+		}
+
+		return status.Updated.Add(interval) // Retry after interval
+	}
+
+	if status.Mapping.Lifetime != 0 { // Check for expiring lease
+		return status.Updated.Add(status.Mapping.Lifetime / 2) // Renew halfway through lease
+	}
+
+	interval := manager.RefreshInterval // Fetch refresh interval
+
+	if interval == 0 { // Check for nil interval
+		interval = DefaultRefreshInterval // Set default interval
+	}
+
+	return status.Updated.Add(interval) // Refresh after interval
+}
+
+// status - fetch status of given managed port
+func (manager *Manager) status(managed *managedPort) ManagedMapping {
+	manager.mutex.Lock() // Lock manager
+
+	defer manager.mutex.Unlock() // Unlock manager
+
+	return managed.status // Return status
+}
+
+// gatewayCheckInterval - fetch interval at which the default gateway is checked for changes
+func (manager *Manager) gatewayCheckInterval() time.Duration {
+	if manager.GatewayCheckInterval == 0 { // Check for nil interval
+		return DefaultGatewayCheckInterval // Return default interval
+	}
+
+	return manager.GatewayCheckInterval // Return interval
+}
+
+// currentGateway - fetch address of default gateway (empty if unknown)
+func (manager *Manager) currentGateway() string {
+	lookup := manager.gateway // Fetch gateway lookup
+
+	if lookup == nil { // Check for nil lookup
+		lookup = DefaultGateway // Set default lookup
+	}
+
+	gateway, err := lookup() // Find default gateway
+
+	if err != nil { // Check for errors
+		return "" // Gateway unknown
+	}
+
+	return gateway.String() // Return gateway
+}
+
+/*
+	END INTERNAL METHODS
+*/
@@ -0,0 +1,159 @@
+package upnp
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestManagerForward - test managed mappings are added, reported and removed on close
+func TestManagerForward(t *testing.T) {
+	gateway := startFakeGateway(t, true, false) // Start NAT-PMP gateway
+
+	defer gateway.Close() // Stop gateway
+
+	manager := NewManager([]PortMapper{gateway.natPMPMapper()}) // Init manager
+
+	mapping, err := manager.Forward(3000) // Forward port
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if mapping.ExternalPort != 13000 { // Check mapping
+		t.Errorf("invalid mapping %s", mapping.String()) // Log found error
+		t.FailNow()                                      // Panic
+	}
+
+	status := manager.Status() // Fetch status
+
+	if len(status) != 1 || status[0].Status != MappingActive || status[0].Mapping.ExternalPort != 13000 || status[0].Expires.IsZero() { // Check status
+		t.Errorf("invalid status %v", status) // Log found error
+		t.FailNow()                           // Panic
+	}
+
+	if err = manager.Close(); err != nil { // Remove mappings
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if len(manager.Status()) != 0 || gateway.mappingCount() != 0 { // Check mappings were removed
+		t.Errorf("expected no mappings, found %d managed, %d on gateway", len(manager.Status()), gateway.mappingCount()) // Log found error
+		t.FailNow()                                                                                                      // Panic
+	}
+}
+
+// TestManagerRenewal - test leases are renewed before expiry
+func TestManagerRenewal(t *testing.T) {
+	gateway := startFakeGateway(t, true, false) // Start NAT-PMP gateway
+
+	defer gateway.Close() // Stop gateway
+
+	manager := NewManager([]PortMapper{gateway.natPMPMapper()}) // Init manager
+
+	manager.Lifetime = 2 * time.Second // Renew after a second
+
+	defer manager.Close() // Remove mappings
+
+	if _, err := manager.Forward(3000); err != nil { // Forward port
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	updated := manager.Status()[0].Updated // Fetch time of first mapping
+
+	waitForStatus(t, manager, func(status ManagedMapping) bool {
+		return status.Status == MappingActive && status.Updated.After(updated) && status.Expires.After(updated.Add(manager.Lifetime)) // Check lease was renewed
+	}) // Wait for renewal
+}
+
+// TestManagerRetry - test failed mappings are retried
+func TestManagerRetry(t *testing.T) {
+	gateway := startFakeGateway(t, false, false) // Start gateway speaking neither protocol
+
+	defer gateway.Close() // Stop gateway
+
+	manager := NewManager([]PortMapper{gateway.natPMPMapper()}) // Init manager
+
+	manager.RetryInterval = 50 * time.Millisecond // Retry quickly
+
+	defer manager.Close() // Remove mappings
+
+	if _, err := manager.Forward(3000); err == nil { // Forward port
+		t.Errorf("expected mapping to fail") // Log found error
+		t.FailNow()                          // Panic
+	}
+
+	if status := manager.Status(); len(status) != 1 || status[0].Status != MappingFailed || status[0].Error == "" { // Check failure is reported
+		t.Errorf("invalid status %v", status) // Log found error
+		t.FailNow()                           // Panic
+	}
+
+	gateway.speak(true, false) // Start speaking NAT-PMP
+
+	waitForStatus(t, manager, func(status ManagedMapping) bool {
+		return status.Status == MappingActive && status.Error == "" // Check mapping was retried
+	}) // Wait for retry
+}
+
+// TestManagerGatewayChange - test mappings are re-added when the default gateway changes
+func TestManagerGatewayChange(t *testing.T) {
+	gateway := startFakeGateway(t, true, false) // Start NAT-PMP gateway
+
+	defer gateway.Close() // Stop gateway
+
+	defaultGateway := net.IPv4(192, 168, 1, 1) // Init default gateway
+	mutex := sync.Mutex{}                      // Init default gateway mutex
+
+	manager := NewManager([]PortMapper{gateway.natPMPMapper()}) // Init manager
+
+	manager.GatewayCheckInterval = 50 * time.Millisecond // Check gateway quickly
+	manager.gateway = func() (net.IP, error) {
+		mutex.Lock() // Lock default gateway
+
+		defer mutex.Unlock() // Unlock default gateway
+
+		return defaultGateway, nil // Return default gateway
+	} // Set gateway lookup
+
+	defer manager.Close() // Remove mappings
+
+	if _, err := manager.Forward(3000); err != nil { // Forward port
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	mutex.Lock()                           // Lock default gateway
+	defaultGateway = net.IPv4(10, 0, 0, 1) // Change default gateway
+	mutex.Unlock()                         // Unlock default gateway
+
+	waitForStatus(t, manager, func(status ManagedMapping) bool {
+		return status.Status == MappingActive && status.Gateway == "10.0.0.1" // Check mapping was re-added
+	}) // Wait for new mapping
+}
+
+/*
+	BEGIN HELPER METHODS:
+*/
+
+// waitForStatus - wait at most 5 seconds for the first mapping of given manager to satisfy given condition
+func waitForStatus(t *testing.T, manager *Manager, condition func(status ManagedMapping) bool) {
+	deadline := time.Now().Add(5 * time.Second) // Init deadline
+
+	for time.Now().Before(deadline) { // Poll status
+		if status := manager.Status(); len(status) != 0 && condition(status[0]) { // Check condition
+			return // Condition satisfied
+		}
+
+		time.Sleep(10 * time.Millisecond) // Wait for status to change
+	}
+
+	t.Errorf("mapping status %v didn't change", manager.Status()) // Log found error
+	t.FailNow()                                                   // Panic
+}
+
+/*
+	END HELPER METHODS
+*/
@@ -0,0 +1,195 @@
+package upnp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	// NATPMPPort - port NAT-PMP, PCP gateways listen on
+	NATPMPPort = 5351
+
+	// natPMPVersion - NAT-PMP protocol version
+	natPMPVersion = 0
+
+	// natPMPOpExternalAddress - NAT-PMP external address request opcode
+	natPMPOpExternalAddress = 0
+
+	// natPMPOpMapUDP - NAT-PMP UDP mapping request opcode
+	natPMPOpMapUDP = 1
+
+	// natPMPOpMapTCP - NAT-PMP TCP mapping request opcode
+	natPMPOpMapTCP = 2
+
+	// natPMPResponseFlag - bit set on opcodes of NAT-PMP, PCP responses
+	natPMPResponseFlag = 128
+)
+
+var (
+	// DefaultNATPMPTimeout - default initial retransmission timeout of NAT-PMP, PCP requests (doubled on each retransmission)
+	DefaultNATPMPTimeout = 250 * time.Millisecond
+
+	// DefaultNATPMPAttempts - default number of times NAT-PMP, PCP requests are sent before giving up
+	DefaultNATPMPAttempts = 3
+
+	// natPMPResultCodes - descriptions of NAT-PMP result codes
+	natPMPResultCodes = map[uint16]string{
+		1: "unsupported version",
+		2: "not authorized",
+		3: "network failure",
+		4: "out of resources",
+		5: "unsupported opcode",
+	}
+)
+
+// NATPMPMapper - NAT-PMP (RFC 6886) port mapper
+type NATPMPMapper struct {
+	Gateway net.IP // Gateway - address of gateway (default gateway if nil)
+
+	Port uint // Port - port gateway listens on (NATPMPPort if 0)
+
+	Timeout time.Duration // Timeout - initial retransmission timeout (DefaultNATPMPTimeout if 0)
+
+	Attempts int // Attempts - number of times requests are sent (DefaultNATPMPAttempts if 0)
+}
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// Name - implement PortMapper interface
+func (mapper *NATPMPMapper) Name() string {
+	return "nat-pmp" // Return name
+}
+
+// ExternalAddress - fetch external address of gateway
+func (mapper *NATPMPMapper) ExternalAddress() (net.IP, error) {
+	response, err := mapper.request([]byte{natPMPVersion, natPMPOpExternalAddress}, 12) // Request external address
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return net.IPv4(response[8], response[9], response[10], response[11]), nil // Return address
+}
+
+// MapPort - forward given external TCP, UDP port (or whichever port the gateway assigns) to given local port for given lifetime
+func (mapper *NATPMPMapper) MapPort(port uint, lifetime time.Duration) (*Mapping, error) {
+	externalIP, err := mapper.ExternalAddress() // Fetch external address
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	mapping := &Mapping{Mapper: mapper.Name(), InternalPort: port, ExternalIP: externalIP} // Init mapping
+
+	for _, opcode := range []byte{natPMPOpMapTCP, natPMPOpMapUDP} { // Iterate through protocols
+		externalPort, granted, err := mapper.mapProtocol(opcode, port, port, lifetime) // Map port
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		if opcode == natPMPOpMapTCP || granted < mapping.Lifetime { // Check for TCP mapping, shorter lease
+			mapping.Lifetime = granted // Set lifetime
+		}
+
+		if opcode == natPMPOpMapTCP { // Check for TCP mapping
+			mapping.ExternalPort = externalPort // Set external port
+		}
+	}
+
+	return mapping, nil // Return mapping
+}
+
+// UnmapPort - remove TCP, UDP mappings of given local port
+func (mapper *NATPMPMapper) UnmapPort(port uint) error {
+	for _, opcode := range []byte{natPMPOpMapTCP, natPMPOpMapUDP} { // Iterate through protocols
+		if _, _, err := mapper.mapProtocol(opcode, port, 0, 0); err != nil { // Remove mapping
+			return err // Return found error
+		}
+	}
+
+	return nil // No error occurred, return nil
+}
+
+/*
+	END EXPORTED METHODS
+*/
+
+/*
+	BEGIN INTERNAL METHODS:
+*/
+
+// mapProtocol - send mapping request with given opcode, returning assigned external port, granted lifetime (a request with a lifetime of 0 removes the mapping)
+func (mapper *NATPMPMapper) mapProtocol(opcode byte, internalPort uint, externalPort uint, lifetime time.Duration) (uint, time.Duration, error) {
+	request := make([]byte, 12) // Init request
+
+	request[0], request[1] = natPMPVersion, opcode                        // Set version, opcode
+	binary.BigEndian.PutUint16(request[4:6], uint16(internalPort))        // Set internal port
+	binary.BigEndian.PutUint16(request[6:8], uint16(externalPort))        // Set suggested external port
+	binary.BigEndian.PutUint32(request[8:12], uint32(lifetime.Seconds())) // Set lifetime
+
+	response, err := mapper.request(request, 16) // Send request
+
+	if err != nil { // Check for errors
+		return 0, 0, err // Return found error
+	}
+
+	if binary.BigEndian.Uint16(response[8:10]) != uint16(internalPort) { // Check response describes mapping
+		return 0, 0, fmt.Errorf("NAT-PMP gateway mapped port %d, expected %d", binary.BigEndian.Uint16(response[8:10]), internalPort) // Return error
+	}
+
+	return uint(binary.BigEndian.Uint16(response[10:12])), time.Duration(binary.BigEndian.Uint32(response[12:16])) * time.Second, nil // Return external port, lifetime
+}
+
+// request - send given request to gateway, returning successful response of given minimum size
+func (mapper *NATPMPMapper) request(request []byte, size int) ([]byte, error) {
+	gateway, err := gatewayAddress(mapper.Gateway, mapper.Port, NATPMPPort) // Fetch gateway address
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	timeout, attempts := mapper.Timeout, mapper.Attempts // Fetch retransmission parameters
+
+	if timeout == 0 { // Check for nil timeout
+		timeout = DefaultNATPMPTimeout // Set default timeout
+	}
+
+	if attempts == 0 { // Check for nil attempts
+		attempts = DefaultNATPMPAttempts // Set default attempts
+	}
+
+	var response []byte // Init response buffer
+
+	err = exchange(gateway, request, timeout, attempts, func(received []byte) (bool, error) {
+		if len(received) < 4 || received[0] != natPMPVersion || received[1] != request[1]|natPMPResponseFlag { // Check response doesn't answer request
+			return false, nil // Ignore response
+		}
+
+		if code := binary.BigEndian.Uint16(received[2:4]); code != 0 { // Check for failure
+			return true, fmt.Errorf("NAT-PMP gateway returned %s (%d)", natPMPResultCodes[code], code) // Return error
+		}
+
+		if len(received) < size { // Check for truncated response
+			return true, fmt.Errorf("invalid NAT-PMP response of %d bytes", len(received)) // Return error
+		}
+
+		response = received // Set response
+
+		return true, nil // Response answers request
+	}) // Exchange request
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	return response, nil // Return response
+}
+
+/*
+	END INTERNAL METHODS
+*/
@@ -0,0 +1,56 @@
+package upnp
+
+import (
+	"testing"
+	"time"
+)
+
+// TestNATPMPMapPort - test functionality of NAT-PMP port mapping
+func TestNATPMPMapPort(t *testing.T) {
+	gateway := startFakeGateway(t, true, false) // Start NAT-PMP gateway
+
+	defer gateway.Close() // Stop gateway
+
+	gateway.dropRequests(1) // Ignore first request
+
+	mapper := gateway.natPMPMapper() // Init mapper
+
+	mapping, err := mapper.MapPort(3000, time.Hour) // Map port
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if mapping.Mapper != "nat-pmp" || mapping.ExternalPort != 13000 || !mapping.ExternalIP.Equal(gateway.externalIP) || mapping.Lifetime != time.Hour { // Check mapping
+		t.Errorf("invalid mapping %s", mapping.String()) // Log found error
+		t.FailNow()                                      // Panic
+	}
+
+	if gateway.mappingCount() != 2 { // Check TCP, UDP mappings were added
+		t.Errorf("expected 2 mappings, found %d", gateway.mappingCount()) // Log found error
+		t.FailNow()                                                       // Panic
+	}
+
+	if err = mapper.UnmapPort(3000); err != nil { // Unmap port
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if gateway.mappingCount() != 0 { // Check mappings were removed
+		t.Errorf("expected no mappings, found %d", gateway.mappingCount()) // Log found error
+		t.FailNow()                                                        // Panic
+	}
+}
+
+// TestNATPMPUnsupported - test NAT-PMP mapping fails against gateways that don't speak NAT-PMP
+func TestNATPMPUnsupported(t *testing.T) {
+	gateway := startFakeGateway(t, false, true) // Start PCP-only gateway
+
+	defer gateway.Close() // Stop gateway
+
+	if _, err := gateway.natPMPMapper().MapPort(3000, time.Hour); err == nil { // Map port
+		t.Errorf("expected NAT-PMP mapping to fail") // Log found error
+		t.FailNow()                                  // Panic
+	}
+}
@@ -0,0 +1,243 @@
+package upnp
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// pcpVersion - PCP protocol version
+	pcpVersion = 2
+
+	// pcpOpMap - PCP MAP opcode
+	pcpOpMap = 1
+
+	// pcpProtocolTCP - IANA protocol number of TCP
+	pcpProtocolTCP = 6
+
+	// pcpProtocolUDP - IANA protocol number of UDP
+	pcpProtocolUDP = 17
+
+	// pcpMapSize - size of PCP MAP requests, responses
+	pcpMapSize = 60
+)
+
+var (
+	// pcpResultCodes - descriptions of PCP result codes
+	pcpResultCodes = map[byte]string{
+		1:  "unsupported version",
+		2:  "not authorized",
+		3:  "malformed request",
+		4:  "unsupported opcode",
+		5:  "unsupported option",
+		6:  "malformed option",
+		7:  "network failure",
+		8:  "no resources",
+		9:  "unsupported protocol",
+		10: "user exceeded quota",
+		11: "cannot provide external",
+		12: "address mismatch",
+		13: "excessive remote peers",
+	}
+)
+
+// PCPMapper - PCP (RFC 6887) port mapper
+type PCPMapper struct {
+	Gateway net.IP // Gateway - address of gateway (default gateway if nil)
+
+	Port uint // Port - port gateway listens on (NATPMPPort if 0)
+
+	Timeout time.Duration // Timeout - initial retransmission timeout (DefaultNATPMPTimeout if 0)
+
+	Attempts int // Attempts - number of times requests are sent (DefaultNATPMPAttempts if 0)
+
+	nonces map[string][]byte // nonces - nonces of mappings by protocol, port (gateways only renew, remove mappings requested with the same nonce)
+
+	mutex sync.Mutex // mutex - guards nonces
+}
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// Name - implement PortMapper interface
+func (mapper *PCPMapper) Name() string {
+	return "pcp" // Return name
+}
+
+// MapPort - forward given external TCP, UDP port (or whichever port the gateway assigns) to given local port for given lifetime
+func (mapper *PCPMapper) MapPort(port uint, lifetime time.Duration) (*Mapping, error) {
+	mapping := &Mapping{Mapper: mapper.Name(), InternalPort: port} // Init mapping
+
+	for _, protocol := range []byte{pcpProtocolTCP, pcpProtocolUDP} { // Iterate through protocols
+		externalPort, externalIP, granted, err := mapper.mapProtocol(protocol, port, port, lifetime) // Map port
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		if protocol == pcpProtocolTCP || granted < mapping.Lifetime { // Check for TCP mapping, shorter lease
+			mapping.Lifetime = granted // Set lifetime
+		}
+
+		if protocol == pcpProtocolTCP { // Check for TCP mapping
+			mapping.ExternalPort, mapping.ExternalIP = externalPort, externalIP // Set external address
+		}
+	}
+
+	return mapping, nil // Return mapping
+}
+
+// UnmapPort - remove TCP, UDP mappings of given local port
+func (mapper *PCPMapper) UnmapPort(port uint) error {
+	for _, protocol := range []byte{pcpProtocolTCP, pcpProtocolUDP} { // Iterate through protocols
+		if _, _, _, err := mapper.mapProtocol(protocol, port, 0, 0); err != nil { // Remove mapping
+			return err // Return found error
+		}
+	}
+
+	return nil // No error occurred, return nil
+}
+
+/*
+	END EXPORTED METHODS
+*/
+
+/*
+	BEGIN INTERNAL METHODS:
+*/
+
+// mapProtocol - send MAP request for given protocol, returning assigned external port, address and granted lifetime (a request with a lifetime of 0 removes the mapping)
+func (mapper *PCPMapper) mapProtocol(protocol byte, internalPort uint, externalPort uint, lifetime time.Duration) (uint, net.IP, time.Duration, error) {
+	gateway, err := gatewayAddress(mapper.Gateway, mapper.Port, NATPMPPort) // Fetch gateway address
+
+	if err != nil { // Check for errors
+		return 0, nil, 0, err // Return found error
+	}
+
+	clientIP, err := localAddressTo(gateway) // Fetch address gateway sees requests from
+
+	if err != nil { // Check for errors
+		return 0, nil, 0, err // Return found error
+	}
+
+	nonce, err := mapper.nonce(protocol, internalPort) // Fetch mapping nonce
+
+	if err != nil { // Check for errors
+		return 0, nil, 0, err // Return found error
+	}
+
+	request := make([]byte, pcpMapSize) // Init request
+
+	request[0], request[1] = pcpVersion, pcpOpMap                        // Set version, opcode
+	binary.BigEndian.PutUint32(request[4:8], uint32(lifetime.Seconds())) // Set lifetime
+	copy(request[8:24], clientIP.To16())                                 // Set client address
+	copy(request[24:36], nonce)                                          // Set nonce
+	request[36] = protocol                                               // Set protocol
+	binary.BigEndian.PutUint16(request[40:42], uint16(internalPort))     // Set internal port
+	binary.BigEndian.PutUint16(request[42:44], uint16(externalPort))     // Set suggested external port
+	copy(request[44:60], net.IPv4zero.To16())                            // Set suggested external address (no preference)
+
+	timeout, attempts := mapper.Timeout, mapper.Attempts // Fetch retransmission parameters
+
+	if timeout == 0 { // Check for nil timeout
+		timeout = DefaultNATPMPTimeout // Set default timeout
+	}
+
+	if attempts == 0 { // Check for nil attempts
+		attempts = DefaultNATPMPAttempts // Set default attempts
+	}
+
+	var response []byte // Init response buffer
+
+	err = exchange(gateway, request, timeout, attempts, func(received []byte) (bool, error) {
+		if len(received) < 4 || received[1] != pcpOpMap|natPMPResponseFlag { // Check response doesn't answer request
+			return false, nil // Ignore response
+		}
+
+		if code := received[3]; code != 0 { // Check for failure
+			return true, fmt.Errorf("PCP gateway returned %s (%d)", pcpResultCodes[code], code) // Return error
+		}
+
+		if len(received) < pcpMapSize || received[0] != pcpVersion { // Check for invalid response
+			return true, fmt.Errorf("invalid PCP response of %d bytes", len(received)) // Return error
+		}
+
+		if !bytes.Equal(received[24:36], nonce) || received[36] != protocol || binary.BigEndian.Uint16(received[40:42]) != uint16(internalPort) { // Check response describes another mapping
+			return false, nil // Ignore response
+		}
+
+		response = received // Set response
+
+		return true, nil // Response answers request
+	}) // Exchange request
+
+	if err != nil { // Check for errors
+		return 0, nil, 0, err // Return found error
+	}
+
+	if lifetime == 0 { // Check mapping was removed
+		mapper.forget(protocol, internalPort) // Forget nonce
+	}
+
+	return uint(binary.BigEndian.Uint16(response[42:44])), net.IP(append([]byte{}, response[44:60]...)), time.Duration(binary.BigEndian.Uint32(response[4:8])) * time.Second, nil // Return external address, lifetime
+}
+
+// nonce - fetch nonce of mapping of given protocol, port (generating one if none exists)
+func (mapper *PCPMapper) nonce(protocol byte, port uint) ([]byte, error) {
+	mapper.mutex.Lock() // Lock mapper
+
+	defer mapper.mutex.Unlock() // Unlock mapper
+
+	if mapper.nonces == nil { // Check for nil nonces
+		mapper.nonces = make(map[string][]byte) // Init nonces
+	}
+
+	key := strconv.Itoa(int(protocol)) + "/" + strconv.Itoa(int(port)) // Init key
+
+	if nonce, found := mapper.nonces[key]; found { // Check for existing nonce
+		return nonce, nil // Return nonce
+	}
+
+	nonce := make([]byte, 12) // Init nonce buffer
+
+	if _, err := rand.Read(nonce); err != nil { // Generate nonce
+		return nil, err // Return found error
+	}
+
+	mapper.nonces[key] = nonce // Set nonce
+
+	return nonce, nil // Return nonce
+}
+
+// forget - forget nonce of removed mapping of given protocol, port
+func (mapper *PCPMapper) forget(protocol byte, port uint) {
+	mapper.mutex.Lock() // Lock mapper
+
+	defer mapper.mutex.Unlock() // Unlock mapper
+
+	delete(mapper.nonces, strconv.Itoa(int(protocol))+"/"+strconv.Itoa(int(port))) // Remove nonce
+}
+
+// localAddressTo - fetch local address used to reach given gateway
+func localAddressTo(gateway *net.UDPAddr) (net.IP, error) {
+	conn, err := net.DialUDP("udp", nil, gateway) // Open socket (no packets are sent)
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	defer conn.Close() // Close socket
+
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil // Return local address
+}
+
+/*
+	END INTERNAL METHODS
+*/
@@ -0,0 +1,49 @@
+package upnp
+
+import (
+	"testing"
+	"time"
+)
+
+// TestPCPMapPort - test functionality of PCP port mapping
+func TestPCPMapPort(t *testing.T) {
+	gateway := startFakeGateway(t, false, true) // Start PCP gateway
+
+	defer gateway.Close() // Stop gateway
+
+	gateway.dropRequests(1) // Ignore first request
+
+	mapper := gateway.pcpMapper() // Init mapper
+
+	mapping, err := mapper.MapPort(3000, time.Hour) // Map port
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if mapping.Mapper != "pcp" || mapping.ExternalPort != 13000 || !mapping.ExternalIP.Equal(gateway.externalIP) || mapping.Lifetime != time.Hour { // Check mapping
+		t.Errorf("invalid mapping %s", mapping.String()) // Log found error
+		t.FailNow()                                      // Panic
+	}
+
+	if _, err = mapper.MapPort(3000, time.Hour); err != nil { // Renew mapping (gateway rejects renewals with another nonce)
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if _, err = gateway.pcpMapper().MapPort(3000, time.Hour); err == nil { // Check other clients can't take over mapping
+		t.Errorf("expected mapping with another nonce to fail") // Log found error
+		t.FailNow()                                             // Panic
+	}
+
+	if err = mapper.UnmapPort(3000); err != nil { // Unmap port
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if gateway.mappingCount() != 0 { // Check mappings were removed
+		t.Errorf("expected no mappings, found %d", gateway.mappingCount()) // Log found error
+		t.FailNow()                                                        // Panic
+	}
+}
@@ -0,0 +1,295 @@
+package upnp
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultLifetime - default lease lifetime requested for port mappings (UPnP mappings don't expire)
+	DefaultLifetime = 2 * time.Hour
+
+	// DefaultDescription - description of port mappings added to UPnP gateways
+	DefaultDescription = "resourceforwarding"
+)
+
+var (
+	// DefaultPortMappers - port mappers tried in order by MapPort, UnmapPort
+	DefaultPortMappers = []PortMapper{&UPnPMapper{}, &NATPMPMapper{}, &PCPMapper{}}
+
+	// ErrNoGateway - error returned when the default gateway can't be determined
+	ErrNoGateway = errors.New("no default gateway found")
+
+	// routeTablePath - path of Linux IPv4 routing table
+	routeTablePath = "/proc/net/route"
+)
+
+// PortMapper - means of asking a gateway to forward an external port to the local node
+type PortMapper interface {
+	Name() string // Name - name of port mapping protocol (e.g. "nat-pmp")
+
+	MapPort(port uint, lifetime time.Duration) (*Mapping, error) // MapPort - forward given external TCP, UDP port (or whichever port the gateway assigns) to given local port for given lifetime
+
+	UnmapPort(port uint) error // UnmapPort - remove mappings of given local port
+}
+
+// Mapping - port mapping added to a gateway
+type Mapping struct {
+	Mapper string `json:"mapper"` // Mapper - name of port mapper mapping was added by
+
+	InternalPort uint `json:"internalPort"` // InternalPort - local port
+
+	ExternalPort uint `json:"externalPort"` // ExternalPort - port assigned by gateway
+
+	ExternalIP net.IP `json:"externalIP"` // ExternalIP - external address of gateway (nil if unknown)
+
+	Lifetime time.Duration `json:"lifetime"` // Lifetime - lease lifetime granted by gateway (0 if mapping doesn't expire)
+}
+
+// UPnPMapper - UPnP IGD port mapper
+type UPnPMapper struct {
+	Description string // Description - description of mappings (DefaultDescription if empty)
+}
+
+/*
+	BEGIN EXPORTED METHODS:
+*/
+
+// MapPort - forward given local port via the first of DefaultPortMappers that succeeds
+func MapPort(port uint, lifetime time.Duration) (*Mapping, error) {
+	return MapPortWith(DefaultPortMappers, port, lifetime) // Map port
+}
+
+// MapPortWith - forward given local port via the first of given port mappers that succeeds
+func MapPortWith(mappers []PortMapper, port uint, lifetime time.Duration) (*Mapping, error) {
+	errs := []string{} // Init error buffer
+
+	for _, mapper := range mappers { // Iterate through mappers
+		mapping, err := mapper.MapPort(port, lifetime) // Map port
+
+		if err == nil { // Check for success
+			return mapping, nil // Return mapping
+		}
+
+		errs = append(errs, mapper.Name()+": "+err.Error()) // Append error
+	}
+
+	return nil, fmt.Errorf("couldn't map port %d (%s)", port, strings.Join(errs, "; ")) // Return error
+}
+
+// UnmapPort - remove mappings of given local port via each of DefaultPortMappers
+func UnmapPort(port uint) error {
+	return UnmapPortWith(DefaultPortMappers, port) // Unmap port
+}
+
+// UnmapPortWith - remove mappings of given local port via each of given port mappers, returning an error if none succeeded
+func UnmapPortWith(mappers []PortMapper, port uint) error {
+	errs := []string{} // Init error buffer
+
+	for _, mapper := range mappers { // Iterate through mappers
+		if err := mapper.UnmapPort(port); err != nil { // Unmap port
+			errs = append(errs, mapper.Name()+": "+err.Error()) // Append error
+		}
+	}
+
+	if len(errs) == len(mappers) { // Check no mapper succeeded
+		return fmt.Errorf("couldn't unmap port %d (%s)", port, strings.Join(errs, "; ")) // Return error
+	}
+
+	return nil // No error occurred, return nil
+}
+
+// String - convert mapping to human-readable string
+func (mapping *Mapping) String() string {
+	external := ":" + strconv.Itoa(int(mapping.ExternalPort)) // Init external address
+
+	if mapping.ExternalIP != nil { // Check for external IP
+		external = net.JoinHostPort(mapping.ExternalIP.String(), strconv.Itoa(int(mapping.ExternalPort))) // Set external address
+	}
+
+	lease := "permanent" // Init lease
+
+	if mapping.Lifetime != 0 { // Check for expiring lease
+		lease = "lease " + mapping.Lifetime.String() // Set lease
+	}
+
+	return fmt.Sprintf("%s -> %d via %s, %s", external, mapping.InternalPort, mapping.Mapper, lease) // Return string
+}
+
+// Name - implement PortMapper interface
+func (mapper *UPnPMapper) Name() string {
+	return "upnp" // Return name
+}
+
+// MapPort - forward given TCP, UDP port on UPnP gateway (lifetime is ignored, mappings don't expire)
+func (mapper *UPnPMapper) MapPort(port uint, lifetime time.Duration) (*Mapping, error) {
+	gateway, err := GetGateway() // Find network gateway device
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	description := mapper.Description // Fetch description
+
+	if description == "" { // Check for nil description
+		description = DefaultDescription // Set default description
+	}
+
+	err = gateway.Forward(uint16(port), description) // Attempt to forward
+
+	if err != nil { // Check for errors
+		return nil, err // Return found error
+	}
+
+	mapping := &Mapping{Mapper: mapper.Name(), InternalPort: port, ExternalPort: port} // Init mapping
+
+	if externalIP, err := gateway.ExternalIP(); err == nil { // Fetch external IP
+		mapping.ExternalIP = net.ParseIP(externalIP) // Set external IP
+	}
+
+	return mapping, nil // Return mapping
+}
+
+// UnmapPort - remove forwarding of given port from UPnP gateway
+func (mapper *UPnPMapper) UnmapPort(port uint) error {
+	gateway, err := GetGateway() // Find network gateway device
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	return gateway.Clear(uint16(port)) // Remove forwarding
+}
+
+// DefaultGateway - determine IPv4 address of default gateway (from the routing table on Linux, assuming the first address of the local network elsewhere)
+func DefaultGateway() (net.IP, error) {
+	if file, err := os.Open(routeTablePath); err == nil { // Open routing table
+		defer file.Close() // Close routing table
+
+		return parseRouteTable(file) // Return gateway
+	}
+
+	conn, err := net.Dial("udp4", "192.0.2.1:9") // Find outbound interface address (no packets are sent)
+
+	if err != nil { // Check for errors
+		return nil, ErrNoGateway // Return error
+	}
+
+	defer conn.Close() // Close socket
+
+	local := conn.LocalAddr().(*net.UDPAddr).IP.To4() // Fetch local address
+
+	if local == nil || local.IsLoopback() { // Check for invalid address
+		return nil, ErrNoGateway // Return error
+	}
+
+	return net.IPv4(local[0], local[1], local[2], 1), nil // Return likely gateway
+}
+
+/*
+	END EXPORTED METHODS
+*/
+
+/*
+	BEGIN INTERNAL METHODS:
+*/
+
+// parseRouteTable - find gateway of default route in given Linux routing table (/proc/net/route format)
+func parseRouteTable(r io.Reader) (net.IP, error) {
+	scanner := bufio.NewScanner(r) // Init scanner
+
+	for scanner.Scan() { // Iterate through routes
+		fields := strings.Fields(scanner.Text()) // Split route
+
+		if len(fields) < 3 || fields[1] != "00000000" { // Check route isn't default route
+			continue // Skip route
+		}
+
+		gateway, err := hex.DecodeString(fields[2]) // Decode gateway
+
+		if err != nil || len(gateway) != net.IPv4len { // Check for errors
+			continue // Skip route
+		}
+
+		binary.BigEndian.PutUint32(gateway, binary.LittleEndian.Uint32(gateway)) // Convert from host byte order
+
+		if ip := net.IP(gateway); !ip.IsUnspecified() { // Check for gateway
+			return ip, nil // Return gateway
+		}
+	}
+
+	return nil, ErrNoGateway // No default route
+}
+
+// gatewayAddress - fetch UDP address of given gateway (default gateway if nil) on given port
+func gatewayAddress(gateway net.IP, port uint, defaultPort uint) (*net.UDPAddr, error) {
+	if gateway == nil { // Check for nil gateway
+		defaultGateway, err := DefaultGateway() // Find default gateway
+
+		if err != nil { // Check for errors
+			return nil, err // Return found error
+		}
+
+		gateway = defaultGateway // Set gateway
+	}
+
+	if port == 0 { // Check for nil port
+		port = defaultPort // Set default port
+	}
+
+	return &net.UDPAddr{IP: gateway, Port: int(port)}, nil // Return address
+}
+
+// exchange - send given request to given gateway over UDP, retransmitting with exponential backoff starting at given timeout until a response accepted by given validator is received (at most given number of attempts)
+func exchange(gateway *net.UDPAddr, request []byte, timeout time.Duration, attempts int, validate func(response []byte) (bool, error)) error {
+	conn, err := net.DialUDP("udp", nil, gateway) // Open socket
+
+	if err != nil { // Check for errors
+		return err // Return found error
+	}
+
+	defer conn.Close() // Close socket
+
+	buffer := make([]byte, 1100) // Init response buffer (maximum PCP message size)
+
+	for attempt := 0; attempt < attempts; attempt++ { // Retransmit request
+		if _, err = conn.Write(request); err != nil { // Send request
+			return err // Return found error
+		}
+
+		deadline := time.Now().Add(timeout << uint(attempt)) // Init deadline
+
+		conn.SetReadDeadline(deadline) // Set read deadline
+
+		for {
+			n, err := conn.Read(buffer) // Read response
+
+			if err != nil { // Check for errors
+				if netErr, isNetErr := err.(net.Error); isNetErr && netErr.Timeout() { // Check timed out
+					break // Retransmit
+				}
+
+				return err // Return found error
+			}
+
+			if matched, err := validate(buffer[:n]); matched || err != nil { // Check response answers request
+				return err // Return error (might be nil)
+			}
+		}
+	}
+
+	return fmt.Errorf("gateway %s didn't respond", gateway) // Return error
+}
+
+/*
+	END INTERNAL METHODS
+*/
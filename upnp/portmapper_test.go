@@ -0,0 +1,318 @@
+package upnp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestMapPortWith - test port mappers are tried in order
+func TestMapPortWith(t *testing.T) {
+	gateway := startFakeGateway(t, false, true) // Start PCP-only gateway
+
+	defer gateway.Close() // Stop gateway
+
+	mappers := []PortMapper{&failingMapper{}, gateway.natPMPMapper(), gateway.pcpMapper()} // Init mappers
+
+	mapping, err := MapPortWith(mappers, 3000, time.Hour) // Map port
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if mapping.Mapper != "pcp" || mapping.ExternalPort != 13000 || !mapping.ExternalIP.Equal(gateway.externalIP) || mapping.Lifetime != time.Hour { // Check mapping
+		t.Errorf("invalid mapping %s", mapping.String()) // Log found error
+		t.FailNow()                                      // Panic
+	}
+
+	if _, err = MapPortWith(mappers[:2], 3000, time.Hour); err == nil || !strings.Contains(err.Error(), "failing: ") || !strings.Contains(err.Error(), "nat-pmp: ") { // Check failing mappers are reported
+		t.Errorf("expected mapping to fail, got %v", err) // Log found error
+		t.FailNow()                                       // Panic
+	}
+}
+
+// TestUnmapPortWith - test mappings are removed if any port mapper succeeds
+func TestUnmapPortWith(t *testing.T) {
+	gateway := startFakeGateway(t, true, false) // Start NAT-PMP-only gateway
+
+	defer gateway.Close() // Stop gateway
+
+	mappers := []PortMapper{&failingMapper{}, gateway.natPMPMapper()} // Init mappers
+
+	if _, err := MapPortWith(mappers, 3000, time.Hour); err != nil { // Map port
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if err := UnmapPortWith(mappers, 3000); err != nil { // Unmap port
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if gateway.mappingCount() != 0 { // Check mappings were removed
+		t.Errorf("expected no mappings, found %d", gateway.mappingCount()) // Log found error
+		t.FailNow()                                                        // Panic
+	}
+
+	if err := UnmapPortWith(mappers[:1], 3000); err == nil { // Check failing mappers are reported
+		t.Errorf("expected unmapping to fail") // Log found error
+		t.FailNow()                            // Panic
+	}
+}
+
+// TestParseRouteTable - test default gateway is read from routing table
+func TestParseRouteTable(t *testing.T) {
+	table := "Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT\n" +
+		"eth0\t0002A8C0\t00000000\t0001\t0\t0\t0\t00FFFFFF\t0\t0\t0\n" +
+		"eth0\t00000000\t0102A8C0\t0003\t0\t0\t0\t00000000\t0\t0\t0\n" // Init routing table
+
+	gateway, err := parseRouteTable(strings.NewReader(table)) // Parse routing table
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	if !gateway.Equal(net.IPv4(192, 168, 2, 1)) { // Check gateway
+		t.Errorf("invalid gateway %s", gateway) // Log found error
+		t.FailNow()                             // Panic
+	}
+
+	if _, err = parseRouteTable(strings.NewReader(strings.Split(table, "\n")[1])); err != ErrNoGateway { // Check table without default route
+		t.Errorf("expected ErrNoGateway, got %v", err) // Log found error
+		t.FailNow()                                    // Panic
+	}
+}
+
+/*
+	BEGIN HELPER METHODS:
+*/
+
+// failingMapper - port mapper failing every request
+type failingMapper struct{}
+
+// Name - implement PortMapper interface
+func (mapper *failingMapper) Name() string {
+	return "failing" // Return name
+}
+
+// MapPort - implement PortMapper interface
+func (mapper *failingMapper) MapPort(port uint, lifetime time.Duration) (*Mapping, error) {
+	return nil, errors.New("no gateway") // Return error
+}
+
+// UnmapPort - implement PortMapper interface
+func (mapper *failingMapper) UnmapPort(port uint) error {
+	return errors.New("no gateway") // Return error
+}
+
+// fakeGateway - local NAT-PMP, PCP gateway mapping internal ports to internal port + 10000
+type fakeGateway struct {
+	conn *net.UDPConn // conn - gateway socket
+
+	externalIP net.IP // externalIP - external address reported by gateway
+
+	natPMP bool // natPMP - gateway speaks NAT-PMP
+
+	pcp bool // pcp - gateway speaks PCP
+
+	drop int // drop - number of requests to ignore before responding (tests retransmission)
+
+	mappings map[string][]byte // mappings - PCP nonces (nil for NAT-PMP) of mappings by protocol, internal port
+
+	mutex sync.Mutex // mutex - guards drop, mappings
+}
+
+// startFakeGateway - start gateway speaking given protocols on a random local port
+func startFakeGateway(t *testing.T, natPMP bool, pcp bool) *fakeGateway {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)}) // Listen on random port
+
+	if err != nil { // Check for errors
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
+	gateway := &fakeGateway{conn: conn, externalIP: net.IPv4(203, 0, 113, 7), natPMP: natPMP, pcp: pcp, mappings: make(map[string][]byte)} // Init gateway
+
+	go gateway.serve() // Respond to requests
+
+	return gateway // Return gateway
+}
+
+// Close - stop gateway
+func (gateway *fakeGateway) Close() error {
+	return gateway.conn.Close() // Close socket
+}
+
+// natPMPMapper - initialize NAT-PMP mapper requesting gateway
+func (gateway *fakeGateway) natPMPMapper() *NATPMPMapper {
+	address := gateway.conn.LocalAddr().(*net.UDPAddr) // Fetch gateway address
+
+	return &NATPMPMapper{Gateway: address.IP, Port: uint(address.Port), Timeout: 50 * time.Millisecond} // Return mapper
+}
+
+// pcpMapper - initialize PCP mapper requesting gateway
+func (gateway *fakeGateway) pcpMapper() *PCPMapper {
+	address := gateway.conn.LocalAddr().(*net.UDPAddr) // Fetch gateway address
+
+	return &PCPMapper{Gateway: address.IP, Port: uint(address.Port), Timeout: 50 * time.Millisecond} // Return mapper
+}
+
+// dropRequests - ignore given number of requests (tests retransmission)
+func (gateway *fakeGateway) dropRequests(count int) {
+	gateway.mutex.Lock() // Lock gateway
+
+	defer gateway.mutex.Unlock() // Unlock gateway
+
+	gateway.drop = count // Set drop count
+}
+
+// speak - set protocols gateway speaks
+func (gateway *fakeGateway) speak(natPMP bool, pcp bool) {
+	gateway.mutex.Lock() // Lock gateway
+
+	defer gateway.mutex.Unlock() // Unlock gateway
+
+	gateway.natPMP, gateway.pcp = natPMP, pcp // Set protocols
+}
+
+// mappingCount - fetch number of active mappings
+func (gateway *fakeGateway) mappingCount() int {
+	gateway.mutex.Lock() // Lock gateway
+
+	defer gateway.mutex.Unlock() // Unlock gateway
+
+	return len(gateway.mappings) // Return mapping count
+}
+
+// serve - respond to requests until gateway is closed
+func (gateway *fakeGateway) serve() {
+	buffer := make([]byte, 1100) // Init request buffer
+
+	for {
+		n, from, err := gateway.conn.ReadFromUDP(buffer) // Read request
+
+		if err != nil { // Check for errors
+			return // Gateway closed
+		}
+
+		if response := gateway.respond(buffer[:n], from); response != nil { // Check for response
+			gateway.conn.WriteToUDP(response, from) // Write response
+		}
+	}
+}
+
+// respond - build response to given request
+func (gateway *fakeGateway) respond(request []byte, from *net.UDPAddr) []byte {
+	gateway.mutex.Lock() // Lock gateway
+
+	defer gateway.mutex.Unlock() // Unlock gateway
+
+	if gateway.drop > 0 { // Check request should be ignored
+		gateway.drop-- // Decrement drop count
+
+		return nil // Ignore request
+	}
+
+	if len(request) < 2 { // Check for invalid request
+		return nil // Ignore request
+	}
+
+	switch {
+	case request[0] == natPMPVersion && gateway.natPMP: // NAT-PMP request
+		return gateway.respondNATPMP(request) // Respond
+	case request[0] == pcpVersion && gateway.pcp: // PCP request
+		return gateway.respondPCP(request, from) // Respond
+	default:
+		return []byte{natPMPVersion, request[1] | natPMPResponseFlag, 0, 1} // Respond with unsupported version
+	}
+}
+
+// respondNATPMP - build response to given NAT-PMP request
+func (gateway *fakeGateway) respondNATPMP(request []byte) []byte {
+	if request[1] == natPMPOpExternalAddress { // Check external address request
+		return append([]byte{natPMPVersion, natPMPResponseFlag, 0, 0, 0, 0, 0, 1}, gateway.externalIP.To4()...) // Respond with external address
+	}
+
+	internalPort := binary.BigEndian.Uint16(request[4:6])                        // Fetch internal port
+	lifetime := binary.BigEndian.Uint32(request[8:12])                           // Fetch lifetime
+	key := strconv.Itoa(int(request[1])) + "/" + strconv.Itoa(int(internalPort)) // Init mapping key
+
+	externalPort := internalPort + 10000 // Init external port
+
+	if lifetime == 0 { // Check mapping removal
+		delete(gateway.mappings, key) // Remove mapping
+
+		externalPort = 0 // Reset external port
+	} else {
+		gateway.mappings[key] = nil // Add mapping
+	}
+
+	response := make([]byte, 16) // Init response
+
+	response[0], response[1] = natPMPVersion, request[1]|natPMPResponseFlag // Set version, opcode
+	binary.BigEndian.PutUint16(response[8:10], internalPort)                // Set internal port
+	binary.BigEndian.PutUint16(response[10:12], externalPort)               // Set external port
+	binary.BigEndian.PutUint32(response[12:16], lifetime)                   // Set lifetime
+
+	return response // Return response
+}
+
+// respondPCP - build response to given PCP MAP request received from given address
+func (gateway *fakeGateway) respondPCP(request []byte, from *net.UDPAddr) []byte {
+	response := make([]byte, pcpMapSize) // Init response
+
+	copy(response, request) // Copy mapping description
+
+	response[1] = request[1] | natPMPResponseFlag // Set opcode
+
+	if len(request) < pcpMapSize || request[1] != pcpOpMap { // Check for unsupported request
+		response[3] = 3 // Set malformed request
+
+		return response // Return response
+	}
+
+	if !net.IP(request[8:24]).Equal(from.IP) { // Check client address
+		response[3] = 12 // Set address mismatch
+
+		return response // Return response
+	}
+
+	nonce := append([]byte{}, request[24:36]...)                                  // Fetch nonce
+	internalPort := binary.BigEndian.Uint16(request[40:42])                       // Fetch internal port
+	lifetime := binary.BigEndian.Uint32(request[4:8])                             // Fetch lifetime
+	key := strconv.Itoa(int(request[36])) + "/" + strconv.Itoa(int(internalPort)) // Init mapping key
+
+	if existing, found := gateway.mappings[key]; found && !bytes.Equal(existing, nonce) { // Check mapping is owned by another client
+		response[3] = 2 // Set not authorized
+
+		return response // Return response
+	}
+
+	externalPort := internalPort + 10000 // Init external port
+
+	if lifetime == 0 { // Check mapping removal
+		delete(gateway.mappings, key) // Remove mapping
+
+		externalPort = 0 // Reset external port
+	} else {
+		gateway.mappings[key] = nonce // Add mapping
+	}
+
+	response[3] = 0                                           // Set success
+	binary.BigEndian.PutUint16(response[42:44], externalPort) // Set external port
+	copy(response[44:60], gateway.externalIP.To16())          // Set external address
+
+	return response // Return response
+}
+
+/*
+	END HELPER METHODS
+*/
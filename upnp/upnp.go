@@ -1,6 +1,8 @@
 package upnp
 
 import (
+	"context"
+	"errors"
 	"strconv"
 	"time"
 
@@ -9,92 +11,67 @@ import (
 	"github.com/dowlandaiello/GoP2P/common"
 )
 
+var (
+	// DiscoveryTimeout - maximum duration to spend discovering a UPnP gateway
+	DiscoveryTimeout = 30 * time.Second
+
+	// ErrNoUPnPGateway - error returned when no UPnP gateway is discovered within DiscoveryTimeout
+	ErrNoUPnPGateway = errors.New("no UPnP-enabled gateway found")
+)
+
 /*
 	BEGIN EXPORTED METHODS:
 */
 
-// GetGateway - get reference to current network gateway device
+// GetGateway - get reference to current network gateway device, discovered within DiscoveryTimeout
 func GetGateway() (*upnp.IGD, error) { // Returns error if forward failed, returns gateway device is succeeded
-	d, err := upnp.Discover() // Attempt to discover gateway device
+	ctx, cancel := context.WithTimeout(context.Background(), DiscoveryTimeout) // Init discovery deadline
 
-	if err != nil { // Check for errors
-		return nil, err // Return error
-	}
+	defer cancel() // Release deadline
 
-	return d, nil // Return gateway
-}
+	d, err := upnp.DiscoverCtx(ctx) // Attempt to discover gateway device
 
-// ForwardPortSilent - forwards specified port on current device without log output
-func ForwardPortSilent(portNumber uint) error { // Returns error if forward failed
-	GatewayDevice, err := GetGateway() // Find network gateway device
+	if err == context.Canceled || ctx.Err() != nil { // Check deadline exceeded
+		return nil, ErrNoUPnPGateway // Return error
+	}
 
 	if err != nil { // Check for errors
-		return err // Return error
+		return nil, err // Return error
 	}
 
-	err = GatewayDevice.Forward(uint16(portNumber), "resourceforwarding") // Attempt to forward
-
-	if err != nil { // Check if error occurred
-		return err // Return error
-	}
+	return d, nil // Return gateway
+}
 
-	return nil // No error occurred, return nil
+// ForwardPortSilent - forwards specified port on current device via the first of DefaultPortMappers that succeeds, without log output
+func ForwardPortSilent(portNumber uint) (*Mapping, error) { // Returns error if forward failed
+	return MapPort(portNumber, DefaultLifetime) // Attempt to forward
 }
 
-// ForwardPort - forwards specified port on current device
-func ForwardPort(portNumber uint) error { // Returns error if forward failed
+// ForwardPort - forwards specified port on current device via the first of DefaultPortMappers that succeeds
+func ForwardPort(portNumber uint) (*Mapping, error) { // Returns error if forward failed
 	s := spinner.New(spinner.CharSets[7], 100*time.Millisecond) // Init loading indicator
 
-	s.Prefix = "   "                                     // Add line spacing
-	s.Suffix = " attempting to discover network gateway" // Add log message
+	s.Prefix = "   "                         // Add line spacing
+	s.Suffix = " attempting to forward port" // Add log message
 
 	s.Start() // Start loading indicator
 
-	GatewayDevice, err := GetGateway() // Find network gateway device
-
-	if err != nil { // Check for errors
-		s.Stop() // Stop loading indicator
-
-		return err // Return error
-	}
+	mapping, err := MapPort(portNumber, DefaultLifetime) // Attempt to forward
 
 	s.Stop() // Stop loading indicator
 
-	common.Println("\nfound gateway") // Log found gateway
-
-	s.Suffix = " attempting to forward port" // Add log message
-
-	s.Restart() // Start loading indicator
-
-	err = GatewayDevice.Forward(uint16(portNumber), "resourceforwarding") // Attempt to forward
-
 	if err != nil { // Check if error occurred
-		s.Stop()   // Stop loading indicator
-		return err // Return error
+		return nil, err // Return error
 	}
 
-	s.Stop() // Stop loading indicator
-
-	common.Println("\nsuccessfully forwarded port " + strconv.Itoa(int(portNumber))) // Log success
+	common.Println("\nsuccessfully forwarded port " + strconv.Itoa(int(portNumber)) + " (" + mapping.String() + ")") // Log success
 
-	return nil // No error occurred, return nil
+	return mapping, nil // No error occurred, return mapping
 }
 
 // RemovePortForward - removes all forwarding for specified port
 func RemovePortForward(portNumber uint) error { // Returns error if removal failed
-	GatewayDevice, err := GetGateway() // Find network gateway device
-
-	if err != nil { // Check for errors
-		return err // Return error
-	}
-
-	err = GatewayDevice.Clear(uint16(portNumber)) // Remove specified port forwarding
-
-	if err != nil { // Check for errors
-		return err // Return error
-	}
-
-	return nil // No error occurred, return nil
+	return UnmapPort(portNumber) // Remove specified port forwarding
 }
 
 /*
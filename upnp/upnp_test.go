@@ -3,10 +3,15 @@ package upnp
 import (
 	"strings"
 	"testing"
+	"time"
 )
 
 // TestDiscoverGateway - test functionality of gateway discovery
 func TestDiscoverGateway(t *testing.T) {
+	defer func(timeout time.Duration) { DiscoveryTimeout = timeout }(DiscoveryTimeout) // Restore discovery timeout
+
+	DiscoveryTimeout = 500 * time.Millisecond // Don't wait on missing gateways
+
 	gateway, err := GetGateway() // Attempt to fetch gateway device
 
 	if err != nil && !strings.Contains(err.Error(), "no UPnP") {
@@ -21,35 +26,87 @@ func TestDiscoverGateway(t *testing.T) {
 
 // TestForwardPort - test functionality of port forwarding
 func TestForwardPort(t *testing.T) {
-	err := ForwardPort(3000) // Attempt to forward port 3000
+	gateway := startFakeGateway(t, true, false) // Start NAT-PMP-only gateway
 
-	if err != nil && !strings.Contains(err.Error(), "no UPnP") {
+	defer gateway.Close() // Stop gateway
+
+	defer useMappers(&failingMapper{}, gateway.natPMPMapper())() // Forward through gateway
+
+	mapping, err := ForwardPort(3000) // Attempt to forward port 3000
+
+	if err != nil { // Check for errors
 		t.Errorf(err.Error()) // If error occurs, print error to testing console
-		t.FailNow()
-	} else if err != nil && strings.Contains(err.Error(), "no UPnP") {
-		t.Logf("WARNING: UPnP testing requires UPnP network support") // Log warning
+		t.FailNow()           // Panic
+	}
+
+	if mapping.Mapper != "nat-pmp" || mapping.InternalPort != 3000 || gateway.mappingCount() == 0 { // Check mapping
+		t.Errorf("invalid mapping %s", mapping.String()) // Log found error
+		t.FailNow()                                      // Panic
 	}
 }
 
 // TestForwardPortSilent - test functionality of silent port forwarding
 func TestForwardPortSilent(t *testing.T) {
-	err := ForwardPortSilent(3000) // Attempt to forward port 3000
+	gateway := startFakeGateway(t, false, true) // Start PCP-only gateway
+
+	defer gateway.Close() // Stop gateway
+
+	defer useMappers(gateway.natPMPMapper(), gateway.pcpMapper())() // Forward through gateway
 
-	if err != nil && !strings.Contains(err.Error(), "no UPnP") { // Check for errors
+	mapping, err := ForwardPortSilent(3000) // Attempt to forward port 3000
+
+	if err != nil { // Check for errors
 		t.Errorf(err.Error()) // If error occurs, print error to testing console
 		t.FailNow()           // Panic
-	} else if err != nil && strings.Contains(err.Error(), "no UPnP") {
-		t.Logf("WARNING: UPnP testing requires UPnP network support") // Log warning
+	}
+
+	if mapping.Mapper != "pcp" || mapping.ExternalPort != 13000 { // Check mapping
+		t.Errorf("invalid mapping %s", mapping.String()) // Log found error
+		t.FailNow()                                      // Panic
 	}
 }
 
+// TestRemovePortForward - test functionality of port forwarding removal
 func TestRemovePortForward(t *testing.T) {
+	gateway := startFakeGateway(t, true, false) // Start NAT-PMP-only gateway
+
+	defer gateway.Close() // Stop gateway
+
+	defer useMappers(gateway.natPMPMapper())() // Forward through gateway
+
+	if _, err := ForwardPortSilent(3000); err != nil { // Attempt to forward port 3000
+		t.Errorf(err.Error()) // Log found error
+		t.FailNow()           // Panic
+	}
+
 	err := RemovePortForward(3000) // Attempt to remove forward on port 3000
 
-	if err != nil && !strings.Contains(err.Error(), "no UPnP") { // Check for errors
+	if err != nil { // Check for errors
 		t.Errorf(err.Error()) // If error occurs, print error to testing console
 		t.FailNow()           // Panic
-	} else if err != nil && strings.Contains(err.Error(), "no UPnP") {
-		t.Logf("WARNING: UPnP testing requires UPnP network support") // Log warning
+	}
+
+	if gateway.mappingCount() != 0 { // Check mapping was removed
+		t.Errorf("expected no mappings, found %d", gateway.mappingCount()) // Log found error
+		t.FailNow()                                                        // Panic
 	}
 }
+
+/*
+	BEGIN HELPER METHODS:
+*/
+
+// useMappers - set DefaultPortMappers to given mappers, returning func restoring previous mappers
+func useMappers(mappers ...PortMapper) func() {
+	previous := DefaultPortMappers // Fetch previous mappers
+
+	DefaultPortMappers = mappers // Set mappers
+
+	return func() {
+		DefaultPortMappers = previous // Restore mappers
+	}
+}
+
+/*
+	END HELPER METHODS
+*/